@@ -6,8 +6,14 @@ import (
 	"time"
 	"trxd/api/middlewares"
 	"trxd/api/routes/admin_stats"
+	"trxd/api/routes/announcements_create"
+	"trxd/api/routes/announcements_delete"
+	"trxd/api/routes/announcements_get"
+	"trxd/api/routes/announcements_update"
 	"trxd/api/routes/attachments_create"
 	"trxd/api/routes/attachments_delete"
+	"trxd/api/routes/audit_get"
+	"trxd/api/routes/author_dashboard"
 	"trxd/api/routes/categories_create"
 	"trxd/api/routes/categories_delete"
 	"trxd/api/routes/categories_get"
@@ -15,18 +21,32 @@ import (
 	"trxd/api/routes/challenges_all_get"
 	"trxd/api/routes/challenges_create"
 	"trxd/api/routes/challenges_delete"
+	"trxd/api/routes/challenges_feedback_create"
+	"trxd/api/routes/challenges_feedback_delete"
 	"trxd/api/routes/challenges_get"
 	"trxd/api/routes/challenges_hidden"
+	"trxd/api/routes/challenges_owners_create"
+	"trxd/api/routes/challenges_owners_delete"
 	"trxd/api/routes/challenges_update"
 	"trxd/api/routes/configs_get"
 	"trxd/api/routes/configs_update"
+	"trxd/api/routes/email_templates_delete"
+	"trxd/api/routes/email_templates_get"
+	"trxd/api/routes/email_templates_update"
+	"trxd/api/routes/events_get"
 	"trxd/api/routes/flags_create"
 	"trxd/api/routes/flags_delete"
 	"trxd/api/routes/flags_update"
 	"trxd/api/routes/instances_create"
 	"trxd/api/routes/instances_delete"
+	"trxd/api/routes/instances_events_get"
 	"trxd/api/routes/instances_get"
+	"trxd/api/routes/instances_pow"
 	"trxd/api/routes/instances_update"
+	"trxd/api/routes/roles_create"
+	"trxd/api/routes/roles_delete"
+	"trxd/api/routes/roles_get"
+	"trxd/api/routes/roles_update"
 	"trxd/api/routes/submissions_create"
 	"trxd/api/routes/submissions_delete"
 	"trxd/api/routes/submissions_get"
@@ -40,38 +60,66 @@ import (
 	"trxd/api/routes/teams_scoreboard_graph"
 	"trxd/api/routes/teams_search"
 	"trxd/api/routes/teams_update"
+	"trxd/api/routes/tickets_all_get"
+	"trxd/api/routes/tickets_attachments_get"
+	"trxd/api/routes/tickets_create"
+	"trxd/api/routes/tickets_get"
+	"trxd/api/routes/tickets_messages_create"
+	"trxd/api/routes/tickets_update"
 	"trxd/api/routes/users_all_get"
+	"trxd/api/routes/users_custom_role"
 	"trxd/api/routes/users_get"
 	"trxd/api/routes/users_info"
 	"trxd/api/routes/users_login"
 	"trxd/api/routes/users_logout"
 	"trxd/api/routes/users_password"
+	"trxd/api/routes/users_password_reset"
 	"trxd/api/routes/users_register"
 	"trxd/api/routes/users_role"
 	"trxd/api/routes/users_search"
 	"trxd/api/routes/users_update"
+	"trxd/api/routes/writeups_accepted"
+	"trxd/api/routes/writeups_create"
+	"trxd/api/routes/writeups_delete"
+	"trxd/api/routes/writeups_get"
+	"trxd/api/routes/writeups_update"
 	"trxd/db"
 	"trxd/utils"
+	"trxd/utils/audit"
 	"trxd/utils/consts"
+	"trxd/utils/events"
 
 	"trxd/utils/log"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/csrf"
 	"github.com/gofiber/fiber/v2/middleware/favicon"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
 	"github.com/gofiber/fiber/v2/middleware/monitor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
 	noAuth    = middlewares.NoAuth
 	spectator = middlewares.Spectator
 	player    = middlewares.Player
-	author    = middlewares.Author
-	admin     = middlewares.Admin
 
-	team = middlewares.Team
+	can = middlewares.Can
+
+	auditAnnouncement  = middlewares.Audit(audit.Announcement)
+	auditCategory      = middlewares.Audit(audit.Category)
+	auditChallenge     = middlewares.Audit(audit.Challenge)
+	auditConfig        = middlewares.Audit(audit.Config)
+	auditEmailTemplate = middlewares.Audit(audit.EmailTemplate)
+	auditRole          = middlewares.Audit(audit.Role)
+	auditSubmission    = middlewares.Audit(audit.Submission)
+	auditUser          = middlewares.Audit(audit.User)
+	auditWriteup       = middlewares.Audit(audit.Writeup)
+
+	team   = middlewares.Team
+	teamOr = middlewares.TeamOr
 
 	start = middlewares.Start
 	end   = middlewares.End
@@ -81,6 +129,12 @@ func SetupApp(ctx context.Context) *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName:   consts.Name,
 		BodyLimit: 50 * 1024 * 1024, // 50MB
+		// c.IP() is the client only behind the trusted proxies, which set
+		// X-Real-IP instead of appending to X-Forwarded-For
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          consts.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	SetupFeatures(app)
@@ -88,7 +142,7 @@ func SetupApp(ctx context.Context) *fiber.App {
 
 	app.Static("/", "./frontend")
 
-	app.Use("/attachments", spectator, team, start, middlewares.Attachments)
+	app.Use("/attachments", spectator, teamOr(consts.PermChallengesRead), start, middlewares.Attachments)
 	app.Static("/attachments", "./attachments", fiber.Static{
 		Download: true,
 	})
@@ -106,6 +160,9 @@ func SetupApp(ctx context.Context) *fiber.App {
 }
 
 func Shutdown(app *fiber.App) {
+	// The event streams never end on their own
+	events.Close()
+
 	err := app.Shutdown()
 	if err != nil {
 		log.Error("Failed to shutdown Fiber app:", "err", err)
@@ -113,6 +170,8 @@ func Shutdown(app *fiber.App) {
 }
 
 func SetupFeatures(app *fiber.App) {
+	app.Use(middlewares.Metrics)
+
 	if consts.AntiPanic {
 		app.Use(func(c *fiber.Ctx) error {
 			defer func() {
@@ -148,9 +207,11 @@ func SetupFeatures(app *fiber.App) {
 		URL:  "/favicon.ico",
 	}))
 
-	app.Get("/monitor", admin, monitor.New(monitor.Config{
+	app.Get("/monitor", can(consts.PermStatsRead), monitor.New(monitor.Config{
 		Title: consts.Name + " Monitor",
 	}))
+
+	app.Get("/metrics", middlewares.MetricsAccess, adaptor.HTTPHandler(promhttp.Handler()))
 }
 
 func SetupApi(ctx context.Context, app *fiber.App) {
@@ -167,15 +228,24 @@ func SetupApi(ctx context.Context, app *fiber.App) {
 		api = app.Group("/api")
 	}
 
+	// The limiters count the requests of each app
+	powLimit := middlewares.Limit(consts.MaxPowRequests, time.Minute)
+
+	api.Get("/openapi.json", noAuth, OpenAPI)
+
 	api.Post("/register", noAuth, users_register.Route)
 	api.Post("/login", noAuth, users_login.Route)
 	api.Post("/logout", noAuth, users_logout.Route)
+	api.Post("/password-reset", noAuth, users_password_reset.Route)
 	api.Get("/info", noAuth, users_info.Route)
 	api.Get("/scoreboard", noAuth, teams_scoreboard.Route)
 	api.Get("/scoreboard/graph", noAuth, teams_scoreboard_graph.Route)
+	api.Get("/announcements", noAuth, announcements_get.Route)
+	api.Get("/events", noAuth, events_get.Route)
 
 	api.Patch("/users", player, users_update.Route)
-	api.Patch("/users/role", admin, users_role.Route)
+	api.Patch("/users/role", can(consts.PermRolesWrite), auditUser, users_role.Route)
+	api.Patch("/users/custom-role", can(consts.PermRolesWrite), auditUser, users_custom_role.Route)
 	api.Patch("/users/password", spectator, users_password.Route)
 	if mode != "true" {
 		api.Get("/users", noAuth, users_all_get.Route)
@@ -194,36 +264,74 @@ func SetupApi(ctx context.Context, app *fiber.App) {
 	api.Get("/teams/search", noAuth, teams_search.Route)
 	api.Get("/teams/:id", noAuth, teams_get.Route)
 
-	api.Post("/categories", author, categories_create.Route)
-	api.Patch("/categories", author, categories_update.Route)
-	api.Delete("/categories", author, categories_delete.Route)
-	api.Get("/categories", spectator, team, start, categories_get.Route)
+	api.Post("/categories", can(consts.PermChallengesWrite), auditCategory, categories_create.Route)
+	api.Patch("/categories", can(consts.PermChallengesWrite), auditCategory, categories_update.Route)
+	api.Delete("/categories", can(consts.PermChallengesWrite), auditCategory, categories_delete.Route)
+	api.Get("/categories", spectator, teamOr(consts.PermChallengesRead), start, categories_get.Route)
 
-	api.Post("/challenges", author, challenges_create.Route)
-	api.Patch("/challenges", author, challenges_update.Route)
-	api.Patch("/challenges/hidden", author, challenges_hidden.Route)
-	api.Delete("/challenges", author, challenges_delete.Route)
-	api.Get("/challenges", spectator, team, start, challenges_all_get.Route)
-	api.Get("/challenges/:id", spectator, team, start, challenges_get.Route)
+	api.Post("/challenges", can(consts.PermChallengesWrite), auditChallenge, challenges_create.Route)
+	api.Patch("/challenges", can(consts.PermChallengesWrite), auditChallenge, challenges_update.Route)
+	api.Patch("/challenges/hidden", can(consts.PermChallengesWrite), auditChallenge, challenges_hidden.Route)
+	api.Delete("/challenges", can(consts.PermChallengesWrite), auditChallenge, challenges_delete.Route)
+	api.Get("/challenges", spectator, teamOr(consts.PermChallengesRead), start, challenges_all_get.Route)
+	api.Get("/challenges/:id", spectator, teamOr(consts.PermChallengesRead), start, challenges_get.Route)
+	api.Post("/challenges/feedback", player, team, start, challenges_feedback_create.Route)
+	api.Delete("/challenges/feedback", player, team, challenges_feedback_delete.Route)
+	api.Post("/challenges/owners", can(consts.PermChallengesWrite), auditChallenge, challenges_owners_create.Route)
+	api.Delete("/challenges/owners", can(consts.PermChallengesWrite), auditChallenge, challenges_owners_delete.Route)
+
+	api.Get("/dashboard", can(consts.PermChallengesRead), author_dashboard.Route)
 
 	api.Post("/instances", player, team, start, instances_create.Route)
+	api.Post("/instances/pow", player, team, start, powLimit, instances_pow.Route)
 	api.Patch("/instances", player, team, start, instances_update.Route)
 	api.Delete("/instances", player, team, start, instances_delete.Route)
-	api.Get("/instances", admin, instances_get.Route)
+	api.Patch("/instances/manage", can(consts.PermInstancesManage), instances_update.Manage)
+	api.Delete("/instances/manage", can(consts.PermInstancesManage), instances_delete.Manage)
+	api.Get("/instances", can(consts.PermInstancesManage), instances_get.Route)
+	api.Get("/instances/events", can(consts.PermInstancesRead), instances_events_get.Route)
 
 	api.Post("/submissions", spectator, team, start, end, submissions_create.Route)
-	api.Get("/submissions", admin, submissions_get.Route)
-	api.Delete("/submissions", admin, submissions_delete.Route)
+	api.Get("/submissions", can(consts.PermSubmissionsRead), submissions_get.Route)
+	api.Delete("/submissions", can(consts.PermSubmissionsWrite), auditSubmission, submissions_delete.Route)
+
+	api.Post("/attachments", can(consts.PermChallengesWrite), auditChallenge, attachments_create.Route)
+	api.Delete("/attachments", can(consts.PermChallengesWrite), auditChallenge, attachments_delete.Route)
+
+	api.Post("/flags", can(consts.PermChallengesWrite), auditChallenge, flags_create.Route)
+	api.Patch("/flags", can(consts.PermChallengesWrite), auditChallenge, flags_update.Route)
+	api.Delete("/flags", can(consts.PermChallengesWrite), auditChallenge, flags_delete.Route)
+
+	api.Post("/writeups", player, teamOr(consts.PermWriteupsModerate), writeups_create.Route)
+	api.Patch("/writeups", can(consts.PermWriteupsWrite), auditWriteup, writeups_update.Route)
+	api.Patch("/writeups/accepted", can(consts.PermWriteupsModerate), auditWriteup, writeups_accepted.Route)
+	api.Delete("/writeups", player, teamOr(consts.PermWriteupsModerate), writeups_delete.Route)
+	api.Get("/writeups", noAuth, writeups_get.Route)
+
+	api.Post("/tickets", player, team, tickets_create.Route)
+	api.Post("/tickets/messages", player, teamOr(consts.PermTicketsManage), tickets_messages_create.Route)
+	api.Patch("/tickets", player, teamOr(consts.PermTicketsManage), tickets_update.Route)
+	api.Get("/tickets", player, teamOr(consts.PermTicketsManage), tickets_all_get.Route)
+	api.Get("/tickets/:id", player, teamOr(consts.PermTicketsManage), tickets_get.Route)
+	api.Get("/tickets/:id/attachments/:hash/:name", player, teamOr(consts.PermTicketsManage), tickets_attachments_get.Route)
+
+	api.Get("/configs", can(consts.PermConfigsRead), configs_get.Route)
+	api.Patch("/configs", can(consts.PermConfigsWrite), auditConfig, configs_update.Route)
+
+	api.Post("/announcements", can(consts.PermAnnouncementsWrite), auditAnnouncement, announcements_create.Route)
+	api.Patch("/announcements", can(consts.PermAnnouncementsWrite), auditAnnouncement, announcements_update.Route)
+	api.Delete("/announcements", can(consts.PermAnnouncementsWrite), auditAnnouncement, announcements_delete.Route)
 
-	api.Post("/attachments", author, attachments_create.Route)
-	api.Delete("/attachments", author, attachments_delete.Route)
+	api.Get("/email-templates", can(consts.PermConfigsRead), email_templates_get.Route)
+	api.Patch("/email-templates", can(consts.PermConfigsWrite), auditEmailTemplate, email_templates_update.Route)
+	api.Delete("/email-templates", can(consts.PermConfigsWrite), auditEmailTemplate, email_templates_delete.Route)
 
-	api.Post("/flags", author, flags_create.Route)
-	api.Patch("/flags", author, flags_update.Route)
-	api.Delete("/flags", author, flags_delete.Route)
+	api.Get("/roles", can(consts.PermRolesWrite), roles_get.Route)
+	api.Post("/roles", can(consts.PermRolesWrite), auditRole, roles_create.Route)
+	api.Patch("/roles", can(consts.PermRolesWrite), auditRole, roles_update.Route)
+	api.Delete("/roles", can(consts.PermRolesWrite), auditRole, roles_delete.Route)
 
-	api.Get("/configs", admin, configs_get.Route)
-	api.Patch("/configs", admin, configs_update.Route)
+	api.Get("/stats", can(consts.PermStatsRead), admin_stats.Route)
 
-	api.Get("/stats", admin, admin_stats.Route)
+	api.Get("/audit", can(consts.PermAuditRead), audit_get.Route)
 }
@@ -2,6 +2,8 @@ package api_test
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 	"trxd/api"
 	"trxd/utils/consts"
@@ -101,3 +103,30 @@ func TestUserMode(t *testing.T) {
 		session.CheckResponse(errorf(consts.NotFound))
 	}
 }
+
+func TestOpenAPI(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Get("/openapi.json", nil, http.StatusOK)
+	body := session.Body()
+	document, ok := body.(map[string]any)
+	if !ok {
+		t.Fatalf("Invalid OpenAPI document: %v", body)
+	}
+
+	paths := document["paths"].(map[string]any)
+	for _, route := range app.GetRoutes(true) {
+		path, ok := strings.CutPrefix(route.Path, "/api")
+		if !ok || route.Method == fiber.MethodHead {
+			continue
+		}
+		path = regexp.MustCompile(`:(\w+)`).ReplaceAllString(path, "{$1}")
+
+		item, ok := paths[path].(map[string]any)
+		if !ok || item[strings.ToLower(route.Method)] == nil {
+			t.Errorf("Route %s %s lacks a schema", route.Method, route.Path)
+		}
+	}
+}
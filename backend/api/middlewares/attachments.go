@@ -4,7 +4,6 @@ import (
 	"strconv"
 	"strings"
 	"trxd/db"
-	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -31,14 +30,12 @@ func Attachments(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.NotFound)
 	}
 
-	role := c.Locals("role").(sqlc.UserRole)
-
 	res, err := db.GetHiddenAndAttachments(c.Context(), int32(challID))
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.InternalServerError, err)
 	}
 	if res == nil || // challenge not found
-		(res.Hidden && !utils.In(role, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})) || // hidden challenge and not author/admin
+		(res.Hidden && !utils.Can(c, consts.PermChallengesRead)) || // hidden challenge and not staff
 		!utils.In(path[3]+"/"+path[4], res.Attachments) { // attachment not found
 		return utils.Error(c, fiber.StatusNotFound, consts.NotFound)
 	}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"trxd/utils"
+	"trxd/utils/audit"
+	"trxd/utils/consts"
+	"trxd/utils/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Audit records the successful requests in the audit log, along with the
+// state of their target before and after them
+func Audit(snapshot audit.Snapshot) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		action := c.Method() + " " + c.Route().Path
+		target, before, err := snapshot(c)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorRecordingAudit, err)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		// The target of a creation is only known after it
+		afterTarget, after, err := snapshot(c)
+		if err != nil {
+			// The mutation already happened, so it is not reported as failed
+			log.Error("Failed to snapshot audit target:", "action", action, "target", target, "err", err)
+			return nil
+		}
+		if afterTarget != "" {
+			target = afterTarget
+		}
+
+		audit.Record(c.Context(), audit.Entry{
+			ActorID: c.Locals("uid").(int32),
+			IP:      c.IP(),
+			Action:  action,
+			Target:  target,
+			Before:  before,
+			After:   after,
+		})
+
+		return nil
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func withUser(c *fiber.Ctx, requireAuth bool, allowedRoles []sqlc.UserRole) error {
+func withUser(c *fiber.Ctx, requireAuth bool, allowedRoles []sqlc.UserRole, required ...string) error {
 	sess, err := db.Store.Get(c)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingSession, err)
@@ -27,10 +27,24 @@ func withUser(c *fiber.Ctx, requireAuth bool, allowedRoles []sqlc.UserRole) erro
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
 	}
+	// The locale chosen by the user takes precedence over Accept-Language
+	if user != nil && user.Locale.Valid {
+		c.Locals("locale", user.Locale.String)
+	}
 	if user == nil || !utils.In(user.Role, allowedRoles) {
 		return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
 	}
 
+	permissions, err := db.GetPermissions(c.Context(), user)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+	}
+	for _, permission := range required {
+		if !utils.In(permission, permissions) {
+			return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
+		}
+	}
+
 	tid := int32(-1)
 	if user.TeamID.Valid {
 		tid = user.TeamID.Int32
@@ -38,6 +52,7 @@ func withUser(c *fiber.Ctx, requireAuth bool, allowedRoles []sqlc.UserRole) erro
 	c.Locals("uid", uid)
 	c.Locals("role", user.Role)
 	c.Locals("tid", tid)
+	c.Locals("permissions", permissions)
 
 	return c.Next()
 }
@@ -54,23 +69,62 @@ func Player(c *fiber.Ctx) error {
 	return withUser(c, true, []sqlc.UserRole{sqlc.UserRolePlayer, sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})
 }
 
-func Author(c *fiber.Ctx) error {
-	return withUser(c, true, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})
-}
+// One guard for each permission, so that they can be told apart when
+// describing the routes
+var guards = func() map[string]fiber.Handler {
+	guards := make(map[string]fiber.Handler, len(consts.Permissions))
+	for _, permission := range consts.Permissions {
+		guards[permission] = func(c *fiber.Ctx) error {
+			return withUser(c, true, consts.Roles, permission)
+		}
+	}
+	return guards
+}()
 
-func Admin(c *fiber.Ctx) error {
-	return withUser(c, true, []sqlc.UserRole{sqlc.UserRoleAdmin})
+// Can allows only the users holding the permission, whatever their user role
+func Can(permission string) fiber.Handler {
+	guard, ok := guards[permission]
+	if !ok {
+		panic("unknown permission: " + permission)
+	}
+	return guard
 }
 
 func Team(c *fiber.Ctx) error {
+	return inTeam(c, "")
+}
+
+// inTeam requires the players to be in a team, unless they hold the permission
+func inTeam(c *fiber.Ctx, permission string) error {
 	tid := c.Locals("tid")
 	role := c.Locals("role")
 
 	if role == nil ||
 		(role.(sqlc.UserRole) == sqlc.UserRolePlayer &&
-			(tid == nil || tid.(int32) == -1)) {
+			(tid == nil || tid.(int32) == -1) &&
+			(permission == "" || !utils.Can(c, permission))) {
 		return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
 	}
 
 	return c.Next()
 }
+
+var teamGuards = func() map[string]fiber.Handler {
+	guards := make(map[string]fiber.Handler, len(consts.Permissions))
+	for _, permission := range consts.Permissions {
+		guards[permission] = func(c *fiber.Ctx) error {
+			return inTeam(c, permission)
+		}
+	}
+	return guards
+}()
+
+// TeamOr is Team, but also lets through the players staffing through a custom
+// role with the permission
+func TeamOr(permission string) fiber.Handler {
+	guard, ok := teamGuards[permission]
+	if !ok {
+		panic("unknown permission: " + permission)
+	}
+	return guard
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"trxd/api"
 	"trxd/db/sqlc"
+	"trxd/utils/consts"
 	"trxd/utils/test_utils"
 )
 
@@ -67,3 +68,95 @@ func TestAuthMiddlewares(t *testing.T) {
 		}
 	}
 }
+
+var testCustomRoles = []struct {
+	role             string
+	method           string
+	endpoint         string
+	expectedStatuses []int
+}{
+	{
+		role:             "support",
+		method:           http.MethodGet,
+		endpoint:         "/submissions",
+		expectedStatuses: []int{http.StatusOK, http.StatusForbidden},
+	},
+	{
+		role:             "support",
+		method:           http.MethodGet,
+		endpoint:         "/instances",
+		expectedStatuses: []int{http.StatusOK, http.StatusForbidden},
+	},
+	{
+		role:             "support",
+		method:           http.MethodPost,
+		endpoint:         "/flags",
+		expectedStatuses: []int{http.StatusForbidden, http.StatusForbidden},
+	},
+	{
+		role:             "infra",
+		method:           http.MethodGet,
+		endpoint:         "/instances",
+		expectedStatuses: []int{http.StatusOK, http.StatusForbidden},
+	},
+	{
+		role:             "infra",
+		method:           http.MethodGet,
+		endpoint:         "/submissions",
+		expectedStatuses: []int{http.StatusForbidden, http.StatusForbidden},
+	},
+	{
+		role:             "support",
+		method:           http.MethodPost,
+		endpoint:         "/submissions",
+		expectedStatuses: []int{http.StatusForbidden, http.StatusForbidden},
+	},
+	{
+		role:             "tickets",
+		method:           http.MethodGet,
+		endpoint:         "/tickets",
+		expectedStatuses: []int{http.StatusOK, http.StatusForbidden},
+	},
+	{
+		role:             "tickets",
+		method:           http.MethodPost,
+		endpoint:         "/tickets",
+		expectedStatuses: []int{http.StatusForbidden, http.StatusForbidden},
+	},
+	{
+		role:             "reader",
+		method:           http.MethodGet,
+		endpoint:         "/challenges",
+		expectedStatuses: []int{http.StatusOK, http.StatusForbidden},
+	},
+}
+
+func TestCustomRoles(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermSubmissionsRead, consts.PermInstancesManage}}, http.StatusOK)
+	admin.Post("/roles", JSON{"name": "infra", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+	admin.Post("/roles", JSON{"name": "tickets", "permissions": []string{consts.PermTicketsManage}}, http.StatusOK)
+	admin.Post("/roles", JSON{"name": "reader", "permissions": []string{consts.PermChallengesRead}}, http.StatusOK)
+
+	user := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": user.Email, "password": "testpass"}, http.StatusOK)
+
+	for _, test := range testCustomRoles {
+		// Granted, then revoked
+		admin.Patch("/users/custom-role", JSON{"user_id": user.ID, "custom_role": test.role}, http.StatusOK)
+		session.Request(test.method, test.endpoint, nil, test.expectedStatuses[0])
+		admin.Patch("/users/custom-role", JSON{"user_id": user.ID, "custom_role": nil}, http.StatusOK)
+		session.Request(test.method, test.endpoint, nil, test.expectedStatuses[1])
+	}
+
+	// The users of a deleted role lose its permissions
+	admin.Patch("/users/custom-role", JSON{"user_id": user.ID, "custom_role": "infra"}, http.StatusOK)
+	admin.Delete("/roles", JSON{"name": "infra"}, http.StatusOK)
+	session.Get("/instances", nil, http.StatusForbidden)
+}
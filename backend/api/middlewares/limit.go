@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"fmt"
+	"time"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// Limit allows each team (or user without a team) at most the given requests
+// every expiration, must follow the authentication middlewares
+func Limit(requests int, expiration time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        requests,
+		Expiration: expiration,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			tid, _ := c.Locals("tid").(int32)
+			if tid != -1 {
+				return fmt.Sprintf("team:%d", tid)
+			}
+			uid, _ := c.Locals("uid").(int32)
+			return fmt.Sprintf("user:%d", uid)
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return utils.Error(c, fiber.StatusTooManyRequests, consts.TooManyRequests)
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/db"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records the duration of each request, labelled by the route
+// template so that the cardinality stays bounded
+func Metrics(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else {
+			status = fiber.StatusInternalServerError
+		}
+	}
+
+	metrics.RequestDuration.
+		WithLabelValues(c.Method(), c.Route().Path, strconv.Itoa(status)).
+		Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// isInternal reports whether the request comes directly from a loopback or
+// private address, requests forwarded by the reverse proxy are external
+func isInternal(c *fiber.Ctx) bool {
+	if c.Get(fiber.HeaderXForwardedFor) != "" || c.Get("X-Real-IP") != "" {
+		return false
+	}
+
+	ip := net.ParseIP(c.IP())
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate())
+}
+
+// MetricsAccess only lets scrapers through: either with the configured bearer
+// token or, without one, from an internal address
+func MetricsAccess(c *fiber.Ctx) error {
+	token, err := db.GetConfig(c.Context(), "metrics-token")
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+	}
+
+	if token == "" {
+		if !isInternal(c) {
+			return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
+		}
+		return c.Next()
+	}
+
+	auth, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+		return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,51 @@
+package middlewares_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+func TestMetrics(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	session := test_utils.NewApiTestSession(t, app, true)
+
+	session.Get("/metrics", nil, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+
+	test_utils.UpdateConfig(t, "metrics-token", "scrape-token")
+
+	session.Get("/metrics", nil, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	session.SendRequest(req, http.StatusForbidden)
+
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	resp := session.SendRequest(req, http.StatusOK)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	for _, name := range []string{
+		"trxd_http_request_duration_seconds",
+		"trxd_db_open_connections",
+		"trxd_redis_ping_seconds",
+	} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("Metric %s not exported", name)
+		}
+	}
+}
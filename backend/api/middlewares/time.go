@@ -3,7 +3,6 @@ package middlewares
 import (
 	"time"
 	"trxd/db"
-	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 
@@ -27,9 +26,7 @@ func timeGate(c *fiber.Ctx, key string, check func(now, t time.Time) bool, errMs
 		return c.Next()
 	}
 
-	role := c.Locals("role")
-	if role == nil ||
-		(role.(sqlc.UserRole) != sqlc.UserRoleAuthor && role.(sqlc.UserRole) != sqlc.UserRoleAdmin) {
+	if !utils.Can(c, consts.PermChallengesRead) {
 		return utils.Error(c, fiber.StatusForbidden, errMsg)
 	}
 
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"trxd/api/openapi"
+	"trxd/api/routes/admin_stats"
+	"trxd/api/routes/announcements_create"
+	"trxd/api/routes/announcements_delete"
+	"trxd/api/routes/announcements_get"
+	"trxd/api/routes/announcements_update"
+	"trxd/api/routes/attachments_create"
+	"trxd/api/routes/attachments_delete"
+	"trxd/api/routes/audit_get"
+	"trxd/api/routes/author_dashboard"
+	"trxd/api/routes/categories_create"
+	"trxd/api/routes/categories_delete"
+	"trxd/api/routes/categories_get"
+	"trxd/api/routes/categories_update"
+	"trxd/api/routes/challenges_all_get"
+	"trxd/api/routes/challenges_create"
+	"trxd/api/routes/challenges_delete"
+	"trxd/api/routes/challenges_feedback_create"
+	"trxd/api/routes/challenges_feedback_delete"
+	"trxd/api/routes/challenges_get"
+	"trxd/api/routes/challenges_hidden"
+	"trxd/api/routes/challenges_owners_create"
+	"trxd/api/routes/challenges_owners_delete"
+	"trxd/api/routes/challenges_update"
+	"trxd/api/routes/configs_get"
+	"trxd/api/routes/configs_update"
+	"trxd/api/routes/email_templates_delete"
+	"trxd/api/routes/email_templates_get"
+	"trxd/api/routes/email_templates_update"
+	"trxd/api/routes/events_get"
+	"trxd/api/routes/flags_create"
+	"trxd/api/routes/flags_delete"
+	"trxd/api/routes/flags_update"
+	"trxd/api/routes/instances_create"
+	"trxd/api/routes/instances_delete"
+	"trxd/api/routes/instances_events_get"
+	"trxd/api/routes/instances_get"
+	"trxd/api/routes/instances_pow"
+	"trxd/api/routes/instances_update"
+	"trxd/api/routes/roles_create"
+	"trxd/api/routes/roles_delete"
+	"trxd/api/routes/roles_get"
+	"trxd/api/routes/roles_update"
+	"trxd/api/routes/submissions_create"
+	"trxd/api/routes/submissions_delete"
+	"trxd/api/routes/submissions_get"
+	"trxd/api/routes/teams_all_get"
+	"trxd/api/routes/teams_get"
+	"trxd/api/routes/teams_join"
+	"trxd/api/routes/teams_join_get"
+	"trxd/api/routes/teams_password"
+	"trxd/api/routes/teams_register"
+	"trxd/api/routes/teams_scoreboard"
+	"trxd/api/routes/teams_scoreboard_graph"
+	"trxd/api/routes/teams_search"
+	"trxd/api/routes/teams_update"
+	"trxd/api/routes/tickets_all_get"
+	"trxd/api/routes/tickets_attachments_get"
+	"trxd/api/routes/tickets_create"
+	"trxd/api/routes/tickets_get"
+	"trxd/api/routes/tickets_messages_create"
+	"trxd/api/routes/tickets_update"
+	"trxd/api/routes/users_all_get"
+	"trxd/api/routes/users_custom_role"
+	"trxd/api/routes/users_get"
+	"trxd/api/routes/users_info"
+	"trxd/api/routes/users_login"
+	"trxd/api/routes/users_logout"
+	"trxd/api/routes/users_password"
+	"trxd/api/routes/users_password_reset"
+	"trxd/api/routes/users_register"
+	"trxd/api/routes/users_role"
+	"trxd/api/routes/users_search"
+	"trxd/api/routes/users_update"
+	"trxd/api/routes/writeups_accepted"
+	"trxd/api/routes/writeups_create"
+	"trxd/api/routes/writeups_delete"
+	"trxd/api/routes/writeups_get"
+	"trxd/api/routes/writeups_update"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var idParam = []openapi.Param{openapi.Int("id", "")}
+
+var eventTypes = []string{
+	string(sqlc.InstanceEventTypeCreate),
+	string(sqlc.InstanceEventTypeExtend),
+	string(sqlc.InstanceEventTypeDelete),
+	string(sqlc.InstanceEventTypeExpire),
+	string(sqlc.InstanceEventTypeKill),
+	string(sqlc.InstanceEventTypeFailure),
+}
+
+// specMiddlewares names the middlewares, the permission guards as
+// can(permission) and teamOr(permission)
+func specMiddlewares() map[string]fiber.Handler {
+	middlewares := map[string]fiber.Handler{
+		"noAuth":    noAuth,
+		"spectator": spectator,
+		"player":    player,
+		"team":      team,
+		"start":     start,
+		"end":       end,
+
+		"audit(announcement)":   auditAnnouncement,
+		"audit(category)":       auditCategory,
+		"audit(challenge)":      auditChallenge,
+		"audit(config)":         auditConfig,
+		"audit(email-template)": auditEmailTemplate,
+		"audit(role)":           auditRole,
+		"audit(submission)":     auditSubmission,
+		"audit(user)":           auditUser,
+		"audit(writeup)":        auditWriteup,
+	}
+	for _, permission := range consts.Permissions {
+		middlewares["can("+permission+")"] = can(permission)
+		middlewares["teamOr("+permission+")"] = teamOr(permission)
+	}
+	return middlewares
+}
+
+// Every route under /api must be described here, TestOpenAPI fails otherwise
+var spec = openapi.Spec{
+	Title:       consts.Name,
+	Version:     "1.0",
+	Prefix:      "/api",
+	Middlewares: specMiddlewares(),
+	Operations: []openapi.Operation{
+		{Handler: users_register.Route, Summary: "Register a user", Request: users_register.Data{}},
+		{Handler: users_login.Route, Summary: "Log in", Request: users_login.Data{}},
+		{Handler: users_logout.Route, Summary: "Log out"},
+		{Handler: users_password_reset.Route, Summary: "Send a password reset link, or reset the password with its token", Request: users_password_reset.Data{}},
+		{Handler: users_info.Route, Summary: "Info on the platform and the logged in user", Response: users_info.Info{}},
+		{Handler: teams_scoreboard.Route, Summary: "Scoreboard", Query: openapi.Pagination, Response: teams_scoreboard.Response{}},
+		{Handler: teams_scoreboard_graph.Route, Summary: "Score history of the top teams", Response: []teams_scoreboard_graph.Top{}},
+		{Handler: announcements_get.Route, Summary: "List the published announcements, the scheduled ones too for their editors", Query: []openapi.Param{
+			openapi.String("since", "only the announcements published or edited after this RFC 3339 time"),
+		}, Response: []announcements_get.Announcement{}},
+		{Handler: events_get.Route, Summary: "Stream of the live events, e.g. the published announcements, as server-sent events", Response: openapi.Schema{}},
+
+		{Handler: users_update.Route, Summary: "Update the logged in user", Request: users_update.Data{}},
+		{Handler: users_role.Route, Summary: "Change the role of a user", Request: users_role.Data{}},
+		{Handler: users_custom_role.Route, Summary: "Grant a custom role to a user, or revoke it", Request: users_custom_role.Data{}},
+		{Handler: users_password.Route, Summary: "Reset the password of a user", Request: users_password.Data{}, Response: users_password.Response{}},
+		{Handler: users_all_get.Route, Summary: "List the users", Query: openapi.Pagination, Response: users_all_get.Response{}},
+		{Handler: users_search.Route, Summary: "Search a user by name or email (admins only)", Query: []openapi.Param{
+			openapi.String("name", ""),
+			openapi.String("email", ""),
+		}, Response: users_get.UserData{}},
+		{Handler: users_get.Route, Summary: "Get a user", Path: idParam, Response: users_get.UserData{}},
+
+		{Handler: teams_register.Route, Summary: "Register a team", Request: teams_register.Data{}},
+		{Handler: teams_join.Route, Summary: "Join a team with its credentials", Request: teams_join.Data{}},
+		{Handler: teams_join_get.Route, Summary: "Join a team with an invite token, or get the invite token of the own team", Query: []openapi.Param{
+			openapi.String("token", "invite token, the token of the own team is returned without it"),
+		}, Response: teams_join_get.Response{}},
+		{Handler: teams_update.Route, Summary: "Update the own team", Request: teams_update.Data{}},
+		{Handler: teams_password.Route, Summary: "Reset the password of a team", Request: teams_password.Data{}, Response: teams_password.Response{}},
+		{Handler: teams_all_get.Route, Summary: "List the teams", Query: openapi.Pagination, Response: teams_all_get.Response{}},
+		{Handler: teams_search.Route, Summary: "Search a team by name or email (admins only)", Query: []openapi.Param{
+			openapi.String("name", ""),
+			openapi.String("email", ""),
+		}, Response: teams_get.TeamData{}},
+		{Handler: teams_get.Route, Summary: "Get a team", Path: idParam, Response: teams_get.TeamData{}},
+
+		{Handler: categories_create.Route, Summary: "Create a category", Request: categories_create.Data{}},
+		{Handler: categories_update.Route, Summary: "Rename a category", Request: categories_update.Data{}},
+		{Handler: categories_delete.Route, Summary: "Delete a category", Request: categories_delete.Data{}},
+		{Handler: categories_get.Route, Summary: "List the categories", Response: []string{}},
+
+		{Handler: challenges_create.Route, Summary: "Create a challenge", Request: challenges_create.Data{}},
+		{Handler: challenges_update.Route, Summary: "Update a challenge", Request: challenges_update.UpdateChallParams{}},
+		{Handler: challenges_hidden.Route, Summary: "Hide or unveil challenges", Request: challenges_hidden.Data{}},
+		{Handler: challenges_delete.Route, Summary: "Delete a challenge", Request: challenges_delete.Data{}},
+		{Handler: challenges_all_get.Route, Summary: "List the challenges", Response: []challenges_all_get.Chall{}},
+		{Handler: challenges_get.Route, Summary: "Get a challenge", Path: idParam, Response: challenges_get.Chall{}},
+		{Handler: challenges_feedback_create.Route, Summary: "Rate a challenge solved by the own team", Request: challenges_feedback_create.Data{}},
+		{Handler: challenges_feedback_delete.Route, Summary: "Delete the own feedback on a challenge", Request: challenges_feedback_delete.Data{}},
+		{Handler: challenges_owners_create.Route, Summary: "Add an owner to a challenge or change its role", Request: challenges_owners_create.Data{}},
+		{Handler: challenges_owners_delete.Route, Summary: "Remove a co-owner from a challenge", Request: challenges_owners_delete.Data{}},
+
+		{Handler: author_dashboard.Route, Summary: "Stats of the challenges owned by the author", Response: author_dashboard.Response{}},
+
+		{Handler: instances_create.Route, Summary: "Start an instance", Request: instances_create.Data{}, Response: instances_create.InstanceInfo{}},
+		{Handler: instances_pow.Route, Summary: "Get the proof of work required to start an instance", Request: instances_pow.Data{}, Response: instances_pow.PowInfo{}},
+		{Handler: instances_update.Route, Summary: "Extend an instance", Request: instances_update.Data{}, Response: instances_update.Response{}},
+		{Handler: instances_delete.Route, Summary: "Stop an instance", Request: instances_delete.Data{}},
+		{Handler: instances_get.Route, Summary: "List the running instances", Response: []sqlc.GetInstancesRow{}},
+		{Handler: instances_events_get.Route, Summary: "History of the instances", Query: append([]openapi.Param{
+			openapi.Int("team_id", ""),
+			openapi.Int("chall_id", ""),
+			openapi.Enum("type", "", eventTypes),
+		}, openapi.Pagination...), Response: instances_events_get.Response{}},
+
+		{Handler: submissions_create.Route, Summary: "Submit a flag", Request: submissions_create.Data{}, Response: submissions_create.Response{}},
+		{Handler: submissions_get.Route, Summary: "List the submissions of the owned challenges, all of them for the admins", Query: append([]openapi.Param{
+			openapi.Bool("flagged", "only the instance flags of other teams"),
+		}, openapi.Pagination...), Response: submissions_get.Response{}},
+		{Handler: submissions_delete.Route, Summary: "Delete a submission", Request: submissions_delete.Data{}},
+
+		{Handler: attachments_create.Route, Summary: "Upload attachments to a challenge", Form: attachments_create.Data{}},
+		{Handler: attachments_delete.Route, Summary: "Delete an attachment", Request: attachments_delete.Data{}},
+
+		{Handler: flags_create.Route, Summary: "Add a flag to a challenge", Request: flags_create.Data{}},
+		{Handler: flags_update.Route, Summary: "Update a flag", Request: flags_update.Data{}},
+		{Handler: flags_delete.Route, Summary: "Delete a flag", Request: flags_delete.Data{}},
+
+		{Handler: writeups_create.Route, Summary: "Submit the writeup of the own team, or an official one", Request: writeups_create.Data{}, Response: writeups_create.Response{}},
+		{Handler: writeups_update.Route, Summary: "Publish or hide a writeup", Request: writeups_update.Data{}},
+		{Handler: writeups_accepted.Route, Summary: "Mark a writeup as accepted by the authors", Request: writeups_accepted.Data{}},
+		{Handler: writeups_delete.Route, Summary: "Delete a writeup", Request: writeups_delete.Data{}},
+		{Handler: writeups_get.Route, Summary: "List the writeups, visible after the end of the CTF or once published", Query: []openapi.Param{
+			openapi.Int("chall_id", ""),
+		}, Response: []writeups_get.Writeup{}},
+
+		{Handler: tickets_create.Route, Summary: "Open a ticket for the own team", Request: tickets_create.Data{}, Response: tickets_create.Response{}},
+		{Handler: tickets_messages_create.Route, Summary: "Reply to a ticket, with optional attachments", Form: tickets_messages_create.Data{}, Response: tickets_messages_create.Response{}},
+		{Handler: tickets_update.Route, Summary: "Close, reopen or mark a ticket as answered", Request: tickets_update.Data{}},
+		{Handler: tickets_all_get.Route, Summary: "List the tickets of the own team, or the ones about the own challenges", Query: []openapi.Param{
+			openapi.Enum("status", "", consts.TicketStatusesStr),
+		}, Response: []tickets_all_get.Ticket{}},
+		{Handler: tickets_get.Route, Summary: "Get a ticket with its messages", Path: idParam, Response: tickets_get.Ticket{}},
+		{Handler: tickets_attachments_get.Route, Summary: "Download an attachment of a ticket", Path: idParam},
+
+		{Handler: configs_get.Route, Summary: "List the configurations", Response: []sqlc.Config{}},
+		{Handler: configs_update.Route, Summary: "Update a configuration", Request: configs_update.Data{}},
+		{Handler: announcements_create.Route, Summary: "Create an announcement", Request: announcements_create.Data{}, Response: announcements_create.Response{}},
+		{Handler: announcements_update.Route, Summary: "Update an announcement", Request: announcements_update.Data{}},
+		{Handler: announcements_delete.Route, Summary: "Delete an announcement", Request: announcements_delete.Data{}},
+		{Handler: email_templates_get.Route, Summary: "List the email templates of every locale", Response: []email_templates_get.EmailTemplate{}},
+		{Handler: email_templates_update.Route, Summary: "Edit an email template", Request: email_templates_update.Data{}},
+		{Handler: email_templates_delete.Route, Summary: "Restore the default email template", Request: email_templates_delete.Data{}},
+
+		{Handler: roles_get.Route, Summary: "List the custom roles and the permissions they can bundle", Response: roles_get.Response{}},
+		{Handler: roles_create.Route, Summary: "Create a custom role", Request: roles_create.Data{}},
+		{Handler: roles_update.Route, Summary: "Update a custom role", Request: roles_update.Data{}},
+		{Handler: roles_delete.Route, Summary: "Delete a custom role", Request: roles_delete.Data{}},
+
+		{Handler: admin_stats.Route, Summary: "Platform statistics", Response: admin_stats.AdminStats{}},
+
+		{Handler: audit_get.Route, Summary: "Audit log of the mutations made by the staff", Query: append([]openapi.Param{
+			openapi.Int("actor_id", "only the mutations of this user"),
+			openapi.String("action", "only the mutations through this endpoint, e.g. PATCH /api/flags"),
+			openapi.String("target", "only the mutations of this target, e.g. challenge:1"),
+			openapi.String("since", "only the mutations made from this RFC 3339 time"),
+			openapi.String("until", "only the mutations made before this RFC 3339 time"),
+		}, openapi.Pagination...), Response: audit_get.Response{}},
+	},
+}
+
+func init() {
+	// Not in the literal, since OpenAPI reads spec
+	spec.Operations = append(spec.Operations, openapi.Operation{
+		Handler: OpenAPI, Summary: "OpenAPI document of the API", Response: openapi.Schema{},
+	})
+}
+
+func OpenAPI(c *fiber.Ctx) error {
+	document, missing := spec.Generate(c.App().GetRoutes(true))
+	if len(missing) != 0 {
+		log.Warn("Undocumented routes left out of the OpenAPI document:", "routes", missing)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(document)
+}
@@ -0,0 +1,255 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"trxd/utils"
+	"unsafe"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const Version = "3.1.0"
+
+type Param struct {
+	Name        string
+	Type        string // JSON type of the value
+	Enum        []string
+	Description string
+}
+
+func Int(name string, description string) Param {
+	return Param{Name: name, Type: "integer", Description: description}
+}
+
+func Bool(name string, description string) Param {
+	return Param{Name: name, Type: "boolean", Description: description}
+}
+
+func String(name string, description string) Param {
+	return Param{Name: name, Type: "string", Description: description}
+}
+
+func Enum(name string, description string, values []string) Param {
+	return Param{Name: name, Type: "string", Enum: values, Description: description}
+}
+
+// Pagination are the query parameters of the paginated routes
+var Pagination = []Param{
+	Int("offset", "number of entries to skip"),
+	Int("limit", "maximum number of entries to return (0 for all)"),
+}
+
+// Operation describes the handler of a route, the one registered last
+type Operation struct {
+	Handler  fiber.Handler
+	Summary  string
+	Path     []Param // Parameters of the path, strings if not listed
+	Query    []Param
+	Request  any // Body parsed as JSON
+	Form     any // Body parsed as a multipart form, the files being the other parts
+	Response any // JSON body of the successful responses, nil when only the status is sent
+}
+
+type Spec struct {
+	Title       string
+	Version     string
+	Prefix      string // Only the routes under it are described, relative to the server url
+	Operations  []Operation
+	Middlewares map[string]fiber.Handler // Listed as x-middlewares on the operations using them
+}
+
+// handlerID identifies a handler by the pointer to its funcval: a top-level
+// function has a single static one, while every closure gets its own, so the
+// same instance must be registered and documented
+func handlerID(handler fiber.Handler) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&handler))
+}
+
+func (p *Param) schema() Schema {
+	schema := Schema{"type": p.Type}
+	if len(p.Enum) != 0 {
+		schema["enum"] = p.Enum
+	}
+	return schema
+}
+
+func (p *Param) parameter(in string) Schema {
+	parameter := Schema{
+		"name":   p.Name,
+		"in":     in,
+		"schema": p.schema(),
+	}
+	if p.Description != "" {
+		parameter["description"] = p.Description
+	}
+	if in == "path" {
+		parameter["required"] = true
+	}
+	return parameter
+}
+
+// pathParams converts the fiber parameters of a path (:name) to the OpenAPI
+// templates ({name})
+func pathParams(path string, params []Param) (string, []Schema) {
+	var parameters []Schema
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		name, ok := strings.CutPrefix(segment, ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(name, "?")
+		segments[i] = "{" + name + "}"
+
+		param := String(name, "")
+		for _, p := range params {
+			if p.Name == name {
+				param = p
+			}
+		}
+		parameters = append(parameters, param.parameter("path"))
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+func (s *Spec) operation(route *fiber.Route, op *Operation) Schema {
+	operation := Schema{}
+	if op.Summary != "" {
+		operation["summary"] = op.Summary
+	}
+
+	var middlewares []string
+	for _, handler := range route.Handlers[:len(route.Handlers)-1] {
+		for name, middleware := range s.Middlewares {
+			if handlerID(middleware) == handlerID(handler) {
+				middlewares = append(middlewares, name)
+			}
+		}
+	}
+	if len(middlewares) != 0 {
+		operation["x-middlewares"] = middlewares
+	}
+
+	_, parameters := pathParams(route.Path, op.Path)
+	for _, param := range op.Query {
+		parameters = append(parameters, param.parameter("query"))
+	}
+	if len(parameters) != 0 {
+		operation["parameters"] = parameters
+	}
+
+	switch {
+	case op.Request != nil:
+		operation["requestBody"] = Schema{
+			"required": true,
+			"content": Schema{
+				fiber.MIMEApplicationJSON: Schema{"schema": schemaOf(reflect.TypeOf(op.Request), "", true)},
+			},
+		}
+	case op.Form != nil:
+		schema := structSchema(reflect.TypeOf(op.Form), "form", true)
+		schema["additionalProperties"] = Schema{"type": "string", "format": "binary"}
+		operation["requestBody"] = Schema{
+			"required": true,
+			"content": Schema{
+				fiber.MIMEMultipartForm: Schema{"schema": schema},
+			},
+		}
+	}
+
+	success := Schema{"description": http.StatusText(http.StatusOK)}
+	if op.Response != nil {
+		success["content"] = Schema{
+			fiber.MIMEApplicationJSON: Schema{"schema": schemaOf(reflect.TypeOf(op.Response), "", false)},
+		}
+	}
+	operation["responses"] = Schema{
+		"200":     success,
+		"default": Schema{"$ref": "#/components/responses/Error"},
+	}
+
+	return operation
+}
+
+// errorComponents describe the errors in both the formats of utils.Errors
+func errorComponents() Schema {
+	entry := schemaOf(reflect.TypeFor[utils.ErrorEntry](), "", false)
+
+	return Schema{
+		"schemas": Schema{
+			"ErrorEntry": entry,
+			"Error": Schema{
+				"oneOf": []Schema{
+					{
+						"type":       "object",
+						"properties": Schema{"error": Schema{"type": "string"}},
+						"required":   []string{"error"},
+					},
+					{
+						"type": "object",
+						"properties": Schema{"errors": Schema{
+							"type":  "array",
+							"items": Schema{"$ref": "#/components/schemas/ErrorEntry"},
+						}},
+						"required": []string{"errors"},
+					},
+				},
+			},
+		},
+		"responses": Schema{
+			"Error": Schema{
+				"description": "Error, structured when requested with the " + utils.HeaderErrorFormat + " header",
+				"content": Schema{
+					fiber.MIMEApplicationJSON: Schema{"schema": Schema{"$ref": "#/components/schemas/Error"}},
+				},
+			},
+		},
+	}
+}
+
+// Generate builds the OpenAPI document of the routes under the prefix,
+// returning also the ones not described by an operation, left out of it
+func (s *Spec) Generate(routes []fiber.Route) (Schema, []string) {
+	operations := make(map[uintptr]*Operation, len(s.Operations))
+	for i := range s.Operations {
+		operations[handlerID(s.Operations[i].Handler)] = &s.Operations[i]
+	}
+
+	paths := Schema{}
+	var missing []string
+	for _, route := range routes {
+		path, ok := strings.CutPrefix(route.Path, s.Prefix)
+		if !ok || route.Method == fiber.MethodHead || len(route.Handlers) == 0 {
+			continue
+		}
+
+		op, ok := operations[handlerID(route.Handlers[len(route.Handlers)-1])]
+		if !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+			continue
+		}
+
+		path, _ = pathParams(path, nil)
+		item, ok := paths[path].(Schema)
+		if !ok {
+			item = Schema{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = s.operation(&route, op)
+	}
+	slices.Sort(missing)
+
+	return Schema{
+		"openapi": Version,
+		"info": Schema{
+			"title":   s.Title,
+			"version": s.Version,
+		},
+		"servers":    []Schema{{"url": s.Prefix}},
+		"paths":      paths,
+		"components": errorComponents(),
+	}, missing
+}
@@ -0,0 +1,118 @@
+package openapi_test
+
+import (
+	"slices"
+	"testing"
+	"trxd/api/openapi"
+	"trxd/utils/test_utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID   *int32   `json:"id" validate:"required,id"`
+	Name string   `json:"name" validate:"max=8"`
+	Tags []string `json:"tags" validate:"omitempty,dive,oneof=a b"`
+}
+
+type Embedded struct {
+	Hidden bool `json:"hidden"`
+}
+
+type Response struct {
+	Total int64  `json:"total"`
+	Host  string `json:"host,omitempty"`
+	*Embedded
+}
+
+func handler(c *fiber.Ctx) error {
+	return nil
+}
+
+func undocumented(c *fiber.Ctx) error {
+	return nil
+}
+
+func middleware(c *fiber.Ctx) error {
+	return c.Next()
+}
+
+func guard(name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("guard", name)
+		return c.Next()
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	guardA, guardB := guard("a"), guard("b")
+	spec := openapi.Spec{
+		Title:       "test",
+		Version:     "1.0",
+		Prefix:      "/api",
+		Middlewares: map[string]fiber.Handler{"middleware": middleware, "guard-a": guardA, "guard-b": guardB},
+		Operations: []openapi.Operation{
+			{Handler: handler, Summary: "Test", Path: []openapi.Param{openapi.Int("id", "")}, Request: Data{}, Response: Response{}},
+		},
+	}
+
+	app := fiber.New()
+	app.Post("/api/tests/:id", middleware, guardB, handler)
+	app.Get("/other", undocumented)
+
+	document, missing := spec.Generate(app.GetRoutes(true))
+	if len(missing) != 0 {
+		t.Fatalf("Unexpected undocumented routes: %v", missing)
+	}
+
+	expected := openapi.Schema{
+		"post": openapi.Schema{
+			"summary":       "Test",
+			"x-middlewares": []string{"middleware", "guard-b"},
+			"parameters": []openapi.Schema{
+				{"name": "id", "in": "path", "required": true, "schema": openapi.Schema{"type": "integer"}},
+			},
+			"requestBody": openapi.Schema{
+				"required": true,
+				"content": openapi.Schema{
+					"application/json": openapi.Schema{"schema": openapi.Schema{
+						"type": "object",
+						"properties": openapi.Schema{
+							"id":   openapi.Schema{"type": []string{"integer", "null"}, "format": "int32", "minimum": 0, "maximum": 2147483647},
+							"name": openapi.Schema{"type": "string", "maxLength": 8},
+							"tags": openapi.Schema{"type": "array", "items": openapi.Schema{"type": "string", "enum": []string{"a", "b"}}},
+						},
+						"required": []string{"id"},
+					}},
+				},
+			},
+			"responses": openapi.Schema{
+				"200": openapi.Schema{
+					"description": "OK",
+					"content": openapi.Schema{
+						"application/json": openapi.Schema{"schema": openapi.Schema{
+							"type": "object",
+							"properties": openapi.Schema{
+								"total":  openapi.Schema{"type": "integer", "format": "int64"},
+								"host":   openapi.Schema{"type": "string"},
+								"hidden": openapi.Schema{"type": "boolean"},
+							},
+							"required": []string{"total"},
+						}},
+					},
+				},
+				"default": openapi.Schema{"$ref": "#/components/responses/Error"},
+			},
+		},
+	}
+	test_utils.Compare(t, expected, document["paths"].(openapi.Schema)["/tests/{id}"])
+
+	app.Get("/api/undocumented", undocumented)
+	document, missing = spec.Generate(app.GetRoutes(true))
+	if !slices.Equal(missing, []string{"GET /api/undocumented"}) {
+		t.Fatalf("Expected the undocumented route to be reported, got: %v", missing)
+	}
+	if _, ok := document["paths"].(openapi.Schema)["/undocumented"]; ok {
+		t.Fatalf("Expected the undocumented route to be left out of the document")
+	}
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/validator"
+)
+
+type Schema = map[string]any
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// schemaOf describes a Go type as encoded by encoding/json, with the
+// constraints of its validate rules. The fields of the request bodies are
+// required according to their rules, the ones of the responses unless
+// omitted when empty
+func schemaOf(t reflect.Type, tag string, request bool) Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(schemaOf(t.Elem(), tag, request))
+	}
+
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return Schema{}
+	}
+
+	var schema Schema
+	switch t.Kind() {
+	case reflect.Bool:
+		schema = Schema{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		schema = Schema{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64:
+		schema = Schema{"type": "integer", "format": "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		schema = Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		schema = Schema{"type": "number"}
+	case reflect.String:
+		schema = Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			schema = Schema{"type": "string", "format": "byte"}
+			break
+		}
+		schema = Schema{"type": "array", "items": schemaOf(t.Elem(), "", request)}
+	case reflect.Map:
+		schema = Schema{"type": "object", "additionalProperties": schemaOf(t.Elem(), "", request)}
+	case reflect.Struct:
+		schema = structSchema(t, "json", request)
+	default:
+		return Schema{}
+	}
+
+	applyRules(schema, validator.Rules(tag))
+	return schema
+}
+
+// structSchema describes the fields of a struct, named after their key tag,
+// with the embedded structs flattened like encoding/json does
+func structSchema(t reflect.Type, key string, request bool) Schema {
+	properties := Schema{}
+	required := []string{}
+
+	for field := range t.Fields() {
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			continue
+		}
+
+		rules := field.Tag.Get("validate")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := structSchema(embedded, key, request)
+				for name, property := range inner["properties"].(Schema) {
+					properties[name] = property
+				}
+				// The fields of a nil embedded pointer are omitted
+				if field.Type.Kind() != reflect.Pointer {
+					if names, ok := inner["required"].([]string); ok {
+						required = append(required, names...)
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaOf(field.Type, rules, request)
+		if request && strings.HasPrefix(rules, "required") ||
+			!request && !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) != 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// nullable allows null besides the type of a schema
+func nullable(schema Schema) Schema {
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []string{t, "null"}
+	}
+	return schema
+}
+
+// applyRules translates the validate rules into the keywords of the schema,
+// the ones after dive apply to the elements
+func applyRules(schema Schema, rules []string) {
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if items, ok := schema["items"].(Schema); ok {
+				applyRules(items, rules[i+1:])
+			} else if values, ok := schema["additionalProperties"].(Schema); ok {
+				applyRules(values, rules[i+1:])
+			}
+			return
+		case "min", "gte":
+			bound(schema, "minimum", "minLength", "minItems", param)
+		case "max", "lte":
+			bound(schema, "maximum", "maxLength", "maxItems", param)
+		case "len":
+			bound(schema, "minimum", "minLength", "minItems", param)
+			bound(schema, "maximum", "maxLength", "maxItems", param)
+		case "oneof":
+			schema["enum"] = strings.Fields(param)
+		case "email":
+			schema["format"] = "email"
+		case "url", "http_url":
+			schema["format"] = "uri"
+		case "required", "omitempty", "":
+		default:
+			// Custom validations are only named
+			schema["x-validate"] = append(asStrings(schema["x-validate"]), name)
+		}
+	}
+}
+
+func asStrings(value any) []string {
+	s, _ := value.([]string)
+	return s
+}
+
+// bound sets the keyword matching the type of the schema
+func bound(schema Schema, number string, length string, items string, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schemaType(schema) {
+	case "integer", "number":
+		schema[number] = value
+	case "string":
+		schema[length] = value
+	case "array":
+		schema[items] = value
+	}
+}
+
+func schemaType(schema Schema) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+	return ""
+}
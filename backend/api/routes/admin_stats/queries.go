@@ -2,15 +2,60 @@ package admin_stats
 
 import (
 	"context"
+	"time"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/instancer"
+	"trxd/instancer/services"
 )
 
-func GetAdminStats(ctx context.Context) (sqlc.GetAdminStatsRow, error) {
+type InstanceUsage struct {
+	sqlc.GetInstancesUsageRow
+	OverSince *time.Time `json:"over_since,omitempty"` // Since when the instance exceeds its thresholds
+}
+
+type AdminStats struct {
+	sqlc.GetAdminStatsRow
+	Nodes []*services.NodeCapacity `json:"nodes,omitempty"`
+	Usage []InstanceUsage          `json:"usage,omitempty"`
+}
+
+func getUsage(ctx context.Context) ([]InstanceUsage, error) {
+	rows, err := db.Sql.GetInstancesUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]InstanceUsage, len(rows))
+	for i, row := range rows {
+		usage[i].GetInstancesUsageRow = row
+		if row.OverSince.Valid {
+			usage[i].OverSince = &row.OverSince.Time
+		}
+	}
+
+	return usage, nil
+}
+
+func GetAdminStats(ctx context.Context) (*AdminStats, error) {
 	stats, err := db.Sql.GetAdminStats(ctx)
 	if err != nil {
-		return sqlc.GetAdminStatsRow{}, err
+		return nil, err
+	}
+
+	nodes, err := instancer.NodesCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := getUsage(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	return &AdminStats{
+		GetAdminStatsRow: stats,
+		Nodes:            nodes,
+		Usage:            usage,
+	}, nil
 }
@@ -7,3 +7,30 @@ SELECT
   (SELECT COUNT(*) FROM challenges WHERE hidden=FALSE) AS total_released_challenges,
   (SELECT COUNT(*) FROM submissions) AS total_submissions,
   (SELECT COUNT(*) FROM submissions WHERE status='Correct') AS total_correct_submissions;
+
+-- name: GetInstancesUsage :many
+-- Retrieves the resource usage of the running instances, flagged ones first
+SELECT
+  u.team_id,
+  t.name AS team_name,
+  u.chall_id,
+  c.name AS chall_name,
+  u.samples,
+  u.cpu,
+  u.cpu_avg,
+  u.cpu_max,
+  u.memory,
+  u.memory_avg,
+  u.memory_max,
+  u.pids,
+  u.pids_max,
+  u.network,
+  u.network_avg,
+  u.network_max,
+  u.over_since,
+  u.flagged,
+  u.updated_at
+FROM instance_usage u
+JOIN teams t ON u.team_id = t.id
+JOIN challenges c ON u.chall_id = c.id
+ORDER BY u.flagged DESC, u.cpu_avg DESC;
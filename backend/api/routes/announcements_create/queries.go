@@ -0,0 +1,33 @@
+package announcements_create
+
+import (
+	"context"
+	"database/sql"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func CreateAnnouncement(ctx context.Context, data *Data) (int32, error) {
+	params := sqlc.CreateAnnouncementParams{
+		Title:    data.Title,
+		Body:     data.Body,
+		Priority: data.Priority,
+		Email:    data.Email,
+	}
+	if params.Priority == "" {
+		params.Priority = sqlc.AnnouncementPriorityNormal
+	}
+	if data.ChallID != nil {
+		params.ChallID = sql.NullInt32{Int32: *data.ChallID, Valid: true}
+	}
+	if data.PublishAt != nil {
+		params.PublishAt = sql.NullTime{Time: data.PublishAt.UTC(), Valid: true}
+	}
+
+	id, err := db.Sql.CreateAnnouncement(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,12 @@
+-- name: CreateAnnouncement :one
+-- Insert a new announcement, published now unless scheduled
+INSERT INTO announcements (title, body, priority, chall_id, publish_at, email)
+  VALUES (
+    sqlc.arg('title'),
+    sqlc.arg('body'),
+    sqlc.arg('priority'),
+    sqlc.narg('chall_id'),
+    COALESCE(sqlc.narg('publish_at')::TIMESTAMP, CURRENT_TIMESTAMP),
+    sqlc.arg('email')
+  )
+  RETURNING id;
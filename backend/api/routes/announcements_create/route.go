@@ -0,0 +1,64 @@
+package announcements_create
+
+import (
+	"time"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/announcements"
+	"trxd/utils/consts"
+	"trxd/utils/email"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+type Data struct {
+	Title     string                    `json:"title" validate:"required,announcement_title"`
+	Body      string                    `json:"body" validate:"required,announcement_body"`
+	Priority  sqlc.AnnouncementPriority `json:"priority" validate:"omitempty,announcement_priority"`
+	ChallID   *int32                    `json:"chall_id" validate:"omitnil,id"`
+	PublishAt *time.Time                `json:"publish_at"` // Published right away if missing
+	Email     bool                      `json:"email"`      // Also emailed to all the users
+}
+
+type Response struct {
+	ID int32 `json:"id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+	if data.Email {
+		configured, err := email.Configured(c.Context())
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+		}
+		if !configured {
+			return utils.Error(c, fiber.StatusBadRequest, consts.EmailNotConfigured)
+		}
+	}
+
+	id, err := CreateAnnouncement(c.Context(), &data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == consts.PGForeignKeyViolation {
+				return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+			}
+		}
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingAnnouncement, err)
+	}
+
+	announcements.Wake()
+
+	return c.Status(fiber.StatusOK).JSON(Response{
+		ID: id,
+	})
+}
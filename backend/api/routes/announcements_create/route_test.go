@@ -0,0 +1,209 @@
+package announcements_create_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/announcements"
+	"trxd/utils/consts"
+	"trxd/utils/events"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"title": "Title"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"title": strings.Repeat("a", consts.MaxAnnouncementTitleLen+1), "body": "Body"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Title", consts.MaxAnnouncementTitleLen)),
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": strings.Repeat("a", consts.MaxAnnouncementBodyLen+1)},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Body", consts.MaxAnnouncementBodyLen)),
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": "Body", "priority": "Urgent"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Priority", strings.Join(consts.AnnouncementPrioritiesStr, " "))),
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": "Body", "chall_id": -1},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": "Body", "publish_at": "tomorrow"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": "Body", "chall_id": 99999},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:       JSON{"title": "Title", "body": "Body"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"title": "Title", "body": "Body", "email": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.EmailNotConfigured),
+	},
+	{
+		testBody:       JSON{"title": "Title", "body": "**Fixed** the remote", "priority": "High", "chall_id": ""},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"title": "Scheduled", "body": "Body", "publish_at": time.Now().Add(time.Hour).Format(time.RFC3339)},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["chall_id"]; ok && content == "" {
+				test.testBody.(JSON)["chall_id"] = chall.ID
+			}
+		}
+		session.Post("/announcements", test.testBody, test.expectedStatus)
+		if test.expectedStatus != http.StatusOK {
+			session.CheckResponse(test.expectedResponse)
+			continue
+		}
+		if _, ok := Json(session.Body())["id"]; !ok {
+			t.Fatal("Expected the id of the announcement")
+		}
+	}
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/announcements", JSON{"title": "Title", "body": "Body"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.Unauthorized))
+}
+
+func TestPublish(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "publisher", "publisher@test.test", "testpass", sqlc.UserRoleAdmin)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "publisher@test.test", "password": "testpass"}, http.StatusOK)
+
+	err := announcements.PublishDue(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to publish announcements: %v", err)
+	}
+
+	msgs, unsubscribe, err := events.Subscribe(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to subscribe to the events: %v", err)
+	}
+	defer unsubscribe()
+
+	session.Post("/announcements", JSON{"title": "Live", "body": "Body"}, http.StatusOK)
+	err = announcements.PublishDue(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to publish announcements: %v", err)
+	}
+
+	select {
+	case msg := <-msgs:
+		var event struct {
+			Type string
+			Data announcements.Event
+		}
+		err := json.Unmarshal([]byte(msg), &event)
+		if err != nil || event.Type != events.TypeAnnouncement || event.Data.Title != "Live" {
+			t.Fatalf("Unexpected event: %s %v", msg, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the announcement on the event stream")
+	}
+
+	// Published only once
+	err = announcements.PublishDue(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to publish announcements: %v", err)
+	}
+	select {
+	case msg := <-msgs:
+		t.Fatalf("Unexpected event: %s", msg)
+	case <-time.After(time.Second):
+	}
+
+	// Held back until the challenge is visible
+	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	session.Post("/announcements", JSON{"title": "Hidden", "body": "Body", "chall_id": chall.ID}, http.StatusOK)
+	err = announcements.PublishDue(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to publish announcements: %v", err)
+	}
+	select {
+	case msg := <-msgs:
+		t.Fatalf("Unexpected event about a hidden challenge: %s", msg)
+	case <-time.After(time.Second):
+	}
+
+	test_utils.UnveilChallenge(t, chall.ID)
+	err = announcements.PublishDue(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to publish announcements: %v", err)
+	}
+	select {
+	case msg := <-msgs:
+		var event struct {
+			Data announcements.Event
+		}
+		err := json.Unmarshal([]byte(msg), &event)
+		if err != nil || event.Data.Title != "Hidden" || event.Data.ChallName == nil || *event.Data.ChallName != "chall" {
+			t.Fatalf("Unexpected event: %s %v", msg, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the announcement once the challenge is visible")
+	}
+}
@@ -0,0 +1,21 @@
+package announcements_delete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+)
+
+// DeleteAnnouncement returns false if the announcement does not exist
+func DeleteAnnouncement(ctx context.Context, id int32) (bool, error) {
+	_, err := db.Sql.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,3 @@
+-- name: DeleteAnnouncement :one
+-- Delete an announcement
+DELETE FROM announcements WHERE id = $1 RETURNING id;
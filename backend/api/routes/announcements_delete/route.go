@@ -0,0 +1,35 @@
+package announcements_delete
+
+import (
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID *int32 `json:"id" validate:"required,id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	deleted, err := DeleteAnnouncement(c.Context(), *data.ID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingAnnouncement, err)
+	}
+	if !deleted {
+		return utils.Error(c, fiber.StatusNotFound, consts.AnnouncementNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,92 @@
+package announcements_delete_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": -1},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ID", 0)),
+	},
+	{
+		testBody:         JSON{"id": 99999},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.AnnouncementNotFound),
+	},
+	{
+		testBody:       JSON{"id": ""},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"id": ""},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.AnnouncementNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/announcements", JSON{"title": "Title", "body": "Body"}, http.StatusOK)
+	id := Json(session.Body())["id"]
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["id"]; ok && content == "" {
+				test.testBody.(JSON)["id"] = id
+			}
+		}
+		session.Delete("/announcements", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	session.Get("/announcements", nil, http.StatusOK)
+	if len(List(session.Body())) != 0 {
+		t.Fatal("Expected no announcements")
+	}
+}
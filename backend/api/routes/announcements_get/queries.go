@@ -0,0 +1,58 @@
+package announcements_get
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Announcement struct {
+	ID        int32                     `json:"id"`
+	Title     string                    `json:"title"`
+	Body      string                    `json:"body"`
+	Priority  sqlc.AnnouncementPriority `json:"priority"`
+	ChallID   *int32                    `json:"chall_id"`
+	ChallName *string                   `json:"chall_name"`
+	PublishAt time.Time                 `json:"publish_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+	Published *bool                     `json:"published,omitempty"` // Only for the admins
+}
+
+// GetAnnouncements returns the announcements published or edited after since,
+// the scheduled ones too if all is set
+func GetAnnouncements(ctx context.Context, since *time.Time, all bool) ([]Announcement, error) {
+	params := sqlc.GetAnnouncementsParams{All: all}
+	if since != nil {
+		params.Since = sql.NullTime{Time: since.UTC(), Valid: true}
+	}
+
+	rows, err := db.Sql.GetAnnouncements(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	announcements := make([]Announcement, len(rows))
+	for i, row := range rows {
+		announcements[i] = Announcement{
+			ID:        row.ID,
+			Title:     row.Title,
+			Body:      row.Body,
+			Priority:  row.Priority,
+			PublishAt: row.PublishAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+		if row.ChallID.Valid {
+			announcements[i].ChallID = &row.ChallID.Int32
+		}
+		if row.ChallName.Valid {
+			announcements[i].ChallName = &row.ChallName.String
+		}
+		if all {
+			announcements[i].Published = &row.Published
+		}
+	}
+
+	return announcements, nil
+}
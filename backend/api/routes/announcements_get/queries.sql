@@ -0,0 +1,18 @@
+-- name: GetAnnouncements :many
+-- Retrieve the announcements published or edited after a time, the scheduled
+-- ones and the ones about hidden challenges only if requested
+SELECT
+  a.id,
+  a.title,
+  a.body,
+  a.priority,
+  a.chall_id,
+  c.name AS chall_name,
+  a.publish_at,
+  a.updated_at,
+  a.published
+FROM announcements a
+LEFT JOIN challenges c ON c.id = a.chall_id
+WHERE (sqlc.arg('all')::BOOLEAN OR (a.publish_at <= CURRENT_TIMESTAMP AND c.hidden IS NOT TRUE))
+  AND (sqlc.narg('since')::TIMESTAMP IS NULL OR a.publish_at > sqlc.narg('since') OR a.updated_at > sqlc.narg('since'))
+ORDER BY a.publish_at DESC, a.id DESC;
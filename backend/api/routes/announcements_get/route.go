@@ -0,0 +1,28 @@
+package announcements_get
+
+import (
+	"time"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	var since *time.Time
+	if c.Query("since") != "" {
+		t, err := time.Parse(time.RFC3339, c.Query("since"))
+		if err != nil {
+			return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+		}
+		since = &t
+	}
+
+	// The editors also see the scheduled announcements
+	announcements, err := GetAnnouncements(c.Context(), since, utils.Can(c, consts.PermAnnouncementsWrite))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingAnnouncements, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(announcements)
+}
@@ -0,0 +1,89 @@
+package announcements_get_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func titles(t *testing.T, body any) []string {
+	var titles []string
+	for _, item := range List(body) {
+		titles = append(titles, Json(item)["title"].(string))
+	}
+	return titles
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	hidden := test_utils.CreateChallenge(t, "hidden", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	visible := test_utils.CreateChallenge(t, "visible", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, visible.ID)
+
+	before := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	admin.Post("/announcements", JSON{"title": "past", "body": "Body", "publish_at": before}, http.StatusOK)
+	admin.Post("/announcements", JSON{"title": "scheduled", "body": "Body", "publish_at": time.Now().Add(time.Hour).Format(time.RFC3339)}, http.StatusOK)
+	admin.Post("/announcements", JSON{"title": "hidden", "body": "Body", "chall_id": hidden.ID, "publish_at": before}, http.StatusOK)
+	admin.Post("/announcements", JSON{"title": "visible", "body": "Body", "chall_id": visible.ID, "publish_at": before}, http.StatusOK)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Get("/announcements", nil, http.StatusOK)
+	body := session.Body()
+	test_utils.Compare(t, []string{"visible", "past"}, titles(t, body))
+	announcement := Json(List(body)[0])
+	test_utils.Compare(t, JSON{
+		"body":       "Body",
+		"chall_id":   visible.ID,
+		"chall_name": "visible",
+		"priority":   sqlc.AnnouncementPriorityNormal,
+		"title":      "visible",
+	}, test_utils.DeleteKeys(announcement, "id", "publish_at", "updated_at"))
+
+	admin.Get("/announcements", nil, http.StatusOK)
+	body = admin.Body()
+	test_utils.Compare(t, []string{"scheduled", "visible", "hidden", "past"}, titles(t, body))
+	if _, ok := Json(List(body)[0])["published"]; !ok {
+		t.Fatal("Expected the publication state for the admins")
+	}
+
+	session.Get("/announcements?since="+url.QueryEscape(time.Now().Add(time.Minute).Format(time.RFC3339)), nil, http.StatusOK)
+	test_utils.Compare(t, []string(nil), titles(t, session.Body()))
+
+	since := time.Now().UTC().Format(time.RFC3339Nano)
+	time.Sleep(10 * time.Millisecond)
+	admin.Patch("/announcements", JSON{"id": Json(List(body)[3])["id"], "body": "Edited"}, http.StatusOK)
+	session.Get("/announcements?since="+url.QueryEscape(since), nil, http.StatusOK)
+	test_utils.Compare(t, []string{"past"}, titles(t, session.Body()))
+
+	session.Get("/announcements?since=yesterday", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidParam))
+}
@@ -0,0 +1,44 @@
+package announcements_update
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// UpdateAnnouncement returns false if the announcement does not exist
+func UpdateAnnouncement(ctx context.Context, data *Data) (bool, error) {
+	params := sqlc.UpdateAnnouncementParams{
+		ID: *data.ID,
+	}
+	if data.Title != nil {
+		params.Title = sql.NullString{String: *data.Title, Valid: true}
+	}
+	if data.Body != nil {
+		params.Body = sql.NullString{String: *data.Body, Valid: true}
+	}
+	if data.Priority != nil {
+		params.Priority = sqlc.NullAnnouncementPriority{AnnouncementPriority: *data.Priority, Valid: true}
+	}
+	if data.ChallID != nil {
+		params.ChallID = sql.NullInt32{Int32: *data.ChallID, Valid: true}
+	}
+	if data.PublishAt != nil {
+		params.PublishAt = sql.NullTime{Time: data.PublishAt.UTC(), Valid: true}
+	}
+	if data.Email != nil {
+		params.Email = sql.NullBool{Bool: *data.Email, Valid: true}
+	}
+
+	_, err := db.Sql.UpdateAnnouncement(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
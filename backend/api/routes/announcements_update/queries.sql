@@ -0,0 +1,13 @@
+-- name: UpdateAnnouncement :one
+-- Update the details of an announcement, a zero challenge ID removes the target
+UPDATE announcements
+SET
+  title = COALESCE(sqlc.narg('title'), title),
+  body = COALESCE(sqlc.narg('body'), body),
+  priority = COALESCE(sqlc.narg('priority'), priority),
+  chall_id = CASE WHEN sqlc.narg('chall_id')::INTEGER IS NULL THEN chall_id ELSE NULLIF(sqlc.narg('chall_id'), 0) END,
+  publish_at = COALESCE(sqlc.narg('publish_at'), publish_at),
+  email = COALESCE(sqlc.narg('email'), email),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING id;
@@ -0,0 +1,68 @@
+package announcements_update
+
+import (
+	"time"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/announcements"
+	"trxd/utils/consts"
+	"trxd/utils/email"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+type Data struct {
+	ID        *int32                     `json:"id" validate:"required,id"`
+	Title     *string                    `json:"title" validate:"omitempty,announcement_title"`
+	Body      *string                    `json:"body" validate:"omitempty,announcement_body"`
+	Priority  *sqlc.AnnouncementPriority `json:"priority" validate:"omitempty,announcement_priority"`
+	ChallID   *int32                     `json:"chall_id" validate:"omitnil,id"` // 0 removes the target challenge
+	PublishAt *time.Time                 `json:"publish_at"`
+	Email     *bool                      `json:"email"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+	if data.Title == nil && data.Body == nil && data.Priority == nil &&
+		data.ChallID == nil && data.PublishAt == nil && data.Email == nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.NoDataToUpdate)
+	}
+	if data.Email != nil && *data.Email {
+		configured, err := email.Configured(c.Context())
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+		}
+		if !configured {
+			return utils.Error(c, fiber.StatusBadRequest, consts.EmailNotConfigured)
+		}
+	}
+
+	updated, err := UpdateAnnouncement(c.Context(), &data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == consts.PGForeignKeyViolation {
+				return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+			}
+		}
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingAnnouncement, err)
+	}
+	if !updated {
+		return utils.Error(c, fiber.StatusNotFound, consts.AnnouncementNotFound)
+	}
+
+	if data.PublishAt != nil {
+		announcements.Wake()
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,121 @@
+package announcements_update_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"title": "Updated"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.NoDataToUpdate),
+	},
+	{
+		testBody:         JSON{"id": "", "priority": "Urgent"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Priority", strings.Join(consts.AnnouncementPrioritiesStr, " "))),
+	},
+	{
+		testBody:         JSON{"id": 99999, "title": "Updated"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.AnnouncementNotFound),
+	},
+	{
+		testBody:         JSON{"id": "", "chall_id": 99999},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:         JSON{"id": "", "email": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.EmailNotConfigured),
+	},
+	{
+		testBody:       JSON{"id": "", "email": false},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"id": "", "title": "Updated", "priority": "Low"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"id": "", "chall_id": ""},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"id": "", "chall_id": 0},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	session.Post("/announcements", JSON{"title": "Title", "body": "Body"}, http.StatusOK)
+	id := Json(session.Body())["id"]
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["id"]; ok && content == "" {
+				test.testBody.(JSON)["id"] = id
+			}
+			if content, ok := body["chall_id"]; ok && content == "" {
+				test.testBody.(JSON)["chall_id"] = chall.ID
+			}
+		}
+		session.Patch("/announcements", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	session.Get("/announcements", nil, http.StatusOK)
+	announcement := Json(List(session.Body())[0])
+	if announcement["title"] != "Updated" || announcement["priority"] != "Low" || announcement["chall_id"] != nil {
+		t.Fatalf("Announcement not updated: %v", announcement)
+	}
+}
@@ -2,18 +2,13 @@ package attachments_create
 
 import (
 	"fmt"
-	"mime/multipart"
-	"os"
-	"path/filepath"
-	"strings"
 	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
+	"trxd/utils/attachments"
 	"trxd/utils/consts"
-	"trxd/utils/crypto_utils"
 	"trxd/validator"
 
-	"trxd/utils/log"
-
 	"github.com/go-playground/form/v4"
 	"github.com/gofiber/fiber/v2"
 	"github.com/lib/pq"
@@ -23,89 +18,6 @@ type Data struct {
 	ChallID *int32 `form:"chall_id" validate:"required,id"`
 }
 
-func extractValidatedAttachments(c *fiber.Ctx, multipartForm *multipart.Form, data Data) ([]string, []*multipart.FileHeader, error) {
-	if len(multipartForm.File) == 0 {
-		return nil, nil, utils.Error(c, fiber.StatusBadRequest, consts.MissingRequiredFields)
-	}
-
-	valid, err := validator.Struct(c, data)
-	if err != nil || !valid {
-		return nil, nil, err
-	}
-
-	names := make([]string, 0)
-	headers := make([]*multipart.FileHeader, 0)
-	for _, files := range multipartForm.File {
-		for _, file := range files {
-			names = append(names, file.Filename)
-			headers = append(headers, file)
-		}
-	}
-
-	valid, err = validator.Var(c, names, "attachments")
-	if err != nil || !valid {
-		return nil, nil, err
-	}
-
-	return names, headers, nil
-}
-
-func saveFiles(c *fiber.Ctx, challID int32, headers []*multipart.FileHeader) ([]string, error) {
-	dir := fmt.Sprintf("attachments/%d/", challID)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		err := os.MkdirAll(dir, 0755)
-		if err != nil {
-			return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingAttachmentsDir, err)
-		}
-	}
-
-	hashes := make([]string, 0, len(headers))
-	for _, file := range headers {
-		cleanPath := filepath.Clean(dir + filepath.Base(file.Filename))
-		if !strings.HasPrefix(cleanPath, dir) {
-			return nil, utils.Error(c, fiber.StatusBadRequest, consts.InvalidFilePath)
-		}
-
-		f, err := file.Open()
-		if err != nil {
-			return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorHashingFile, err)
-		}
-		defer func() {
-			err := f.Close()
-			if err != nil {
-				log.Error("Failed to close file after hashing", "err", err)
-			}
-		}()
-
-		hash, err := crypto_utils.HashFile(f)
-		if err != nil {
-			return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorHashingFile, err)
-		}
-
-		hashes = append(hashes, hash)
-
-		hashedPath := dir + hash + "/"
-		if _, err := os.Stat(hashedPath); os.IsNotExist(err) {
-			err := os.MkdirAll(hashedPath, 0755)
-			if err != nil {
-				return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingAttachmentsDir, err)
-			}
-		}
-
-		cleanPath = filepath.Clean(hashedPath + filepath.Base(file.Filename))
-		if !strings.HasPrefix(cleanPath, hashedPath) {
-			return nil, utils.Error(c, fiber.StatusBadRequest, consts.InvalidFilePath)
-		}
-
-		err = c.SaveFile(file, cleanPath)
-		if err != nil {
-			return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSavingFile, err)
-		}
-	}
-
-	return hashes, nil
-}
-
 func Route(c *fiber.Ctx) error {
 	multipartForm, err := c.MultipartForm()
 	if err != nil {
@@ -118,7 +30,16 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidFormData)
 	}
 
-	names, headers, err := extractValidatedAttachments(c, multipartForm, data)
+	if len(multipartForm.File) == 0 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.MissingRequiredFields)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	names, headers, err := attachments.FromForm(c, multipartForm)
 	if err != nil || names == nil || headers == nil {
 		return err
 	}
@@ -131,7 +52,17 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
-	hashes, err := saveFiles(c, *data.ChallID, headers)
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
+	hashes, err := attachments.Save(c, fmt.Sprintf("attachments/%d/", *data.ChallID), headers)
 	if err != nil || hashes == nil {
 		return err
 	}
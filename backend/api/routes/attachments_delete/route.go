@@ -3,6 +3,8 @@ package attachments_delete
 import (
 	"fmt"
 	"os"
+	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -10,11 +12,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	ChallID *int32    `json:"chall_id" validate:"required,id"`
+	Names   *[]string `json:"names" validate:"required,attachments"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		ChallID *int32    `json:"chall_id" validate:"required,id"`
-		Names   *[]string `json:"names" validate:"required,attachments"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -38,6 +42,17 @@ func Route(c *fiber.Ctx) error {
 
 		hashes = append(hashes, hash)
 	}
+
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, challID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
 	for i, name := range *data.Names {
 		err = DeleteAttachment(c.Context(), challID, name)
 		if err != nil {
@@ -110,7 +110,7 @@ func TestRoute(t *testing.T) {
 	h2 := test_utils.HashFile(t, dir+"test2.txt")
 	h3 := test_utils.HashFile(t, dir+"test3.txt")
 
-	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
 
 	var challID int32
 	session := test_utils.NewApiTestSession(t, app)
@@ -120,6 +120,7 @@ func TestRoute(t *testing.T) {
 	chall := test_utils.TryCreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
 	if chall != nil {
 		challID = chall.ID
+		test_utils.AddChallengeOwner(t, challID, author.ID, sqlc.OwnerRoleOwner)
 	}
 
 	session = test_utils.NewApiTestSession(t, app)
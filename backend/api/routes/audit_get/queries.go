@@ -0,0 +1,86 @@
+package audit_get
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Filters struct {
+	ActorID int32
+	Action  string // Method and path of the endpoint, e.g. PATCH /api/flags
+	Target  string // e.g. challenge:1
+	Since   *time.Time
+	Until   *time.Time
+}
+
+type Entry struct {
+	ID        int32           `json:"id"`
+	ActorID   *int32          `json:"actor_id"` // null once the user is deleted
+	ActorName string          `json:"actor_name"`
+	IP        string          `json:"ip"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before"` // The changed fields, null if created
+	After     json.RawMessage `json:"after"`  // The changed fields, null if deleted
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func GetAuditEntries(ctx context.Context, filters *Filters, offset int32, limit int32) (int64, []Entry, error) {
+	actorID := sql.NullInt32{Int32: filters.ActorID, Valid: filters.ActorID != 0}
+	action := sql.NullString{String: filters.Action, Valid: filters.Action != ""}
+	target := sql.NullString{String: filters.Target, Valid: filters.Target != ""}
+
+	total, err := db.Sql.GetTotalAuditEntries(ctx, sqlc.GetTotalAuditEntriesParams{
+		ActorID: actorID,
+		Action:  action,
+		Target:  target,
+		Since:   nullTime(filters.Since),
+		Until:   nullTime(filters.Until),
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rows, err := db.Sql.GetAuditEntries(ctx, sqlc.GetAuditEntriesParams{
+		ActorID: actorID,
+		Action:  action,
+		Target:  target,
+		Since:   nullTime(filters.Since),
+		Until:   nullTime(filters.Until),
+		Offset:  offset,
+		Limit:   sql.NullInt32{Int32: limit, Valid: limit != 0},
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	entries := make([]Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = Entry{
+			ID:        row.ID,
+			ActorName: row.ActorName,
+			IP:        row.Ip,
+			Action:    row.Action,
+			Target:    row.Target,
+			Before:    row.Before,
+			After:     row.After,
+			Timestamp: row.Timestamp,
+		}
+		if row.ActorID.Valid {
+			entries[i].ActorID = &row.ActorID.Int32
+		}
+	}
+
+	return total, entries, nil
+}
@@ -0,0 +1,22 @@
+-- name: GetTotalAuditEntries :one
+-- Counts the audit entries matching the filters
+SELECT COUNT(*)
+  FROM audit_log
+  WHERE (sqlc.narg('actor_id')::INTEGER IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('action')::TEXT IS NULL OR action = sqlc.narg('action'))
+    AND (sqlc.narg('target')::TEXT IS NULL OR target = sqlc.narg('target'))
+    AND (sqlc.narg('since')::TIMESTAMP IS NULL OR timestamp >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::TIMESTAMP IS NULL OR timestamp < sqlc.narg('until'));
+
+-- name: GetAuditEntries :many
+-- Fetches the audit entries matching the filters, the latest first, with pagination
+SELECT *
+  FROM audit_log
+  WHERE (sqlc.narg('actor_id')::INTEGER IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('action')::TEXT IS NULL OR action = sqlc.narg('action'))
+    AND (sqlc.narg('target')::TEXT IS NULL OR target = sqlc.narg('target'))
+    AND (sqlc.narg('since')::TIMESTAMP IS NULL OR timestamp >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::TIMESTAMP IS NULL OR timestamp < sqlc.narg('until'))
+  ORDER BY id DESC
+  OFFSET sqlc.arg('offset')
+  LIMIT sqlc.narg('limit');
@@ -0,0 +1,71 @@
+package audit_get
+
+import (
+	"math"
+	"time"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Response struct {
+	Total   int64   `json:"total"`
+	Entries []Entry `json:"entries"`
+}
+
+func parseTime(c *fiber.Ctx, key string) (*time.Time, bool) {
+	if c.Query(key) == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, c.Query(key))
+	if err != nil {
+		return nil, false
+	}
+	t = t.UTC()
+	return &t, true
+}
+
+func Route(c *fiber.Ctx) error {
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 || offset > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 || limit > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	actorID := c.QueryInt("actor_id", 0)
+	if actorID < 0 || actorID > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	since, ok := parseTime(c, "since")
+	if !ok {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+	until, ok := parseTime(c, "until")
+	if !ok {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	filters := &Filters{
+		ActorID: int32(actorID),
+		Action:  c.Query("action"),
+		Target:  c.Query("target"),
+		Since:   since,
+		Until:   until,
+	}
+
+	total, entries, err := GetAuditEntries(c.Context(), filters, int32(offset), int32(limit))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingAuditLog, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Total:   total,
+		Entries: entries,
+	})
+}
@@ -0,0 +1,191 @@
+package audit_get_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func Int32(val any) int32 {
+	return int32(val.(float64))
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func flags(state any) []any {
+	var flags []any
+	for _, flag := range List(Json(state)["flags"]) {
+		flags = append(flags, Json(flag)["flag"])
+	}
+	return flags
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	test_utils.UpdateConfig(t, "chall-min-points", "100")
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.Get("/info", nil, http.StatusOK)
+	adminID := Int32(Json(admin.Body())["id"])
+
+	admin.Get("/challenges", nil, http.StatusOK)
+	var challID int32
+	for _, chall := range List(admin.Body()) {
+		if Json(chall)["name"] == "chall-1" {
+			challID = Int32(Json(chall)["id"])
+			break
+		}
+	}
+
+	player := test_utils.NewApiTestSession(t, app)
+	player.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	player.Get("/audit", nil, http.StatusForbidden)
+
+	// Only the successful mutations are recorded
+	player.Patch("/flags", JSON{"chall_id": challID, "flag": "flag{test-1}", "new_flag": "flag{player}"}, http.StatusForbidden)
+	admin.Patch("/flags", JSON{"chall_id": 99999, "flag": "flag{test-1}", "new_flag": "flag{missing}"}, http.StatusNotFound)
+	admin.Patch("/flags", JSON{"chall_id": challID, "flag": "flag{test-1}", "new_flag": "flag{changed}"}, http.StatusOK)
+	admin.Patch("/configs", JSON{"key": "chall-min-points", "value": "50"}, http.StatusOK)
+
+	admin.Get("/audit", nil, http.StatusOK)
+	body := Json(admin.Body())
+	if Int32(body["total"]) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %v", body["total"])
+	}
+	entries := List(body["entries"])
+
+	// The latest first, reduced to the changed fields
+	expected := JSON{
+		"actor_id":   adminID,
+		"actor_name": "e",
+		"action":     "PATCH /api/configs",
+		"target":     "config:chall-min-points",
+		"before":     JSON{"value": "100"},
+		"after":      JSON{"value": "50"},
+	}
+	test_utils.Compare(t, expected, test_utils.DeleteKeys(entries[0], "id", "ip", "timestamp"))
+
+	flagEntry := Json(entries[1])
+	test_utils.Compare(t, "PATCH /api/flags", flagEntry["action"])
+	test_utils.Compare(t, fmt.Sprintf("challenge:%d", challID), flagEntry["target"])
+	if !slices.Contains(flags(flagEntry["before"]), "flag{test-1}") || slices.Contains(flags(flagEntry["before"]), "flag{changed}") {
+		t.Errorf("Unexpected flags before the update: %v", flagEntry["before"])
+	}
+	if !slices.Contains(flags(flagEntry["after"]), "flag{changed}") || slices.Contains(flags(flagEntry["after"]), "flag{test-1}") {
+		t.Errorf("Unexpected flags after the update: %v", flagEntry["after"])
+	}
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	filters := []struct {
+		query string
+		total int32
+	}{
+		{"target=config:chall-min-points", 1},
+		{"action=" + url.QueryEscape("PATCH /api/flags"), 1},
+		{fmt.Sprintf("actor_id=%d", adminID), 2},
+		{fmt.Sprintf("actor_id=%d", adminID+1000), 0},
+		{"since=" + url.QueryEscape(future), 0},
+		{"until=" + url.QueryEscape(future), 2},
+		{"limit=1", 2},
+	}
+	for _, filter := range filters {
+		admin.Get("/audit?"+filter.query, nil, http.StatusOK)
+		body := Json(admin.Body())
+		if Int32(body["total"]) != filter.total {
+			t.Errorf("%s: expected %d entries, got %v", filter.query, filter.total, body["total"])
+		}
+	}
+	admin.Get("/audit?limit=1", nil, http.StatusOK)
+	if len(List(Json(admin.Body())["entries"])) != 1 {
+		t.Errorf("Expected the entries to be limited to 1")
+	}
+
+	admin.Get("/audit?actor_id=-1", nil, http.StatusBadRequest)
+	admin.CheckResponse(errorf(consts.InvalidParam))
+	admin.Get("/audit?since=yesterday", nil, http.StatusBadRequest)
+	admin.CheckResponse(errorf(consts.InvalidParam))
+}
+
+func TestTargets(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+
+	entry := func(target string) map[string]any {
+		admin.Get("/audit?target="+url.QueryEscape(target), nil, http.StatusOK)
+		entries := List(Json(admin.Body())["entries"])
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 audit entry for %s, got %d", target, len(entries))
+		}
+		return test_utils.DeleteKeys(entries[0], "id", "actor_id", "actor_name", "ip", "timestamp").(map[string]any)
+	}
+
+	// The secret configs are only marked as changed
+	admin.Patch("/configs", JSON{"key": "metrics-token", "value": "secret-token"}, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"action": "PATCH /api/configs",
+		"target": "config:metrics-token",
+		"before": JSON{"value": "[redacted]"},
+		"after":  JSON{"value": "[changed]"},
+	}, entry("config:metrics-token"))
+
+	// The renamed targets are recorded by their new name
+	admin.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermSubmissionsRead}}, http.StatusOK)
+	admin.Patch("/roles", JSON{"name": "support", "new_name": "helpdesk"}, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"action": "POST /api/roles",
+		"target": "role:support",
+		"before": nil,
+		"after":  JSON{"name": "support", "description": "", "permissions": []string{consts.PermSubmissionsRead}},
+	}, entry("role:support"))
+	test_utils.Compare(t, JSON{
+		"action": "PATCH /api/roles",
+		"target": "role:helpdesk",
+		"before": JSON{"name": "support"},
+		"after":  JSON{"name": "helpdesk"},
+	}, entry("role:helpdesk"))
+
+	admin.Post("/categories", JSON{"name": "audit-cat"}, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"action": "POST /api/categories",
+		"target": "category:audit-cat",
+		"before": nil,
+		"after":  JSON{"name": "audit-cat", "visible_challs": 0},
+	}, entry("category:audit-cat"))
+
+	// The created announcements are found by the id in the response
+	admin.Post("/announcements", JSON{"title": "Audited", "body": "test"}, http.StatusOK)
+	id := Int32(Json(admin.Body())["id"])
+	if action := entry(fmt.Sprintf("announcement:%d", id))["action"]; action != "POST /api/announcements" {
+		t.Errorf("Unexpected action for the announcement: %v", action)
+	}
+}
@@ -0,0 +1,60 @@
+package author_dashboard
+
+import (
+	"context"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Challenge struct {
+	ID               int32           `json:"id"`
+	Name             string          `json:"name"`
+	Category         string          `json:"category"`
+	Hidden           bool            `json:"hidden"`
+	Points           int32           `json:"points"`
+	Role             *sqlc.OwnerRole `json:"role"` // Null for the admins on challenges they do not own
+	Solves           int32           `json:"solves"`
+	WrongSubmissions int64           `json:"wrong_submissions"`
+	Teams            int64           `json:"teams"` // Teams that submitted at least once
+	FirstSolve       *time.Time      `json:"first_solve"`
+	LastSolve        *time.Time      `json:"last_solve"`
+}
+
+// GetDashboard returns the stats of the challenges owned by the user, or of
+// all of them
+func GetDashboard(ctx context.Context, uid int32, all bool) ([]Challenge, error) {
+	rows, err := db.Sql.GetAuthorDashboard(ctx, sqlc.GetAuthorDashboardParams{
+		UserID: uid,
+		All:    all,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	challenges := make([]Challenge, 0, len(rows))
+	for _, row := range rows {
+		challenge := Challenge{
+			ID:               row.ID,
+			Name:             row.Name,
+			Category:         row.Category,
+			Hidden:           row.Hidden,
+			Points:           row.Points,
+			Solves:           row.Solves,
+			WrongSubmissions: row.WrongSubmissions,
+			Teams:            row.Teams,
+		}
+		if row.Role.Valid {
+			challenge.Role = &row.Role.OwnerRole
+		}
+		if row.FirstSolve.Valid {
+			challenge.FirstSolve = &row.FirstSolve.Time
+		}
+		if row.LastSolve.Valid {
+			challenge.LastSolve = &row.LastSolve.Time
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, nil
+}
@@ -0,0 +1,13 @@
+-- name: GetAuthorDashboard :many
+-- Retrieve the solves and the wrong submissions of the players on the challenges of an owner, or on all of them
+SELECT c.id, c.name, c.category, c.hidden, c.points, c.solves, o.role,
+    COUNT(s.id) FILTER (WHERE s.status = 'Wrong') AS wrong_submissions,
+    COUNT(DISTINCT u.team_id) AS teams,
+    MIN(s.timestamp) FILTER (WHERE s.status = 'Correct') AS first_solve,
+    MAX(s.timestamp) FILTER (WHERE s.status = 'Correct') AS last_solve
+  FROM challenges c
+  LEFT JOIN challenge_owners o ON o.chall_id = c.id AND o.user_id = sqlc.arg('user_id')
+  LEFT JOIN (submissions s JOIN users u ON u.id = s.user_id AND u.role = 'Player') ON s.chall_id = c.id
+  WHERE sqlc.arg('all')::BOOLEAN OR o.user_id IS NOT NULL
+  GROUP BY c.id, o.role
+  ORDER BY c.category ASC, c.name ASC;
@@ -0,0 +1,36 @@
+package author_dashboard
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Response struct {
+	Solves           int64       `json:"solves"`
+	WrongSubmissions int64       `json:"wrong_submissions"`
+	Challenges       []Challenge `json:"challenges"`
+}
+
+func Route(c *fiber.Ctx) error {
+	uid := c.Locals("uid").(int32)
+
+	// The authors see the stats of the challenges they own, the others of every one
+	all := c.Locals("role").(sqlc.UserRole) != sqlc.UserRoleAuthor
+	challenges, err := GetDashboard(c.Context(), uid, all)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenges, err)
+	}
+
+	res := Response{
+		Challenges: challenges,
+	}
+	for _, challenge := range challenges {
+		res.Solves += int64(challenge.Solves)
+		res.WrongSubmissions += challenge.WrongSubmissions
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,92 @@
+package author_dashboard_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func Int32(val any) int32 {
+	return int32(val.(float64))
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	session.Get("/dashboard", nil, http.StatusOK)
+	session.CheckResponse(JSON{
+		"challenges":        []JSON{},
+		"solves":            0,
+		"wrong_submissions": 0,
+	})
+
+	session.Get("/challenges", nil, http.StatusOK)
+	var challIDs []int32
+	for _, chall := range List(session.Body()) {
+		if Json(chall)["name"] == "chall-1" || Json(chall)["name"] == "chall-2" {
+			challIDs = append(challIDs, Int32(Json(chall)["id"]))
+		}
+	}
+	for _, id := range challIDs {
+		test_utils.AddChallengeOwner(t, id, author.ID, sqlc.OwnerRoleCoOwner)
+	}
+
+	session.Get("/dashboard", nil, http.StatusOK)
+	body := session.Body()
+	challenges := List(Json(body)["challenges"])
+	if len(challenges) != 2 {
+		t.Fatalf("Expected 2 challenges, got %d", len(challenges))
+	}
+	test_utils.Compare(t, JSON{
+		"category":          "cat-2",
+		"hidden":            false,
+		"name":              "chall-2",
+		"points":            500,
+		"role":              "CoOwner",
+		"solves":            1,
+		"teams":             1,
+		"wrong_submissions": 0,
+	}, test_utils.DeleteKeys(challenges[1], "id", "first_solve", "last_solve"))
+	if Json(challenges[1])["first_solve"] == nil || Json(challenges[1])["last_solve"] == nil {
+		t.Fatal("Expected the solve times of chall-2")
+	}
+	if Json(challenges[0])["wrong_submissions"].(float64) < 1 {
+		t.Fatal("Expected the wrong submissions of chall-1")
+	}
+
+	// The admins see every challenge
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.Get("/dashboard", nil, http.StatusOK)
+	body = admin.Body()
+	challenges = List(Json(body)["challenges"])
+	if len(challenges) != 5 {
+		t.Fatalf("Expected 5 challenges, got %d", len(challenges))
+	}
+	for _, chall := range challenges {
+		if Json(chall)["role"] != nil {
+			t.Fatalf("Expected no role for the admin, got %v", Json(chall)["role"])
+		}
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	Name string `json:"name" validate:"required,category_name"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		Name string `json:"name" validate:"required,category_name"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
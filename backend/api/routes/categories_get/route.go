@@ -1,7 +1,6 @@
 package categories_get
 
 import (
-	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 
@@ -9,9 +8,7 @@ import (
 )
 
 func Route(c *fiber.Ctx) error {
-	role := c.Locals("role").(sqlc.UserRole)
-
-	all := utils.In(role, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})
+	all := utils.Can(c, consts.PermChallengesRead)
 	categories, err := GetCategories(c.Context(), all)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingCategories, err)
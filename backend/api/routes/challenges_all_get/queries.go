@@ -5,30 +5,32 @@ import (
 	"time"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/proxy"
 )
 
 type Chall struct {
-	ID           int32          `json:"id"`
-	Name         string         `json:"name"`
-	Category     string         `json:"category"`
-	Description  string         `json:"description"`
-	Authors      []string       `json:"authors"`
-	Instance     bool           `json:"instance"`
-	Hidden       bool           `json:"hidden"`
-	Points       int            `json:"points"`
-	Solves       int            `json:"solves"`
-	Solved       bool           `json:"solved"`
-	FirstBlood   bool           `json:"first_blood"`
-	Attachments  []string       `json:"attachments"`
-	Tags         []string       `json:"tags"`
-	Host         string         `json:"host"`
-	Port         int            `json:"port"`
-	ConnType     sqlc.ConnType  `json:"conn_type"`
-	MaxPoints    int            `json:"max_points"`
-	ScoreType    sqlc.ScoreType `json:"score_type"`
-	Timeout      int            `json:"timeout"`
-	InstanceHost string         `json:"instance_host,omitempty"`
-	InstancePort int            `json:"instance_port,omitempty"`
+	ID            int32          `json:"id"`
+	Name          string         `json:"name"`
+	Category      string         `json:"category"`
+	Description   string         `json:"description"`
+	Authors       []string       `json:"authors"`
+	Instance      bool           `json:"instance"`
+	Hidden        bool           `json:"hidden"`
+	Points        int            `json:"points"`
+	Solves        int            `json:"solves"`
+	Solved        bool           `json:"solved"`
+	FirstBlood    bool           `json:"first_blood"`
+	Attachments   []string       `json:"attachments"`
+	Tags          []string       `json:"tags"`
+	Host          string         `json:"host"`
+	Port          int            `json:"port"`
+	ConnType      sqlc.ConnType  `json:"conn_type"`
+	MaxPoints     int            `json:"max_points"`
+	ScoreType     sqlc.ScoreType `json:"score_type"`
+	Timeout       int            `json:"timeout"`
+	InstanceHost  string         `json:"instance_host,omitempty"`
+	InstancePort  int            `json:"instance_port,omitempty"`
+	InstanceToken string         `json:"instance_token,omitempty"`
 }
 
 func GetChallenges(ctx context.Context, uid int32, tid int32, author bool) ([]Chall, error) {
@@ -49,7 +51,7 @@ func GetChallenges(ctx context.Context, uid int32, tid int32, author bool) ([]Ch
 			Category:    challenge.Category,
 			Description: challenge.Description,
 			Authors:     challenge.Authors,
-			Instance:    challenge.Type != sqlc.DeployTypeNormal,
+			Instance:    challenge.Type != sqlc.DeployTypeNormal && !challenge.Shared,
 			Hidden:      challenge.Hidden,
 			Points:      int(challenge.Points),
 			Solves:      int(challenge.Solves),
@@ -71,6 +73,10 @@ func GetChallenges(ctx context.Context, uid int32, tid int32, author bool) ([]Ch
 		if challenge.Tags != nil {
 			chall.Tags = challenge.Tags
 		}
+		// Shared deployments without a host are reachable at the global domain
+		if chall.Host == "" && challenge.Shared && challenge.DeploymentHost.Valid {
+			chall.Host = challenge.DeploymentHost.String
+		}
 
 		if challenge.ExpiresAt.Valid {
 			chall.Timeout = int(time.Until(challenge.ExpiresAt.Time).Seconds())
@@ -84,6 +90,12 @@ func GetChallenges(ctx context.Context, uid int32, tid int32, author bool) ([]Ch
 			}
 			if challenge.InstancePort.Valid {
 				chall.InstancePort = int(challenge.InstancePort.Int32)
+			} else {
+				chall.InstanceToken, err = proxy.AccessToken(ctx, chall.InstanceHost, nil, tid,
+					challenge.InstanceTeamOnly.Bool, challenge.ExpiresAt.Time)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 
@@ -10,8 +10,15 @@ SELECT
     i.expires_at,
     i.host AS instance_host,
     i.port AS instance_port,
-    i.docker_id
+    i.docker_id,
+    i.team_only AS instance_team_only,
+    COALESCE(d.shared, FALSE) AS shared,
+    dep.host AS deployment_host
   FROM challenges c
+  LEFT JOIN docker_configs d
+    ON d.chall_id = c.id
+  LEFT JOIN deployments dep
+    ON dep.chall_id = c.id
   LEFT JOIN attachments a
     ON a.chall_id = c.id
   LEFT JOIN (
@@ -25,5 +32,5 @@ SELECT
   LEFT JOIN instances i
     ON i.chall_id = c.id
       AND i.team_id = (SELECT team_id FROM tid)
-  GROUP BY c.id, s.first_blood, i.expires_at, i.host, i.port, i.docker_id 
+  GROUP BY c.id, s.first_blood, i.expires_at, i.host, i.port, i.docker_id, i.team_only, d.shared, dep.host
   ORDER BY c.points ASC, c.id ASC;
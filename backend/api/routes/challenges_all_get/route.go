@@ -1,7 +1,6 @@
 package challenges_all_get
 
 import (
-	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 
@@ -11,9 +10,8 @@ import (
 func Route(c *fiber.Ctx) error {
 	uid := c.Locals("uid").(int32)
 	tid := c.Locals("tid").(int32)
-	role := c.Locals("role").(sqlc.UserRole)
 
-	all := utils.In(role, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})
+	all := utils.Can(c, consts.PermChallengesRead)
 	challenges, err := GetChallenges(c.Context(), uid, tid, all)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenges, err)
@@ -2,6 +2,7 @@ package challenges_create
 
 import (
 	"context"
+	"fmt"
 	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/utils/consts"
@@ -9,9 +10,17 @@ import (
 	"github.com/lib/pq"
 )
 
-func CreateChallenge(ctx context.Context, name, category, description string,
+// CreateChallenge creates the challenge, owned by the user if not -1
+func CreateChallenge(ctx context.Context, owner int32, name, category, description string,
 	challType sqlc.DeployType, maxPoints int32, scoreType sqlc.ScoreType) (*sqlc.Challenge, error) {
-	id, err := db.Sql.CreateChallenge(ctx, sqlc.CreateChallengeParams{
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer db.Rollback(tx)
+	queries := db.Sql.WithTx(tx)
+
+	id, err := queries.CreateChallenge(ctx, sqlc.CreateChallengeParams{
 		Name:        name,
 		Category:    category,
 		Description: description,
@@ -28,6 +37,22 @@ func CreateChallenge(ctx context.Context, name, category, description string,
 		return nil, err
 	}
 
+	if owner != -1 {
+		err = queries.SetChallengeOwner(ctx, sqlc.SetChallengeOwnerParams{
+			ChallID: id,
+			UserID:  owner,
+			Role:    sqlc.OwnerRoleOwner,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &sqlc.Challenge{
 		ID:          id,
 		Name:        name,
@@ -10,15 +10,17 @@ import (
 	"github.com/lib/pq"
 )
 
+type Data struct {
+	Name        string          `json:"name" validate:"required,challenge_name"`
+	Category    string          `json:"category" validate:"required,category_name"`
+	Description string          `json:"description" validate:"challenge_description"`
+	Type        sqlc.DeployType `json:"type" validate:"required,challenge_type"`
+	MaxPoints   int32           `json:"max_points" validate:"required,challenge_max_points"`
+	ScoreType   sqlc.ScoreType  `json:"score_type" validate:"required,challenge_score_type"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		Name        string          `json:"name" validate:"required,challenge_name"`
-		Category    string          `json:"category" validate:"required,category_name"`
-		Description string          `json:"description" validate:"challenge_description"`
-		Type        sqlc.DeployType `json:"type" validate:"required,challenge_type"`
-		MaxPoints   int32           `json:"max_points" validate:"required,challenge_max_points"`
-		ScoreType   sqlc.ScoreType  `json:"score_type" validate:"required,challenge_score_type"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -28,7 +30,10 @@ func Route(c *fiber.Ctx) error {
 		return err
 	}
 
-	challenge, err := CreateChallenge(c.Context(), data.Name, data.Category, data.Description, data.Type, data.MaxPoints, data.ScoreType)
+	// The author creating the challenge owns it
+	uid := c.Locals("uid").(int32)
+
+	challenge, err := CreateChallenge(c.Context(), uid, data.Name, data.Category, data.Description, data.Type, data.MaxPoints, data.ScoreType)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == consts.PGForeignKeyViolation {
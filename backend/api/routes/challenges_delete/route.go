@@ -1,6 +1,8 @@
 package challenges_delete
 
 import (
+	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -8,10 +10,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -21,6 +25,16 @@ func Route(c *fiber.Ctx) error {
 		return err
 	}
 
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
 	err = DeleteChallenge(c.Context(), *data.ChallID)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingChallenge, err)
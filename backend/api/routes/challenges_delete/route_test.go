@@ -41,8 +41,9 @@ var testData = []struct {
 		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
 	},
 	{
-		testBody:       JSON{"chall_id": 99999},
-		expectedStatus: http.StatusOK,
+		testBody:         JSON{"chall_id": 99999},
+		expectedStatus:   http.StatusForbidden,
+		expectedResponse: errorf(consts.NotChallengeOwner),
 	},
 	{
 		testBody:         JSON{"chall_id": math.MaxInt32 + 1},
@@ -63,7 +64,7 @@ func TestRoute(t *testing.T) {
 	app := api.SetupApp(t.Context())
 	defer api.Shutdown(app)
 
-	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
 
 	var challID int32
 	for _, test := range testData {
@@ -74,6 +75,7 @@ func TestRoute(t *testing.T) {
 		chall := test_utils.TryCreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
 		if chall != nil {
 			challID = chall.ID
+			test_utils.AddChallengeOwner(t, challID, author.ID, sqlc.OwnerRoleOwner)
 		}
 
 		if body, ok := test.testBody.(JSON); ok && body != nil {
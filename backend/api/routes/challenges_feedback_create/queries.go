@@ -0,0 +1,38 @@
+package challenges_feedback_create
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// SubmitFeedback replaces the feedback of the user, returns false if their
+// team has not solved the challenge
+func SubmitFeedback(ctx context.Context, uid int32, tid int32, data *Data) (bool, error) {
+	params := sqlc.SubmitFeedbackParams{
+		UserID:  uid,
+		ChallID: *data.ChallID,
+		TeamID:  tid,
+	}
+	if data.Vote != nil {
+		params.Vote = sqlc.NullFeedbackVote{FeedbackVote: *data.Vote, Valid: true}
+	}
+	if data.Difficulty != nil {
+		params.Difficulty = sql.NullInt32{Int32: *data.Difficulty, Valid: true}
+	}
+	if data.Comment != nil && *data.Comment != "" {
+		params.Comment = sql.NullString{String: *data.Comment, Valid: true}
+	}
+
+	_, err := db.Sql.SubmitFeedback(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,21 @@
+-- name: SubmitFeedback :one
+-- Insert or update the feedback of a user, keeping the fields not given, only if their team solved the challenge
+INSERT INTO challenge_feedbacks (user_id, chall_id, vote, difficulty, comment)
+  SELECT sqlc.arg('user_id')::INTEGER, sqlc.arg('chall_id')::INTEGER,
+    sqlc.narg('vote')::feedback_vote, sqlc.narg('difficulty')::INTEGER, sqlc.narg('comment')::TEXT
+  WHERE EXISTS (
+    SELECT 1
+      FROM submissions
+      JOIN users ON users.id = submissions.user_id
+      JOIN teams ON users.team_id = teams.id
+      WHERE users.role = 'Player'
+        AND submissions.chall_id = sqlc.arg('chall_id')
+        AND submissions.status = 'Correct'
+        AND teams.id = sqlc.arg('team_id')
+  )
+  ON CONFLICT (user_id, chall_id) DO UPDATE
+    SET vote = COALESCE(EXCLUDED.vote, challenge_feedbacks.vote),
+      difficulty = COALESCE(EXCLUDED.difficulty, challenge_feedbacks.difficulty),
+      comment = COALESCE(EXCLUDED.comment, challenge_feedbacks.comment),
+      updated_at = CURRENT_TIMESTAMP
+  RETURNING chall_id;
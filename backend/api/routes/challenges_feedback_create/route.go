@@ -0,0 +1,55 @@
+package challenges_feedback_create
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID    *int32             `json:"chall_id" validate:"required,id"`
+	Vote       *sqlc.FeedbackVote `json:"vote" validate:"omitnil,feedback_vote"`
+	Difficulty *int32             `json:"difficulty" validate:"omitnil,feedback_difficulty"`
+	Comment    *string            `json:"comment" validate:"omitnil,feedback_comment"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	if data.Vote == nil && data.Difficulty == nil && data.Comment == nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.MissingRequiredFields)
+	}
+
+	challenge, err := db.GetChallengeByID(c.Context(), *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if challenge == nil || challenge.Hidden {
+		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+
+	submitted, err := SubmitFeedback(c.Context(), uid, tid, &data)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSubmittingFeedback, err)
+	}
+	if !submitted {
+		return utils.Error(c, fiber.StatusForbidden, consts.ChallengeNotSolved)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,176 @@
+package challenges_feedback_create_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+	solver           bool
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"vote": "Like"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"chall_id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"chall_id": -1, "vote": "Like"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "vote": "Love"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Vote", strings.Join(consts.FeedbackVotesStr, " "))),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "difficulty": consts.MinFeedbackDifficulty - 1},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "Difficulty", consts.MinFeedbackDifficulty)),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "difficulty": consts.MaxFeedbackDifficulty + 1},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Difficulty", consts.MaxFeedbackDifficulty)),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "comment": strings.Repeat("a", consts.MaxFeedbackCommentLen+1)},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Comment", consts.MaxFeedbackCommentLen)),
+	},
+	{
+		testBody:         JSON{"chall_id": 99999, "vote": "Like"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "vote": "Like"},
+		expectedStatus:   http.StatusForbidden,
+		expectedResponse: errorf(consts.ChallengeNotSolved),
+	},
+	{
+		testBody:       JSON{"chall_id": "", "vote": "Dislike", "difficulty": 2},
+		expectedStatus: http.StatusOK,
+		solver:         true,
+	},
+	{
+		testBody:       JSON{"chall_id": "", "vote": "Like", "difficulty": 4, "comment": "Nice one"},
+		expectedStatus: http.StatusOK,
+		solver:         true,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	admin.Post("/flags", JSON{"chall_id": chall.ID, "flag": "flag{test}", "regex": false}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "solver", "solver@test.test", "testpass", sqlc.UserRolePlayer)
+	solver := test_utils.NewApiTestSession(t, app)
+	solver.Post("/login", JSON{"email": "solver@test.test", "password": "testpass"}, http.StatusOK)
+	solver.Post("/teams/register", JSON{"name": "solvers", "password": "teampasswd"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "mate", "mate@test.test", "testpass", sqlc.UserRolePlayer)
+	mate := test_utils.NewApiTestSession(t, app)
+	mate.Post("/login", JSON{"email": "mate@test.test", "password": "testpass"}, http.StatusOK)
+	mate.Post("/teams/join", JSON{"name": "solvers", "password": "teampasswd"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "other", "other@test.test", "testpass", sqlc.UserRolePlayer)
+	other := test_utils.NewApiTestSession(t, app)
+	other.Post("/login", JSON{"email": "other@test.test", "password": "testpass"}, http.StatusOK)
+	other.Post("/teams/register", JSON{"name": "others", "password": "teampasswd"}, http.StatusOK)
+	other.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{wrong}"}, http.StatusOK)
+
+	solver.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{test}"}, http.StatusOK)
+
+	for _, test := range testData {
+		session := other
+		if test.solver {
+			session = mate
+		}
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["chall_id"]; ok && content == "" {
+				test.testBody.(JSON)["chall_id"] = chall.ID
+			}
+		}
+		session.Post("/challenges/feedback", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// The fields not given keep their previous values
+	mate.Post("/challenges/feedback", JSON{"chall_id": chall.ID, "comment": "Nicer one"}, http.StatusOK)
+	solver.Post("/challenges/feedback", JSON{"chall_id": chall.ID, "vote": "Dislike"}, http.StatusOK)
+	admin.Post("/challenges/feedback", JSON{"chall_id": chall.ID, "vote": "Like"}, http.StatusForbidden)
+	admin.CheckResponse(errorf(consts.ChallengeNotSolved))
+
+	other.Get(fmt.Sprintf("/challenges/%d", chall.ID), nil, http.StatusOK)
+	body := Json(other.Body())
+	test_utils.Compare(t, JSON{
+		"difficulty":       4,
+		"difficulty_votes": 1,
+		"dislikes":         1,
+		"likes":            1,
+	}, body["rating"])
+	if _, ok := body["feedback"]; ok {
+		t.Fatal("Expected the feedback to be visible only to the authors")
+	}
+
+	admin.Get(fmt.Sprintf("/challenges/%d", chall.ID), nil, http.StatusOK)
+	feedback := test_utils.DeleteKeys(Json(admin.Body())["feedback"], "updated_at", "user_id", "team_id")
+	test_utils.Compare(t, []JSON{
+		{
+			"comment":    nil,
+			"difficulty": nil,
+			"team_name":  "solvers",
+			"user_name":  "solver",
+			"vote":       "Dislike",
+		},
+		{
+			"comment":    "Nicer one",
+			"difficulty": 4,
+			"team_name":  "solvers",
+			"user_name":  "mate",
+			"vote":       "Like",
+		},
+	}, feedback)
+}
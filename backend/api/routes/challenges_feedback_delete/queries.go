@@ -0,0 +1,25 @@
+package challenges_feedback_delete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// DeleteFeedback returns false if the user left no feedback on the challenge
+func DeleteFeedback(ctx context.Context, uid int32, challengeID int32) (bool, error) {
+	_, err := db.Sql.DeleteFeedback(ctx, sqlc.DeleteFeedbackParams{
+		UserID:  uid,
+		ChallID: challengeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
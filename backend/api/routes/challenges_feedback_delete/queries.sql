@@ -0,0 +1,3 @@
+-- name: DeleteFeedback :one
+-- Delete the feedback of a user on a challenge
+DELETE FROM challenge_feedbacks WHERE user_id = $1 AND chall_id = $2 RETURNING chall_id;
@@ -0,0 +1,37 @@
+package challenges_feedback_delete
+
+import (
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	uid := c.Locals("uid").(int32)
+
+	deleted, err := DeleteFeedback(c.Context(), uid, *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingFeedback, err)
+	}
+	if !deleted {
+		return utils.Error(c, fiber.StatusNotFound, consts.FeedbackNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,99 @@
+package challenges_feedback_delete_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"chall_id": -1},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
+	},
+	{
+		testBody:         JSON{"chall_id": 99999},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.FeedbackNotFound),
+	},
+	{
+		testBody:       JSON{"chall_id": ""},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"chall_id": ""},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.FeedbackNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	admin.Post("/flags", JSON{"chall_id": chall.ID, "flag": "flag{test}", "regex": false}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{test}"}, http.StatusOK)
+	session.Post("/challenges/feedback", JSON{"chall_id": chall.ID, "vote": "Like", "difficulty": 3}, http.StatusOK)
+
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["chall_id"]; ok && content == "" {
+				test.testBody.(JSON)["chall_id"] = chall.ID
+			}
+		}
+		session.Delete("/challenges/feedback", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	session.Get(fmt.Sprintf("/challenges/%d", chall.ID), nil, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"difficulty":       nil,
+		"difficulty_votes": 0,
+		"dislikes":         0,
+		"likes":            0,
+	}, Json(session.Body())["rating"])
+}
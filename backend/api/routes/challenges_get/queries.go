@@ -3,26 +3,85 @@ package challenges_get
 import (
 	"context"
 	"database/sql"
+	"time"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/instancer"
 )
 
 type DockerConfig struct {
-	Image      string  `json:"image"`
-	Compose    string  `json:"compose"`
-	HashDomain *bool   `json:"hash_domain"`
-	Lifetime   *int    `json:"lifetime"`
-	Envs       *string `json:"envs"`
-	MaxMemory  *int    `json:"max_memory"`
-	MaxCpu     *string `json:"max_cpu"`
+	Image             string             `json:"image"`
+	Compose           string             `json:"compose"`
+	HashDomain        *bool              `json:"hash_domain"`
+	Lifetime          *int               `json:"lifetime"`
+	Envs              *string            `json:"envs"`
+	MaxMemory         *int               `json:"max_memory"`
+	MaxCpu            *string            `json:"max_cpu"`
+	TeamOnly          *bool              `json:"team_only"`
+	PowDifficulty     *int               `json:"pow_difficulty"`
+	MaxLifetime       *int               `json:"max_lifetime"`
+	MaxExtensions     *int               `json:"max_extensions"`
+	ExtensionCooldown *int               `json:"extension_cooldown"`
+	ExtensionWindow   *int               `json:"extension_window"`
+	InstanceFlag      *bool              `json:"instance_flag"`
+	CpuThreshold      *int               `json:"cpu_threshold"`
+	MemoryThreshold   *int               `json:"memory_threshold"`
+	PidsThreshold     *int               `json:"pids_threshold"`
+	NetworkThreshold  *int               `json:"network_threshold"`
+	ThresholdDuration *int               `json:"threshold_duration"`
+	AutoKill          *bool              `json:"auto_kill"`
+	Egress            *sqlc.EgressPolicy `json:"egress"`
+	Shared            *bool              `json:"shared"`
+	Placement         []string           `json:"placement"`
+	ReservedMemory    *int               `json:"reserved_memory"`
+	ReservedCpu       *string            `json:"reserved_cpu"`
+}
+
+// optionalInt returns nil for the settings left to the global configuration
+func optionalInt(value sql.NullInt32) *int {
+	if !value.Valid {
+		return nil
+	}
+	return new(int(value.Int32))
+}
+
+type Deployment struct {
+	Status    sqlc.DeploymentStatus `json:"status"`
+	Error     string                `json:"error"`
+	Restarts  int32                 `json:"restarts"`
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
+}
+
+type Rating struct {
+	Likes           int64    `json:"likes"`
+	Dislikes        int64    `json:"dislikes"`
+	DifficultyVotes int64    `json:"difficulty_votes"`
+	Difficulty      *float64 `json:"difficulty"` // Average of the votes, null without any
+}
+
+type Feedback struct {
+	UserID     int32              `json:"user_id"`
+	UserName   string             `json:"user_name"`
+	TeamID     *int32             `json:"team_id"`
+	TeamName   *string            `json:"team_name"`
+	Vote       *sqlc.FeedbackVote `json:"vote"`
+	Difficulty *int32             `json:"difficulty"`
+	Comment    *string            `json:"comment"`
+	UpdatedAt  time.Time          `json:"updated_at"`
 }
 
 type Chall struct {
 	SolvesList []sqlc.GetChallengeSolvesRow `json:"solves_list"`
+	Rating     Rating                       `json:"rating"`
 
 	Type         *sqlc.DeployType               `json:"type,omitempty"`
 	Flags        *[]sqlc.GetFlagsByChallengeRow `json:"flags,omitempty"`
+	Owners       *[]sqlc.GetChallengeOwnersRow  `json:"owners,omitempty"`
+	Feedback     *[]Feedback                    `json:"feedback,omitempty"`
 	DockerConfig *DockerConfig                  `json:"docker_config,omitempty"`
+	Deployment   *Deployment                    `json:"deployment,omitempty"`
+
+	Extension *instancer.ExtensionBudget `json:"extension,omitempty"`
 }
 
 func GetFlagsByChallenge(ctx context.Context, challengeID int32) ([]sqlc.GetFlagsByChallengeRow, error) {
@@ -34,7 +93,10 @@ func GetFlagsByChallenge(ctx context.Context, challengeID int32) ([]sqlc.GetFlag
 	return flags, nil
 }
 
-func GetChallenge(ctx context.Context, id int32, uid int32, tid int32, author bool) (*Chall, error) {
+// GetChallenge returns the challenge as seen by the user: the ones reading
+// every challenge also see the hidden ones, and the ones managing it also its
+// flags, owners, feedback and docker config
+func GetChallenge(ctx context.Context, id int32, uid int32, tid int32, author bool, manage bool) (*Chall, error) {
 	challenge, err := db.GetChallengeByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -58,21 +120,52 @@ func GetChallenge(ctx context.Context, id int32, uid int32, tid int32, author bo
 		chall.SolvesList = solves
 	}
 
+	rating, err := GetRating(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	chall.Rating = *rating
+
+	if challenge.Type != sqlc.DeployTypeNormal {
+		chall.Extension, err = GetExtensionBudget(ctx, id, tid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if !author { // Not Author
 		return &chall, nil
 	}
 
+	chall.Type = &challenge.Type
+	if !manage { // Not an owner of the challenge
+		return &chall, nil
+	}
+
 	flags, err := GetFlagsByChallenge(ctx, challenge.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	chall.Type = &challenge.Type
 	chall.Flags = &[]sqlc.GetFlagsByChallengeRow{}
 	if flags != nil {
 		chall.Flags = &flags
 	}
 
+	owners, err := db.Sql.GetChallengeOwners(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	chall.Owners = &[]sqlc.GetChallengeOwnersRow{}
+	if owners != nil {
+		chall.Owners = &owners
+	}
+
+	chall.Feedback, err = GetFeedback(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	dockerConfig, err := db.Sql.GetChallDockerConfig(ctx, challenge.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -82,14 +175,133 @@ func GetChallenge(ctx context.Context, id int32, uid int32, tid int32, author bo
 	}
 
 	chall.DockerConfig = &DockerConfig{
-		Image:      dockerConfig.Image,
-		Compose:    dockerConfig.Compose,
-		HashDomain: &dockerConfig.HashDomain,
-		Lifetime:   new(int(dockerConfig.Lifetime)),
-		Envs:       &dockerConfig.Envs,
-		MaxMemory:  new(int(dockerConfig.MaxMemory)),
-		MaxCpu:     &dockerConfig.MaxCpu,
+		Image:             dockerConfig.Image,
+		Compose:           dockerConfig.Compose,
+		HashDomain:        &dockerConfig.HashDomain,
+		Lifetime:          new(int(dockerConfig.Lifetime)),
+		Envs:              &dockerConfig.Envs,
+		MaxMemory:         new(int(dockerConfig.MaxMemory)),
+		MaxCpu:            &dockerConfig.MaxCpu,
+		TeamOnly:          &dockerConfig.TeamOnly,
+		PowDifficulty:     new(int(dockerConfig.PowDifficulty)),
+		MaxLifetime:       optionalInt(dockerConfig.MaxLifetime),
+		MaxExtensions:     optionalInt(dockerConfig.MaxExtensions),
+		ExtensionCooldown: optionalInt(dockerConfig.ExtensionCooldown),
+		ExtensionWindow:   optionalInt(dockerConfig.ExtensionWindow),
+		InstanceFlag:      &dockerConfig.InstanceFlag,
+		CpuThreshold:      new(int(dockerConfig.CpuThreshold)),
+		MemoryThreshold:   new(int(dockerConfig.MemoryThreshold)),
+		PidsThreshold:     new(int(dockerConfig.PidsThreshold)),
+		NetworkThreshold:  new(int(dockerConfig.NetworkThreshold)),
+		ThresholdDuration: new(int(dockerConfig.ThresholdDuration)),
+		AutoKill:          &dockerConfig.AutoKill,
+		Egress:            &dockerConfig.Egress,
+		Shared:            &dockerConfig.Shared,
+		Placement:         dockerConfig.Placement,
+		ReservedMemory:    new(int(dockerConfig.ReservedMemory)),
+		ReservedCpu:       &dockerConfig.ReservedCpu,
+	}
+
+	if !dockerConfig.Shared || challenge.Type == sqlc.DeployTypeNormal {
+		return &chall, nil
+	}
+
+	chall.Deployment, err = GetDeployment(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	return &chall, nil
 }
+
+// GetExtensionBudget returns what the team can still extend its instance by,
+// nil without an active instance
+func GetExtensionBudget(ctx context.Context, challengeID int32, tid int32) (*instancer.ExtensionBudget, error) {
+	if tid == -1 {
+		return nil, nil
+	}
+
+	instance, err := instancer.GetInstance(ctx, challengeID, tid)
+	if err != nil || instance == nil {
+		return nil, err
+	}
+
+	conf, err := db.GetDockerConfigsByID(ctx, challengeID)
+	if err != nil || conf == nil {
+		return nil, err
+	}
+
+	return instancer.NewExtensionPolicy(conf).Budget(instance), nil
+}
+
+// GetDeployment returns the state of the shared deployment, pending until
+// the deployer handles it for the first time
+func GetDeployment(ctx context.Context, challengeID int32) (*Deployment, error) {
+	deployment, err := db.Sql.GetDeployment(ctx, challengeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &Deployment{Status: sqlc.DeploymentStatusPending}, nil
+		}
+		return nil, err
+	}
+
+	return &Deployment{
+		Status:    deployment.Status,
+		Error:     deployment.Error,
+		Restarts:  deployment.Restarts,
+		UpdatedAt: &deployment.UpdatedAt,
+	}, nil
+}
+
+// GetRating returns the likes, dislikes and average difficulty voted by the
+// solvers of a challenge
+func GetRating(ctx context.Context, challengeID int32) (*Rating, error) {
+	row, err := db.Sql.GetChallengeRating(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rating := Rating{
+		Likes:           row.Likes,
+		Dislikes:        row.Dislikes,
+		DifficultyVotes: row.DifficultyVotes,
+	}
+	if row.DifficultyVotes > 0 {
+		rating.Difficulty = &row.Difficulty
+	}
+
+	return &rating, nil
+}
+
+// GetFeedback returns all the feedback left on a challenge, newest first
+func GetFeedback(ctx context.Context, challengeID int32) (*[]Feedback, error) {
+	rows, err := db.Sql.GetChallengeFeedback(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := make([]Feedback, 0, len(rows))
+	for _, row := range rows {
+		entry := Feedback{
+			UserID:    row.UserID,
+			UserName:  row.UserName,
+			UpdatedAt: row.UpdatedAt,
+		}
+		if row.TeamID.Valid {
+			entry.TeamID = &row.TeamID.Int32
+			entry.TeamName = &row.TeamName.String
+		}
+		if row.Vote.Valid {
+			entry.Vote = &row.Vote.FeedbackVote
+		}
+		if row.Difficulty.Valid {
+			entry.Difficulty = &row.Difficulty.Int32
+		}
+		if row.Comment.Valid {
+			entry.Comment = &row.Comment.String
+		}
+		feedback = append(feedback, entry)
+	}
+
+	return &feedback, nil
+}
@@ -15,3 +15,26 @@ SELECT flag, regex FROM flags WHERE chall_id = $1;
 
 -- name: GetChallDockerConfig :one
 SELECT * FROM docker_configs WHERE chall_id = $1;
+
+-- name: GetDeployment :one
+-- Retrieves the state of the shared deployment of a challenge
+SELECT status, error, restarts, updated_at FROM deployments WHERE chall_id = $1;
+
+-- name: GetChallengeRating :one
+-- Retrieve the aggregated feedback of a challenge
+SELECT COUNT(*) FILTER (WHERE vote = 'Like') AS likes,
+    COUNT(*) FILTER (WHERE vote = 'Dislike') AS dislikes,
+    COUNT(difficulty) AS difficulty_votes,
+    COALESCE(ROUND(AVG(difficulty), 2), 0)::FLOAT AS difficulty
+  FROM challenge_feedbacks
+  WHERE chall_id = $1;
+
+-- name: GetChallengeFeedback :many
+-- Retrieve all the feedback left on a challenge
+SELECT users.id AS user_id, users.name AS user_name, teams.id AS team_id, teams.name AS team_name,
+    challenge_feedbacks.vote, challenge_feedbacks.difficulty, challenge_feedbacks.comment, challenge_feedbacks.updated_at
+  FROM challenge_feedbacks
+  JOIN users ON users.id = challenge_feedbacks.user_id
+  LEFT JOIN teams ON teams.id = users.team_id
+  WHERE challenge_feedbacks.chall_id = $1
+  ORDER BY challenge_feedbacks.updated_at DESC;
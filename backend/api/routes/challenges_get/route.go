@@ -1,6 +1,7 @@
 package challenges_get
 
 import (
+	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
@@ -12,7 +13,6 @@ import (
 func Route(c *fiber.Ctx) error {
 	uid := c.Locals("uid").(int32)
 	tid := c.Locals("tid").(int32)
-	role := c.Locals("role").(sqlc.UserRole)
 
 	challengeIDInt, err := c.ParamsInt("id")
 	if err != nil {
@@ -24,8 +24,17 @@ func Route(c *fiber.Ctx) error {
 		return err
 	}
 
-	all := utils.In(role, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin})
-	challenge, err := GetChallenge(c.Context(), challengeID, uid, tid, all)
+	all := utils.Can(c, consts.PermChallengesRead)
+	manage := false
+	if all {
+		role := c.Locals("role").(sqlc.UserRole)
+		manage, err = db.CanManageChallenge(c.Context(), uid, role, challengeID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+	}
+
+	challenge, err := GetChallenge(c.Context(), challengeID, uid, tid, all, manage)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenges, err)
 	}
@@ -29,6 +29,13 @@ func Int32(val any) int32 {
 	return int32(val.(float64))
 }
 
+var noRating = JSON{
+	"difficulty":       nil,
+	"difficulty_votes": 0,
+	"dislikes":         0,
+	"likes":            0,
+}
+
 func TestMain(m *testing.M) {
 	test_utils.Main(m)
 }
@@ -51,6 +58,7 @@ func TestRoute(t *testing.T) {
 	}
 
 	expectedPlayer := JSON{
+		"rating": noRating,
 		"solves_list": []JSON{
 			{
 				"name": "A",
@@ -84,6 +92,7 @@ func TestRoute(t *testing.T) {
 	session.CheckFilteredResponse(expectedPlayer, "id", "timestamp")
 
 	expectedAuthor := JSON{
+		"feedback": []any{},
 		"flags": []JSON{
 			{
 				"flag":  "flag{test-1}",
@@ -94,6 +103,13 @@ func TestRoute(t *testing.T) {
 				"regex": true,
 			},
 		},
+		"owners": []JSON{
+			{
+				"name": "test2",
+				"role": "Owner",
+			},
+		},
+		"rating": noRating,
 		"solves_list": []JSON{
 			{
 				"name": "A",
@@ -102,7 +118,8 @@ func TestRoute(t *testing.T) {
 		"type": "Normal",
 	}
 
-	test_utils.RegisterUser(t, "test2", "test3@test.test", "testpass", sqlc.UserRoleAuthor)
+	author := test_utils.RegisterUser(t, "test2", "test3@test.test", "testpass", sqlc.UserRoleAuthor)
+	test_utils.AddChallengeOwner(t, id, author.ID, sqlc.OwnerRoleOwner)
 
 	session = test_utils.NewApiTestSession(t, app)
 	session.Post("/login", JSON{"email": "test3@test.test", "password": "testpass"}, http.StatusOK)
@@ -111,21 +128,31 @@ func TestRoute(t *testing.T) {
 	session.CheckFilteredResponse(expectedAuthor, "id", "timestamp")
 
 	expectedAuthorHidden := JSON{
+		"feedback": []any{},
 		"flags": []JSON{
 			{
 				"flag":  "flag{test-5}",
 				"regex": false,
 			},
 		},
+		"owners": []JSON{
+			{
+				"name": "test2",
+				"role": "Owner",
+			},
+		},
+		"rating":      noRating,
 		"solves_list": []any{},
 		"type":        "Normal",
 	}
 
 	session.Get("/challenges", nil, http.StatusOK)
 	body = session.Body()
-	var id3, id5 int32
+	var id2, id3, id5 int32
 	for _, chall := range List(body) {
 		switch Json(chall)["name"] {
+		case "chall-2":
+			id2 = Int32(Json(chall)["id"])
 		case "chall-3":
 			id3 = Int32(Json(chall)["id"])
 		case "chall-5":
@@ -133,25 +160,67 @@ func TestRoute(t *testing.T) {
 		}
 	}
 
+	expectedNotOwned := JSON{
+		"rating": noRating,
+		"solves_list": []JSON{
+			{
+				"name": "B",
+			},
+		},
+		"type": "Normal",
+	}
+
+	session.Get(fmt.Sprintf("/challenges/%d", id2), nil, http.StatusOK)
+	session.CheckFilteredResponse(expectedNotOwned, "id", "timestamp")
+
+	test_utils.AddChallengeOwner(t, id3, author.ID, sqlc.OwnerRoleOwner)
+	test_utils.AddChallengeOwner(t, id5, author.ID, sqlc.OwnerRoleOwner)
+
 	session.Get(fmt.Sprintf("/challenges/%d", id5), nil, http.StatusOK)
 	session.CheckFilteredResponse(expectedAuthorHidden, "id", "timestamp")
 
 	expectedDocker := JSON{
 		"docker_config": JSON{
-			"compose":     "",
-			"envs":        "",
-			"hash_domain": true,
-			"image":       "echo-server:latest",
-			"lifetime":    0,
-			"max_cpu":     "",
-			"max_memory":  0,
+			"compose":            "",
+			"envs":               "",
+			"hash_domain":        true,
+			"image":              "echo-server:latest",
+			"lifetime":           0,
+			"max_cpu":            "",
+			"max_memory":         0,
+			"team_only":          false,
+			"pow_difficulty":     0,
+			"max_lifetime":       0,
+			"max_extensions":     0,
+			"extension_cooldown": 0,
+			"extension_window":   0,
+			"instance_flag":      false,
+			"cpu_threshold":      0,
+			"memory_threshold":   0,
+			"pids_threshold":     0,
+			"network_threshold":  0,
+			"threshold_duration": 0,
+			"auto_kill":          false,
+			"egress":             "Full",
+			"shared":             false,
+			"placement":          []string{},
+			"reserved_memory":    0,
+			"reserved_cpu":       "",
 		},
+		"feedback": []any{},
 		"flags": []JSON{
 			{
 				"flag":  "flag{test-3}",
 				"regex": false,
 			},
 		},
+		"owners": []JSON{
+			{
+				"name": "test2",
+				"role": "Owner",
+			},
+		},
+		"rating": noRating,
 		"solves_list": []JSON{
 			{
 				"name": "A",
@@ -171,20 +240,46 @@ func TestRoute(t *testing.T) {
 
 	expectedInstance := JSON{
 		"docker_config": JSON{
-			"compose":     "",
-			"envs":        "",
-			"hash_domain": true,
-			"image":       "echo-server:latest",
-			"lifetime":    0,
-			"max_cpu":     "",
-			"max_memory":  0,
+			"compose":            "",
+			"envs":               "",
+			"hash_domain":        true,
+			"image":              "echo-server:latest",
+			"lifetime":           0,
+			"max_cpu":            "",
+			"max_memory":         0,
+			"team_only":          false,
+			"pow_difficulty":     0,
+			"max_lifetime":       0,
+			"max_extensions":     0,
+			"extension_cooldown": 0,
+			"extension_window":   0,
+			"instance_flag":      false,
+			"cpu_threshold":      0,
+			"memory_threshold":   0,
+			"pids_threshold":     0,
+			"network_threshold":  0,
+			"threshold_duration": 0,
+			"auto_kill":          false,
+			"egress":             "Full",
+			"shared":             false,
+			"placement":          []string{},
+			"reserved_memory":    0,
+			"reserved_cpu":       "",
 		},
+		"feedback": []any{},
 		"flags": []JSON{
 			{
 				"flag":  "flag{test-3}",
 				"regex": false,
 			},
 		},
+		"owners": []JSON{
+			{
+				"name": "test2",
+				"role": "Owner",
+			},
+		},
+		"rating": noRating,
 		"solves_list": []JSON{
 			{
 				"name": "A",
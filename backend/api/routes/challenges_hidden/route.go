@@ -1,17 +1,22 @@
 package challenges_hidden
 
 import (
+	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
+	"trxd/utils/announcements"
 	"trxd/utils/consts"
 	"trxd/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	ChallIDs []int32 `json:"chall_ids" validate:"required,id"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		ChallIDs []int32 `json:"chall_ids" validate:"required,id"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -21,10 +26,25 @@ func Route(c *fiber.Ctx) error {
 		return err
 	}
 
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	for _, challID := range data.ChallIDs {
+		owned, err := db.CanManageChallenge(c.Context(), uid, role, challID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if !owned {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+	}
+
 	err = ToggleChallengesHidden(c.Context(), data.ChallIDs)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingChallenge, err)
 	}
 
+	// The announcements about the challenges may be due now that they're visible
+	announcements.Wake()
+
 	return c.SendStatus(fiber.StatusOK)
 }
@@ -36,10 +36,10 @@ func TestRoute(t *testing.T) {
 	app := api.SetupApp(t.Context())
 	defer api.Shutdown(app)
 
-	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
 
 	session := test_utils.NewApiTestSession(t, app)
-	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	session.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
 	session.Get("/challenges", nil, http.StatusOK)
 	body := session.Body()
 
@@ -50,6 +50,17 @@ func TestRoute(t *testing.T) {
 		hiddens[challIDs[len(challIDs)-1]] = Json(chall)["hidden"].(bool)
 	}
 
+	// The authors only toggle the challenges they own
+	authorSession := test_utils.NewApiTestSession(t, app)
+	authorSession.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	authorSession.Patch("/challenges/hidden", JSON{"chall_ids": challIDs}, http.StatusForbidden)
+	authorSession.CheckResponse(errorf(consts.NotChallengeOwner))
+	test_utils.AddChallengeOwner(t, challIDs[0], author.ID, sqlc.OwnerRoleCoOwner)
+	authorSession.Patch("/challenges/hidden", JSON{"chall_ids": challIDs[:1]}, http.StatusOK)
+	authorSession.CheckResponse(nil)
+	authorSession.Patch("/challenges/hidden", JSON{"chall_ids": challIDs[:1]}, http.StatusOK)
+	authorSession.CheckResponse(nil)
+
 	session.Patch("/challenges/hidden", JSON{}, http.StatusBadRequest)
 	session.CheckResponse(errorf(consts.MissingRequiredFields))
 
@@ -0,0 +1,42 @@
+package challenges_owners_create
+
+import (
+	"context"
+	"fmt"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// SetChallengeOwner adds the user to the owners of the challenge, or changes
+// their role, a new Owner makes the previous one a co-owner
+func SetChallengeOwner(ctx context.Context, challengeID int32, userID int32, role sqlc.OwnerRole) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer db.Rollback(tx)
+	queries := db.Sql.WithTx(tx)
+
+	if role == sqlc.OwnerRoleOwner {
+		err = queries.DemoteChallengeOwner(ctx, challengeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = queries.SetChallengeOwner(ctx, sqlc.SetChallengeOwnerParams{
+		ChallID: challengeID,
+		UserID:  userID,
+		Role:    role,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+-- name: DemoteChallengeOwner :exec
+-- Make the current Owner of a challenge a co-owner
+UPDATE challenge_owners SET role = 'CoOwner' WHERE chall_id = $1 AND role = 'Owner';
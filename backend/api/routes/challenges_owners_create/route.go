@@ -0,0 +1,83 @@
+package challenges_owners_create
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID *int32          `json:"chall_id" validate:"required,id"`
+	UserID  *int32          `json:"user_id" validate:"required,id"`
+	Role    *sqlc.OwnerRole `json:"role" validate:"omitnil,owner_role"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	challenge, err := db.GetChallengeByID(c.Context(), *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if challenge == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+	}
+
+	// Among the authors only the Owner chooses the co-owners
+	uid := c.Locals("uid").(int32)
+	if c.Locals("role").(sqlc.UserRole) == sqlc.UserRoleAuthor {
+		ownerRole, err := db.GetOwnerRole(c.Context(), *data.ChallID, uid)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if ownerRole == nil || *ownerRole != sqlc.OwnerRoleOwner {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+	}
+
+	user, err := db.GetUserByID(c.Context(), *data.UserID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
+	}
+	if user == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.UserNotFound)
+	}
+	if !utils.In(user.Role, []sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin}) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.UserNotAuthor)
+	}
+
+	role := sqlc.OwnerRoleCoOwner
+	if data.Role != nil {
+		role = *data.Role
+	}
+
+	// The Owner is only replaced by naming a new one
+	if role != sqlc.OwnerRoleOwner {
+		current, err := db.GetOwnerRole(c.Context(), *data.ChallID, *data.UserID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if current != nil && *current == sqlc.OwnerRoleOwner {
+			return utils.Error(c, fiber.StatusBadRequest, consts.OwnerCannotBeRemoved)
+		}
+	}
+
+	err = SetChallengeOwner(c.Context(), *data.ChallID, *data.UserID, role)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingOwners, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,87 @@
+package challenges_owners_create_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	owner := test_utils.RegisterUser(t, "owner", "owner@test.test", "testpass", sqlc.UserRoleAuthor)
+	coOwner := test_utils.RegisterUser(t, "co-owner", "co-owner@test.test", "testpass", sqlc.UserRoleAuthor)
+	player := test_utils.RegisterUser(t, "player", "player@test.test", "testpass", sqlc.UserRolePlayer)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "owner@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.AddChallengeOwner(t, chall.ID, owner.ID, sqlc.OwnerRoleOwner)
+
+	session.Post("/challenges/owners", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidJSON))
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.MissingRequiredFields))
+	session.Post("/challenges/owners", JSON{"chall_id": -1, "user_id": coOwner.ID}, http.StatusBadRequest)
+	session.CheckResponse(errorf(test_utils.Format(consts.MinError, "ChallID", 0)))
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID, "role": "Invalid"}, http.StatusBadRequest)
+	session.CheckResponse(errorf(test_utils.Format(consts.OneOfError, "Role", strings.Join(consts.OwnerRolesStr, " "))))
+	session.Post("/challenges/owners", JSON{"chall_id": 99999, "user_id": coOwner.ID}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.ChallengeNotFound))
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": 99999}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.UserNotFound))
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": player.ID}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.UserNotAuthor))
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": owner.ID, "role": "CoOwner"}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.OwnerCannotBeRemoved))
+
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	// The co-owners cannot choose the other owners
+	coSession := test_utils.NewApiTestSession(t, app)
+	coSession.Post("/login", JSON{"email": "co-owner@test.test", "password": "testpass"}, http.StatusOK)
+	coSession.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID, "role": "Owner"}, http.StatusForbidden)
+	coSession.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	session.Get(fmt.Sprintf("/challenges/%d", chall.ID), nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{
+		{"id": owner.ID, "name": "owner", "role": "Owner"},
+		{"id": coOwner.ID, "name": "co-owner", "role": "CoOwner"},
+	}, session.Body().(map[string]any)["owners"])
+
+	// Naming a new Owner makes the previous one a co-owner
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID, "role": "Owner"}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	coSession.Get(fmt.Sprintf("/challenges/%d", chall.ID), nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{
+		{"id": coOwner.ID, "name": "co-owner", "role": "Owner"},
+		{"id": owner.ID, "name": "owner", "role": "CoOwner"},
+	}, coSession.Body().(map[string]any)["owners"])
+
+	// The admins manage the owners of every challenge
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": owner.ID, "role": "Owner"}, http.StatusOK)
+	admin.CheckResponse(nil)
+}
@@ -0,0 +1,19 @@
+package challenges_owners_delete
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func RemoveChallengeOwner(ctx context.Context, challengeID int32, userID int32) error {
+	err := db.Sql.RemoveChallengeOwner(ctx, sqlc.RemoveChallengeOwnerParams{
+		ChallID: challengeID,
+		UserID:  userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
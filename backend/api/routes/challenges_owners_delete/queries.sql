@@ -0,0 +1,3 @@
+-- name: RemoveChallengeOwner :exec
+-- Remove a user from the owners of a challenge
+DELETE FROM challenge_owners WHERE chall_id = $1 AND user_id = $2;
@@ -0,0 +1,58 @@
+package challenges_owners_delete
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+	UserID  *int32 `json:"user_id" validate:"required,id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	// Among the authors only the Owner chooses the co-owners
+	uid := c.Locals("uid").(int32)
+	if c.Locals("role").(sqlc.UserRole) == sqlc.UserRoleAuthor {
+		ownerRole, err := db.GetOwnerRole(c.Context(), *data.ChallID, uid)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if ownerRole == nil || *ownerRole != sqlc.OwnerRoleOwner {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+	}
+
+	role, err := db.GetOwnerRole(c.Context(), *data.ChallID, *data.UserID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if role == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.OwnerNotFound)
+	}
+	if *role == sqlc.OwnerRoleOwner {
+		return utils.Error(c, fiber.StatusBadRequest, consts.OwnerCannotBeRemoved)
+	}
+
+	err = RemoveChallengeOwner(c.Context(), *data.ChallID, *data.UserID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingOwners, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,72 @@
+package challenges_owners_delete_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	owner := test_utils.RegisterUser(t, "owner", "owner@test.test", "testpass", sqlc.UserRoleAuthor)
+	coOwner := test_utils.RegisterUser(t, "co-owner", "co-owner@test.test", "testpass", sqlc.UserRoleAuthor)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "owner@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.AddChallengeOwner(t, chall.ID, owner.ID, sqlc.OwnerRoleOwner)
+	test_utils.AddChallengeOwner(t, chall.ID, coOwner.ID, sqlc.OwnerRoleCoOwner)
+
+	session.Delete("/challenges/owners", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidJSON))
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.MissingRequiredFields))
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": -1}, http.StatusBadRequest)
+	session.CheckResponse(errorf(test_utils.Format(consts.MinError, "UserID", 0)))
+	session.Delete("/challenges/owners", JSON{"chall_id": 99999, "user_id": coOwner.ID}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.NotChallengeOwner))
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": 99999}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.OwnerNotFound))
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": owner.ID}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.OwnerCannotBeRemoved))
+
+	// The co-owners cannot remove the other owners
+	coSession := test_utils.NewApiTestSession(t, app)
+	coSession.Post("/login", JSON{"email": "co-owner@test.test", "password": "testpass"}, http.StatusOK)
+	coSession.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusForbidden)
+	coSession.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.OwnerNotFound))
+
+	// Without the ownership the challenge cannot be changed anymore
+	coSession.Post("/flags", JSON{"chall_id": chall.ID, "flag": "flag{test}"}, http.StatusForbidden)
+	coSession.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	// The admins manage the owners of every challenge
+	test_utils.AddChallengeOwner(t, chall.ID, coOwner.ID, sqlc.OwnerRoleCoOwner)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": coOwner.ID}, http.StatusOK)
+	admin.CheckResponse(nil)
+	admin.Delete("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": owner.ID}, http.StatusBadRequest)
+	admin.CheckResponse(errorf(consts.OwnerCannotBeRemoved))
+}
@@ -50,6 +50,13 @@ func nullConnType(src *sqlc.ConnType) sqlc.NullConnType {
 	return sqlc.NullConnType{ConnType: *src, Valid: true}
 }
 
+func nullEgressPolicy(src *sqlc.EgressPolicy) sqlc.NullEgressPolicy {
+	if src == nil {
+		return sqlc.NullEgressPolicy{Valid: false}
+	}
+	return sqlc.NullEgressPolicy{EgressPolicy: *src, Valid: true}
+}
+
 func IsChallEmpty(data *UpdateChallParams) bool {
 	if data.Name == "" && data.Category == "" && data.Description == nil && data.Authors == nil &&
 		data.Tags == nil && data.Type == nil && data.Hidden == nil && data.MaxPoints == nil &&
@@ -61,7 +68,12 @@ func IsChallEmpty(data *UpdateChallParams) bool {
 
 func IsDockerConfigsEmpty(data *UpdateChallParams) bool {
 	if data.Image == nil && data.Compose == nil && data.HashDomain == nil && data.Lifetime == nil &&
-		data.Envs == nil && data.MaxMemory == nil && data.MaxCpu == nil {
+		data.Envs == nil && data.MaxMemory == nil && data.MaxCpu == nil && data.TeamOnly == nil &&
+		data.PowDifficulty == nil && data.MaxLifetime == nil && data.MaxExtensions == nil &&
+		data.ExtensionCooldown == nil && data.ExtensionWindow == nil && data.InstanceFlag == nil &&
+		data.CpuThreshold == nil && data.MemoryThreshold == nil && data.PidsThreshold == nil &&
+		data.NetworkThreshold == nil && data.ThresholdDuration == nil && data.AutoKill == nil &&
+		data.Egress == nil && data.Shared == nil && data.Placement == nil && data.ReservedMemory == nil && data.ReservedCpu == nil {
 		return true
 	}
 	return false
@@ -94,14 +106,35 @@ func UpdateChallenge(ctx context.Context, data *UpdateChallParams) error {
 	}
 
 	dockerParams := sqlc.UpdateDockerConfigsParams{
-		ChallID:    *data.ChallID,
-		Image:      nullString(data.Image),
-		Compose:    nullString(data.Compose),
-		HashDomain: nullBool(data.HashDomain),
-		Lifetime:   nullInt32(data.Lifetime),
-		Envs:       nullString(data.Envs),
-		MaxMemory:  nullInt32(data.MaxMemory),
-		MaxCpu:     nullString(data.MaxCpu),
+		ChallID:           *data.ChallID,
+		Image:             nullString(data.Image),
+		Compose:           nullString(data.Compose),
+		HashDomain:        nullBool(data.HashDomain),
+		Lifetime:          nullInt32(data.Lifetime),
+		Envs:              nullString(data.Envs),
+		MaxMemory:         nullInt32(data.MaxMemory),
+		MaxCpu:            nullString(data.MaxCpu),
+		TeamOnly:          nullBool(data.TeamOnly),
+		PowDifficulty:     nullInt32(data.PowDifficulty),
+		MaxLifetime:       nullInt32(data.MaxLifetime),
+		MaxExtensions:     nullInt32(data.MaxExtensions),
+		ExtensionCooldown: nullInt32(data.ExtensionCooldown),
+		ExtensionWindow:   nullInt32(data.ExtensionWindow),
+		InstanceFlag:      nullBool(data.InstanceFlag),
+		CpuThreshold:      nullInt32(data.CpuThreshold),
+		MemoryThreshold:   nullInt32(data.MemoryThreshold),
+		PidsThreshold:     nullInt32(data.PidsThreshold),
+		NetworkThreshold:  nullInt32(data.NetworkThreshold),
+		ThresholdDuration: nullInt32(data.ThresholdDuration),
+		AutoKill:          nullBool(data.AutoKill),
+		Egress:            nullEgressPolicy(data.Egress),
+		Shared:            nullBool(data.Shared),
+		ReservedMemory:    nullInt32(data.ReservedMemory),
+		ReservedCpu:       nullString(data.ReservedCpu),
+	}
+
+	if data.Placement != nil {
+		dockerParams.Placement = *data.Placement
 	}
 
 	tx, err := db.BeginTx(ctx)
@@ -17,7 +17,8 @@ SET
 WHERE id = sqlc.arg('chall_id');
 
 -- name: UpdateDockerConfigs :exec
--- Updates the Docker configurations for the challenge with the given ID
+-- Updates the Docker configurations for the challenge with the given ID,
+-- -1 resets an extension limit to the global one
 UPDATE docker_configs
 SET
   image = COALESCE(sqlc.narg('image'), image),
@@ -26,5 +27,23 @@ SET
   lifetime = COALESCE(sqlc.narg('lifetime'), lifetime),
   envs = COALESCE(sqlc.narg('envs'), envs),
   max_memory = COALESCE(sqlc.narg('max_memory'), max_memory),
-  max_cpu = COALESCE(sqlc.narg('max_cpu'), max_cpu)
+  max_cpu = COALESCE(sqlc.narg('max_cpu'), max_cpu),
+  team_only = COALESCE(sqlc.narg('team_only'), team_only),
+  pow_difficulty = COALESCE(sqlc.narg('pow_difficulty'), pow_difficulty),
+  max_lifetime = CASE WHEN sqlc.narg('max_lifetime') = -1 THEN NULL ELSE COALESCE(sqlc.narg('max_lifetime'), max_lifetime) END,
+  max_extensions = CASE WHEN sqlc.narg('max_extensions') = -1 THEN NULL ELSE COALESCE(sqlc.narg('max_extensions'), max_extensions) END,
+  extension_cooldown = CASE WHEN sqlc.narg('extension_cooldown') = -1 THEN NULL ELSE COALESCE(sqlc.narg('extension_cooldown'), extension_cooldown) END,
+  extension_window = CASE WHEN sqlc.narg('extension_window') = -1 THEN NULL ELSE COALESCE(sqlc.narg('extension_window'), extension_window) END,
+  instance_flag = COALESCE(sqlc.narg('instance_flag'), instance_flag),
+  cpu_threshold = COALESCE(sqlc.narg('cpu_threshold'), cpu_threshold),
+  memory_threshold = COALESCE(sqlc.narg('memory_threshold'), memory_threshold),
+  pids_threshold = COALESCE(sqlc.narg('pids_threshold'), pids_threshold),
+  network_threshold = COALESCE(sqlc.narg('network_threshold'), network_threshold),
+  threshold_duration = COALESCE(sqlc.narg('threshold_duration'), threshold_duration),
+  auto_kill = COALESCE(sqlc.narg('auto_kill'), auto_kill),
+  egress = COALESCE(sqlc.narg('egress'), egress),
+  shared = COALESCE(sqlc.narg('shared'), shared),
+  placement = COALESCE(sqlc.narg('placement'), placement),
+  reserved_memory = COALESCE(sqlc.narg('reserved_memory'), reserved_memory),
+  reserved_cpu = COALESCE(sqlc.narg('reserved_cpu'), reserved_cpu)
 WHERE chall_id = sqlc.arg('chall_id');
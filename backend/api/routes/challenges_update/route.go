@@ -1,9 +1,14 @@
 package challenges_update
 
 import (
+	"context"
+	"encoding/json"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/instancer"
+	"trxd/instancer/infos"
 	"trxd/utils"
+	"trxd/utils/announcements"
 	"trxd/utils/consts"
 	"trxd/validator"
 
@@ -26,13 +31,107 @@ type UpdateChallParams struct {
 	Port        *int32           `json:"port" validate:"omitempty,challenge_port"`
 	ConnType    *sqlc.ConnType   `json:"conn_type" validate:"omitempty,challenge_conn_type"`
 
-	Image      *string `json:"image"`
-	Compose    *string `json:"compose"`
-	HashDomain *bool   `json:"hash_domain"`
-	Lifetime   *int32  `json:"lifetime" validate:"omitempty,challenge_lifetime"`
-	Envs       *string `json:"envs" validate:"omitempty,challenge_envs"`
-	MaxMemory  *int32  `json:"max_memory" validate:"omitempty,challenge_max_memory"`
-	MaxCpu     *string `json:"max_cpu" validate:"omitempty,challenge_max_cpu"`
+	Image             *string            `json:"image"`
+	Compose           *string            `json:"compose"`
+	HashDomain        *bool              `json:"hash_domain"`
+	Lifetime          *int32             `json:"lifetime" validate:"omitempty,challenge_lifetime"`
+	Envs              *string            `json:"envs" validate:"omitempty,challenge_envs"`
+	MaxMemory         *int32             `json:"max_memory" validate:"omitempty,challenge_max_memory"`
+	MaxCpu            *string            `json:"max_cpu" validate:"omitempty,challenge_max_cpu"`
+	TeamOnly          *bool              `json:"team_only"`
+	PowDifficulty     *int32             `json:"pow_difficulty" validate:"omitempty,challenge_pow_difficulty"`
+	MaxLifetime       *int32             `json:"max_lifetime" validate:"omitempty,challenge_max_lifetime"`
+	MaxExtensions     *int32             `json:"max_extensions" validate:"omitempty,challenge_max_extensions"`
+	ExtensionCooldown *int32             `json:"extension_cooldown" validate:"omitempty,challenge_extension_cooldown"`
+	ExtensionWindow   *int32             `json:"extension_window" validate:"omitempty,challenge_extension_window"`
+	InstanceFlag      *bool              `json:"instance_flag"`
+	CpuThreshold      *int32             `json:"cpu_threshold" validate:"omitempty,challenge_cpu_threshold"`
+	MemoryThreshold   *int32             `json:"memory_threshold" validate:"omitempty,challenge_memory_threshold"`
+	PidsThreshold     *int32             `json:"pids_threshold" validate:"omitempty,challenge_pids_threshold"`
+	NetworkThreshold  *int32             `json:"network_threshold" validate:"omitempty,challenge_network_threshold"`
+	ThresholdDuration *int32             `json:"threshold_duration" validate:"omitempty,challenge_threshold_duration"`
+	AutoKill          *bool              `json:"auto_kill"`
+	Egress            *sqlc.EgressPolicy `json:"egress" validate:"omitempty,challenge_egress"`
+	Shared            *bool              `json:"shared"`
+	Placement         *[]string          `json:"placement" validate:"omitempty,challenge_placement"`
+	ReservedMemory    *int32             `json:"reserved_memory" validate:"omitempty,challenge_reserved_memory"`
+	ReservedCpu       *string            `json:"reserved_cpu" validate:"omitempty,challenge_reserved_cpu"`
+}
+
+// orStored returns the updated value if any, the stored one otherwise
+func orStored[T any](value *T, stored T) T {
+	if value != nil {
+		return *value
+	}
+	return stored
+}
+
+// proxySupportsTeamOnly reports whether the built-in proxy serving the
+// connection type of the challenge is enabled when its instances are team
+// only, as they get no Traefik route and would be unreachable otherwise
+func proxySupportsTeamOnly(ctx context.Context, data *UpdateChallParams, challenge *sqlc.Challenge,
+	conf *sqlc.GetDockerConfigsByIDRow) (bool, error) {
+
+	teamOnly := orStored(data.TeamOnly, conf != nil && conf.TeamOnly)
+	if !teamOnly {
+		return true, nil
+	}
+
+	key := "http-proxy-port"
+	if orStored(data.ConnType, challenge.ConnType) == sqlc.ConnTypeTCP {
+		key = "tcp-proxy-port"
+	}
+
+	port, err := db.GetConfig(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	return port != "" && port != "0", nil
+}
+
+// parseEnvs decodes the environment of the challenge, already validated
+func parseEnvs(envs string) map[string]string {
+	var parsed map[string]string
+	_ = json.Unmarshal([]byte(envs), &parsed)
+	return parsed
+}
+
+// sharedUsesFlag reports whether a shared deployment would render per-instance
+// flags: it belongs to no team, so the flag would never be stored
+func sharedUsesFlag(data *UpdateChallParams, conf *sqlc.GetDockerConfigsByIDRow) bool {
+	var stored sqlc.GetDockerConfigsByIDRow
+	if conf != nil {
+		stored = *conf
+	}
+	if !orStored(data.Shared, stored.Shared) {
+		return false
+	}
+	if orStored(data.InstanceFlag, stored.InstanceFlag) {
+		return true
+	}
+
+	for _, value := range parseEnvs(orStored(data.Envs, stored.Envs)) {
+		if infos.UsesFlag(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// staticFlag reports whether the author overrides the per-instance flag with
+// a FLAG variable not rendering it, which no submission could match
+func staticFlag(data *UpdateChallParams, conf *sqlc.GetDockerConfigsByIDRow) bool {
+	var stored sqlc.GetDockerConfigsByIDRow
+	if conf != nil {
+		stored = *conf
+	}
+	if !orStored(data.InstanceFlag, stored.InstanceFlag) {
+		return false
+	}
+
+	flag, ok := parseEnvs(orStored(data.Envs, stored.Envs))[consts.FlagEnv]
+	return ok && !infos.UsesFlag(flag)
 }
 
 func Route(c *fiber.Ctx) error {
@@ -57,6 +156,35 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
+	conf, err := db.GetDockerConfigsByID(c.Context(), challenge.ID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+
+	supported, err := proxySupportsTeamOnly(c.Context(), &data, challenge, conf)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+	}
+	if !supported {
+		return utils.Error(c, fiber.StatusBadRequest, consts.TeamOnlyRequiresProxy)
+	}
+	if sharedUsesFlag(&data, conf) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.SharedInstanceFlag)
+	}
+	if staticFlag(&data, conf) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InstanceFlagNotRendered)
+	}
+
 	err = UpdateChallenge(c.Context(), &data)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -70,5 +198,11 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingChallenge, err)
 	}
 
+	// The challenge configuration may affect its shared deployment
+	instancer.WakeDeployer()
+	if data.Hidden != nil && !*data.Hidden {
+		announcements.Wake()
+	}
+
 	return c.SendStatus(fiber.StatusOK)
 }
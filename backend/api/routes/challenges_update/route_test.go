@@ -109,6 +109,11 @@ var testData = []struct {
 		expectedStatus:   http.StatusBadRequest,
 		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "ConnType", consts.ConnTypesStr)),
 	},
+	{
+		testBody:         JSON{"chall_id": "", "egress": "aaa"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Egress", consts.EgressPoliciesStr)),
+	},
 	{
 		testBody:         JSON{"chall_id": "", "lifetime": -1},
 		expectedStatus:   http.StatusBadRequest,
@@ -179,6 +184,26 @@ var testData = []struct {
 		expectedStatus:   http.StatusNotFound,
 		expectedResponse: errorf(consts.CategoryNotFound),
 	},
+	{
+		testBody:         JSON{"chall_id": "", "conn_type": "HTTP", "team_only": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.TeamOnlyRequiresProxy),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "shared": true, "envs": `{"FLAG": "{{.Flag}}"}`},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.SharedInstanceFlag),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "shared": true, "instance_flag": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.SharedInstanceFlag),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "instance_flag": true, "envs": `{"FLAG": "flag{static}"}`},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InstanceFlagNotRendered),
+	},
 	{
 		testBody: JSON{
 			"chall_id":    "",
@@ -195,13 +220,31 @@ var testData = []struct {
 			"host":        "http://ctf.theromanxpl0.it",
 			"port":        1234,
 
-			"image":       "ubuntu:latest",
-			"compose":     "",
-			"hash_domain": true,
-			"lifetime":    60,
-			"envs":        `{"key": "value"}`,
-			"max_memory":  512,
-			"max_cpu":     "1.0",
+			"image":              "ubuntu:latest",
+			"compose":            "",
+			"hash_domain":        true,
+			"lifetime":           60,
+			"envs":               `{"key": "value"}`,
+			"max_memory":         512,
+			"max_cpu":            "1.0",
+			"team_only":          true,
+			"pow_difficulty":     8,
+			"max_lifetime":       3600,
+			"max_extensions":     3,
+			"extension_cooldown": 60,
+			"extension_window":   300,
+			"instance_flag":      false,
+			"cpu_threshold":      150,
+			"memory_threshold":   256,
+			"pids_threshold":     100,
+			"network_threshold":  1024,
+			"threshold_duration": 60,
+			"auto_kill":          true,
+			"egress":             "None",
+			"shared":             true,
+			"placement":          []string{"node.labels.zone==eu"},
+			"reserved_memory":    256,
+			"reserved_cpu":       "0.5",
 		},
 		expectedStatus: http.StatusOK,
 	},
@@ -211,7 +254,7 @@ func TestRoute(t *testing.T) {
 	app := api.SetupApp(t.Context())
 	defer api.Shutdown(app)
 
-	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
 
 	var challID int32
 	session := test_utils.NewApiTestSession(t, app)
@@ -221,11 +264,22 @@ func TestRoute(t *testing.T) {
 	chall := test_utils.TryCreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
 	if chall != nil {
 		challID = chall.ID
+		test_utils.AddChallengeOwner(t, challID, author.ID, sqlc.OwnerRoleOwner)
+	}
+	owners := []JSON{
+		{
+			"id":   author.ID,
+			"name": "author",
+			"role": "Owner",
+		},
 	}
 
 	session.Patch("/challenges", nil, http.StatusBadRequest)
 	session.CheckResponse(errorf(consts.InvalidJSON))
 
+	// Team only TCP instances are served by the TLS proxy, the HTTP one is disabled
+	test_utils.UpdateConfig(t, "tcp-proxy-port", "4443")
+
 	for i, test := range testData {
 		session := test_utils.NewApiTestSession(t, app)
 		session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
@@ -252,7 +306,7 @@ func TestRoute(t *testing.T) {
 				"hidden":      test.testBody["hidden"],
 				"host":        test.testBody["host"],
 				"id":          challID,
-				"instance":    test.testBody["type"] != "Normal",
+				"instance":    test.testBody["type"] != "Normal" && test.testBody["shared"] != true,
 				"max_points":  test.testBody["max_points"],
 				"name":        test.testBody["name"],
 				"points":      test.testBody["max_points"],
@@ -276,15 +330,46 @@ func TestRoute(t *testing.T) {
 			body = session.Body()
 			expected = JSON{
 				"docker_config": JSON{
-					"compose":     test.testBody["compose"],
-					"envs":        test.testBody["envs"],
-					"hash_domain": test.testBody["hash_domain"],
-					"image":       test.testBody["image"],
-					"lifetime":    test.testBody["lifetime"],
-					"max_cpu":     test.testBody["max_cpu"],
-					"max_memory":  test.testBody["max_memory"],
+					"compose":            test.testBody["compose"],
+					"envs":               test.testBody["envs"],
+					"hash_domain":        test.testBody["hash_domain"],
+					"image":              test.testBody["image"],
+					"lifetime":           test.testBody["lifetime"],
+					"max_cpu":            test.testBody["max_cpu"],
+					"max_memory":         test.testBody["max_memory"],
+					"team_only":          test.testBody["team_only"],
+					"pow_difficulty":     test.testBody["pow_difficulty"],
+					"max_lifetime":       test.testBody["max_lifetime"],
+					"max_extensions":     test.testBody["max_extensions"],
+					"extension_cooldown": test.testBody["extension_cooldown"],
+					"extension_window":   test.testBody["extension_window"],
+					"instance_flag":      test.testBody["instance_flag"],
+					"cpu_threshold":      test.testBody["cpu_threshold"],
+					"memory_threshold":   test.testBody["memory_threshold"],
+					"pids_threshold":     test.testBody["pids_threshold"],
+					"network_threshold":  test.testBody["network_threshold"],
+					"threshold_duration": test.testBody["threshold_duration"],
+					"auto_kill":          test.testBody["auto_kill"],
+					"egress":             test.testBody["egress"],
+					"shared":             test.testBody["shared"],
+					"placement":          test.testBody["placement"],
+					"reserved_memory":    test.testBody["reserved_memory"],
+					"reserved_cpu":       test.testBody["reserved_cpu"],
+				},
+				"deployment": JSON{
+					"error":    "",
+					"restarts": 0,
+					"status":   "Pending",
+				},
+				"feedback": []string{},
+				"flags":    []string{},
+				"rating": JSON{
+					"difficulty":       nil,
+					"difficulty_votes": 0,
+					"dislikes":         0,
+					"likes":            0,
 				},
-				"flags":       []string{},
+				"owners":      owners,
 				"solves_list": []string{},
 				"type":        test.testBody["type"],
 			}
@@ -304,13 +389,31 @@ func TestRoute(t *testing.T) {
 		"host":        "",
 		"port":        0,
 
-		"image":       "",
-		"compose":     "",
-		"hash_domain": false,
-		"lifetime":    0,
-		"envs":        "",
-		"max_memory":  0,
-		"max_cpu":     "",
+		"image":              "",
+		"compose":            "",
+		"hash_domain":        false,
+		"lifetime":           0,
+		"envs":               "",
+		"max_memory":         0,
+		"max_cpu":            "",
+		"team_only":          false,
+		"pow_difficulty":     0,
+		"max_lifetime":       0,
+		"max_extensions":     0,
+		"extension_cooldown": 0,
+		"extension_window":   0,
+		"instance_flag":      false,
+		"cpu_threshold":      0,
+		"memory_threshold":   0,
+		"pids_threshold":     0,
+		"network_threshold":  0,
+		"threshold_duration": 0,
+		"auto_kill":          false,
+		"egress":             "Full",
+		"shared":             false,
+		"placement":          []string{},
+		"reserved_memory":    0,
+		"reserved_cpu":       "",
 	}
 
 	session = test_utils.NewApiTestSession(t, app)
@@ -354,17 +457,55 @@ func TestRoute(t *testing.T) {
 	body = session.Body()
 	expected = JSON{
 		"docker_config": JSON{
-			"compose":     testBody["compose"],
-			"envs":        testBody["envs"],
-			"hash_domain": testBody["hash_domain"],
-			"image":       testBody["image"],
-			"lifetime":    testBody["lifetime"],
-			"max_cpu":     testBody["max_cpu"],
-			"max_memory":  testBody["max_memory"],
+			"compose":            testBody["compose"],
+			"envs":               testBody["envs"],
+			"hash_domain":        testBody["hash_domain"],
+			"image":              testBody["image"],
+			"lifetime":           testBody["lifetime"],
+			"max_cpu":            testBody["max_cpu"],
+			"max_memory":         testBody["max_memory"],
+			"team_only":          testBody["team_only"],
+			"pow_difficulty":     testBody["pow_difficulty"],
+			"max_lifetime":       testBody["max_lifetime"],
+			"max_extensions":     testBody["max_extensions"],
+			"extension_cooldown": testBody["extension_cooldown"],
+			"extension_window":   testBody["extension_window"],
+			"instance_flag":      testBody["instance_flag"],
+			"cpu_threshold":      testBody["cpu_threshold"],
+			"memory_threshold":   testBody["memory_threshold"],
+			"pids_threshold":     testBody["pids_threshold"],
+			"network_threshold":  testBody["network_threshold"],
+			"threshold_duration": testBody["threshold_duration"],
+			"auto_kill":          testBody["auto_kill"],
+			"egress":             testBody["egress"],
+			"shared":             testBody["shared"],
+			"placement":          testBody["placement"],
+			"reserved_memory":    testBody["reserved_memory"],
+			"reserved_cpu":       testBody["reserved_cpu"],
 		},
-		"flags":       []string{},
+		"feedback": []string{},
+		"flags":    []string{},
+		"rating": JSON{
+			"difficulty":       nil,
+			"difficulty_votes": 0,
+			"dislikes":         0,
+			"likes":            0,
+		},
+		"owners":      owners,
 		"solves_list": []string{},
 		"type":        "Container",
 	}
 	test_utils.Compare(t, expected, body)
+
+	// 0 disables an extension limit, -1 goes back to the global one
+	session.Patch("/challenges", JSON{"chall_id": challID, "max_lifetime": -1, "max_extensions": -1}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Get(fmt.Sprintf("/challenges/%d", challID), nil, http.StatusOK)
+	dockerConfig := Json(Json(session.Body())["docker_config"])
+	if dockerConfig["max_lifetime"] != nil || dockerConfig["max_extensions"] != nil {
+		t.Errorf("Expected the global extension limits, got %v %v", dockerConfig["max_lifetime"], dockerConfig["max_extensions"])
+	}
+	if dockerConfig["extension_cooldown"] != 0.0 || dockerConfig["extension_window"] != 0.0 {
+		t.Errorf("Expected no extension limits, got %v %v", dockerConfig["extension_cooldown"], dockerConfig["extension_window"])
+	}
 }
@@ -11,11 +11,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	Key   string  `json:"key" validate:"required"`
+	Value *string `json:"value" validate:"required"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		Key   string  `json:"key" validate:"required"`
-		Value *string `json:"value" validate:"required"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -0,0 +1,26 @@
+package email_templates_delete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// DeleteEmailTemplate restores the default template, returning false if it
+// was not edited
+func DeleteEmailTemplate(ctx context.Context, name sqlc.EmailTemplateName, locale string) (bool, error) {
+	_, err := db.Sql.DeleteEmailTemplate(ctx, sqlc.DeleteEmailTemplateParams{
+		Name:   name,
+		Locale: locale,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
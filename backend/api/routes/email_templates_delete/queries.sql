@@ -0,0 +1,3 @@
+-- name: DeleteEmailTemplate :one
+-- Delete the override of an email template, restoring the default one
+DELETE FROM email_templates WHERE name = $1 AND locale = $2 RETURNING name;
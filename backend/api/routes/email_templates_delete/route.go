@@ -0,0 +1,37 @@
+package email_templates_delete
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	Name   string `json:"name" validate:"required,email_template"`
+	Locale string `json:"locale" validate:"required,locale"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	deleted, err := DeleteEmailTemplate(c.Context(), sqlc.EmailTemplateName(data.Name), data.Locale)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingEmailTemplate, err)
+	}
+	if !deleted {
+		return utils.Error(c, fiber.StatusNotFound, consts.EmailTemplateNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,69 @@
+package email_templates_delete_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"name": "PasswordReset"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"name": "PasswordReset", "locale": "it"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.EmailTemplateNotFound),
+	},
+	{
+		testBody:       JSON{"name": "PasswordReset", "locale": "en"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"name": "PasswordReset", "locale": "en"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.EmailTemplateNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Patch("/email-templates", JSON{"name": "PasswordReset", "locale": "en", "subject": "Reset", "html": "{{.Link}}", "text": "{{.Link}}"}, http.StatusOK)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		session.Delete("/email-templates", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+}
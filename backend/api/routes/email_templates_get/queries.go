@@ -0,0 +1,36 @@
+package email_templates_get
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/email"
+)
+
+type EmailTemplate struct {
+	sqlc.EmailTemplate
+	Custom bool `json:"custom"` // Edited by the admins, the default one otherwise
+}
+
+// GetEmailTemplates returns the template of every name and locale, the edited
+// ones replacing the defaults
+func GetEmailTemplates(ctx context.Context) ([]EmailTemplate, error) {
+	overrides, err := db.Sql.GetEmailTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := email.DefaultTemplates()
+	templates := make([]EmailTemplate, len(defaults))
+	for i, tmpl := range defaults {
+		templates[i].EmailTemplate = tmpl
+		for _, override := range overrides {
+			if override.Name == tmpl.Name && override.Locale == tmpl.Locale {
+				templates[i].EmailTemplate = override
+				templates[i].Custom = true
+			}
+		}
+	}
+
+	return templates, nil
+}
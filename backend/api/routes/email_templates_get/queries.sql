@@ -0,0 +1,3 @@
+-- name: GetEmailTemplates :many
+-- Retrieve all the overrides of the email templates
+SELECT * FROM email_templates ORDER BY name, locale;
@@ -0,0 +1,17 @@
+package email_templates_get
+
+import (
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	templates, err := GetEmailTemplates(c.Context())
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingEmailTemplates, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(templates)
+}
@@ -0,0 +1,60 @@
+package email_templates_get_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/email"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+
+	expected := []JSON{}
+	for _, tmpl := range email.DefaultTemplates() {
+		expected = append(expected, JSON{
+			"name":    tmpl.Name,
+			"locale":  tmpl.Locale,
+			"subject": tmpl.Subject,
+			"html":    tmpl.Html,
+			"text":    tmpl.Text,
+			"custom":  false,
+		})
+	}
+	if len(expected) != len(consts.EmailTemplateNamesStr)*len(consts.Locales) {
+		t.Fatalf("Expected a default template for every name and locale, got %d", len(expected))
+	}
+	session.Get("/email-templates", nil, http.StatusOK)
+	session.CheckResponse(expected)
+
+	session.Patch("/email-templates", JSON{"name": "Verification", "locale": "it", "subject": "Verifica", "html": "{{.Link}}", "text": "{{.Link}}"}, http.StatusOK)
+	for _, tmpl := range expected {
+		if tmpl["name"] == sqlc.EmailTemplateNameVerification && tmpl["locale"] == "it" {
+			tmpl["subject"] = "Verifica"
+			tmpl["html"] = "{{.Link}}"
+			tmpl["text"] = "{{.Link}}"
+			tmpl["custom"] = true
+		}
+	}
+	session.Get("/email-templates", nil, http.StatusOK)
+	session.CheckResponse(expected)
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Get("/email-templates", nil, http.StatusUnauthorized)
+	session.CheckResponse(JSON{"error": consts.Unauthorized})
+}
@@ -0,0 +1,22 @@
+package email_templates_update
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func UpdateEmailTemplate(ctx context.Context, tmpl *sqlc.EmailTemplate) error {
+	err := db.Sql.UpsertEmailTemplate(ctx, sqlc.UpsertEmailTemplateParams{
+		Name:    tmpl.Name,
+		Locale:  tmpl.Locale,
+		Subject: tmpl.Subject,
+		Html:    tmpl.Html,
+		Text:    tmpl.Text,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+-- name: UpsertEmailTemplate :exec
+-- Insert or replace the override of an email template
+INSERT INTO email_templates (name, locale, subject, html, text)
+  VALUES ($1, $2, $3, $4, $5)
+  ON CONFLICT (name, locale) DO UPDATE SET
+    subject = EXCLUDED.subject,
+    html = EXCLUDED.html,
+    text = EXCLUDED.text;
@@ -0,0 +1,49 @@
+package email_templates_update
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/email"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	Name    string `json:"name" validate:"required,email_template"`
+	Locale  string `json:"locale" validate:"required,locale"`
+	Subject string `json:"subject" validate:"required"`
+	Html    string `json:"html" validate:"required"`
+	Text    string `json:"text" validate:"required"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	tmpl := sqlc.EmailTemplate{
+		Name:    sqlc.EmailTemplateName(data.Name),
+		Locale:  data.Locale,
+		Subject: data.Subject,
+		Html:    data.Html,
+		Text:    data.Text,
+	}
+	if !email.ValidTemplate(&tmpl) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidTemplate)
+	}
+
+	err = UpdateEmailTemplate(c.Context(), &tmpl)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingEmailTemplate, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
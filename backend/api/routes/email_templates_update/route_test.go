@@ -0,0 +1,113 @@
+package email_templates_update_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"name": "Verification", "locale": "en", "subject": "Verify"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"name": "Welcome", "locale": "en", "subject": "Verify", "html": "{{.Link}}", "text": "{{.Link}}"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Name", strings.Join(consts.EmailTemplateNamesStr, " "))),
+	},
+	{
+		testBody:         JSON{"name": "Verification", "locale": "xx", "subject": "Verify", "html": "{{.Link}}", "text": "{{.Link}}"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Locale", strings.Join(consts.Locales, " "))),
+	},
+	{
+		testBody:         JSON{"name": "Verification", "locale": "en", "subject": "Verify", "html": "{{.Link}", "text": "{{.Link}}"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidTemplate),
+	},
+	{
+		testBody:         JSON{"name": "Verification", "locale": "en", "subject": "Verify", "html": "{{.Link}}", "text": "{{.Token}}"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidTemplate),
+	},
+	{
+		testBody:       JSON{"name": "Verification", "locale": "en", "subject": "Verify", "html": "<a href=\"{{.Link}}\">Verify</a>", "text": "{{.Link}}"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"name": "Verification", "locale": "en", "subject": "Verify now", "html": "<a href=\"{{.Link}}\">Verify</a>", "text": "{{.Link}}"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"name": "Announcement", "locale": "it", "subject": "{{.Title}}", "html": "{{.Body}}", "text": "{{.Body}}"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		session.Patch("/email-templates", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+
+		if test.expectedStatus != http.StatusOK {
+			continue
+		}
+
+		body := test.testBody.(JSON)
+		session.Get("/email-templates", nil, http.StatusOK)
+		found := false
+		for _, itemInt := range List(session.Body()) {
+			item := Json(itemInt)
+			if item["name"] != body["name"] || item["locale"] != body["locale"] {
+				continue
+			}
+			found = true
+			if item["subject"] != body["subject"] || item["custom"] != true {
+				t.Fatalf("Template %s (%s) was not updated: %v", body["name"], body["locale"], item)
+			}
+		}
+		if !found {
+			t.Fatalf("Template %s (%s) not found", body["name"], body["locale"])
+		}
+	}
+}
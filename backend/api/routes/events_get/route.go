@@ -0,0 +1,58 @@
+package events_get
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/events"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Interval of the comments keeping the stream open through the proxies, and
+// detecting the clients gone
+const keepAlive = 30 * time.Second
+
+// Route streams the live events as server-sent events, each of them a JSON
+// object with its type and data
+func Route(c *fiber.Ctx) error {
+	msgs, unsubscribe, err := events.Subscribe(c.Context())
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSubscribingEvents, err)
+	}
+	closing := events.Closing()
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+		for {
+			if w.Flush() != nil {
+				return
+			}
+
+			select {
+			case <-closing:
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(w, "data: %s\n\n", msg)
+			case <-ticker.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+		}
+	})
+
+	return nil
+}
@@ -2,6 +2,7 @@ package flags_create
 
 import (
 	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -9,12 +10,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+	Flag    string `json:"flag" validate:"required,flag"`
+	Regex   bool   `json:"regex"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-		Flag    string `json:"flag" validate:"required,flag"`
-		Regex   bool   `json:"regex"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -32,6 +35,16 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
 	flag, err := CreateFlag(c.Context(), *data.ChallID, data.Flag, data.Regex)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingFlag, err)
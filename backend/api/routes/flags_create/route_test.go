@@ -74,11 +74,12 @@ func TestRoute(t *testing.T) {
 	app := api.SetupApp(t.Context())
 	defer api.Shutdown(app)
 
-	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAuthor)
+	user := test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAuthor)
 	session := test_utils.NewApiTestSession(t, app)
 	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
 	session.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
 	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.AddChallengeOwner(t, chall.ID, user.ID, sqlc.OwnerRoleOwner)
 
 	for _, test := range testData {
 		session := test_utils.NewApiTestSession(t, app)
@@ -91,4 +92,11 @@ func TestRoute(t *testing.T) {
 		session.Post("/flags", test.testBody, test.expectedStatus)
 		session.CheckResponse(test.expectedResponse)
 	}
+
+	// Only the owners and the admins manage the flags of a challenge
+	test_utils.RegisterUser(t, "other", "other@test.test", "testpass", sqlc.UserRoleAuthor)
+	other := test_utils.NewApiTestSession(t, app)
+	other.Post("/login", JSON{"email": "other@test.test", "password": "testpass"}, http.StatusOK)
+	other.Post("/flags", JSON{"chall_id": chall.ID, "flag": "flag{other}"}, http.StatusForbidden)
+	other.CheckResponse(errorf(consts.NotChallengeOwner))
 }
@@ -104,7 +104,7 @@ func TestRoute(t *testing.T) {
 	app := api.SetupApp(t.Context())
 	defer api.Shutdown(app)
 
-	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAuthor)
+	user := test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAuthor)
 	session := test_utils.NewApiTestSession(t, app)
 	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
 	session.Get("/challenges", nil, http.StatusOK)
@@ -117,6 +117,7 @@ func TestRoute(t *testing.T) {
 			break
 		}
 	}
+	test_utils.AddChallengeOwner(t, challID, user.ID, sqlc.OwnerRoleCoOwner)
 
 	for _, test := range testData {
 		session := test_utils.NewApiTestSession(t, app)
@@ -6,6 +6,7 @@ import (
 	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/instancer"
+	"trxd/proxy"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -17,6 +18,7 @@ type InstanceInfo struct {
 	Host    string `json:"host"`
 	Port    *int32 `json:"port,omitempty"`
 	Timeout int    `json:"timeout"`
+	Token   string `json:"token,omitempty"`
 }
 
 func createInstance(c *fiber.Ctx, tid int32, chall *db.Chall) (*InstanceInfo, error) {
@@ -42,28 +44,67 @@ func createInstance(c *fiber.Ctx, tid int32, chall *db.Chall) (*InstanceInfo, er
 			return nil, utils.Error(c, fiber.StatusConflict, consts.AlreadyAnActiveInstance)
 		case "[no image or compose]":
 			return nil, utils.Error(c, fiber.StatusBadRequest, consts.InvalidImage)
+		case "[no node available]":
+			return nil, utils.Error(c, fiber.StatusServiceUnavailable, consts.NoNodeAvailable)
 		default:
 			return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingInstance, err)
 		}
 	}
 
+	token, err := proxy.AccessToken(c.Context(), res.Host, res.Port, tid, res.TeamOnly, res.Expiration)
+	if err != nil {
+		return nil, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingInstance, err)
+	}
+
 	return &InstanceInfo{
 		Host:    res.Host,
 		Port:    res.Port,
 		Timeout: max(int(time.Until(res.Expiration).Seconds()), 0),
+		Token:   token,
 	}, nil
 }
 
+func checkPow(c *fiber.Ctx, tid int32, chall *db.Chall, nonce *string, solution *string) (bool, error) {
+	if chall.DockerConfig == nil {
+		return true, nil
+	}
+
+	base, _ := chall.DockerConfig.PowDifficulty.(int64)
+	difficulty, err := instancer.PowDifficulty(c.Context(), int(base))
+	if err != nil {
+		return false, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingProofOfWork, err)
+	}
+	if difficulty == 0 {
+		return true, nil
+	}
+	if nonce == nil || solution == nil {
+		return false, utils.Error(c, fiber.StatusForbidden, consts.MissingProofOfWork)
+	}
+
+	valid, err := instancer.VerifyPow(c.Context(), tid, chall.Info.ID, *nonce, *solution)
+	if err != nil {
+		return false, utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingProofOfWork, err)
+	}
+	if !valid {
+		return false, utils.Error(c, fiber.StatusForbidden, consts.InvalidProofOfWork)
+	}
+
+	return true, nil
+}
+
+type Data struct {
+	ChallID     *int32  `json:"chall_id" validate:"required,id"`
+	PowNonce    *string `json:"pow_nonce" validate:"omitempty,pow_solution"`
+	PowSolution *string `json:"pow_solution" validate:"omitempty,pow_solution"`
+}
+
 func Route(c *fiber.Ctx) error {
-	role := c.Locals("role").(sqlc.UserRole)
 	tid := c.Locals("tid").(int32)
 	if tid == -1 {
 		return utils.Error(c, fiber.StatusForbidden, consts.TeamNotFound)
 	}
 
-	var data struct {
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -81,11 +122,11 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
-	if chall.Info.Hidden && !utils.In(role,
-		[]sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin}) {
+	if chall.Info.Hidden && !utils.Can(c, consts.PermChallengesRead) {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
-	if chall.Info.Type == sqlc.DeployTypeNormal {
+	// Shared challenges are deployed once by the platform for everyone
+	if chall.Info.Type == sqlc.DeployTypeNormal || (chall.DockerConfig != nil && chall.DockerConfig.Shared) {
 		return utils.Error(c, fiber.StatusBadRequest, consts.ChallengeNotInstanciable)
 	}
 
@@ -97,6 +138,11 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusConflict, consts.AlreadyAnActiveInstance)
 	}
 
+	valid, err = checkPow(c, tid, chall, data.PowNonce, data.PowSolution)
+	if err != nil || !valid {
+		return err
+	}
+
 	info, err := createInstance(c, tid, chall)
 	if err != nil || info == nil {
 		return err
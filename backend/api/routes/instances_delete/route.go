@@ -11,11 +11,23 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	TeamID  *int32 `json:"team_id" validate:"omitnil,id"`
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+}
+
+// Route deletes the instance of the team
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		TeamID  *int32 `json:"team_id" validate:"omitnil,id"`
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-	}
+	return remove(c, false)
+}
+
+// Manage deletes the instance of any team, for the staff managing the instances
+func Manage(c *fiber.Ctx) error {
+	return remove(c, true)
+}
+
+func remove(c *fiber.Ctx, manage bool) error {
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -33,9 +45,8 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
-	role := c.Locals("role").(sqlc.UserRole)
-	if chall.Info.Hidden && !utils.In(role,
-		[]sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin}) {
+	// The staff managing the instances reach the ones of hidden challenges too
+	if chall.Info.Hidden && !manage && !utils.Can(c, consts.PermChallengesRead) {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 	if chall.Info.Type == sqlc.DeployTypeNormal {
@@ -43,7 +54,7 @@ func Route(c *fiber.Ctx) error {
 	}
 
 	var tid int32
-	if role == sqlc.UserRoleAdmin && data.TeamID != nil {
+	if manage && data.TeamID != nil {
 		tid = *data.TeamID
 	} else {
 		tid = c.Locals("tid").(int32)
@@ -132,4 +132,32 @@ func TestRoute(t *testing.T) {
 	author.CheckResponse(errorf(consts.InstanceNotFound))
 	admin.Delete("/instances", JSON{"chall_id": challID4, "team_id": tid}, http.StatusOK)
 	admin.CheckResponse(nil)
+
+	// The staff managing the instances stop them without a team
+	infra := test_utils.RegisterUser(t, "infra", "infra@test.test", "infrapass", sqlc.UserRoleSpectator)
+	admin.Post("/roles", JSON{"name": "infra", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+	admin.Patch("/users/custom-role", JSON{"user_id": infra.ID, "custom_role": "infra"}, http.StatusOK)
+
+	staff := test_utils.NewApiTestSession(t, app)
+	staff.Post("/login", JSON{"email": "infra@test.test", "password": "infrapass"}, http.StatusOK)
+	staff.CheckResponse(nil)
+
+	session.Post("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	staff.Delete("/instances", JSON{"chall_id": challID3, "team_id": tid}, http.StatusForbidden)
+	staff.CheckResponse(errorf(consts.Forbidden))
+	session.Delete("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+	staff.Delete("/instances/manage", JSON{"chall_id": challID3}, http.StatusForbidden)
+	staff.CheckResponse(errorf(consts.TeamNotFound))
+	staff.Delete("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusOK)
+	staff.CheckResponse(nil)
+
+	// Hidden challenges don't stop the staff without challenges.read
+	session.Post("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	admin.Patch("/challenges/hidden", JSON{"chall_ids": []int32{challID3}}, http.StatusOK)
+	session.Delete("/instances", JSON{"chall_id": challID3}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.ChallengeNotFound))
+	staff.Delete("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusOK)
+	staff.CheckResponse(nil)
+	admin.Patch("/challenges/hidden", JSON{"chall_ids": []int32{challID3}}, http.StatusOK)
 }
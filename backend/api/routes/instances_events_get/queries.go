@@ -0,0 +1,52 @@
+package instances_events_get
+
+import (
+	"context"
+	"database/sql"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Filters struct {
+	TeamID  int32
+	ChallID int32
+	Type    sqlc.InstanceEventType
+	Owner   int32
+}
+
+func GetInstanceEvents(ctx context.Context, filters *Filters, offset int32, limit int32) (int64, []sqlc.GetInstanceEventsRow, error) {
+	teamID := sql.NullInt32{Int32: filters.TeamID, Valid: filters.TeamID != 0}
+	challID := sql.NullInt32{Int32: filters.ChallID, Valid: filters.ChallID != 0}
+	eventType := sqlc.NullInstanceEventType{InstanceEventType: filters.Type, Valid: filters.Type != ""}
+	owner := sql.NullInt32{Int32: filters.Owner, Valid: filters.Owner != 0}
+
+	total, err := db.Sql.GetTotalInstanceEvents(ctx, sqlc.GetTotalInstanceEventsParams{
+		TeamID:  teamID,
+		ChallID: challID,
+		Type:    eventType,
+		Owner:   owner,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	events, err := db.Sql.GetInstanceEvents(ctx, sqlc.GetInstanceEventsParams{
+		TeamID:  teamID,
+		ChallID: challID,
+		Type:    eventType,
+		Owner:   owner,
+		Offset:  offset,
+		Limit:   sql.NullInt32{Int32: limit, Valid: limit != 0},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return total, []sqlc.GetInstanceEventsRow{}, nil
+		}
+		return 0, nil, err
+	}
+	if events == nil {
+		events = []sqlc.GetInstanceEventsRow{}
+	}
+
+	return total, events, nil
+}
@@ -0,0 +1,34 @@
+-- name: GetTotalInstanceEvents :one
+-- Counts the instance events matching the filters
+SELECT COUNT(*)
+  FROM instance_events e
+  LEFT JOIN challenges c ON e.chall_id = c.id
+  WHERE (sqlc.narg('team_id')::INTEGER IS NULL OR e.team_id = sqlc.narg('team_id'))
+    AND (sqlc.narg('chall_id')::INTEGER IS NULL OR e.chall_id = sqlc.narg('chall_id'))
+    AND (sqlc.narg('type')::instance_event_type IS NULL OR e.type = sqlc.narg('type'))
+    AND (sqlc.narg('owner')::INTEGER IS NULL OR e.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = sqlc.narg('owner')));
+
+-- name: GetInstanceEvents :many
+-- Fetches the instance events matching the filters, with pagination
+SELECT
+    e.id,
+    COALESCE(e.team_id, 0) AS team_id,
+    COALESCE(t.name, '') AS team_name,
+    COALESCE(e.chall_id, 0) AS chall_id,
+    COALESCE(c.name, '') AS chall_name,
+    e.type,
+    e.duration,
+    e.host,
+    COALESCE(e.port, 0) AS port,
+    e.error,
+    e.timestamp
+  FROM instance_events e
+  LEFT JOIN teams t ON e.team_id = t.id
+  LEFT JOIN challenges c ON e.chall_id = c.id
+  WHERE (sqlc.narg('team_id')::INTEGER IS NULL OR e.team_id = sqlc.narg('team_id'))
+    AND (sqlc.narg('chall_id')::INTEGER IS NULL OR e.chall_id = sqlc.narg('chall_id'))
+    AND (sqlc.narg('type')::instance_event_type IS NULL OR e.type = sqlc.narg('type'))
+    AND (sqlc.narg('owner')::INTEGER IS NULL OR e.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = sqlc.narg('owner')))
+  ORDER BY e.id DESC
+  OFFSET sqlc.arg('offset')
+  LIMIT sqlc.narg('limit');
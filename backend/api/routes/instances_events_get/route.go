@@ -0,0 +1,75 @@
+package instances_events_get
+
+import (
+	"math"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var eventTypes = []sqlc.InstanceEventType{
+	sqlc.InstanceEventTypeCreate,
+	sqlc.InstanceEventTypeExtend,
+	sqlc.InstanceEventTypeDelete,
+	sqlc.InstanceEventTypeExpire,
+	sqlc.InstanceEventTypeKill,
+	sqlc.InstanceEventTypeFailure,
+}
+
+type Response struct {
+	Total  int64                       `json:"total"`
+	Events []sqlc.GetInstanceEventsRow `json:"events"`
+}
+
+func Route(c *fiber.Ctx) error {
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 || offset > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 || limit > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	teamID := c.QueryInt("team_id", 0)
+	if teamID < 0 || teamID > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	challID := c.QueryInt("chall_id", 0)
+	if challID < 0 || challID > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	eventType := sqlc.InstanceEventType(c.Query("type"))
+	if eventType != "" && !utils.In(eventType, eventTypes) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	filters := &Filters{
+		TeamID:  int32(teamID),
+		ChallID: int32(challID),
+		Type:    eventType,
+	}
+
+	// Authors only see the history of their own challenges
+	if role == sqlc.UserRoleAuthor {
+		filters.Owner = uid
+	}
+
+	total, events, err := GetInstanceEvents(c.Context(), filters, int32(offset), int32(limit))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingInstanceEvents, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Total:  total,
+		Events: events,
+	})
+}
@@ -0,0 +1,146 @@
+package instances_events_get_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func Int32(val any) int32 {
+	return int32(val.(float64))
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func event(challID int32, challName string, eventType string) JSON {
+	return JSON{
+		"chall_id":   challID,
+		"chall_name": challName,
+		"error":      "",
+		"port":       0,
+		"team_name":  "test-team",
+		"type":       eventType,
+	}
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	author1 := test_utils.RegisterUser(t, "author1", "author1@test.test", "testpass", sqlc.UserRoleAuthor)
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.CheckResponse(nil)
+
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author1@test.test", "password": "testpass"}, http.StatusOK)
+	author.CheckResponse(nil)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "testpass"}, http.StatusOK)
+	session.Get("/challenges", nil, http.StatusOK)
+	body := session.Body()
+
+	var challID3, challID4 int32
+	for _, chall := range List(body) {
+		switch Json(chall)["name"] {
+		case "chall-3":
+			challID3 = Int32(Json(chall)["id"])
+		case "chall-4":
+			challID4 = Int32(Json(chall)["id"])
+		}
+	}
+	test_utils.AddChallengeOwner(t, challID3, author1.ID, sqlc.OwnerRoleOwner)
+
+	session.Get("/instances/events", nil, http.StatusForbidden)
+
+	admin.Get("/instances/events", nil, http.StatusOK)
+	admin.CheckResponse(JSON{"total": 0, "events": []JSON{}})
+
+	admin.Get("/instances/events?type=aaa", nil, http.StatusBadRequest)
+	admin.CheckResponse(errorf(consts.InvalidParam))
+	admin.Get("/instances/events?chall_id=-1", nil, http.StatusBadRequest)
+	admin.CheckResponse(errorf(consts.InvalidParam))
+
+	session.Post("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	session.Delete("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	session.Post("/instances", JSON{"chall_id": challID4}, http.StatusOK)
+
+	admin.Get("/instances/events", nil, http.StatusOK)
+	admin.CheckFilteredResponse(JSON{
+		"total": 4,
+		"events": []JSON{
+			event(challID4, "chall-4", "Create"),
+			event(challID3, "chall-3", "Delete"),
+			event(challID3, "chall-3", "Extend"),
+			event(challID3, "chall-3", "Create"),
+		},
+	}, "id", "team_id", "host", "duration", "timestamp")
+
+	admin.Get("/instances/events?type=Create&limit=1", nil, http.StatusOK)
+	admin.CheckFilteredResponse(JSON{
+		"total": 2,
+		"events": []JSON{
+			event(challID4, "chall-4", "Create"),
+		},
+	}, "id", "team_id", "host", "duration", "timestamp")
+
+	admin.Get(fmt.Sprintf("/instances/events?chall_id=%d&type=Delete", challID3), nil, http.StatusOK)
+	admin.CheckFilteredResponse(JSON{
+		"total": 1,
+		"events": []JSON{
+			event(challID3, "chall-3", "Delete"),
+		},
+	}, "id", "team_id", "host", "duration", "timestamp")
+
+	// author1 only owns chall-3
+	author.Get("/instances/events", nil, http.StatusOK)
+	author.CheckFilteredResponse(JSON{
+		"total": 3,
+		"events": []JSON{
+			event(challID3, "chall-3", "Delete"),
+			event(challID3, "chall-3", "Extend"),
+			event(challID3, "chall-3", "Create"),
+		},
+	}, "id", "team_id", "host", "duration", "timestamp")
+
+	author.Get(fmt.Sprintf("/instances/events?chall_id=%d", challID4), nil, http.StatusOK)
+	author.CheckResponse(JSON{"total": 0, "events": []JSON{}})
+
+	session.Delete("/instances", JSON{"chall_id": challID4}, http.StatusOK)
+
+	// The history outlives the challenge
+	admin.Delete("/challenges", JSON{"chall_id": challID4}, http.StatusOK)
+	admin.Get("/instances/events?type=Delete", nil, http.StatusOK)
+	admin.CheckFilteredResponse(JSON{
+		"total": 2,
+		"events": []JSON{
+			event(0, "", "Delete"),
+			event(challID3, "chall-3", "Delete"),
+		},
+	}, "id", "team_id", "host", "duration", "timestamp")
+}
@@ -0,0 +1,79 @@
+package instances_pow
+
+import (
+	"errors"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/instancer"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PowInfo struct {
+	Nonce      string `json:"nonce,omitempty"`
+	Difficulty int    `json:"difficulty"`
+	Timeout    int    `json:"timeout,omitempty"`
+}
+
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	tid := c.Locals("tid").(int32)
+	if tid == -1 {
+		return utils.Error(c, fiber.StatusForbidden, consts.TeamNotFound)
+	}
+
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	chall, err := db.GetChallenge(c.Context(), *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if chall == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+	}
+
+	if chall.Info.Hidden && !utils.Can(c, consts.PermChallengesRead) {
+		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+	}
+	if chall.Info.Type == sqlc.DeployTypeNormal || chall.DockerConfig == nil || chall.DockerConfig.Shared {
+		return utils.Error(c, fiber.StatusBadRequest, consts.ChallengeNotInstanciable)
+	}
+
+	base, _ := chall.DockerConfig.PowDifficulty.(int64)
+	difficulty, err := instancer.PowDifficulty(c.Context(), int(base))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingProofOfWork, err)
+	}
+	if difficulty == 0 {
+		return c.Status(fiber.StatusOK).JSON(PowInfo{})
+	}
+
+	pow, err := instancer.IssuePow(c.Context(), tid, *data.ChallID, difficulty)
+	if errors.Is(err, instancer.ErrTooManyPows) {
+		return utils.Error(c, fiber.StatusTooManyRequests, consts.TooManyPendingPows)
+	}
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingProofOfWork, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(PowInfo{
+		Nonce:      pow.Nonce,
+		Difficulty: pow.Difficulty,
+		Timeout:    max(int(time.Until(pow.ExpiresAt).Seconds()), 0),
+	})
+}
@@ -0,0 +1,186 @@
+package instances_pow_test
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/instancer"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func Int32(val any) int32 {
+	return int32(val.(float64))
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func solve(nonce string, difficulty int) string {
+	for i := 0; ; i++ {
+		solution := strconv.Itoa(i)
+		if instancer.CheckPow(nonce, solution, difficulty) {
+			return solution
+		}
+	}
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	session.Get("/challenges", nil, http.StatusOK)
+	body := session.Body()
+
+	var challID1, challID3 int32
+	for _, chall := range List(body) {
+		switch Json(chall)["name"] {
+		case "chall-1":
+			challID1 = Int32(Json(chall)["id"])
+		case "chall-3":
+			challID3 = Int32(Json(chall)["id"])
+		}
+	}
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusForbidden)
+
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "testpass"}, http.StatusOK)
+	session.Post("/instances/pow", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidJSON))
+
+	session.Post("/instances/pow", JSON{}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.MissingRequiredFields))
+
+	session.Post("/instances/pow", JSON{"chall_id": math.MaxInt32 + 1}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidJSON))
+
+	session.Post("/instances/pow", JSON{"chall_id": 99999}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.ChallengeNotFound))
+
+	session.Post("/instances/pow", JSON{"chall_id": challID1}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.ChallengeNotInstanciable))
+
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	session.CheckResponse(JSON{"difficulty": 0})
+
+	test_utils.UpdateConfig(t, "instance-pow-difficulty", "8")
+
+	session.Post("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.MissingProofOfWork))
+
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	body = session.Body()
+	if Int32(Json(body)["difficulty"]) != 8 {
+		t.Fatalf("Expected difficulty 8: %+v", body)
+	}
+	if _, ok := Json(body)["timeout"]; !ok {
+		t.Fatalf("Expected timeout to be present in response: %+v", body)
+	}
+	nonce := Json(body)["nonce"].(string)
+	solution := solve(nonce, 8)
+
+	session.Post("/instances", JSON{"chall_id": challID3, "pow_nonce": "invalid", "pow_solution": solution}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.InvalidProofOfWork))
+
+	session.Post("/instances", JSON{"chall_id": challID3, "pow_nonce": nonce, "pow_solution": solution}, http.StatusOK)
+	session.Delete("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+
+	session.Post("/instances", JSON{"chall_id": challID3, "pow_nonce": nonce, "pow_solution": solution}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.InvalidProofOfWork))
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	session.Patch("/challenges", JSON{"chall_id": challID3, "pow_difficulty": 4}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	body = session.Body()
+	if Int32(Json(body)["difficulty"]) != 4 {
+		t.Fatalf("Expected difficulty 4: %+v", body)
+	}
+
+	test_utils.UpdateConfig(t, "instance-pow-load-step", "1")
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	body = session.Body()
+	if Int32(Json(body)["difficulty"]) != 4 {
+		t.Fatalf("Expected difficulty 4 without active instances: %+v", body)
+	}
+
+	nonce = Json(body)["nonce"].(string)
+	session.Post("/instances", JSON{"chall_id": challID3, "pow_nonce": nonce, "pow_solution": solve(nonce, 4)}, http.StatusOK)
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	body = session.Body()
+	if Int32(Json(body)["difficulty"]) != 5 {
+		t.Fatalf("Expected difficulty 5 with an active instance: %+v", body)
+	}
+	session.Delete("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+
+	test_utils.UpdateConfig(t, "instance-pow-difficulty", "0")
+	test_utils.UpdateConfig(t, "instance-pow-load-step", "0")
+}
+
+func TestRouteLimits(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	session.Get("/challenges", nil, http.StatusOK)
+
+	var challID3 int32
+	for _, chall := range List(session.Body()) {
+		if Json(chall)["name"] == "chall-3" {
+			challID3 = Int32(Json(chall)["id"])
+		}
+	}
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "testpass"}, http.StatusOK)
+
+	test_utils.UpdateConfig(t, "instance-pow-difficulty", "4")
+
+	for range consts.MaxPendingPows {
+		session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusOK)
+	}
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusTooManyRequests)
+	session.CheckResponse(errorf(consts.TooManyPendingPows))
+
+	for range consts.MaxPowRequests - consts.MaxPendingPows - 1 {
+		session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusTooManyRequests)
+	}
+	session.Post("/instances/pow", JSON{"chall_id": challID3}, http.StatusTooManyRequests)
+	session.CheckResponse(errorf(consts.TooManyRequests))
+
+	test_utils.UpdateConfig(t, "instance-pow-difficulty", "0")
+}
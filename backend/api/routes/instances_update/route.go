@@ -13,16 +13,28 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	TeamID  *int32 `json:"team_id" validate:"omitnil,id"`
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+}
+
+type Response struct {
+	Timeout   int                        `json:"timeout"`
+	Extension *instancer.ExtensionBudget `json:"extension"`
+}
+
+// Route extends the instance of the team
 func Route(c *fiber.Ctx) error {
-	role := c.Locals("role").(sqlc.UserRole)
-	tid := c.Locals("tid").(int32)
-	if tid == -1 {
-		return utils.Error(c, fiber.StatusForbidden, consts.TeamNotFound)
-	}
+	return update(c, false)
+}
 
-	var data struct {
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-	}
+// Manage extends the instance of any team, for the staff managing the instances
+func Manage(c *fiber.Ctx) error {
+	return update(c, true)
+}
+
+func update(c *fiber.Ctx, manage bool) error {
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -40,30 +52,67 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 
-	if chall.Info.Hidden && !utils.In(role,
-		[]sqlc.UserRole{sqlc.UserRoleAuthor, sqlc.UserRoleAdmin}) {
+	// The staff managing the instances reach the ones of hidden challenges too
+	if chall.Info.Hidden && !manage && !utils.Can(c, consts.PermChallengesRead) {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
 	}
 	if chall.Info.Type == sqlc.DeployTypeNormal {
 		return utils.Error(c, fiber.StatusBadRequest, consts.ChallengeNotInstanciable)
 	}
 
+	var tid int32
+	if manage && data.TeamID != nil {
+		tid = *data.TeamID
+	} else {
+		tid = c.Locals("tid").(int32)
+	}
+
+	if tid == -1 {
+		return utils.Error(c, fiber.StatusForbidden, consts.TeamNotFound)
+	}
+
 	if chall.DockerConfig.Lifetime == 0 {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.MissingLifetime, errors.New(consts.MissingLifetime))
 	}
-	lifetime := time.Second * time.Duration(chall.DockerConfig.Lifetime.(int64))
-	expires_at := time.Now().Add(lifetime)
 
-	err = instancer.UpdateInstanceExpire(c.Context(), tid, *data.ChallID, expires_at)
+	instance, err := instancer.GetInstance(c.Context(), *data.ChallID, tid)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingInstance, err)
+	}
+	if instance == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.InstanceNotFound)
+	}
+
+	// The limits of the policy are for the teams, not the staff managing them
+	policy := instancer.NewExtensionPolicy(chall.DockerConfig)
+	if manage {
+		policy = policy.Unlimited()
+	}
+
+	expiresAt, budget, err := instancer.ExtendInstance(c.Context(), instance, policy)
 	if err != nil {
-		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingInstance, err)
+		switch err.Error() {
+		case "[max extensions]":
+			return utils.Error(c, fiber.StatusForbidden, consts.MaxExtensionsReached)
+		case "[max lifetime]":
+			return utils.Error(c, fiber.StatusForbidden, consts.MaxLifetimeReached)
+		case "[extension window]":
+			return utils.Error(c, fiber.StatusForbidden, consts.ExtensionNotAllowedYet)
+		case "[extension cooldown]":
+			return utils.Error(c, fiber.StatusTooManyRequests, consts.ExtensionCooldown)
+		case "[race condition]":
+			return utils.Error(c, fiber.StatusConflict, consts.AlreadyExtended)
+		default:
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingInstance, err)
+		}
 	}
 
-	timeout := int(time.Until(expires_at).Seconds())
+	timeout := int(time.Until(expiresAt).Seconds())
 	if timeout < 0 {
 		timeout = 0
 	}
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"timeout": timeout,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Timeout:   timeout,
+		Extension: budget,
 	})
 }
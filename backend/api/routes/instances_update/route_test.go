@@ -1,6 +1,7 @@
 package instances_update_test
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 	"testing"
@@ -37,7 +38,7 @@ func TestRoute(t *testing.T) {
 	defer api.Shutdown(app)
 
 	test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
-	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	player := test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
 
 	session := test_utils.NewApiTestSession(t, app)
 	session.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
@@ -91,9 +92,92 @@ func TestRoute(t *testing.T) {
 	session.Delete("/instances", JSON{"chall_id": challID4}, http.StatusNotFound)
 	session.CheckResponse(errorf(consts.InstanceNotFound))
 
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.InstanceNotFound))
+
+	session.Post("/instances", JSON{"chall_id": challID3}, http.StatusOK)
 	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusOK)
 	body = session.Body()
 	if _, ok := Json(body)["timeout"]; !ok {
 		t.Fatalf("Expected timeout to be present in response: %+v", body)
 	}
+	test_utils.Compare(t, JSON{
+		"extensions_left": nil,
+		"lifetime_left":   nil,
+		"next_extension":  0,
+	}, Json(body)["extension"])
+
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+
+	author.Patch("/challenges", JSON{"chall_id": challID3, "max_extensions": 2}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"extensions_left": 0,
+		"lifetime_left":   nil,
+		"next_extension":  0,
+	}, Json(session.Body())["extension"])
+	session.Get(fmt.Sprintf("/challenges/%d", challID3), nil, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"extensions_left": 0,
+		"lifetime_left":   nil,
+		"next_extension":  0,
+	}, Json(session.Body())["extension"])
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.MaxExtensionsReached))
+
+	author.Patch("/challenges", JSON{"chall_id": challID3, "max_extensions": 10, "extension_cooldown": 3600}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusTooManyRequests)
+	session.CheckResponse(errorf(consts.ExtensionCooldown))
+
+	author.Patch("/challenges", JSON{"chall_id": challID3, "extension_cooldown": 0, "extension_window": 60}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.ExtensionNotAllowedYet))
+
+	author.Patch("/challenges", JSON{"chall_id": challID3, "extension_window": 0, "max_lifetime": 1}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.MaxLifetimeReached))
+
+	// The staff managing the instances reach the ones of the teams
+	session.Get("/info", nil, http.StatusOK)
+	tid := Int32(Json(session.Body())["team_id"])
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	session.Patch("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+
+	// The extension policy only limits the teams
+	admin.Patch("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusOK)
+	test_utils.Compare(t, JSON{
+		"extensions_left": nil,
+		"lifetime_left":   nil,
+		"next_extension":  0,
+	}, Json(admin.Body())["extension"])
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.MaxLifetimeReached))
+
+	// Nor do hidden challenges stop the staff without challenges.read
+	infra := test_utils.RegisterUser(t, "infra", "infra@test.test", "infrapass", sqlc.UserRoleSpectator)
+	admin.Post("/roles", JSON{"name": "infra", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+	admin.Patch("/users/custom-role", JSON{"user_id": infra.ID, "custom_role": "infra"}, http.StatusOK)
+	staff := test_utils.NewApiTestSession(t, app)
+	staff.Post("/login", JSON{"email": "infra@test.test", "password": "infrapass"}, http.StatusOK)
+
+	admin.Patch("/challenges/hidden", JSON{"chall_ids": []int32{challID3}}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.ChallengeNotFound))
+	staff.Patch("/instances/manage", JSON{"chall_id": challID3, "team_id": tid}, http.StatusOK)
+	if _, ok := Json(staff.Body())["timeout"]; !ok {
+		t.Fatalf("Expected timeout to be present in response: %+v", staff.Body())
+	}
+	admin.Patch("/challenges/hidden", JSON{"chall_ids": []int32{challID3}}, http.StatusOK)
+
+	// The staff competing in a team are limited on the route of the teams
+	admin.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": "infra"}, http.StatusOK)
+	session.Patch("/instances", JSON{"chall_id": challID3}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.MaxLifetimeReached))
+	admin.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": nil}, http.StatusOK)
+
+	session.Delete("/instances", JSON{"chall_id": challID3}, http.StatusOK)
 }
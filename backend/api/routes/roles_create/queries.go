@@ -0,0 +1,22 @@
+package roles_create
+
+import (
+	"context"
+	"slices"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func CreateRole(ctx context.Context, data *Data) error {
+	slices.Sort(data.Permissions)
+	err := db.Sql.CreateRole(ctx, sqlc.CreateRoleParams{
+		Name:        data.Name,
+		Description: data.Description,
+		Permissions: slices.Compact(data.Permissions),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
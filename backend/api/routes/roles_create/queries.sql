@@ -0,0 +1,3 @@
+-- name: CreateRole :exec
+-- Create a custom role
+INSERT INTO roles (name, description, permissions) VALUES ($1, $2, $3);
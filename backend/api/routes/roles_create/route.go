@@ -0,0 +1,43 @@
+package roles_create
+
+import (
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+type Data struct {
+	Name        string   `json:"name" validate:"required,role_name"`
+	Description string   `json:"description" validate:"role_description"`
+	Permissions []string `json:"permissions" validate:"required,permissions"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+	if !utils.CanGrant(c, data.Permissions) {
+		return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+	}
+
+	err = CreateRole(c.Context(), &data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == consts.PGUniqueViolation {
+				return utils.Error(c, fiber.StatusConflict, consts.RoleAlreadyExists)
+			}
+		}
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingRole, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,106 @@
+package roles_create_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"name": "support"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"name": strings.Repeat("a", consts.MaxRoleNameLen+1), "permissions": []string{}},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Name", consts.MaxRoleNameLen)),
+	},
+	{
+		testBody:         JSON{"name": "support", "permissions": []string{"flags.read"}},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Permissions[0]", strings.Join(consts.Permissions, " "))),
+	},
+	{
+		testBody:       JSON{"name": "support", "description": "Help desk", "permissions": []string{consts.PermSubmissionsRead, consts.PermInstancesManage}},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"name": "support", "permissions": []string{}},
+		expectedStatus:   http.StatusConflict,
+		expectedResponse: errorf(consts.RoleAlreadyExists),
+	},
+	{
+		testBody:       JSON{"name": "infra", "permissions": []string{consts.PermInstancesManage, consts.PermInstancesManage}},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		session.Post("/roles", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	expected := []JSON{
+		{"name": "infra", "description": "", "permissions": []string{consts.PermInstancesManage}},
+		{"name": "support", "description": "Help desk", "permissions": []string{consts.PermInstancesManage, consts.PermSubmissionsRead}},
+	}
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Get("/roles", nil, http.StatusOK)
+	test_utils.Compare(t, expected, session.Body().(map[string]any)["roles"])
+}
+
+func TestRoutePermissionNotHeld(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin2", "admin2@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "admin2@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite, consts.PermSubmissionsRead}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "roles"}, http.StatusOK)
+
+	// Staff holding roles.write can't create roles beyond their own permissions
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+	staffSession.Post("/roles", JSON{"name": "configs", "permissions": []string{consts.PermConfigsWrite}}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Post("/roles", JSON{"name": "mixed", "permissions": []string{consts.PermSubmissionsRead, consts.PermAuditRead}}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Post("/roles", JSON{"name": "submissions", "permissions": []string{consts.PermSubmissionsRead}}, http.StatusOK)
+	staffSession.CheckResponse(nil)
+}
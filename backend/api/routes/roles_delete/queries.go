@@ -0,0 +1,22 @@
+package roles_delete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+)
+
+// DeleteRole returns false if the role does not exist, its users are left
+// with the permissions of their user role
+func DeleteRole(ctx context.Context, name string) (bool, error) {
+	_, err := db.Sql.DeleteRole(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
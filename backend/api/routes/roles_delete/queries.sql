@@ -0,0 +1,3 @@
+-- name: DeleteRole :one
+-- Delete a custom role, its users keep only the permissions of their user role
+DELETE FROM roles WHERE name = $1 RETURNING name;
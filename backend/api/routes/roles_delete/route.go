@@ -0,0 +1,48 @@
+package roles_delete
+
+import (
+	"trxd/db"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	Name string `json:"name" validate:"required,role_name"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	// No one can delete a role holding permissions they don't hold
+	role, err := db.GetRole(c.Context(), data.Name)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+	}
+	if role == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+	}
+	if !utils.CanGrant(c, role.Permissions) {
+		return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+	}
+
+	deleted, err := DeleteRole(c.Context(), data.Name)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingRole, err)
+	}
+	if !deleted {
+		return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
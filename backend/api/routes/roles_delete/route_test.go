@@ -0,0 +1,100 @@
+package roles_delete_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"name": "missing"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.RoleNotFound),
+	},
+	{
+		testBody:       JSON{"name": "support"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"name": "support"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.RoleNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermSubmissionsRead}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "support"}, http.StatusOK)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		session.Delete("/roles", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// Its users are left with the permissions of their user role
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+	staffSession.Get("/info", nil, http.StatusOK)
+	info := staffSession.Body().(map[string]any)
+	test_utils.Compare(t, nil, info["custom_role"])
+	test_utils.Compare(t, []string{}, info["permissions"])
+}
+
+func TestRoutePermissionNotHeld(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin2", "admin2@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff2", "staff2@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "admin2@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite}}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "configs", "permissions": []string{consts.PermConfigsWrite}}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "empty", "permissions": []string{}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "roles"}, http.StatusOK)
+
+	// Staff holding roles.write can't delete the roles holding more
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+	staffSession.Delete("/roles", JSON{"name": "configs"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Delete("/roles", JSON{"name": "empty"}, http.StatusOK)
+	staffSession.CheckResponse(nil)
+}
@@ -0,0 +1,19 @@
+package roles_get
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func GetRoles(ctx context.Context) ([]sqlc.Role, error) {
+	roles, err := db.Sql.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []sqlc.Role{}
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,3 @@
+-- name: GetRoles :many
+-- Retrieve all the custom roles
+SELECT * FROM roles ORDER BY name ASC;
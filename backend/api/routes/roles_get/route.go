@@ -0,0 +1,26 @@
+package roles_get
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Response struct {
+	Permissions []string    `json:"permissions"` // The ones that can be bundled in a role
+	Roles       []sqlc.Role `json:"roles"`
+}
+
+func Route(c *fiber.Ctx) error {
+	roles, err := GetRoles(c.Context())
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Permissions: consts.Permissions,
+		Roles:       roles,
+	})
+}
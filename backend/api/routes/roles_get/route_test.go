@@ -0,0 +1,40 @@
+package roles_get_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+
+	expected := JSON{
+		"permissions": consts.Permissions,
+		"roles":       []JSON{},
+	}
+	session.Get("/roles", nil, http.StatusOK)
+	session.CheckResponse(expected)
+
+	session.Post("/roles", JSON{"name": "infra", "description": "Keeps the instances alive", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+
+	expected["roles"] = []JSON{
+		{"name": "infra", "description": "Keeps the instances alive", "permissions": []string{consts.PermInstancesManage}},
+	}
+	session.Get("/roles", nil, http.StatusOK)
+	session.CheckResponse(expected)
+}
@@ -0,0 +1,42 @@
+package roles_update
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// UpdateRole returns false if the role does not exist
+func UpdateRole(ctx context.Context, data *Data) (bool, error) {
+	params := sqlc.UpdateRoleParams{
+		Name: data.Name,
+	}
+	if data.NewName != nil && *data.NewName != "" {
+		params.NewName = sql.NullString{String: *data.NewName, Valid: true}
+	}
+	if data.Description != nil {
+		params.Description = sql.NullString{String: *data.Description, Valid: true}
+	}
+	if data.Permissions != nil {
+		permissions := slices.Clone(*data.Permissions)
+		slices.Sort(permissions)
+		params.Permissions = slices.Compact(permissions)
+		// An empty list leaves the role without permissions, not untouched
+		if params.Permissions == nil {
+			params.Permissions = []string{}
+		}
+	}
+
+	_, err := db.Sql.UpdateRole(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,9 @@
+-- name: UpdateRole :one
+-- Update a custom role, renaming it also renames it for its users
+UPDATE roles
+SET
+  name = COALESCE(sqlc.narg('new_name'), name),
+  description = COALESCE(sqlc.narg('description'), description),
+  permissions = COALESCE(sqlc.narg('permissions')::VARCHAR[], permissions)
+WHERE name = sqlc.arg('name')
+RETURNING name;
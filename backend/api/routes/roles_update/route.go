@@ -0,0 +1,63 @@
+package roles_update
+
+import (
+	"trxd/db"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+type Data struct {
+	Name        string    `json:"name" validate:"required,role_name"`
+	NewName     *string   `json:"new_name" validate:"omitempty,role_name"`
+	Description *string   `json:"description" validate:"omitnil,role_description"`
+	Permissions *[]string `json:"permissions" validate:"omitnil,permissions"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+	if data.NewName == nil && data.Description == nil && data.Permissions == nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.NoDataToUpdate)
+	}
+	if data.Permissions != nil && !utils.CanGrant(c, *data.Permissions) {
+		return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+	}
+
+	// No one can change a role holding permissions they don't hold
+	role, err := db.GetRole(c.Context(), data.Name)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+	}
+	if role == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+	}
+	if !utils.CanGrant(c, role.Permissions) {
+		return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+	}
+
+	updated, err := UpdateRole(c.Context(), &data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == consts.PGUniqueViolation {
+				return utils.Error(c, fiber.StatusConflict, consts.RoleAlreadyExists)
+			}
+		}
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingRole, err)
+	}
+	if !updated {
+		return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
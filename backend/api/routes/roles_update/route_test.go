@@ -0,0 +1,135 @@
+package roles_update_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"description": "Updated"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"name": "support"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.NoDataToUpdate),
+	},
+	{
+		testBody:         JSON{"name": "support", "permissions": []string{"flags.read"}},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Permissions[0]", strings.Join(consts.Permissions, " "))),
+	},
+	{
+		testBody:         JSON{"name": "missing", "description": "Updated"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.RoleNotFound),
+	},
+	{
+		testBody:         JSON{"name": "support", "new_name": "infra"},
+		expectedStatus:   http.StatusConflict,
+		expectedResponse: errorf(consts.RoleAlreadyExists),
+	},
+	{
+		testBody:       JSON{"name": "support", "description": "Updated", "permissions": []string{consts.PermSubmissionsRead}},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"name": "support", "new_name": "helpdesk"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"name": "infra", "permissions": []string{}},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "infra", "permissions": []string{consts.PermInstancesManage}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "support"}, http.StatusOK)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		session.Patch("/roles", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	expected := []JSON{
+		{"name": "helpdesk", "description": "Updated", "permissions": []string{consts.PermSubmissionsRead}},
+		{"name": "infra", "description": "", "permissions": []string{}},
+	}
+	session.Get("/roles", nil, http.StatusOK)
+	test_utils.Compare(t, expected, session.Body().(map[string]any)["roles"])
+
+	// The renamed role is still granted to its users, with the new permissions
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+	staffSession.Get("/info", nil, http.StatusOK)
+	info := staffSession.Body().(map[string]any)
+	test_utils.Compare(t, "helpdesk", info["custom_role"])
+	test_utils.Compare(t, []string{consts.PermSubmissionsRead}, info["permissions"])
+}
+
+func TestRoutePermissionNotHeld(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin2", "admin2@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff2", "staff2@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "admin2@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "roles"}, http.StatusOK)
+
+	// Staff holding roles.write can't add to their own role what they don't hold
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+	staffSession.Patch("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite, consts.PermConfigsWrite}}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Patch("/roles", JSON{"name": "roles", "description": "Roles"}, http.StatusOK)
+	staffSession.CheckResponse(nil)
+
+	staffSession.Get("/info", nil, http.StatusOK)
+	test_utils.Compare(t, []string{consts.PermRolesWrite}, staffSession.Body().(map[string]any)["permissions"])
+
+	// Nor change the roles holding them
+	session.Post("/roles", JSON{"name": "configs", "permissions": []string{consts.PermConfigsWrite}}, http.StatusOK)
+	staffSession.Patch("/roles", JSON{"name": "configs", "permissions": []string{}}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Patch("/roles", JSON{"name": "configs", "new_name": "stripped"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+}
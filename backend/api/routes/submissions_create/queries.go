@@ -2,16 +2,59 @@ package submissions_create
 
 import (
 	"context"
+	"database/sql"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/utils/metrics"
 )
 
-func SubmitFlag(ctx context.Context, userID int32, role sqlc.UserRole,
-	challengeID int32, flag string) (sqlc.SubmissionStatus, bool, error) {
-	valid, err := db.Sql.CheckFlags(ctx, sqlc.CheckFlagsParams{
-		Flag:    flag,
+// checkInstanceFlag matches a flag against the ones generated for the
+// instances of a challenge, returning the team owning it when it belongs
+// to someone else
+func checkInstanceFlag(ctx context.Context, teamID int32, role sqlc.UserRole,
+	challengeID int32, flag string) (bool, sql.NullInt32, error) {
+	owner, err := db.Sql.GetInstanceFlagOwner(ctx, sqlc.GetInstanceFlagOwnerParams{
 		ChallID: challengeID,
+		Flag:    flag,
+		TeamID:  teamID,
 	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, sql.NullInt32{}, nil
+		}
+		return false, sql.NullInt32{}, err
+	}
+
+	if owner == teamID || role != sqlc.UserRolePlayer {
+		return true, sql.NullInt32{}, nil
+	}
+
+	return false, sql.NullInt32{Int32: owner, Valid: true}, nil
+}
+
+func SubmitFlag(ctx context.Context, userID int32, teamID int32, role sqlc.UserRole,
+	challenge *sqlc.Challenge, flag string) (sqlc.SubmissionStatus, bool, error) {
+	var valid bool
+	var flagOwner sql.NullInt32
+
+	instanceFlag := false
+	if challenge.Type != sqlc.DeployTypeNormal {
+		conf, err := db.GetDockerConfigsByID(ctx, challenge.ID)
+		if err != nil {
+			return sqlc.SubmissionStatusInvalid, false, err
+		}
+		instanceFlag = conf != nil && conf.InstanceFlag && !conf.Shared
+	}
+
+	var err error
+	if instanceFlag {
+		valid, flagOwner, err = checkInstanceFlag(ctx, teamID, role, challenge.ID, flag)
+	} else {
+		valid, err = db.Sql.CheckFlags(ctx, sqlc.CheckFlagsParams{
+			Flag:    flag,
+			ChallID: challenge.ID,
+		})
+	}
 	if err != nil {
 		return sqlc.SubmissionStatusInvalid, false, err
 	}
@@ -26,14 +69,20 @@ func SubmitFlag(ctx context.Context, userID int32, role sqlc.UserRole,
 	}
 
 	res, err := db.Sql.Submit(ctx, sqlc.SubmitParams{
-		UserID: userID,
-		ID:     challengeID,
-		Status: status,
-		Flag:   flag,
+		UserID:    userID,
+		ID:        challenge.ID,
+		Status:    status,
+		Flag:      flag,
+		FlagOwner: flagOwner,
 	})
 	if err != nil {
 		return sqlc.SubmissionStatusInvalid, false, err
 	}
 
+	metrics.Submissions.WithLabelValues(string(res.Status)).Inc()
+	if res.FirstBlood {
+		metrics.FirstBloods.Inc()
+	}
+
 	return res.Status, res.FirstBlood, nil
 }
@@ -8,6 +8,13 @@ WITH challenge AS (
     SELECT challenges.id FROM challenges
     WHERE challenges.id = $2 FOR UPDATE
   )
-INSERT INTO submissions (user_id, chall_id, status, flag)
-  VALUES ($1, (SELECT id FROM challenge), sqlc.arg(status), $3)
+INSERT INTO submissions (user_id, chall_id, status, flag, flag_owner)
+  VALUES ($1, (SELECT id FROM challenge), sqlc.arg(status), $3, sqlc.narg(flag_owner))
   RETURNING status, first_blood;
+
+-- name: GetInstanceFlagOwner :one
+-- Finds the team an instance flag was generated for, preferring the given team
+SELECT team_id FROM instance_secrets
+  WHERE chall_id = sqlc.arg(chall_id) AND name = 'Flag' AND value = sqlc.arg(flag)
+  ORDER BY team_id = sqlc.arg(team_id) DESC
+  LIMIT 1;
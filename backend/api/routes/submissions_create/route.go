@@ -12,11 +12,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+	Flag    string `json:"flag" validate:"required,flag"`
+}
+
+type Response struct {
+	Status     sqlc.SubmissionStatus `json:"status"`
+	FirstBlood bool                  `json:"first_blood"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		ChallID *int32 `json:"chall_id" validate:"required,id"`
-		Flag    string `json:"flag" validate:"required,flag"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -35,6 +42,7 @@ func Route(c *fiber.Ctx) error {
 	}
 
 	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
 	role := c.Locals("role").(sqlc.UserRole)
 	if role == sqlc.UserRolePlayer && challenge.Hidden {
 		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
@@ -42,7 +50,7 @@ func Route(c *fiber.Ctx) error {
 
 	data.Flag = strings.TrimSpace(data.Flag)
 
-	status, first_blood, err := SubmitFlag(c.Context(), uid, role, *data.ChallID, data.Flag)
+	status, first_blood, err := SubmitFlag(c.Context(), uid, tid, role, challenge, data.Flag)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSubmittingFlag, err)
 	}
@@ -51,8 +59,8 @@ func Route(c *fiber.Ctx) error {
 		go discord.BroadcastFirstBlood(c.Context(), challenge, uid)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status":      status,
-		"first_blood": first_blood,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Status:     status,
+		FirstBlood: first_blood,
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 	"trxd/api"
+	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/utils/consts"
 	"trxd/utils/test_utils"
@@ -185,3 +186,61 @@ func TestRoute(t *testing.T) {
 	session.Post("/submissions", JSON{"chall_id": chall_no_flag.ID, "flag": "flag{test}"}, http.StatusOK)
 	session.CheckResponse(JSON{"status": sqlc.SubmissionStatusWrong, "first_blood": false})
 }
+
+func TestInstanceFlag(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	test_utils.RegisterUser(t, "test-2", "test-2@test.test", "testpass", sqlc.UserRolePlayer)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test-2@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team-2", "password": "teampasswd"}, http.StatusOK)
+	team1 := test_utils.GetTeamByName(t, "test-team")
+	team2 := test_utils.GetTeamByName(t, "test-team-2")
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeContainer, 1, sqlc.ScoreTypeDynamic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	admin.Post("/flags", JSON{"chall_id": chall.ID, "flag": "flag{static}", "regex": false}, http.StatusOK)
+	admin.Patch("/challenges", JSON{"chall_id": chall.ID, "instance_flag": true}, http.StatusOK)
+
+	for _, secret := range []sqlc.CreateInstanceSecretParams{
+		{TeamID: team1.ID, ChallID: chall.ID, Name: consts.FlagSecret, Value: "flag{team1}"},
+		{TeamID: team2.ID, ChallID: chall.ID, Name: consts.FlagSecret, Value: "flag{team2}"},
+	} {
+		err := db.Sql.CreateInstanceSecret(t.Context(), secret)
+		if err != nil {
+			t.Fatalf("Failed to create instance secret: %v", err)
+		}
+	}
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{static}"}, http.StatusOK)
+	session.CheckResponse(JSON{"status": sqlc.SubmissionStatusWrong, "first_blood": false})
+	session.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{team2}"}, http.StatusOK)
+	session.CheckResponse(JSON{"status": sqlc.SubmissionStatusWrong, "first_blood": false})
+	session.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{team1}"}, http.StatusOK)
+	session.CheckResponse(JSON{"status": sqlc.SubmissionStatusCorrect, "first_blood": true})
+
+	admin.Post("/submissions", JSON{"chall_id": chall.ID, "flag": "flag{team2}"}, http.StatusOK)
+	admin.CheckResponse(JSON{"status": sqlc.SubmissionStatusCorrect, "first_blood": false})
+
+	admin.Get("/submissions?flagged=true", nil, http.StatusOK)
+	body := Json(admin.Body())
+	if Int32(body["total"]) != 1 {
+		t.Fatalf("Expected 1 flagged submission, got %v", body["total"])
+	}
+	flagged := Json(List(body["submissions"])[0])
+	if flagged["flag"] != "flag{team2}" || Int32(flagged["flag_owner_id"]) != team2.ID || flagged["flag_owner_name"] != "test-team-2" {
+		t.Fatalf("Unexpected flagged submission: %v", flagged)
+	}
+}
@@ -2,6 +2,8 @@ package submissions_delete
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"trxd/db"
 )
 
@@ -13,3 +15,17 @@ func DeleteSubmission(ctx context.Context, subID int32) error {
 
 	return nil
 }
+
+// GetSubmissionChallenge returns the challenge of the submission, nil if it
+// does not exist
+func GetSubmissionChallenge(ctx context.Context, subID int32) (*int32, error) {
+	challID, err := db.Sql.GetSubmissionChallenge(ctx, subID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &challID, nil
+}
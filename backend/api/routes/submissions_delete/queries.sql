@@ -1,3 +1,7 @@
 -- name: DeleteSubmission :exec
 -- Delete a submission by its ID
 DELETE FROM submissions WHERE id = $1;
+
+-- name: GetSubmissionChallenge :one
+-- Retrieve the challenge of a submission
+SELECT chall_id FROM submissions WHERE id = $1;
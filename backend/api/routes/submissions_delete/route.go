@@ -1,6 +1,8 @@
 package submissions_delete
 
 import (
+	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/validator"
@@ -8,10 +10,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	SubID *int32 `json:"sub_id" validate:"required,id"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		SubID *int32 `json:"sub_id" validate:"required,id"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -21,6 +25,27 @@ func Route(c *fiber.Ctx) error {
 		return err
 	}
 
+	// The authors only delete the submissions of their challenges
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	if role == sqlc.UserRoleAuthor {
+		challID, err := GetSubmissionChallenge(c.Context(), *data.SubID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingSubmissions, err)
+		}
+		if challID == nil {
+			return utils.Error(c, fiber.StatusNotFound, consts.SubmissionNotFound)
+		}
+
+		owned, err := db.CanManageChallenge(c.Context(), uid, role, *challID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if !owned {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+	}
+
 	err = DeleteSubmission(c.Context(), *data.SubID)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingSubmission, err)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 	"trxd/api"
+	"trxd/db/sqlc"
 	"trxd/utils/consts"
 	"trxd/utils/test_utils"
 )
@@ -66,4 +67,18 @@ func TestRoute(t *testing.T) {
 	submissions1 := List(Json(body)["submissions"])[1:]
 	submissions2 := List(Json(body2)["submissions"])
 	test_utils.Compare(t, submissions1, submissions2)
+
+	// The authors only delete the submissions of their challenges
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	authorSession := test_utils.NewApiTestSession(t, app)
+	authorSession.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	authorSession.Delete("/submissions", JSON{"sub_id": 99999}, http.StatusNotFound)
+	authorSession.CheckResponse(errorf(consts.SubmissionNotFound))
+	authorSession.Delete("/submissions", JSON{"sub_id": newFirstID}, http.StatusForbidden)
+	authorSession.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	challID := Int32(Json(List(Json(body2)["submissions"])[0])["chall_id"])
+	test_utils.AddChallengeOwner(t, challID, author.ID, sqlc.OwnerRoleCoOwner)
+	authorSession.Delete("/submissions", JSON{"sub_id": newFirstID}, http.StatusOK)
+	authorSession.CheckResponse(nil)
 }
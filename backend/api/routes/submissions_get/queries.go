@@ -18,19 +18,30 @@ type Submissions struct {
 	ChallName  string                `json:"chall_name"`
 	Status     sqlc.SubmissionStatus `json:"status"`
 	FirstBlood bool                  `json:"first_blood"`
-	Flag       string                `json:"flag"`
+	Flag       string                `json:"flag,omitempty"` // Only to the ones reading the challenges
 	Timestamp  time.Time             `json:"timestamp"`
+
+	FlagOwnerID   *int32 `json:"flag_owner_id,omitempty"`
+	FlagOwnerName string `json:"flag_owner_name,omitempty"`
 }
 
-func getSubs(ctx context.Context, offset int32, limit int32) (int64, []sqlc.GetSubmissionsRow, error) {
-	total, err := db.Sql.GetTotalSubmissions(ctx)
+func getSubs(ctx context.Context, flags bool, flagged bool, owner int32, offset int32, limit int32) (int64, []sqlc.GetSubmissionsRow, error) {
+	ownerID := sql.NullInt32{Int32: owner, Valid: owner != -1}
+
+	total, err := db.Sql.GetTotalSubmissions(ctx, sqlc.GetTotalSubmissionsParams{
+		Flagged: flagged,
+		Owner:   ownerID,
+	})
 	if err != nil {
 		return 0, nil, err
 	}
 
 	submissions, err := db.Sql.GetSubmissions(ctx, sqlc.GetSubmissionsParams{
-		Offset: offset,
-		Limit:  sql.NullInt32{Int32: limit, Valid: limit != 0},
+		Flags:   flags,
+		Flagged: flagged,
+		Owner:   ownerID,
+		Offset:  offset,
+		Limit:   sql.NullInt32{Int32: limit, Valid: limit != 0},
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -42,14 +53,16 @@ func getSubs(ctx context.Context, offset int32, limit int32) (int64, []sqlc.GetS
 	return total, submissions, nil
 }
 
-func GetSubmissions(ctx context.Context, offset int32, limit int32) (int64, []Submissions, error) {
+// GetSubmissions returns the submissions of every challenge, or only of the
+// ones of an owner if not -1, with the flags submitted only if requested
+func GetSubmissions(ctx context.Context, flags bool, flagged bool, owner int32, offset int32, limit int32) (int64, []Submissions, error) {
 	userModeStr, err := db.GetConfig(ctx, "user-mode")
 	if err != nil {
 		return 0, nil, err
 	}
 	userMode := userModeStr == "true"
 
-	total, submissions, err := getSubs(ctx, offset, limit)
+	total, submissions, err := getSubs(ctx, flags, flagged, owner, offset, limit)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -71,6 +84,10 @@ func GetSubmissions(ctx context.Context, offset int32, limit int32) (int64, []Su
 			subs[i].UserID = &submission.UserID
 			subs[i].UserName = submission.UserName
 		}
+		if submission.FlagOwner.Valid {
+			subs[i].FlagOwnerID = &submission.FlagOwner.Int32
+			subs[i].FlagOwnerName = submission.FlagOwnerName
+		}
 	}
 
 	return total, subs, nil
@@ -1,9 +1,13 @@
 -- name: GetTotalSubmissions :one
--- fetches total number of submissions
-SELECT COUNT(*) FROM submissions;
+-- fetches total number of submissions, optionally only the flagged ones or the ones of the challenges of an owner
+SELECT COUNT(*) FROM submissions
+  WHERE (NOT sqlc.arg('flagged')::BOOLEAN OR flag_owner IS NOT NULL)
+    AND (sqlc.narg('owner')::INTEGER IS NULL
+      OR chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = sqlc.narg('owner')));
 
 -- name: GetSubmissions :many
--- fetches all submissions, with pagination
+-- fetches all submissions, with pagination, optionally only the flagged ones or the ones of the challenges of an owner,
+-- the flags only if requested
 SELECT
     s.id,
     s.user_id,
@@ -14,12 +18,18 @@ SELECT
     c.name AS chall_name,
     s.status,
     s.first_blood,
-    s.flag,
-    s.timestamp
+    CASE WHEN sqlc.arg('flags')::BOOLEAN THEN s.flag ELSE '' END AS flag,
+    s.timestamp,
+    s.flag_owner,
+    COALESCE(o.name, '') AS flag_owner_name
   FROM submissions s
   JOIN users u ON s.user_id = u.id
   JOIN teams t ON u.team_id = t.id
   JOIN challenges c ON s.chall_id = c.id
+  LEFT JOIN teams o ON s.flag_owner = o.id
+  WHERE (NOT sqlc.arg('flagged')::BOOLEAN OR s.flag_owner IS NOT NULL)
+    AND (sqlc.narg('owner')::INTEGER IS NULL
+      OR s.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = sqlc.narg('owner')))
   ORDER BY s.id DESC
   OFFSET sqlc.arg('offset')
   LIMIT sqlc.narg('limit');
@@ -2,12 +2,18 @@ package submissions_get
 
 import (
 	"math"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+type Response struct {
+	Total       int64         `json:"total"`
+	Submissions []Submissions `json:"submissions"`
+}
+
 func Route(c *fiber.Ctx) error {
 	offset := c.QueryInt("offset", 0)
 	if offset < 0 || offset > math.MaxInt32 {
@@ -19,13 +25,25 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
 	}
 
-	totalUsers, submissionsData, err := GetSubmissions(c.Context(), int32(offset), int32(limit))
+	// Submissions of instance flags belonging to other teams
+	flagged := c.QueryBool("flagged", false)
+
+	// The authors only see the submissions of their challenges
+	owner := int32(-1)
+	if c.Locals("role").(sqlc.UserRole) == sqlc.UserRoleAuthor {
+		owner = c.Locals("uid").(int32)
+	}
+
+	// The flags are as secret as the challenges
+	flags := utils.Can(c, consts.PermChallengesRead)
+
+	totalUsers, submissionsData, err := GetSubmissions(c.Context(), flags, flagged, owner, int32(offset), int32(limit))
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingSubmissions, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"total":       totalUsers,
-		"submissions": submissionsData,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Total:       totalUsers,
+		Submissions: submissionsData,
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 	"trxd/api"
+	"trxd/db/sqlc"
 	"trxd/utils/consts"
 	"trxd/utils/test_utils"
 )
@@ -16,6 +17,18 @@ func errorf(val any) JSON {
 	return JSON{"error": val}
 }
 
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func Int32(val any) int32 {
+	return int32(val.(float64))
+}
+
 func TestMain(m *testing.M) {
 	test_utils.Main(m)
 }
@@ -150,6 +163,45 @@ func TestRoute(t *testing.T) {
 	sub = subSet(expected, 1, 3)
 	session.CheckFilteredResponse(sub, "id", "user_id", "team_id", "chall_id", "timestamp")
 
+	// The authors only see the submissions of their challenges
+
+	session.Get("/submissions", nil, http.StatusOK)
+	var challID int32
+	for _, submission := range List(Json(session.Body())["submissions"]) {
+		if Json(submission)["chall_name"] == "chall-2" {
+			challID = Int32(Json(submission)["chall_id"])
+		}
+	}
+
+	author := test_utils.RegisterUser(t, "author", "author@test.test", "authorpass", sqlc.UserRoleAuthor)
+	authorSession := test_utils.NewApiTestSession(t, app)
+	authorSession.Post("/login", JSON{"email": "author@test.test", "password": "authorpass"}, http.StatusOK)
+	authorSession.Get("/submissions", nil, http.StatusOK)
+	authorSession.CheckResponse(JSON{"submissions": []JSON{}, "total": 0})
+
+	test_utils.AddChallengeOwner(t, challID, author.ID, sqlc.OwnerRoleOwner)
+	authorSession.Get("/submissions", nil, http.StatusOK)
+	sub = JSON{"submissions": expected["submissions"].([]JSON)[1:2], "total": 1}
+	authorSession.CheckFilteredResponse(sub, "id", "user_id", "team_id", "chall_id", "timestamp")
+
+	// The staff not reading the challenges do not see the flags
+
+	support := test_utils.RegisterUser(t, "support", "support@test.test", "supportpass", sqlc.UserRolePlayer)
+	session.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermSubmissionsRead}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": support.ID, "custom_role": "support"}, http.StatusOK)
+
+	supportSession := test_utils.NewApiTestSession(t, app)
+	supportSession.Post("/login", JSON{"email": "support@test.test", "password": "supportpass"}, http.StatusOK)
+	supportSession.Get("/submissions?limit=1", nil, http.StatusOK)
+	first := JSON{}
+	for key, value := range expected["submissions"].([]JSON)[0] {
+		if key != "flag" {
+			first[key] = value
+		}
+	}
+	supportSession.CheckFilteredResponse(JSON{"submissions": []JSON{first}, "total": expected["total"]},
+		"id", "user_id", "team_id", "chall_id", "timestamp")
+
 	// User Mode
 
 	test_utils.UpdateConfig(t, "user-mode", "true")
@@ -8,6 +8,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Response struct {
+	Total int64      `json:"total"`
+	Teams []TeamData `json:"teams"`
+}
+
 func Route(c *fiber.Ctx) error {
 	offset := c.QueryInt("offset", 0)
 	if offset < 0 || offset > math.MaxInt32 {
@@ -24,8 +29,8 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"total": totalTeams,
-		"teams": teamsData,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Total: totalTeams,
+		Teams: teamsData,
 	})
 }
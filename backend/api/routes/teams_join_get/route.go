@@ -10,6 +10,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Response struct {
+	Token string `json:"token"`
+}
+
 func joinTeam(c *fiber.Ctx, tid int32) error {
 	uid := c.Locals("uid").(int32)
 
@@ -47,8 +51,8 @@ func getTeamToken(c *fiber.Ctx, tid int32) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.InternalServerError, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"token": token,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Token: token,
 	})
 }
 
@@ -10,11 +10,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	TeamID      *int32 `json:"team_id" validate:"omitnil,id"`
+	NewPassword string `json:"new_password" validate:"omitempty,password"`
+}
+
+type Response struct {
+	NewPassword string `json:"new_password"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		TeamID      *int32 `json:"team_id" validate:"omitnil,id"`
-		NewPassword string `json:"new_password" validate:"omitempty,password"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -54,7 +60,7 @@ func Route(c *fiber.Ctx) error {
 	if data.NewPassword != "" {
 		return c.SendStatus(fiber.StatusOK)
 	}
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"new_password": newPassword,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		NewPassword: newPassword,
 	})
 }
@@ -10,11 +10,13 @@ import (
 	"github.com/lib/pq"
 )
 
+type Data struct {
+	Name    string  `json:"name" validate:"team_name"`
+	Country *string `json:"country" validate:"omitempty,country"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		Name    string  `json:"name" validate:"team_name"`
-		Country *string `json:"country" validate:"omitempty,country"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -0,0 +1,57 @@
+package tickets_all_get
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Ticket struct {
+	ID        int32             `json:"id"`
+	TeamID    int32             `json:"team_id"`
+	TeamName  string            `json:"team_name"`
+	ChallID   *int32            `json:"chall_id"`
+	ChallName *string           `json:"chall_name"`
+	Title     string            `json:"title"`
+	Status    sqlc.TicketStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func GetTickets(ctx context.Context, status sqlc.TicketStatus, all bool, tid int32, owner int32) ([]Ticket, error) {
+	params := sqlc.GetTicketsParams{
+		Status: sqlc.NullTicketStatus{TicketStatus: status, Valid: status != ""},
+		All:    all,
+		Owner:  sql.NullInt32{Int32: owner, Valid: owner != -1},
+	}
+	if tid != -1 {
+		params.TeamID = sql.NullInt32{Int32: tid, Valid: true}
+	}
+
+	rows, err := db.Sql.GetTickets(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]Ticket, 0, len(rows))
+	for _, row := range rows {
+		ticket := Ticket{
+			ID:        row.ID,
+			TeamID:    row.TeamID,
+			TeamName:  row.TeamName,
+			Title:     row.Title,
+			Status:    row.Status,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+		if row.ChallID.Valid {
+			ticket.ChallID = &row.ChallID.Int32
+			ticket.ChallName = &row.ChallName.String
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, nil
+}
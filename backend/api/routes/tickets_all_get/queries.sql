@@ -0,0 +1,13 @@
+-- name: GetTickets :many
+-- Retrieve the tickets of a team, the ones about the challenges owned by an author or all of them
+SELECT tickets.id, tickets.team_id, teams.name AS team_name,
+    tickets.chall_id, challenges.name AS chall_name,
+    tickets.title, tickets.status, tickets.created_at, tickets.updated_at
+  FROM tickets
+  JOIN teams ON teams.id = tickets.team_id
+  LEFT JOIN challenges ON challenges.id = tickets.chall_id
+  WHERE (sqlc.narg('status')::ticket_status IS NULL OR tickets.status = sqlc.narg('status'))
+    AND (sqlc.arg('all')::BOOLEAN
+      OR tickets.team_id = sqlc.narg('team_id')
+      OR tickets.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = sqlc.narg('owner')))
+  ORDER BY tickets.updated_at DESC, tickets.id DESC;
@@ -0,0 +1,35 @@
+package tickets_all_get
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	status := c.Query("status")
+	if status != "" && !utils.In(status, consts.TicketStatusesStr) {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	// The users managing the tickets see all of them, the authors only the
+	// ones about the challenges they own
+	manage := utils.Can(c, consts.PermTicketsManage)
+	owner := int32(-1)
+	if manage && role == sqlc.UserRoleAuthor {
+		owner = uid
+	}
+
+	tickets, err := GetTickets(c.Context(), sqlc.TicketStatus(status), manage && role != sqlc.UserRoleAuthor, tid, owner)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(tickets)
+}
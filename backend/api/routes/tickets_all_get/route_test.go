@@ -0,0 +1,77 @@
+package tickets_all_get_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	authorUser := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	test_utils.AddChallengeOwner(t, chall.ID, authorUser.ID, sqlc.OwnerRoleOwner)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/tickets", JSON{"chall_id": chall.ID, "title": "Remote down", "body": "The remote does not answer"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test2", "test2@test.test", "testpass", sqlc.UserRolePlayer)
+	session2 := test_utils.NewApiTestSession(t, app)
+	session2.Post("/login", JSON{"email": "test2@test.test", "password": "testpass"}, http.StatusOK)
+	session2.Post("/teams/register", JSON{"name": "test-team2", "password": "teampasswd"}, http.StatusOK)
+	session2.Post("/tickets", JSON{"title": "Scoreboard", "body": "Our solve is missing"}, http.StatusOK)
+	closed := Json(session2.Body())["id"]
+	session2.Patch("/tickets", JSON{"id": closed, "status": "Closed"}, http.StatusOK)
+
+	session.Get("/tickets?status=Deleted", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidParam))
+
+	remote := JSON{"team_name": "test-team", "chall_id": chall.ID, "chall_name": "chall", "title": "Remote down", "status": "Open"}
+	scoreboard := JSON{"team_name": "test-team2", "chall_id": nil, "chall_name": nil, "title": "Scoreboard", "status": "Closed"}
+
+	// Every team only sees its own tickets
+	session.Get("/tickets", nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{remote}, test_utils.DeleteKeys(session.Body(), "id", "team_id", "created_at", "updated_at"))
+	session2.Get("/tickets", nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{scoreboard}, test_utils.DeleteKeys(session2.Body(), "id", "team_id", "created_at", "updated_at"))
+
+	// The authors see the ones about their challenges
+	author.Get("/tickets", nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{remote}, test_utils.DeleteKeys(author.Body(), "id", "team_id", "created_at", "updated_at"))
+
+	// The admins see all of them, the latest updated first
+	admin.Get("/tickets", nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{scoreboard, remote}, test_utils.DeleteKeys(admin.Body(), "id", "team_id", "created_at", "updated_at"))
+	admin.Get("/tickets?status=Open", nil, http.StatusOK)
+	test_utils.Compare(t, []JSON{remote}, test_utils.DeleteKeys(admin.Body(), "id", "team_id", "created_at", "updated_at"))
+}
@@ -0,0 +1,22 @@
+package tickets_attachments_get
+
+import (
+	"context"
+	"slices"
+	"trxd/db"
+)
+
+func HasAttachment(ctx context.Context, ticketID int32, path string) (bool, error) {
+	messages, err := db.Sql.GetTicketMessages(ctx, ticketID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, msg := range messages {
+		if slices.Contains(msg.Attachments, path) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package tickets_attachments_get
+
+import (
+	"net/url"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/tickets"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	ticketIDInt, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+	ticketID := int32(ticketIDInt)
+	valid, err := validator.Var(c, ticketID, "id")
+	if err != nil || !valid {
+		return err
+	}
+
+	ticket, _, err := tickets.Access(c.Context(), ticketID, uid, tid, role, utils.Can(c, consts.PermTicketsManage))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+	if ticket == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.TicketNotFound)
+	}
+
+	// Only the files attached to the messages are served, which also keeps
+	// the path inside the directory of the ticket
+	hash, err := url.PathUnescape(c.Params("hash"))
+	if err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+	path := hash + "/" + name
+	found, err := HasAttachment(c.Context(), ticket.ID, path)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+	if !found {
+		return utils.Error(c, fiber.StatusNotFound, consts.NotFound)
+	}
+
+	return c.Download(tickets.AttachmentsDir(ticket.ID)+path, name)
+}
@@ -0,0 +1,76 @@
+package tickets_attachments_get_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	module := test_utils.GetModuleName(t)
+	dir := "/tmp/" + module + "/"
+	test_utils.CreateDir(t, dir)
+	test_utils.CreateFile(t, dir+"log.txt", "connection refused")
+	hash := test_utils.HashFile(t, dir+"log.txt")
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/tickets", JSON{"title": "Remote down", "body": "The remote does not answer"}, http.StatusOK)
+	ticketID := Json(session.Body())["id"]
+	session.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Logs"}, []string{dir + "log.txt"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test2", "test2@test.test", "testpass", sqlc.UserRolePlayer)
+	session2 := test_utils.NewApiTestSession(t, app)
+	session2.Post("/login", JSON{"email": "test2@test.test", "password": "testpass"}, http.StatusOK)
+	session2.Post("/teams/register", JSON{"name": "test-team2", "password": "teampasswd"}, http.StatusOK)
+
+	url := fmt.Sprintf("/tickets/%v/attachments/%s/log.txt", ticketID, hash)
+
+	session2.Get(url, nil, http.StatusNotFound)
+	session2.CheckResponse(errorf(consts.TicketNotFound))
+	session.Get(fmt.Sprintf("/tickets/%v/attachments/%s/other.txt", ticketID, hash), nil, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.NotFound))
+	session.Get(fmt.Sprintf("/tickets/%v/attachments/%s/..%%2F..%%2F..%%2Fgo.mod", ticketID, hash), nil, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.NotFound))
+
+	for _, s := range []interface {
+		Get(string, any, int) *http.Response
+	}{session, admin} {
+		res := s.Get(url, nil, http.StatusOK)
+		content, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("Failed to read the attachment: %v", err)
+		}
+		if string(content) != "connection refused" {
+			t.Fatalf("Expected the content of the attachment, got %q", content)
+		}
+	}
+}
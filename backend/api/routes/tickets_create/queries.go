@@ -0,0 +1,49 @@
+package tickets_create
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// CreateTicket opens the ticket together with its first message
+func CreateTicket(ctx context.Context, uid int32, tid int32, data *Data) (int32, error) {
+	params := sqlc.CreateTicketParams{
+		TeamID: tid,
+		Title:  data.Title,
+	}
+	if data.ChallID != nil {
+		params.ChallID = sql.NullInt32{Int32: *data.ChallID, Valid: true}
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer db.Rollback(tx)
+	queries := db.Sql.WithTx(tx)
+
+	id, err := queries.CreateTicket(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = queries.CreateTicketMessage(ctx, sqlc.CreateTicketMessageParams{
+		TicketID:    id,
+		UserID:      sql.NullInt32{Int32: uid, Valid: true},
+		Body:        data.Body,
+		Attachments: []string{},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
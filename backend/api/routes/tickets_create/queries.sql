@@ -0,0 +1,3 @@
+-- name: CreateTicket :one
+-- Open a ticket for a team
+INSERT INTO tickets (team_id, chall_id, title) VALUES ($1, $2, $3) RETURNING id;
@@ -0,0 +1,69 @@
+package tickets_create
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/log"
+	"trxd/utils/tickets"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"omitnil,id"`
+	Title   string `json:"title" validate:"required,ticket_title"`
+	Body    string `json:"body" validate:"required,ticket_message"`
+}
+
+type Response struct {
+	ID int32 `json:"id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+
+	// The tickets are opened by the teams, the organizers reply to them
+	if tid == -1 {
+		return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
+	}
+
+	if data.ChallID != nil {
+		challenge, err := db.GetChallengeByID(c.Context(), *data.ChallID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if challenge == nil || (challenge.Hidden && !utils.Can(c, consts.PermChallengesRead)) {
+			return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+		}
+	}
+
+	id, err := CreateTicket(c.Context(), uid, tid, &data)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorCreatingTicket, err)
+	}
+
+	user, err := db.GetUserByID(c.Context(), uid)
+	if err != nil || user == nil {
+		log.Error("Failed to fetch ticket sender:", "id", id, "err", err)
+	} else if ticket, err := db.Sql.GetTicket(c.Context(), id); err != nil {
+		log.Error("Failed to fetch ticket:", "id", id, "err", err)
+	} else {
+		go tickets.Notify(context.Background(), &ticket, user.Name, data.Body, false)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{ID: id})
+}
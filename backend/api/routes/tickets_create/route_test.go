@@ -0,0 +1,138 @@
+package tickets_create_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"title": "Remote down"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"body": "The remote does not answer"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"title": strings.Repeat("a", consts.MaxTicketTitleLen+1), "body": "The remote does not answer"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Title", consts.MaxTicketTitleLen)),
+	},
+	{
+		testBody:         JSON{"title": "Remote down", "body": strings.Repeat("a", consts.MaxTicketMessageLen+1)},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Body", consts.MaxTicketMessageLen)),
+	},
+	{
+		testBody:         JSON{"chall_id": -1, "title": "Remote down", "body": "The remote does not answer"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
+	},
+	{
+		testBody:         JSON{"chall_id": 99999, "title": "Remote down", "body": "The remote does not answer"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:         JSON{"chall_id": "hidden", "title": "Remote down", "body": "The remote does not answer"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:       JSON{"chall_id": "", "title": "Remote down", "body": "The remote does not answer"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"title": "Scoreboard", "body": "Our solve is missing"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	hidden := test_utils.CreateChallenge(t, "hidden", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+
+	// The organizers reply to the tickets, without opening them
+	admin.Post("/tickets", JSON{"title": "Remote down", "body": "The remote does not answer"}, http.StatusForbidden)
+	admin.CheckResponse(errorf(consts.Forbidden))
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/tickets", JSON{"title": "Remote down", "body": "The remote does not answer"}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+
+	var ids []any
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			switch body["chall_id"] {
+			case "":
+				body["chall_id"] = chall.ID
+			case "hidden":
+				body["chall_id"] = hidden.ID
+			}
+		}
+		session.Post("/tickets", test.testBody, test.expectedStatus)
+		if test.expectedStatus != http.StatusOK {
+			session.CheckResponse(test.expectedResponse)
+			continue
+		}
+		ids = append(ids, Json(session.Body())["id"])
+	}
+
+	session.Get("/tickets", nil, http.StatusOK)
+	body := test_utils.DeleteKeys(session.Body(), "id", "team_id", "created_at", "updated_at")
+	expected := []JSON{
+		{"team_name": "test-team", "chall_id": nil, "chall_name": nil, "title": "Scoreboard", "status": "Open"},
+		{"team_name": "test-team", "chall_id": chall.ID, "chall_name": "chall", "title": "Remote down", "status": "Open"},
+	}
+	test_utils.Compare(t, expected, body)
+
+	// The first message is the body of the ticket
+	session.Get(fmt.Sprintf("/tickets/%v", ids[0]), nil, http.StatusOK)
+	messages := Json(session.Body())["messages"].([]any)
+	if len(messages) != 1 || Json(messages[0])["body"] != "The remote does not answer" || Json(messages[0])["staff"] != false {
+		t.Fatalf("Expected the first message to be the body of the ticket, got %v", messages)
+	}
+}
@@ -0,0 +1,73 @@
+package tickets_get
+
+import (
+	"context"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Message struct {
+	ID          int32     `json:"id"`
+	UserID      *int32    `json:"user_id"`   // Null if the user was deleted
+	UserName    *string   `json:"user_name"` // Null if the user was deleted
+	Staff       bool      `json:"staff"`
+	Body        string    `json:"body"`
+	Attachments []string  `json:"attachments"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type Ticket struct {
+	ID        int32             `json:"id"`
+	TeamID    int32             `json:"team_id"`
+	TeamName  string            `json:"team_name"`
+	ChallID   *int32            `json:"chall_id"`
+	ChallName *string           `json:"chall_name"`
+	Title     string            `json:"title"`
+	Status    sqlc.TicketStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Messages  []Message         `json:"messages"`
+}
+
+func GetTicket(ctx context.Context, ticket *sqlc.GetTicketRow) (*Ticket, error) {
+	rows, err := db.Sql.GetTicketMessages(ctx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Ticket{
+		ID:        ticket.ID,
+		TeamID:    ticket.TeamID,
+		TeamName:  ticket.TeamName,
+		Title:     ticket.Title,
+		Status:    ticket.Status,
+		CreatedAt: ticket.CreatedAt,
+		UpdatedAt: ticket.UpdatedAt,
+		Messages:  make([]Message, 0, len(rows)),
+	}
+	if ticket.ChallID.Valid {
+		res.ChallID = &ticket.ChallID.Int32
+		res.ChallName = &ticket.ChallName.String
+	}
+
+	for _, row := range rows {
+		msg := Message{
+			ID:          row.ID,
+			Staff:       row.Staff,
+			Body:        row.Body,
+			Attachments: row.Attachments,
+			Timestamp:   row.Timestamp,
+		}
+		if row.UserID.Valid {
+			msg.UserID = &row.UserID.Int32
+			msg.UserName = &row.UserName.String
+		}
+		if msg.Attachments == nil {
+			msg.Attachments = []string{}
+		}
+		res.Messages = append(res.Messages, msg)
+	}
+
+	return &res, nil
+}
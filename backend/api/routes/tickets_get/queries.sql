@@ -0,0 +1,8 @@
+-- name: GetTicketMessages :many
+-- Retrieve the messages of a ticket, oldest first
+SELECT ticket_messages.id, ticket_messages.user_id, users.name AS user_name,
+    ticket_messages.staff, ticket_messages.body, ticket_messages.attachments, ticket_messages.timestamp
+  FROM ticket_messages
+  LEFT JOIN users ON users.id = ticket_messages.user_id
+  WHERE ticket_messages.ticket_id = $1
+  ORDER BY ticket_messages.id ASC;
@@ -0,0 +1,42 @@
+package tickets_get
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/tickets"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	ticketIDInt, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+	ticketID := int32(ticketIDInt)
+	valid, err := validator.Var(c, ticketID, "id")
+	if err != nil || !valid {
+		return err
+	}
+
+	ticket, _, err := tickets.Access(c.Context(), ticketID, uid, tid, role, utils.Can(c, consts.PermTicketsManage))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+	if ticket == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.TicketNotFound)
+	}
+
+	res, err := GetTicket(c.Context(), ticket)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,91 @@
+package tickets_get_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	authorUser := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	test_utils.AddChallengeOwner(t, chall.ID, authorUser.ID, sqlc.OwnerRoleOwner)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/tickets", JSON{"chall_id": chall.ID, "title": "Remote down", "body": "The remote does not answer"}, http.StatusOK)
+	ticketID := Json(session.Body())["id"]
+	author.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Restarted it"}, nil, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test2", "test2@test.test", "testpass", sqlc.UserRolePlayer)
+	session2 := test_utils.NewApiTestSession(t, app)
+	session2.Post("/login", JSON{"email": "test2@test.test", "password": "testpass"}, http.StatusOK)
+	session2.Post("/teams/register", JSON{"name": "test-team2", "password": "teampasswd"}, http.StatusOK)
+
+	session.Get("/tickets/abc", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidParam))
+	session.Get("/tickets/-1", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(test_utils.Format(consts.MinError, "id", 0)))
+	session.Get("/tickets/99999", nil, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.TicketNotFound))
+	session2.Get(fmt.Sprintf("/tickets/%v", ticketID), nil, http.StatusNotFound)
+	session2.CheckResponse(errorf(consts.TicketNotFound))
+
+	// The tickets are managed through the permission, not the user role
+	supportUser := test_utils.RegisterUser(t, "support", "support@test.test", "testpass", sqlc.UserRolePlayer)
+	admin.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermTicketsManage}}, http.StatusOK)
+	admin.Patch("/users/custom-role", JSON{"user_id": supportUser.ID, "custom_role": "support"}, http.StatusOK)
+	support := test_utils.NewApiTestSession(t, app)
+	support.Post("/login", JSON{"email": "support@test.test", "password": "testpass"}, http.StatusOK)
+
+	expected := JSON{
+		"team_name":  "test-team",
+		"chall_id":   chall.ID,
+		"chall_name": "chall",
+		"title":      "Remote down",
+		"status":     "Answered",
+		"messages": []JSON{
+			{"user_name": "test", "staff": false, "body": "The remote does not answer", "attachments": []string{}},
+			{"user_name": "author", "staff": true, "body": "Restarted it", "attachments": []string{}},
+		},
+	}
+	for _, s := range []interface {
+		Get(string, any, int) *http.Response
+		Body(...bool) any
+	}{session, author, admin, support} {
+		s.Get(fmt.Sprintf("/tickets/%v", ticketID), nil, http.StatusOK)
+		body := test_utils.DeleteKeys(s.Body(), "id", "team_id", "user_id", "created_at", "updated_at", "timestamp")
+		test_utils.Compare(t, expected, body)
+	}
+}
@@ -0,0 +1,23 @@
+package tickets_messages_create
+
+import (
+	"context"
+	"database/sql"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func CreateMessage(ctx context.Context, ticketID int32, uid int32, staff bool, body string, attachments []string) (int32, error) {
+	id, err := db.Sql.CreateTicketMessage(ctx, sqlc.CreateTicketMessageParams{
+		Staff:       staff,
+		TicketID:    ticketID,
+		UserID:      sql.NullInt32{Int32: uid, Valid: true},
+		Body:        body,
+		Attachments: attachments,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
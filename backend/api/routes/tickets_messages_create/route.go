@@ -0,0 +1,87 @@
+package tickets_messages_create
+
+import (
+	"context"
+	"path/filepath"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/attachments"
+	"trxd/utils/consts"
+	"trxd/utils/log"
+	"trxd/utils/tickets"
+	"trxd/validator"
+
+	"github.com/go-playground/form/v4"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	TicketID *int32 `form:"ticket_id" validate:"required,id"`
+	Body     string `form:"body" validate:"required,ticket_message"`
+}
+
+type Response struct {
+	ID int32 `json:"id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	multipartForm, err := c.MultipartForm()
+	if err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidMultipartForm)
+	}
+
+	var data Data
+	decoder := form.NewDecoder()
+	if err = decoder.Decode(&data, multipartForm.Value); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidFormData)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	names, headers, err := attachments.FromForm(c, multipartForm)
+	if err != nil || headers == nil {
+		return err
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	ticket, staff, err := tickets.Access(c.Context(), *data.TicketID, uid, tid, role, utils.Can(c, consts.PermTicketsManage))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+	if ticket == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.TicketNotFound)
+	}
+
+	hashes, err := attachments.Save(c, tickets.AttachmentsDir(ticket.ID), headers)
+	if err != nil || hashes == nil {
+		return err
+	}
+
+	// Relative to the directory of the ticket
+	paths := make([]string, len(hashes))
+	for i, hash := range hashes {
+		paths[i] = hash + "/" + filepath.Base(names[i])
+	}
+
+	// The status follows who wrote last, reopening the closed tickets
+	id, err := CreateMessage(c.Context(), ticket.ID, uid, staff, data.Body, paths)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSendingTicketMessage, err)
+	}
+
+	user, err := db.GetUserByID(c.Context(), uid)
+	if err != nil || user == nil {
+		log.Error("Failed to fetch ticket sender:", "id", ticket.ID, "err", err)
+	} else {
+		go tickets.Notify(context.Background(), ticket, user.Name, data.Body, staff)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{ID: id})
+}
@@ -0,0 +1,146 @@
+package tickets_messages_create_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         JSON
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         JSON{"body": "Still down"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"ticket_id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"ticket_id": -1, "body": "Still down"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "TicketID", 0)),
+	},
+	{
+		testBody:         JSON{"ticket_id": "", "body": strings.Repeat("a", consts.MaxTicketMessageLen+1)},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Body", consts.MaxTicketMessageLen)),
+	},
+	{
+		testBody:         JSON{"ticket_id": 99999, "body": "Still down"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.TicketNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	module := test_utils.GetModuleName(t)
+	dir := "/tmp/" + module + "/"
+	test_utils.CreateDir(t, dir)
+	test_utils.CreateFile(t, dir+"log.txt", "connection refused")
+	hash := test_utils.HashFile(t, dir+"log.txt")
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	authorUser := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	test_utils.AddChallengeOwner(t, chall.ID, authorUser.ID, sqlc.OwnerRoleOwner)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+	test_utils.RegisterUser(t, "other", "other@test.test", "testpass", sqlc.UserRoleAuthor)
+	other := test_utils.NewApiTestSession(t, app)
+	other.Post("/login", JSON{"email": "other@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/tickets", JSON{"chall_id": chall.ID, "title": "Remote down", "body": "The remote does not answer"}, http.StatusOK)
+	ticketID := Json(session.Body())["id"]
+
+	test_utils.RegisterUser(t, "test2", "test2@test.test", "testpass", sqlc.UserRolePlayer)
+	session2 := test_utils.NewApiTestSession(t, app)
+	session2.Post("/login", JSON{"email": "test2@test.test", "password": "testpass"}, http.StatusOK)
+	session2.Post("/teams/register", JSON{"name": "test-team2", "password": "teampasswd"}, http.StatusOK)
+
+	session.Post("/tickets/messages", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidMultipartForm))
+
+	for _, test := range testData {
+		if content, ok := test.testBody["ticket_id"]; ok && content == "" {
+			test.testBody["ticket_id"] = ticketID
+		}
+		session.PostMultipart("/tickets/messages", test.testBody, nil, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// Only the team and the authors of the challenge see the ticket
+	session2.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Hello"}, nil, http.StatusNotFound)
+	session2.CheckResponse(errorf(consts.TicketNotFound))
+	other.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Hello"}, nil, http.StatusNotFound)
+	other.CheckResponse(errorf(consts.TicketNotFound))
+
+	checkStatus := func(expected sqlc.TicketStatus) {
+		session.Get(fmt.Sprintf("/tickets/%v", ticketID), nil, http.StatusOK)
+		if status := Json(session.Body())["status"]; status != string(expected) {
+			t.Fatalf("Expected the ticket to be %s, got %v", expected, status)
+		}
+	}
+
+	author.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Restarted it"}, nil, http.StatusOK)
+	checkStatus(sqlc.TicketStatusAnswered)
+
+	session.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Still down"}, []string{dir + "log.txt"}, http.StatusOK)
+	checkStatus(sqlc.TicketStatusOpen)
+
+	admin.Patch("/tickets", JSON{"id": ticketID, "status": sqlc.TicketStatusClosed}, http.StatusOK)
+	admin.PostMultipart("/tickets/messages", JSON{"ticket_id": ticketID, "body": "Fixed"}, nil, http.StatusOK)
+	checkStatus(sqlc.TicketStatusAnswered)
+
+	session.Get(fmt.Sprintf("/tickets/%v", ticketID), nil, http.StatusOK)
+	body := test_utils.DeleteKeys(Json(session.Body())["messages"], "id", "user_id", "timestamp")
+	expected := []JSON{
+		{"user_name": "test", "staff": false, "body": "The remote does not answer", "attachments": []string{}},
+		{"user_name": "author", "staff": true, "body": "Restarted it", "attachments": []string{}},
+		{"user_name": "test", "staff": false, "body": "Still down", "attachments": []string{hash + "/log.txt"}},
+		{"user_name": "admin", "staff": true, "body": "Fixed", "attachments": []string{}},
+	}
+	test_utils.Compare(t, expected, body)
+
+	path := fmt.Sprintf("attachments/tickets/%v/%s/log.txt", ticketID, hash)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Fatalf("Expected attachment file %s to exist", path)
+	}
+}
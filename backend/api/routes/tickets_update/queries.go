@@ -0,0 +1,14 @@
+package tickets_update
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func SetTicketStatus(ctx context.Context, id int32, status sqlc.TicketStatus) error {
+	return db.Sql.SetTicketStatus(ctx, sqlc.SetTicketStatusParams{
+		ID:     id,
+		Status: status,
+	})
+}
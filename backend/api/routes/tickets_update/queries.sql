@@ -0,0 +1,3 @@
+-- name: SetTicketStatus :exec
+-- Change the status of a ticket
+UPDATE tickets SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
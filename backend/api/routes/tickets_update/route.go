@@ -0,0 +1,52 @@
+package tickets_update
+
+import (
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/tickets"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID     *int32            `json:"id" validate:"required,id"`
+	Status sqlc.TicketStatus `json:"status" validate:"required,ticket_status"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	ticket, staff, err := tickets.Access(c.Context(), *data.ID, uid, tid, role, utils.Can(c, consts.PermTicketsManage))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingTickets, err)
+	}
+	if ticket == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.TicketNotFound)
+	}
+
+	// The teams can only close or reopen their tickets
+	if !staff && data.Status == sqlc.TicketStatusAnswered {
+		return utils.Error(c, fiber.StatusForbidden, consts.Forbidden)
+	}
+
+	err = SetTicketStatus(c.Context(), ticket.ID, data.Status)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingTicket, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
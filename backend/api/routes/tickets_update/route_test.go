@@ -0,0 +1,111 @@
+package tickets_update_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"status": "Closed"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": "", "status": "Deleted"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Status", strings.Join(consts.TicketStatusesStr, " "))),
+	},
+	{
+		testBody:         JSON{"id": 99999, "status": "Closed"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.TicketNotFound),
+	},
+	{
+		testBody:         JSON{"id": "", "status": "Answered"},
+		expectedStatus:   http.StatusForbidden,
+		expectedResponse: errorf(consts.Forbidden),
+	},
+	{
+		testBody:       JSON{"id": "", "status": "Closed"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"id": "", "status": "Open"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/tickets", JSON{"title": "Scoreboard", "body": "Our solve is missing"}, http.StatusOK)
+	ticketID := Json(session.Body())["id"]
+
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["id"]; ok && content == "" {
+				body["id"] = ticketID
+			}
+		}
+		session.Patch("/tickets", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// Not about a challenge of the author
+	author.Patch("/tickets", JSON{"id": ticketID, "status": "Closed"}, http.StatusNotFound)
+	author.CheckResponse(errorf(consts.TicketNotFound))
+
+	admin.Patch("/tickets", JSON{"id": ticketID, "status": "Answered"}, http.StatusOK)
+	session.Get(fmt.Sprintf("/tickets/%v", ticketID), nil, http.StatusOK)
+	if status := Json(session.Body())["status"]; status != string(sqlc.TicketStatusAnswered) {
+		t.Fatalf("Expected the ticket to be Answered, got %v", status)
+	}
+}
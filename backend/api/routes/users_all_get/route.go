@@ -9,6 +9,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Response struct {
+	Total int64      `json:"total"`
+	Users []UserData `json:"users"`
+}
+
 func Route(c *fiber.Ctx) error {
 	role := c.Locals("role")
 
@@ -31,8 +36,8 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUsers, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"total": totalUsers,
-		"users": usersData,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		Total: totalUsers,
+		Users: usersData,
 	})
 }
@@ -0,0 +1,29 @@
+package users_custom_role
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// SetUserCustomRole returns false if the user does not exist
+func SetUserCustomRole(ctx context.Context, uid int32, customRole *string) (bool, error) {
+	params := sqlc.SetUserCustomRoleParams{
+		ID: uid,
+	}
+	if customRole != nil {
+		params.CustomRole = sql.NullString{String: *customRole, Valid: true}
+	}
+
+	_, err := db.Sql.SetUserCustomRole(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,3 @@
+-- name: SetUserCustomRole :one
+-- Grant a custom role to a user, or revoke it
+UPDATE users SET custom_role = $2 WHERE id = $1 RETURNING id;
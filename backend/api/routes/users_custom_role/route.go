@@ -0,0 +1,78 @@
+package users_custom_role
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+type Data struct {
+	UserID     *int32  `json:"user_id" validate:"required,id"`
+	CustomRole *string `json:"custom_role" validate:"omitnil,role_name"` // null revokes it
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	user, err := db.GetUserByID(c.Context(), *data.UserID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
+	}
+	if user == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.UserNotFound)
+	}
+	if user.Role == sqlc.UserRoleAdmin && c.Locals("role").(sqlc.UserRole) != sqlc.UserRoleAdmin {
+		return utils.Error(c, fiber.StatusForbidden, consts.AdminRoleProtected)
+	}
+
+	// No one can revoke a role holding permissions they don't hold
+	if user.CustomRole.Valid {
+		role, err := db.GetRole(c.Context(), user.CustomRole.String)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+		}
+		if role != nil && !utils.CanGrant(c, role.Permissions) {
+			return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+		}
+	}
+	if data.CustomRole != nil {
+		role, err := db.GetRole(c.Context(), *data.CustomRole)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingRoles, err)
+		}
+		if role == nil {
+			return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+		}
+		if !utils.CanGrant(c, role.Permissions) {
+			return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+		}
+	}
+
+	updated, err := SetUserCustomRole(c.Context(), *data.UserID, data.CustomRole)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == consts.PGForeignKeyViolation {
+				return utils.Error(c, fiber.StatusNotFound, consts.RoleNotFound)
+			}
+		}
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorChangingUserRole, err)
+	}
+	if !updated {
+		return utils.Error(c, fiber.StatusNotFound, consts.UserNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,132 @@
+package users_custom_role_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+	expectedRole     any
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"custom_role": "support"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"user_id": 99999, "custom_role": "support"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.UserNotFound),
+	},
+	{
+		testBody:         JSON{"user_id": "", "custom_role": "missing"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.RoleNotFound),
+	},
+	{
+		testBody:       JSON{"user_id": "", "custom_role": "support"},
+		expectedStatus: http.StatusOK,
+		expectedRole:   "support",
+	},
+	{
+		testBody:       JSON{"user_id": "", "custom_role": nil},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "support", "permissions": []string{consts.PermSubmissionsRead}}, http.StatusOK)
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["user_id"]; ok && content == "" {
+				test.testBody.(JSON)["user_id"] = staff.ID
+			}
+		}
+		session.Patch("/users/custom-role", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+
+		if test.expectedStatus == http.StatusOK {
+			staffSession := test_utils.NewApiTestSession(t, app)
+			staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+			staffSession.Get("/info", nil, http.StatusOK)
+			test_utils.Compare(t, test.expectedRole, staffSession.Body().(map[string]any)["custom_role"])
+		}
+	}
+}
+
+func TestRouteEscalation(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	admin := test_utils.RegisterUser(t, "admin2", "admin2@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff2", "staff2@test.test", "testpass", sqlc.UserRolePlayer)
+	player := test_utils.RegisterUser(t, "player2", "player2@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": admin.Email, "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite}}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "configs", "permissions": []string{consts.PermConfigsWrite}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "roles"}, http.StatusOK)
+
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+
+	// Roles with permissions the staff doesn't hold can't be granted, not
+	// even to themselves
+	staffSession.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "configs"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": "configs"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+
+	// Nor revoked or replaced
+	session.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": "configs"}, http.StatusOK)
+	staffSession.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": nil}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": "roles"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	session.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": nil}, http.StatusOK)
+
+	// Only admins change the roles of an admin
+	staffSession.Patch("/users/custom-role", JSON{"user_id": admin.ID, "custom_role": "roles"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.AdminRoleProtected))
+	staffSession.Patch("/users/custom-role", JSON{"user_id": admin.ID, "custom_role": nil}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.AdminRoleProtected))
+	session.Patch("/users/custom-role", JSON{"user_id": admin.ID, "custom_role": "roles"}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	staffSession.Patch("/users/custom-role", JSON{"user_id": player.ID, "custom_role": "roles"}, http.StatusOK)
+	staffSession.CheckResponse(nil)
+	staffSession.Get("/info", nil, http.StatusOK)
+	test_utils.Compare(t, []string{consts.PermRolesWrite}, staffSession.Body().(map[string]any)["permissions"])
+}
@@ -3,12 +3,32 @@ package users_info
 import (
 	"fmt"
 	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
+	"trxd/utils/i18n"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+type Info struct {
+	EmailVerification bool   `json:"email_verification"`
+	StartTime         string `json:"start_time,omitempty"`
+	EndTime           string `json:"end_time,omitempty"`
+	*UserInfo                // Only set for logged in users
+}
+
+type UserInfo struct {
+	ID          int32         `json:"id"`
+	Name        string        `json:"name"`
+	Role        sqlc.UserRole `json:"role"`
+	CustomRole  *string       `json:"custom_role"`
+	Permissions []string      `json:"permissions"` // Granted by both roles
+	UserMode    bool          `json:"user_mode"`
+	TeamID      *int32        `json:"team_id"`
+	Locale      string        `json:"locale"` // Chosen by the user or negotiated
+}
+
 func Route(c *fiber.Ctx) error {
 	emailVerification, err := db.GetConfig(c.Context(), "email-verification")
 	if err != nil {
@@ -23,14 +43,10 @@ func Route(c *fiber.Ctx) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
 	}
 
-	info := fiber.Map{
-		"email_verification": emailVerification == "true",
-	}
-	if startTime != "" {
-		info["start_time"] = startTime
-	}
-	if endTime != "" {
-		info["end_time"] = endTime
+	info := Info{
+		EmailVerification: emailVerification == "true",
+		StartTime:         startTime,
+		EndTime:           endTime,
 	}
 
 	uidLocal := c.Locals("uid")
@@ -47,21 +63,37 @@ func Route(c *fiber.Ctx) error {
 	if user == nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, fmt.Errorf("user not found"))
 	}
-	info["id"] = user.ID
-	info["name"] = user.Name
-	info["role"] = user.Role
 
 	userMode, err := db.GetConfig(c.Context(), "user-mode")
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
 	}
-	info["user_mode"] = userMode == "true"
 
 	var teamID *int32
 	if user.TeamID.Valid {
 		teamID = &user.TeamID.Int32
 	}
-	info["team_id"] = teamID
+
+	var customRole *string
+	if user.CustomRole.Valid {
+		customRole = &user.CustomRole.String
+	}
+
+	permissions, _ := c.Locals("permissions").([]string)
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	info.UserInfo = &UserInfo{
+		ID:          user.ID,
+		Name:        user.Name,
+		Role:        user.Role,
+		CustomRole:  customRole,
+		Permissions: permissions,
+		UserMode:    userMode == "true",
+		TeamID:      teamID,
+		Locale:      i18n.Locale(c),
+	}
 
 	return c.Status(fiber.StatusOK).JSON(info)
 }
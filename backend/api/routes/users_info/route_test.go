@@ -6,6 +6,7 @@ import (
 	"time"
 	"trxd/api"
 	"trxd/db/sqlc"
+	"trxd/utils/consts"
 	"trxd/utils/test_utils"
 )
 
@@ -38,8 +39,11 @@ func TestRoute(t *testing.T) {
 		"email_verification": false,
 		"name":               "test",
 		"role":               sqlc.UserRolePlayer,
+		"custom_role":        nil,
+		"permissions":        []string{},
 		"team_id":            nil,
 		"user_mode":          false,
+		"locale":             "en",
 	}
 	session.Get("/info", nil, http.StatusOK)
 	session.CheckFilteredResponse(expected, "id")
@@ -54,9 +58,12 @@ func TestRoute(t *testing.T) {
 		"end_time":           endTime,
 		"name":               "test",
 		"role":               sqlc.UserRolePlayer,
+		"custom_role":        nil,
+		"permissions":        []string{},
 		"start_time":         startTime,
 		"team_id":            nil,
 		"user_mode":          false,
+		"locale":             "en",
 	}
 	session.Get("/info", nil, http.StatusOK)
 	session.CheckFilteredResponse(expected, "id")
@@ -69,10 +76,14 @@ func TestRoute(t *testing.T) {
 		"email_verification": false,
 		"name":               "test",
 		"role":               sqlc.UserRolePlayer,
+		"custom_role":        nil,
+		"permissions":        []string{},
 		"user_mode":          false,
+		"locale":             "en",
 	}
 	session.Get("/info", nil, http.StatusOK)
 	body := session.Body()
+	id := Json(body)["id"]
 	test_utils.DeleteKeys(body, "id")
 	if Json(body)["team_id"] == nil {
 		t.Errorf("Expected team_id to be set, got nil")
@@ -84,4 +95,14 @@ func TestRoute(t *testing.T) {
 	expected["email_verification"] = true
 	session.Get("/info", nil, http.StatusOK)
 	session.CheckFilteredResponse(expected, "id", "team_id")
+
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@email.com", "password": "testpass"}, http.StatusOK)
+	admin.Post("/roles", JSON{"name": "infra", "permissions": []string{consts.PermInstancesRead, consts.PermInstancesManage}}, http.StatusOK)
+	admin.Patch("/users/custom-role", JSON{"user_id": id, "custom_role": "infra"}, http.StatusOK)
+
+	expected["custom_role"] = "infra"
+	expected["permissions"] = []string{consts.PermInstancesManage, consts.PermInstancesRead}
+	session.Get("/info", nil, http.StatusOK)
+	session.CheckFilteredResponse(expected, "id", "team_id")
 }
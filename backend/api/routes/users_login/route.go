@@ -9,16 +9,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	Email    string `json:"email" validate:"required,user_email"`
+	Password string `json:"password" validate:"required,password"`
+}
+
 func Route(c *fiber.Ctx) error {
 	uid := c.Locals("uid")
 	if uid != nil {
 		return utils.Error(c, fiber.StatusForbidden, consts.AlreadyLoggedIn)
 	}
 
-	var data struct {
-		Email    string `json:"email" validate:"required,user_email"`
-		Password string `json:"password" validate:"required,password"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
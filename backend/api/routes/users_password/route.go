@@ -10,11 +10,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	UserID      *int32 `json:"user_id" validate:"omitnil,id"`
+	NewPassword string `json:"new_password" validate:"omitempty,password"`
+}
+
+type Response struct {
+	NewPassword string `json:"new_password"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		UserID      *int32 `json:"user_id" validate:"omitnil,id"`
-		NewPassword string `json:"new_password" validate:"omitempty,password"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -50,7 +56,7 @@ func Route(c *fiber.Ctx) error {
 	if data.NewPassword != "" {
 		return c.SendStatus(fiber.StatusOK)
 	}
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"new_password": newPassword,
+	return c.Status(fiber.StatusOK).JSON(Response{
+		NewPassword: newPassword,
 	})
 }
@@ -0,0 +1,35 @@
+package users_password_reset
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+)
+
+func GetUserByEmail(ctx context.Context, email string) (*sqlc.User, error) {
+	user, err := db.Sql.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// passwordVersion identifies the current password of a user, so that the
+// reset tokens stop working once it changes
+func passwordVersion(user *sqlc.User) (string, error) {
+	sum := sha256.Sum256([]byte(user.PasswordSalt + user.PasswordHash))
+	version, err := utils.BytesToHex(sum[:8])
+	if err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
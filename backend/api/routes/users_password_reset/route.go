@@ -0,0 +1,195 @@
+package users_password_reset
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"trxd/api/routes/users_password"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/utils/email"
+	"trxd/utils/i18n"
+	"trxd/utils/jwt"
+	"trxd/utils/log"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validity of the reset links
+const tokenLifetime = time.Hour
+
+// Minimum time between two reset links sent to the same user
+const resetCooldown = 5 * time.Minute
+
+func cooldownKey(uid int32) string {
+	return fmt.Sprintf("password-reset:%d", uid)
+}
+
+type Data struct {
+	Email       string `json:"email" validate:"omitempty,user_email"`
+	Token       string `json:"token" validate:"omitempty,jwt"`
+	NewPassword string `json:"new_password" validate:"omitempty,password"`
+}
+
+// Route sends a reset link to the email of a user or, given the token of the
+// link, sets the new password
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	switch {
+	case data.Token != "" && data.NewPassword != "":
+		return resetPassword(c, data.Token, data.NewPassword)
+	case data.Email != "":
+		return sendResetLink(c, data.Email)
+	default:
+		return utils.Error(c, fiber.StatusBadRequest, consts.MissingRequiredFields)
+	}
+}
+
+// sendResetLink answers the same whether a user has the email or not, and
+// whether the link was sent or not, not to disclose the registered ones
+func sendResetLink(c *fiber.Ctx, to string) error {
+	domain, err := db.GetConfig(c.Context(), "domain")
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+	}
+	if domain == "" {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.InvalidDomain)
+	}
+
+	err = email.InitEmailClientFromConfigs(c.Context())
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorInitializingEmailClient, err)
+	}
+
+	user, err := GetUserByEmail(c.Context(), to)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
+	}
+	if user == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	sent, err := startCooldown(c.Context(), user.ID)
+	if err != nil {
+		log.Error("Failed to start the password reset cooldown:", "user", user.ID, "err", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if !sent {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	err = mailResetLink(c, user, domain)
+	if err != nil {
+		log.Error("Failed to send password reset:", "user", user.ID, "err", err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// startCooldown reports whether no reset link was sent to the user in the
+// last resetCooldown, starting a new one. The in-memory storage ignores the
+// expiration, so the end of the cooldown is stored as the value too.
+func startCooldown(ctx context.Context, uid int32) (bool, error) {
+	key := cooldownKey(uid)
+	until := strconv.FormatInt(time.Now().Add(resetCooldown).Unix(), 10)
+
+	ok, err := db.StorageSetNX(ctx, key, until, resetCooldown)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	val, err := db.StorageGet(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if val != nil {
+		end, err := strconv.ParseInt(*val, 10, 64)
+		if err == nil && time.Now().Unix() < end {
+			return false, nil
+		}
+	}
+
+	err = db.StorageDelete(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	return db.StorageSetNX(ctx, key, until, resetCooldown)
+}
+
+func mailResetLink(c *fiber.Ctx, user *sqlc.User, domain string) error {
+	version, err := passwordVersion(user)
+	if err != nil {
+		return err
+	}
+	signed, err := jwt.GenerateJWT(c.Context(), jwt.Map{
+		"reset":    user.ID,
+		"password": version,
+		"exp":      time.Now().Add(tokenLifetime).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	locale := i18n.Locale(c)
+	if user.Locale.Valid {
+		locale = user.Locale.String
+	}
+	msg, err := email.RenderTemplate(c.Context(), sqlc.EmailTemplateNamePasswordReset, locale, &email.TemplateData{
+		Link: fmt.Sprintf("http://%s/reset-password?token=%s", domain, signed),
+	})
+	if err != nil {
+		return err
+	}
+
+	return email.SendEmail(c.Context(), user.Email, msg.Subject, msg.Html, msg.Text)
+}
+
+func resetPassword(c *fiber.Ctx, token string, newPassword string) error {
+	claims, err := jwt.ParseAndValidateJWT(c.Context(), token)
+	if err != nil {
+		return utils.Error(c, fiber.StatusUnauthorized, consts.InvalidToken)
+	}
+
+	uid, ok := claims["reset"].(float64)
+	if !ok {
+		return utils.Error(c, fiber.StatusUnauthorized, consts.InvalidToken)
+	}
+
+	user, err := db.GetUserByID(c.Context(), int32(uid))
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
+	}
+	if user == nil {
+		return utils.Error(c, fiber.StatusUnauthorized, consts.InvalidToken)
+	}
+
+	// The token is consumed by the password change
+	version, err := passwordVersion(user)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorResettingUserPassword, err)
+	}
+	if claims["password"] != version {
+		return utils.Error(c, fiber.StatusUnauthorized, consts.InvalidToken)
+	}
+
+	err = users_password.ResetUserPassword(c.Context(), user.ID, newPassword)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorResettingUserPassword, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
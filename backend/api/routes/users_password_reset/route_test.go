@@ -0,0 +1,139 @@
+package users_password_reset_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+	"trxd/api"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/jwt"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"token": "AAA"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJWT),
+	},
+	{
+		testBody:         JSON{"email": "invalid"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidEmail),
+	},
+	{
+		testBody:         JSON{"email": "test@test.test", "new_password": "short"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "NewPassword", consts.MinPasswordLen)),
+	},
+	{
+		testBody:       JSON{"email": "missing@test.test"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+// version matches the password version signed in the reset tokens
+func version(t *testing.T, uid int32) string {
+	user, err := db.GetUserByID(t.Context(), uid)
+	if err != nil || user == nil {
+		t.Fatalf("Failed to fetch user: %v", err)
+	}
+	sum := sha256.Sum256([]byte(user.PasswordSalt + user.PasswordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+func token(t *testing.T, claims jwt.Map) string {
+	signed, err := jwt.GenerateJWT(t.Context(), claims)
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+	return signed
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	user := test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+
+	// Nothing listens on the email server, sending fails
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/password-reset", JSON{"email": "missing@test.test"}, http.StatusInternalServerError)
+	session.CheckResponse(errorf(consts.InvalidDomain))
+	test_utils.UpdateConfig(t, "domain", "test.test")
+	test_utils.UpdateConfig(t, "email-server", "127.0.0.1")
+	test_utils.UpdateConfig(t, "email-port", "1")
+	test_utils.UpdateConfig(t, "email-addr", "ctf@test.test")
+
+	for _, test := range testData {
+		session := test_utils.NewApiTestSession(t, app)
+		session.Post("/password-reset", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// A known email is answered like a missing one, even if sending fails
+	session.Post("/password-reset", JSON{"email": "test@test.test"}, http.StatusOK)
+	session.CheckResponse(nil)
+	cooldown, err := db.StorageGet(t.Context(), fmt.Sprintf("password-reset:%d", user.ID))
+	if err != nil || cooldown == nil {
+		t.Fatalf("Expected a cooldown after a reset link: %v", err)
+	}
+	session.Post("/password-reset", JSON{"email": "test@test.test"}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	signed := token(t, jwt.Map{"email": "test@test.test"})
+	session.Post("/password-reset", JSON{"token": signed, "new_password": "newpass1"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.InvalidToken))
+
+	signed = token(t, jwt.Map{"reset": user.ID, "password": "0000000000000000", "exp": exp})
+	session.Post("/password-reset", JSON{"token": signed, "new_password": "newpass1"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.InvalidToken))
+
+	signed = token(t, jwt.Map{"reset": user.ID, "password": version(t, user.ID), "exp": time.Now().Add(-time.Minute).Unix()})
+	session.Post("/password-reset", JSON{"token": signed, "new_password": "newpass1"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.InvalidToken))
+
+	signed = token(t, jwt.Map{"reset": user.ID, "password": version(t, user.ID), "exp": exp})
+	session.Post("/password-reset", JSON{"token": signed, "new_password": "newpass1"}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	// The token is consumed by the password change
+	session.Post("/password-reset", JSON{"token": signed, "new_password": "newpass2"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.InvalidToken))
+
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusUnauthorized)
+	session.CheckResponse(errorf(consts.InvalidCredentials))
+	session.Post("/login", JSON{"email": "test@test.test", "password": "newpass1"}, http.StatusOK)
+	session.CheckResponse(nil)
+}
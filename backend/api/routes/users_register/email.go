@@ -4,19 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"trxd/db"
+	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
 	"trxd/utils/email"
+	"trxd/utils/i18n"
 	"trxd/utils/jwt"
 	"trxd/validator"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// TODO: move these into consts
-const SUBJECT = "Email Verification Required"
-const BODY_TEMPLATE = "Hello,\n\nTo Confirm your email address, please click the link below:\nhttp://%s/api/register?token=%s\n\nThank you!"
-
 func verifyMailEnabled(c *fiber.Ctx) (bool, error) {
 	verification, err := db.GetConfig(c.Context(), "email-verification")
 	if err != nil {
@@ -63,8 +61,14 @@ func registerViaMail(c *fiber.Ctx, registerEmail string) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSigningVerificationToken, err)
 	}
 
-	body := fmt.Sprintf(BODY_TEMPLATE, domain, signed)
-	err = email.SendEmail(c.Context(), registerEmail, SUBJECT, body)
+	msg, err := email.RenderTemplate(c.Context(), sqlc.EmailTemplateNameVerification, i18n.Locale(c), &email.TemplateData{
+		Link: fmt.Sprintf("http://%s/api/register?token=%s", domain, signed),
+	})
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorRenderingEmail, err)
+	}
+
+	err = email.SendEmail(c.Context(), registerEmail, msg.Subject, msg.Html, msg.Text)
 	if err != nil {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSendingVerificationEmail, err)
 	}
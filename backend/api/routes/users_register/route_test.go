@@ -5,7 +5,9 @@ import (
 	"strings"
 	"testing"
 	"trxd/api"
+	"trxd/utils"
 	"trxd/utils/consts"
+	"trxd/utils/i18n"
 	jwt_utils "trxd/utils/jwt"
 	"trxd/utils/test_utils"
 
@@ -190,6 +192,7 @@ func TestRoute(t *testing.T) {
 		"role":               "Player",
 		"team_id":            nil,
 		"user_mode":          false,
+		"locale":             "en",
 	}
 	session.Get("/info", nil, http.StatusOK)
 	session.CheckFilteredResponse(expected, "id")
@@ -206,3 +209,84 @@ func TestRoute(t *testing.T) {
 	session.Post("/login", JSON{"email": "m@m.m", "password": "12345678"}, http.StatusOK)
 	session.CheckResponse(nil)
 }
+
+func TestStructuredErrors(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.UpdateConfig(t, "allow-register", "true")
+	session := test_utils.NewApiTestSession(t, app)
+	session.Header.Set(utils.HeaderErrorFormat, utils.ErrorFormatStructured)
+
+	session.Post("/register", JSON{"name": "structured", "email": "invalid", "password": "short"}, http.StatusBadRequest)
+	session.CheckResponse(JSON{"errors": []JSON{
+		{"code": "invalid_email", "message": consts.InvalidEmail, "field": "email"},
+		{"code": "min_error", "message": test_utils.Format(consts.MinError, "Password", consts.MinPasswordLen), "field": "password"},
+	}})
+
+	session.Post("/register", JSON{"name": "structured", "email": "structured@test.test", "password": "testpass"}, http.StatusOK)
+	session.CheckResponse(nil)
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/register", JSON{"name": "structured", "email": "structured@test.test", "password": "testpass"}, http.StatusConflict)
+	session.CheckResponse(errorf(consts.UserAlreadyExists))
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Header.Set(utils.HeaderErrorFormat, utils.ErrorFormatStructured)
+	session.Post("/register", JSON{"name": "structured", "email": "structured@test.test", "password": "testpass"}, http.StatusConflict)
+	session.CheckResponse(JSON{"errors": []JSON{
+		{"code": "user_already_exists", "message": consts.UserAlreadyExists},
+	}})
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.UpdateConfig(t, "allow-register", "true")
+	session := test_utils.NewApiTestSession(t, app)
+	session.Header.Set("Accept-Language", "it-IT,it;q=0.9,en;q=0.8")
+	session.Header.Set(utils.HeaderErrorFormat, utils.ErrorFormatStructured)
+
+	session.Post("/register", JSON{"name": "localized", "email": "invalid", "password": "short"}, http.StatusBadRequest)
+	session.CheckResponse(JSON{"errors": []JSON{
+		{"code": "invalid_email", "message": i18n.T("it", consts.InvalidEmail), "field": "email"},
+		{"code": "min_error", "message": test_utils.Format(i18n.T("it", consts.MinError), "Password", consts.MinPasswordLen), "field": "password"},
+	}})
+
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusForbidden)
+	session.CheckResponse(JSON{"errors": []JSON{
+		{"code": "already_registered", "message": i18n.T("it", consts.AlreadyRegistered)},
+	}})
+
+	// The locale chosen by the user takes precedence over Accept-Language
+	session.Header.Set("Accept-Language", "en")
+	session.Patch("/users", JSON{"locale": "it"}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusForbidden)
+	session.CheckResponse(JSON{"errors": []JSON{
+		{"code": "already_registered", "message": i18n.T("it", consts.AlreadyRegistered)},
+	}})
+
+	session.Patch("/users", JSON{"locale": ""}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Header.Del(utils.HeaderErrorFormat)
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.AlreadyRegistered))
+
+	// The compatibility format stays in English whatever the locale
+	session.Header.Set("Accept-Language", "it-IT,it;q=0.9,en;q=0.8")
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.AlreadyRegistered))
+	session.Patch("/users", JSON{"locale": "it"}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Post("/register", JSON{"name": "localized", "email": "localized@test.test", "password": "testpass"}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.AlreadyRegistered))
+
+	session = test_utils.NewApiTestSession(t, app)
+	session.Header.Set("Accept-Language", "it-IT,it;q=0.9,en;q=0.8")
+	session.Post("/register", JSON{"name": "localized2", "email": "invalid", "password": "testpass"}, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidEmail))
+}
@@ -1,6 +1,7 @@
 package users_role
 
 import (
+	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/utils"
 	"trxd/utils/consts"
@@ -9,11 +10,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	UserID  *int32        `json:"user_id" validate:"required,id"`
+	NewRole sqlc.UserRole `json:"new_role" validate:"required,user_role"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		UserID  *int32        `json:"user_id" validate:"required,id"`
-		NewRole sqlc.UserRole `json:"new_role" validate:"required,user_role"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
@@ -26,6 +29,20 @@ func Route(c *fiber.Ctx) error {
 	if data.NewRole == sqlc.UserRoleAdmin {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidRole)
 	}
+	if !utils.CanGrant(c, consts.RolePermissions[data.NewRole]) {
+		return utils.Error(c, fiber.StatusForbidden, consts.PermissionNotHeld)
+	}
+
+	user, err := db.GetUserByID(c.Context(), *data.UserID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingUser, err)
+	}
+	if user == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.UserNotFound)
+	}
+	if user.Role == sqlc.UserRoleAdmin && c.Locals("role").(sqlc.UserRole) != sqlc.UserRoleAdmin {
+		return utils.Error(c, fiber.StatusForbidden, consts.AdminRoleProtected)
+	}
 
 	err = ChangeUserRole(c.Context(), *data.UserID, data.NewRole)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 	"trxd/api"
+	"trxd/db/sqlc"
 	"trxd/utils/consts"
 	"trxd/utils/test_utils"
 )
@@ -142,3 +143,38 @@ func TestRoute(t *testing.T) {
 		t.Fatalf("Expected score to be restored, got %v", Json(body)["score"])
 	}
 }
+
+func TestRouteEscalation(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	admin := test_utils.RegisterUser(t, "admin2", "admin2@test.test", "testpass", sqlc.UserRoleAdmin)
+	staff := test_utils.RegisterUser(t, "staff2", "staff2@test.test", "testpass", sqlc.UserRolePlayer)
+	player := test_utils.RegisterUser(t, "player2", "player2@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": admin.Email, "password": "testpass"}, http.StatusOK)
+	session.Post("/roles", JSON{"name": "roles", "permissions": []string{consts.PermRolesWrite}}, http.StatusOK)
+	session.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "roles"}, http.StatusOK)
+
+	staffSession := test_utils.NewApiTestSession(t, app)
+	staffSession.Post("/login", JSON{"email": staff.Email, "password": "testpass"}, http.StatusOK)
+
+	// Only admins change the role of an admin
+	staffSession.Patch("/users/role", JSON{"user_id": admin.ID, "new_role": "Spectator"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.AdminRoleProtected))
+	session.Get(fmt.Sprintf("/users/%d", admin.ID), nil, http.StatusOK)
+	if Json(session.Body())["role"] != "Admin" {
+		t.Fatalf("Expected role to be 'Admin', got '%v'", Json(session.Body())["role"])
+	}
+
+	// The Author role grants permissions the staff doesn't hold
+	staffSession.Patch("/users/role", JSON{"user_id": player.ID, "new_role": "Author"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+	staffSession.Patch("/users/role", JSON{"user_id": staff.ID, "new_role": "Author"}, http.StatusForbidden)
+	staffSession.CheckResponse(errorf(consts.PermissionNotHeld))
+
+	staffSession.Patch("/users/role", JSON{"user_id": player.ID, "new_role": "Spectator"}, http.StatusOK)
+	staffSession.CheckResponse(nil)
+	staffSession.Patch("/users/role", JSON{"user_id": 99999, "new_role": "Spectator"}, http.StatusNotFound)
+	staffSession.CheckResponse(errorf(consts.UserNotFound))
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/lib/pq"
 )
 
-func UpdateUser(ctx context.Context, tx *sql.Tx, userID int32, name string, country *string) error {
+func UpdateUser(ctx context.Context, tx *sql.Tx, userID int32, name string, country *string, locale *string) error {
 	params := sqlc.UpdateUserParams{
 		ID:   userID,
 		Name: sql.NullString{String: name, Valid: name != ""},
@@ -19,6 +19,10 @@ func UpdateUser(ctx context.Context, tx *sql.Tx, userID int32, name string, coun
 	if country != nil {
 		params.Country = sql.NullString{String: *country, Valid: true}
 	}
+	// An empty locale goes back to the negotiated one
+	if locale != nil {
+		params.Locale = sql.NullString{String: *locale, Valid: true}
+	}
 	sqlTx := db.Sql.WithTx(tx)
 	err := sqlTx.UpdateUser(ctx, params)
 	if err != nil {
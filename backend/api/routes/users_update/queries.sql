@@ -3,5 +3,6 @@
 UPDATE users
 SET
   name = COALESCE(sqlc.narg('name'), name),
-  country = COALESCE(sqlc.narg('country'), country)
+  country = COALESCE(sqlc.narg('country'), country),
+  locale = CASE WHEN sqlc.narg('locale')::VARCHAR IS NULL THEN locale ELSE NULLIF(sqlc.narg('locale'), '') END
 WHERE id = $1;
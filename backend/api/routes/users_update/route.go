@@ -10,16 +10,19 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type Data struct {
+	Name    string  `json:"name" validate:"user_name"`
+	Country *string `json:"country" validate:"omitempty,country"`
+	Locale  *string `json:"locale" validate:"omitempty,locale"`
+}
+
 func Route(c *fiber.Ctx) error {
-	var data struct {
-		Name    string  `json:"name" validate:"user_name"`
-		Country *string `json:"country" validate:"omitempty,country"`
-	}
+	var data Data
 	if err := c.BodyParser(&data); err != nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
 	}
 
-	if data.Name == "" && data.Country == nil {
+	if data.Name == "" && data.Country == nil && data.Locale == nil {
 		return utils.Error(c, fiber.StatusBadRequest, consts.MissingRequiredFields)
 	}
 	valid, err := validator.Struct(c, data)
@@ -35,7 +38,7 @@ func Route(c *fiber.Ctx) error {
 	}
 	defer db.Rollback(tx)
 
-	err = UpdateUser(c.Context(), tx, uid, data.Name, data.Country)
+	err = UpdateUser(c.Context(), tx, uid, data.Name, data.Country, data.Locale)
 	if err != nil {
 		if err.Error() == "[name already taken]" {
 			return utils.Error(c, fiber.StatusConflict, consts.NameAlreadyTaken)
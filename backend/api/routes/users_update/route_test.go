@@ -82,6 +82,19 @@ var testData = []struct {
 		testBody:       JSON{"name": "cc"},
 		expectedStatus: http.StatusOK,
 	},
+	{
+		testBody:         JSON{"locale": "xx"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.OneOfError, "Locale", strings.Join(consts.Locales, " "))),
+	},
+	{
+		testBody:       JSON{"locale": "it"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"locale": ""},
+		expectedStatus: http.StatusOK,
+	},
 }
 
 func TestRoute(t *testing.T) {
@@ -99,6 +112,15 @@ func TestRoute(t *testing.T) {
 		session.CheckResponse(test.expectedResponse)
 	}
 
+	session.Patch("/users", JSON{"locale": "it"}, http.StatusOK)
+	session.CheckResponse(nil)
+	session.Get("/info", nil, http.StatusOK)
+	if Json(session.Body())["locale"] != "it" {
+		t.Fatal("Expected the chosen locale")
+	}
+	session.Patch("/users", JSON{"locale": ""}, http.StatusOK)
+	session.CheckResponse(nil)
+
 	app2 := api.SetupApp(t.Context())
 	defer api.Shutdown(app2)
 	test_utils.UpdateConfig(t, "user-mode", "true")
@@ -0,0 +1,39 @@
+package writeups_accepted
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// GetWriteupChallenge returns the challenge of the writeup, nil if it does
+// not exist
+func GetWriteupChallenge(ctx context.Context, id int32) (*int32, error) {
+	challID, err := db.Sql.GetWriteupChallenge(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &challID, nil
+}
+
+// SetAccepted returns false if the writeup does not exist
+func SetAccepted(ctx context.Context, id int32, accepted bool) (bool, error) {
+	_, err := db.Sql.SetWriteupAccepted(ctx, sqlc.SetWriteupAcceptedParams{
+		ID:       id,
+		Accepted: accepted,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,7 @@
+-- name: GetWriteupChallenge :one
+-- Retrieve the challenge of a writeup
+SELECT chall_id FROM writeups WHERE id = $1;
+
+-- name: SetWriteupAccepted :one
+-- Mark a writeup as accepted by the authors, or remove the mark
+UPDATE writeups SET accepted = $2 WHERE id = $1 RETURNING id;
@@ -0,0 +1,56 @@
+package writeups_accepted
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID       *int32 `json:"id" validate:"required,id"`
+	Accepted *bool  `json:"accepted" validate:"required"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	challID, err := GetWriteupChallenge(c.Context(), *data.ID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingWriteups, err)
+	}
+	if challID == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+	}
+
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *challID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
+	updated, err := SetAccepted(c.Context(), *data.ID, *data.Accepted)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingWriteup, err)
+	}
+	if !updated {
+		return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
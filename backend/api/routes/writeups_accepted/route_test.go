@@ -0,0 +1,108 @@
+package writeups_accepted_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"accepted": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": 99999, "accepted": true},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+	{
+		testBody:       JSON{"id": "", "accepted": true},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	authorUser := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+	author.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "writeup"}, http.StatusOK)
+	id := Json(session.Body())["id"]
+
+	session.Patch("/writeups/accepted", JSON{"id": id, "accepted": true}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+
+	// The authors only moderate the writeups of the challenges they own
+	author.Patch("/writeups/accepted", JSON{"id": id, "accepted": true}, http.StatusForbidden)
+	author.CheckResponse(errorf(consts.NotChallengeOwner))
+	test_utils.AddChallengeOwner(t, chall.ID, authorUser.ID, sqlc.OwnerRoleOwner)
+
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["id"]; ok && content == "" {
+				test.testBody.(JSON)["id"] = id
+			}
+		}
+		author.Patch("/writeups/accepted", test.testBody, test.expectedStatus)
+		author.CheckResponse(test.expectedResponse)
+	}
+
+	session.Get("/writeups", nil, http.StatusOK)
+	if Json(List(session.Body())[0])["accepted"] != true {
+		t.Fatal("Expected the writeup to be accepted")
+	}
+
+	// Editing the writeup removes the mark
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "edited"}, http.StatusOK)
+	session.Get("/writeups", nil, http.StatusOK)
+	if Json(List(session.Body())[0])["accepted"] != false {
+		t.Fatal("Expected the edited writeup not to be accepted")
+	}
+}
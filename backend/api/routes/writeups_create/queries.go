@@ -0,0 +1,27 @@
+package writeups_create
+
+import (
+	"context"
+	"database/sql"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+func SubmitWriteup(ctx context.Context, uid int32, tid int32, data *Data) (int32, error) {
+	params := sqlc.SubmitWriteupParams{
+		ChallID: *data.ChallID,
+		UserID:  sql.NullInt32{Int32: uid, Valid: true},
+		Content: data.Content,
+		Url:     data.Url,
+	}
+	if tid != -1 {
+		params.TeamID = sql.NullInt32{Int32: tid, Valid: true}
+	}
+
+	id, err := db.Sql.SubmitWriteup(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,11 @@
+-- name: SubmitWriteup :one
+-- Insert the writeup of a team replacing its previous one, or an official one without a team
+INSERT INTO writeups (chall_id, team_id, user_id, content, url)
+  VALUES (sqlc.arg('chall_id'), sqlc.narg('team_id'), sqlc.arg('user_id'), sqlc.arg('content'), sqlc.arg('url'))
+  ON CONFLICT (team_id, chall_id) DO UPDATE
+    SET user_id = EXCLUDED.user_id,
+      content = EXCLUDED.content,
+      url = EXCLUDED.url,
+      accepted = FALSE,
+      updated_at = CURRENT_TIMESTAMP
+  RETURNING id;
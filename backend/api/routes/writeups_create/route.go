@@ -0,0 +1,71 @@
+package writeups_create
+
+import (
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ChallID *int32 `json:"chall_id" validate:"required,id"`
+	Content string `json:"content" validate:"writeup_content"`
+	Url     string `json:"url" validate:"omitempty,writeup_url"`
+}
+
+type Response struct {
+	ID int32 `json:"id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	// Either written on the platform or hosted elsewhere
+	if (data.Content == "") == (data.Url == "") {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidWriteup)
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	challenge, err := db.GetChallengeByID(c.Context(), *data.ChallID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if challenge == nil || (challenge.Hidden && !utils.Can(c, consts.PermChallengesRead)) {
+		return utils.Error(c, fiber.StatusNotFound, consts.ChallengeNotFound)
+	}
+
+	// The moderators of the challenge, without a team, write the official ones
+	if tid == -1 {
+		if !utils.Can(c, consts.PermWriteupsModerate) {
+			return utils.Error(c, fiber.StatusForbidden, consts.TeamNotFound)
+		}
+		owned, err := db.CanManageChallenge(c.Context(), uid, role, *data.ChallID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+		if !owned {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+	}
+
+	id, err := SubmitWriteup(c.Context(), uid, tid, &data)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorSubmittingWriteup, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(Response{ID: id})
+}
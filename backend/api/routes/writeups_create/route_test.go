@@ -0,0 +1,145 @@
+package writeups_create_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"content": "writeup"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"chall_id": -1, "content": "writeup"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ChallID", 0)),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "content": strings.Repeat("a", consts.MaxWriteupLen+1)},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MaxError, "Content", consts.MaxWriteupLen)),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "url": "example.com"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidHttpUrl),
+	},
+	{
+		testBody:         JSON{"chall_id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidWriteup),
+	},
+	{
+		testBody:         JSON{"chall_id": "", "content": "writeup", "url": "https://example.com"},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidWriteup),
+	},
+	{
+		testBody:         JSON{"chall_id": 99999, "content": "writeup"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.ChallengeNotFound),
+	},
+	{
+		testBody:       JSON{"chall_id": "", "content": "# Writeup"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"chall_id": "", "url": "https://example.com/writeup"},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	hidden := test_utils.CreateChallenge(t, "hidden", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "writeup"}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+
+	var id any
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["chall_id"]; ok && content == "" {
+				test.testBody.(JSON)["chall_id"] = chall.ID
+			}
+		}
+		session.Post("/writeups", test.testBody, test.expectedStatus)
+		if test.expectedStatus != http.StatusOK {
+			session.CheckResponse(test.expectedResponse)
+			continue
+		}
+		// The writeup of a team is replaced
+		body := Json(session.Body())
+		if id != nil && body["id"] != id {
+			t.Fatalf("Expected the writeup %v to be replaced, got %v", id, body["id"])
+		}
+		id = body["id"]
+	}
+
+	session.Post("/writeups", JSON{"chall_id": hidden.ID, "content": "writeup"}, http.StatusNotFound)
+	session.CheckResponse(errorf(consts.ChallengeNotFound))
+
+	// Only the moderators of the challenge write the official ones
+	test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+	author.Post("/writeups", JSON{"chall_id": chall.ID, "content": "official"}, http.StatusForbidden)
+	author.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	// Staffing without a team doesn't make the writeups official
+	staff := test_utils.RegisterUser(t, "staff", "staff@test.test", "testpass", sqlc.UserRolePlayer)
+	admin.Post("/roles", JSON{"name": "staff", "permissions": []string{consts.PermChallengesRead}}, http.StatusOK)
+	admin.Patch("/users/custom-role", JSON{"user_id": staff.ID, "custom_role": "staff"}, http.StatusOK)
+	session = test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "staff@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "official"}, http.StatusForbidden)
+
+	admin.Post("/writeups", JSON{"chall_id": hidden.ID, "content": "official"}, http.StatusOK)
+	official := Json(admin.Body())["id"]
+	admin.Post("/writeups", JSON{"chall_id": hidden.ID, "content": "another official"}, http.StatusOK)
+	if Json(admin.Body())["id"] == official {
+		t.Fatal("Expected the official writeups not to be replaced")
+	}
+}
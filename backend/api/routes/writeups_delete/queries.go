@@ -0,0 +1,33 @@
+package writeups_delete
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// DeleteWriteup returns false if the writeup does not exist or cannot be
+// deleted: besides the ones of the team, official allows deleting the
+// official ones and all any of them
+func DeleteWriteup(ctx context.Context, id int32, tid int32, official bool, all bool) (bool, error) {
+	params := sqlc.DeleteWriteupParams{
+		ID:       id,
+		All:      all,
+		Official: official,
+	}
+	if tid != -1 {
+		params.TeamID = sql.NullInt32{Int32: tid, Valid: true}
+	}
+
+	_, err := db.Sql.DeleteWriteup(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,8 @@
+-- name: DeleteWriteup :one
+-- Delete a writeup of the team, an official one or any of them
+DELETE FROM writeups
+  WHERE id = sqlc.arg('id')
+    AND (sqlc.arg('all')::BOOLEAN
+      OR (sqlc.arg('official')::BOOLEAN AND team_id IS NULL)
+      OR team_id = sqlc.arg('team_id'))
+  RETURNING id;
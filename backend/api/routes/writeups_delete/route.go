@@ -0,0 +1,64 @@
+package writeups_delete
+
+import (
+	"trxd/api/routes/writeups_accepted"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID *int32 `json:"id" validate:"required,id"`
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	uid := c.Locals("uid").(int32)
+	tid := c.Locals("tid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+
+	// The moderators of the challenge also delete the official writeups, the
+	// ones allowed to write any writeup also the ones of the other teams
+	moderate := utils.Can(c, consts.PermWriteupsModerate)
+	write := utils.Can(c, consts.PermWriteupsWrite)
+	owned := false
+	if moderate || write {
+		challID, err := writeups_accepted.GetWriteupChallenge(c.Context(), *data.ID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingWriteups, err)
+		}
+		if challID == nil {
+			return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+		}
+		owned, err = db.CanManageChallenge(c.Context(), uid, role, *challID)
+		if err != nil {
+			return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+		}
+	}
+
+	deleted, err := DeleteWriteup(c.Context(), *data.ID, tid, owned && moderate, owned && write)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorDeletingWriteup, err)
+	}
+	if !deleted {
+		if (moderate || write) && !owned {
+			return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+		}
+		return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,130 @@
+package writeups_delete_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": 99999},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+	{
+		testBody:         JSON{"id": "other"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+	{
+		testBody:         JSON{"id": "official"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+	{
+		testBody:       JSON{"id": "own"},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:         JSON{"id": "own"},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	owner := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	admin.Post("/challenges/owners", JSON{"chall_id": chall.ID, "user_id": owner.ID}, http.StatusOK)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+	author.Post("/writeups", JSON{"chall_id": chall.ID, "content": "official"}, http.StatusOK)
+	ids := map[string]any{"official": Json(author.Body())["id"]}
+
+	test_utils.RegisterUser(t, "other", "other@test.test", "testpass", sqlc.UserRolePlayer)
+	other := test_utils.NewApiTestSession(t, app)
+	other.Post("/login", JSON{"email": "other@test.test", "password": "testpass"}, http.StatusOK)
+	other.Post("/teams/register", JSON{"name": "other-team", "password": "teampasswd"}, http.StatusOK)
+	other.Post("/writeups", JSON{"chall_id": chall.ID, "content": "other"}, http.StatusOK)
+	ids["other"] = Json(other.Body())["id"]
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "own"}, http.StatusOK)
+	ids["own"] = Json(session.Body())["id"]
+
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if name, ok := body["id"].(string); ok {
+				test.testBody.(JSON)["id"] = ids[name]
+			}
+		}
+		session.Delete("/writeups", test.testBody, test.expectedStatus)
+		session.CheckResponse(test.expectedResponse)
+	}
+
+	// The authors delete the official ones of their challenges, the admins any
+	// of them
+	test_utils.RegisterUser(t, "stranger", "stranger@test.test", "testpass", sqlc.UserRoleAuthor)
+	stranger := test_utils.NewApiTestSession(t, app)
+	stranger.Post("/login", JSON{"email": "stranger@test.test", "password": "testpass"}, http.StatusOK)
+	stranger.Delete("/writeups", JSON{"id": ids["official"]}, http.StatusForbidden)
+	stranger.CheckResponse(errorf(consts.NotChallengeOwner))
+
+	author.Delete("/writeups", JSON{"id": ids["other"]}, http.StatusNotFound)
+	author.CheckResponse(errorf(consts.WriteupNotFound))
+	author.Delete("/writeups", JSON{"id": ids["official"]}, http.StatusOK)
+	admin.Delete("/writeups", JSON{"id": ids["other"]}, http.StatusOK)
+
+	admin.Get("/writeups", nil, http.StatusOK)
+	if len(List(admin.Body())) != 0 {
+		t.Fatal("Expected no writeups")
+	}
+}
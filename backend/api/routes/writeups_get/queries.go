@@ -0,0 +1,85 @@
+package writeups_get
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+type Writeup struct {
+	ID        int32     `json:"id"`
+	ChallID   int32     `json:"chall_id"`
+	ChallName string    `json:"chall_name"`
+	TeamID    *int32    `json:"team_id"`   // Null for the official ones
+	TeamName  *string   `json:"team_name"` // Null for the official ones
+	Official  bool      `json:"official"`
+	Content   string    `json:"content"`
+	Url       string    `json:"url"`
+	Accepted  bool      `json:"accepted"`
+	Published *bool     `json:"published,omitempty"`
+	Hidden    *bool     `json:"hidden,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Ended reports whether the end-time has passed, never without one
+func Ended(endTime string) (bool, error) {
+	if endTime == "" {
+		return false, nil
+	}
+
+	end, err := time.Parse(time.RFC3339, endTime)
+	if err != nil {
+		return false, err
+	}
+
+	return !time.Now().Before(end), nil
+}
+
+// GetWriteups returns the writeups visible to the team, all of them for the
+// moderators, only of the challenges of the owner if not -1
+func GetWriteups(ctx context.Context, challID int32, tid int32, all bool, owner int32, ended bool) ([]Writeup, error) {
+	params := sqlc.GetWriteupsParams{
+		All:   all,
+		Owner: sql.NullInt32{Int32: owner, Valid: owner != -1},
+		Ended: ended,
+	}
+	if challID != 0 {
+		params.ChallID = sql.NullInt32{Int32: challID, Valid: true}
+	}
+	if tid != -1 {
+		params.TeamID = sql.NullInt32{Int32: tid, Valid: true}
+	}
+
+	rows, err := db.Sql.GetWriteups(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	writeups := make([]Writeup, 0, len(rows))
+	for _, row := range rows {
+		writeup := Writeup{
+			ID:        row.ID,
+			ChallID:   row.ChallID,
+			ChallName: row.ChallName,
+			Official:  !row.TeamID.Valid,
+			Content:   row.Content,
+			Url:       row.Url,
+			Accepted:  row.Accepted,
+			UpdatedAt: row.UpdatedAt,
+		}
+		if row.TeamID.Valid {
+			writeup.TeamID = &row.TeamID.Int32
+			writeup.TeamName = &row.TeamName.String
+		}
+		// The moderation state is only shown to who can change it
+		if row.Moderated {
+			writeup.Published = &row.Published
+			writeup.Hidden = &row.Hidden
+		}
+		writeups = append(writeups, writeup)
+	}
+
+	return writeups, nil
+}
@@ -0,0 +1,18 @@
+-- name: GetWriteups :many
+-- Retrieve the writeups visible to the team, all of them for the moderators
+-- (of the challenges they own for an owner), grouped by challenge with the
+-- official and accepted ones first
+SELECT writeups.id, writeups.chall_id, challenges.name AS chall_name,
+    writeups.team_id, teams.name AS team_name, writeups.content, writeups.url,
+    writeups.accepted, writeups.published, writeups.hidden, writeups.updated_at,
+    (sqlc.arg('all')::BOOLEAN AND (sqlc.narg('owner')::INTEGER IS NULL OR owners.user_id IS NOT NULL))::BOOLEAN AS moderated
+  FROM writeups
+  JOIN challenges ON challenges.id = writeups.chall_id
+  LEFT JOIN teams ON teams.id = writeups.team_id
+  LEFT JOIN challenge_owners owners ON owners.chall_id = writeups.chall_id AND owners.user_id = sqlc.narg('owner')
+  WHERE (sqlc.narg('chall_id')::INTEGER IS NULL OR writeups.chall_id = sqlc.narg('chall_id'))
+    AND ((sqlc.arg('all')::BOOLEAN AND (sqlc.narg('owner')::INTEGER IS NULL OR owners.user_id IS NOT NULL))
+      OR writeups.team_id = sqlc.arg('team_id')
+      OR (NOT writeups.hidden AND NOT challenges.hidden
+        AND (sqlc.arg('ended')::BOOLEAN OR writeups.published)))
+  ORDER BY writeups.chall_id, writeups.team_id IS NOT NULL, writeups.accepted DESC, writeups.id;
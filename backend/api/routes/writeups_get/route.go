@@ -0,0 +1,47 @@
+package writeups_get
+
+import (
+	"math"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Route(c *fiber.Ctx) error {
+	challID := c.QueryInt("chall_id", 0)
+	if challID < 0 || challID > math.MaxInt32 {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidParam)
+	}
+
+	endTime, err := db.GetConfig(c.Context(), "end-time")
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingConfig, err)
+	}
+	ended, err := Ended(endTime)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorParsingTime, err)
+	}
+
+	tid, ok := c.Locals("tid").(int32)
+	if !ok {
+		tid = -1
+	}
+
+	// The moderators also see the hidden and unpublished ones, the authors
+	// only of the challenges they own
+	all := utils.Can(c, consts.PermWriteupsModerate)
+	owner := int32(-1)
+	if role, ok := c.Locals("role").(sqlc.UserRole); ok && role == sqlc.UserRoleAuthor {
+		owner = c.Locals("uid").(int32)
+	}
+
+	writeups, err := GetWriteups(c.Context(), int32(challID), tid, all, owner, ended)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingWriteups, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(writeups)
+}
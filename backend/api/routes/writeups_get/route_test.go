@@ -0,0 +1,141 @@
+package writeups_get_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+func contents(body any) []string {
+	var contents []string
+	for _, writeup := range List(body) {
+		contents = append(contents, Json(writeup)["content"].(string))
+	}
+	return contents
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+	other := test_utils.CreateChallenge(t, "other", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, other.ID)
+
+	test_utils.RegisterUser(t, "a", "a@test.test", "testpass", sqlc.UserRolePlayer)
+	teamA := test_utils.NewApiTestSession(t, app)
+	teamA.Post("/login", JSON{"email": "a@test.test", "password": "testpass"}, http.StatusOK)
+	teamA.Post("/teams/register", JSON{"name": "team-a", "password": "teampasswd"}, http.StatusOK)
+	teamA.Post("/writeups", JSON{"chall_id": chall.ID, "content": "writeup a"}, http.StatusOK)
+	idA := Json(teamA.Body())["id"]
+
+	test_utils.RegisterUser(t, "b", "b@test.test", "testpass", sqlc.UserRolePlayer)
+	teamB := test_utils.NewApiTestSession(t, app)
+	teamB.Post("/login", JSON{"email": "b@test.test", "password": "testpass"}, http.StatusOK)
+	teamB.Post("/teams/register", JSON{"name": "team-b", "password": "teampasswd"}, http.StatusOK)
+	teamB.Post("/writeups", JSON{"chall_id": chall.ID, "content": "writeup b"}, http.StatusOK)
+	idB := Json(teamB.Body())["id"]
+
+	admin.Post("/writeups", JSON{"chall_id": chall.ID, "content": "official"}, http.StatusOK)
+
+	session := test_utils.NewApiTestSession(t, app)
+	session.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string(nil), contents(session.Body()))
+
+	teamA.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string{"writeup a"}, contents(teamA.Body()))
+
+	admin.Get("/writeups", nil, http.StatusOK)
+	body := admin.Body()
+	test_utils.Compare(t, []string{"official", "writeup a", "writeup b"}, contents(body))
+	test_utils.Compare(t, JSON{
+		"accepted":   false,
+		"chall_id":   chall.ID,
+		"chall_name": "chall",
+		"content":    "official",
+		"hidden":     false,
+		"official":   true,
+		"published":  false,
+		"team_id":    nil,
+		"team_name":  nil,
+		"url":        "",
+	}, test_utils.DeleteKeys(List(body)[0], "id", "updated_at"))
+
+	// The authors moderate only the writeups of the challenges they own
+	authorUser := test_utils.RegisterUser(t, "author", "author@test.test", "testpass", sqlc.UserRoleAuthor)
+	author := test_utils.NewApiTestSession(t, app)
+	author.Post("/login", JSON{"email": "author@test.test", "password": "testpass"}, http.StatusOK)
+	author.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string(nil), contents(author.Body()))
+	test_utils.AddChallengeOwner(t, chall.ID, authorUser.ID, sqlc.OwnerRoleOwner)
+	author.Get("/writeups", nil, http.StatusOK)
+	body = author.Body()
+	test_utils.Compare(t, []string{"official", "writeup a", "writeup b"}, contents(body))
+	if _, ok := Json(List(body)[0])["published"]; !ok {
+		t.Fatal("Expected the moderation state for the owner")
+	}
+
+	// Published before the end by the admins
+	admin.Patch("/writeups", JSON{"id": idB, "published": true}, http.StatusOK)
+	session.Get("/writeups", nil, http.StatusOK)
+	body = session.Body()
+	test_utils.Compare(t, []string{"writeup b"}, contents(body))
+	test_utils.Compare(t, JSON{
+		"accepted":   false,
+		"chall_id":   chall.ID,
+		"chall_name": "chall",
+		"content":    "writeup b",
+		"official":   false,
+		"team_id":    Json(List(body)[0])["team_id"],
+		"team_name":  "team-b",
+		"url":        "",
+	}, test_utils.DeleteKeys(List(body)[0], "id", "updated_at"))
+
+	test_utils.UpdateConfig(t, "end-time", time.Now().Add(-time.Hour).Format(time.RFC3339))
+	session.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string{"official", "writeup a", "writeup b"}, contents(session.Body()))
+
+	admin.Patch("/writeups", JSON{"id": idA, "hidden": true}, http.StatusOK)
+	session.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string{"official", "writeup b"}, contents(session.Body()))
+	teamA.Get("/writeups", nil, http.StatusOK)
+	test_utils.Compare(t, []string{"official", "writeup a", "writeup b"}, contents(teamA.Body()))
+
+	session.Get(fmt.Sprintf("/writeups?chall_id=%d", other.ID), nil, http.StatusOK)
+	test_utils.Compare(t, []string(nil), contents(session.Body()))
+	session.Get(fmt.Sprintf("/writeups?chall_id=%d", chall.ID), nil, http.StatusOK)
+	test_utils.Compare(t, []string{"official", "writeup b"}, contents(session.Body()))
+
+	session.Get("/writeups?chall_id=-1", nil, http.StatusBadRequest)
+	session.CheckResponse(errorf(consts.InvalidParam))
+
+	test_utils.UpdateConfig(t, "end-time", "")
+}
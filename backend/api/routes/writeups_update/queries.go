@@ -0,0 +1,32 @@
+package writeups_update
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+// ModerateWriteup returns false if the writeup does not exist
+func ModerateWriteup(ctx context.Context, data *Data) (bool, error) {
+	params := sqlc.ModerateWriteupParams{
+		ID: *data.ID,
+	}
+	if data.Published != nil {
+		params.Published = sql.NullBool{Bool: *data.Published, Valid: true}
+	}
+	if data.Hidden != nil {
+		params.Hidden = sql.NullBool{Bool: *data.Hidden, Valid: true}
+	}
+
+	_, err := db.Sql.ModerateWriteup(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
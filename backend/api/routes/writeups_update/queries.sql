@@ -0,0 +1,7 @@
+-- name: ModerateWriteup :one
+-- Publish or hide a writeup
+UPDATE writeups
+  SET published = COALESCE(sqlc.narg('published'), published),
+    hidden = COALESCE(sqlc.narg('hidden'), hidden)
+  WHERE id = sqlc.arg('id')
+  RETURNING id;
@@ -0,0 +1,63 @@
+package writeups_update
+
+import (
+	"trxd/api/routes/writeups_accepted"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils"
+	"trxd/utils/consts"
+	"trxd/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Data struct {
+	ID        *int32 `json:"id" validate:"required,id"`
+	Published *bool  `json:"published"` // Visible before the end of the CTF
+	Hidden    *bool  `json:"hidden"`    // Never listed to the players
+}
+
+func Route(c *fiber.Ctx) error {
+	var data Data
+	if err := c.BodyParser(&data); err != nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.InvalidJSON)
+	}
+
+	valid, err := validator.Struct(c, data)
+	if err != nil || !valid {
+		return err
+	}
+
+	if data.Published == nil && data.Hidden == nil {
+		return utils.Error(c, fiber.StatusBadRequest, consts.NoDataToUpdate)
+	}
+
+	challID, err := writeups_accepted.GetWriteupChallenge(c.Context(), *data.ID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingWriteups, err)
+	}
+	if challID == nil {
+		return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+	}
+
+	// A custom role may grant the permission to an author
+	uid := c.Locals("uid").(int32)
+	role := c.Locals("role").(sqlc.UserRole)
+	owned, err := db.CanManageChallenge(c.Context(), uid, role, *challID)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorFetchingChallenge, err)
+	}
+	if !owned {
+		return utils.Error(c, fiber.StatusForbidden, consts.NotChallengeOwner)
+	}
+
+	updated, err := ModerateWriteup(c.Context(), &data)
+	if err != nil {
+		return utils.Error(c, fiber.StatusInternalServerError, consts.ErrorUpdatingWriteup, err)
+	}
+	if !updated {
+		return utils.Error(c, fiber.StatusNotFound, consts.WriteupNotFound)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,106 @@
+package writeups_update_test
+
+import (
+	"net/http"
+	"testing"
+	"trxd/api"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/test_utils"
+)
+
+type JSON map[string]any
+
+func errorf(val any) JSON {
+	return JSON{"error": val}
+}
+
+func Json(val any) map[string]any {
+	return val.(map[string]any)
+}
+
+func List(val any) []any {
+	return val.([]any)
+}
+
+func TestMain(m *testing.M) {
+	test_utils.Main(m)
+}
+
+var testData = []struct {
+	testBody         any
+	expectedStatus   int
+	expectedResponse JSON
+}{
+	{
+		testBody:         nil,
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.InvalidJSON),
+	},
+	{
+		testBody:         JSON{"published": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.MissingRequiredFields),
+	},
+	{
+		testBody:         JSON{"id": -1, "published": true},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(test_utils.Format(consts.MinError, "ID", 0)),
+	},
+	{
+		testBody:         JSON{"id": ""},
+		expectedStatus:   http.StatusBadRequest,
+		expectedResponse: errorf(consts.NoDataToUpdate),
+	},
+	{
+		testBody:         JSON{"id": 99999, "published": true},
+		expectedStatus:   http.StatusNotFound,
+		expectedResponse: errorf(consts.WriteupNotFound),
+	},
+	{
+		testBody:       JSON{"id": "", "published": true},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		testBody:       JSON{"id": "", "hidden": true},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+func TestRoute(t *testing.T) {
+	app := api.SetupApp(t.Context())
+	defer api.Shutdown(app)
+
+	test_utils.RegisterUser(t, "admin", "admin@test.test", "testpass", sqlc.UserRoleAdmin)
+	admin := test_utils.NewApiTestSession(t, app)
+	admin.Post("/login", JSON{"email": "admin@test.test", "password": "testpass"}, http.StatusOK)
+	admin.Post("/categories", JSON{"name": "cat"}, http.StatusOK)
+	chall := test_utils.CreateChallenge(t, "chall", "cat", "test-desc", sqlc.DeployTypeNormal, 1, sqlc.ScoreTypeStatic)
+	test_utils.UnveilChallenge(t, chall.ID)
+
+	test_utils.RegisterUser(t, "test", "test@test.test", "testpass", sqlc.UserRolePlayer)
+	session := test_utils.NewApiTestSession(t, app)
+	session.Post("/login", JSON{"email": "test@test.test", "password": "testpass"}, http.StatusOK)
+	session.Post("/teams/register", JSON{"name": "test-team", "password": "teampasswd"}, http.StatusOK)
+	session.Post("/writeups", JSON{"chall_id": chall.ID, "content": "writeup"}, http.StatusOK)
+	id := Json(session.Body())["id"]
+
+	session.Patch("/writeups", JSON{"id": id, "published": true}, http.StatusForbidden)
+	session.CheckResponse(errorf(consts.Forbidden))
+
+	for _, test := range testData {
+		if body, ok := test.testBody.(JSON); ok && body != nil {
+			if content, ok := body["id"]; ok && content == "" {
+				test.testBody.(JSON)["id"] = id
+			}
+		}
+		admin.Patch("/writeups", test.testBody, test.expectedStatus)
+		admin.CheckResponse(test.expectedResponse)
+	}
+
+	admin.Get("/writeups", nil, http.StatusOK)
+	writeup := Json(List(admin.Body())[0])
+	if writeup["published"] != true || writeup["hidden"] != true {
+		t.Fatalf("Writeup not moderated: %v", writeup)
+	}
+}
@@ -101,3 +101,36 @@ func GetTotalCategoryChallenges(ctx context.Context) ([]sqlc.GetTotalCategoryCha
 
 	return challenges, nil
 }
+
+// GetOwnerRole returns the role of the user among the owners of the
+// challenge, nil if they do not own it
+func GetOwnerRole(ctx context.Context, challengeID int32, userID int32) (*sqlc.OwnerRole, error) {
+	role, err := Sql.GetOwnerRole(ctx, sqlc.GetOwnerRoleParams{
+		ChallID: challengeID,
+		UserID:  userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// CanManageChallenge reports whether a user holding the permission to change
+// challenges or submissions can use it on the challenge: the authors only on
+// the ones they own or co-own, the admins and the custom roles on every one
+func CanManageChallenge(ctx context.Context, userID int32, userRole sqlc.UserRole, challengeID int32) (bool, error) {
+	if userRole != sqlc.UserRoleAuthor {
+		return true, nil
+	}
+
+	role, err := GetOwnerRole(ctx, challengeID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
@@ -203,6 +203,14 @@ func initDB(test ...bool) (bool, error) {
 	return true, nil
 }
 
+// Stats returns the statistics of the connection pool
+func Stats() sql.DBStats {
+	if db == nil {
+		return sql.DBStats{}
+	}
+	return db.Stats()
+}
+
 func BeginTx(ctx context.Context) (*sql.Tx, error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+)
+
+func GetRole(ctx context.Context, name string) (*sqlc.Role, error) {
+	role, err := Sql.GetRole(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// GetPermissions returns the permissions of the user role of the user
+// together with the ones of their custom role
+func GetPermissions(ctx context.Context, user *sqlc.User) ([]string, error) {
+	permissions := slices.Clone(consts.RolePermissions[user.Role])
+	if !user.CustomRole.Valid {
+		return permissions, nil
+	}
+
+	role, err := GetRole(ctx, user.CustomRole.String)
+	if err != nil || role == nil {
+		return permissions, err
+	}
+
+	for _, permission := range role.Permissions {
+		if !slices.Contains(permissions, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions, nil
+}
@@ -39,6 +39,44 @@ func (q *Queries) GetChallengeByID(ctx context.Context, id int32) (Challenge, er
 	return i, err
 }
 
+const getChallengeOwners = `-- name: GetChallengeOwners :many
+SELECT users.id, users.name, challenge_owners.role
+  FROM challenge_owners
+  JOIN users ON users.id = challenge_owners.user_id
+  WHERE challenge_owners.chall_id = $1
+  ORDER BY challenge_owners.role ASC, users.name ASC
+`
+
+type GetChallengeOwnersRow struct {
+	ID   int32     `json:"id"`
+	Name string    `json:"name"`
+	Role OwnerRole `json:"role"`
+}
+
+// Retrieve the owners of a challenge, the Owner first
+func (q *Queries) GetChallengeOwners(ctx context.Context, challID int32) ([]GetChallengeOwnersRow, error) {
+	rows, err := q.query(ctx, q.getChallengeOwnersStmt, getChallengeOwners, challID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChallengeOwnersRow
+	for rows.Next() {
+		var i GetChallengeOwnersRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getDockerConfigsByID = `-- name: GetDockerConfigsByID :one
 SELECT
   image,
@@ -47,19 +85,55 @@ SELECT
   envs,
   COALESCE(NULLIF(lifetime, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-lifetime')) AS lifetime,
   COALESCE(NULLIF(max_memory, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-max-memory')) AS max_memory,
-  COALESCE(NULLIF(max_cpu, ''), (SELECT value FROM configs WHERE key='instance-max-cpu')) AS max_cpu
+  COALESCE(NULLIF(max_cpu, ''), (SELECT value FROM configs WHERE key='instance-max-cpu')) AS max_cpu,
+  team_only,
+  COALESCE(NULLIF(pow_difficulty, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-pow-difficulty')) AS pow_difficulty,
+  COALESCE(max_lifetime, (SELECT value::INTEGER FROM configs WHERE key='instance-max-lifetime')) AS max_lifetime,
+  COALESCE(max_extensions, (SELECT value::INTEGER FROM configs WHERE key='instance-max-extensions')) AS max_extensions,
+  COALESCE(extension_cooldown, (SELECT value::INTEGER FROM configs WHERE key='instance-extension-cooldown')) AS extension_cooldown,
+  COALESCE(extension_window, (SELECT value::INTEGER FROM configs WHERE key='instance-extension-window')) AS extension_window,
+  instance_flag,
+  COALESCE(NULLIF(cpu_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-cpu-threshold')) AS cpu_threshold,
+  COALESCE(NULLIF(memory_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-memory-threshold')) AS memory_threshold,
+  COALESCE(NULLIF(pids_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-pids-threshold')) AS pids_threshold,
+  COALESCE(NULLIF(network_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-network-threshold')) AS network_threshold,
+  COALESCE(NULLIF(threshold_duration, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-threshold-duration')) AS threshold_duration,
+  auto_kill,
+  egress,
+  shared,
+  placement,
+  reserved_memory,
+  reserved_cpu
 FROM docker_configs
 WHERE chall_id = $1
 `
 
 type GetDockerConfigsByIDRow struct {
-	Image      string      `json:"image"`
-	Compose    string      `json:"compose"`
-	HashDomain bool        `json:"hash_domain"`
-	Envs       string      `json:"envs"`
-	Lifetime   interface{} `json:"lifetime"`
-	MaxMemory  interface{} `json:"max_memory"`
-	MaxCpu     interface{} `json:"max_cpu"`
+	Image             string       `json:"image"`
+	Compose           string       `json:"compose"`
+	HashDomain        bool         `json:"hash_domain"`
+	Envs              string       `json:"envs"`
+	Lifetime          interface{}  `json:"lifetime"`
+	MaxMemory         interface{}  `json:"max_memory"`
+	MaxCpu            interface{}  `json:"max_cpu"`
+	TeamOnly          bool         `json:"team_only"`
+	PowDifficulty     interface{}  `json:"pow_difficulty"`
+	MaxLifetime       interface{}  `json:"max_lifetime"`
+	MaxExtensions     interface{}  `json:"max_extensions"`
+	ExtensionCooldown interface{}  `json:"extension_cooldown"`
+	ExtensionWindow   interface{}  `json:"extension_window"`
+	InstanceFlag      bool         `json:"instance_flag"`
+	CpuThreshold      interface{}  `json:"cpu_threshold"`
+	MemoryThreshold   interface{}  `json:"memory_threshold"`
+	PidsThreshold     interface{}  `json:"pids_threshold"`
+	NetworkThreshold  interface{}  `json:"network_threshold"`
+	ThresholdDuration interface{}  `json:"threshold_duration"`
+	AutoKill          bool         `json:"auto_kill"`
+	Egress            EgressPolicy `json:"egress"`
+	Shared            bool         `json:"shared"`
+	Placement         []string     `json:"placement"`
+	ReservedMemory    int32        `json:"reserved_memory"`
+	ReservedCpu       string       `json:"reserved_cpu"`
 }
 
 // Retrieve Docker configurations by challenge ID
@@ -74,6 +148,24 @@ func (q *Queries) GetDockerConfigsByID(ctx context.Context, challID int32) (GetD
 		&i.Lifetime,
 		&i.MaxMemory,
 		&i.MaxCpu,
+		&i.TeamOnly,
+		&i.PowDifficulty,
+		&i.MaxLifetime,
+		&i.MaxExtensions,
+		&i.ExtensionCooldown,
+		&i.ExtensionWindow,
+		&i.InstanceFlag,
+		&i.CpuThreshold,
+		&i.MemoryThreshold,
+		&i.PidsThreshold,
+		&i.NetworkThreshold,
+		&i.ThresholdDuration,
+		&i.AutoKill,
+		&i.Egress,
+		&i.Shared,
+		pq.Array(&i.Placement),
+		&i.ReservedMemory,
+		&i.ReservedCpu,
 	)
 	return i, err
 }
@@ -102,6 +194,23 @@ func (q *Queries) GetHiddenAndAttachments(ctx context.Context, id int32) (GetHid
 	return i, err
 }
 
+const getOwnerRole = `-- name: GetOwnerRole :one
+SELECT role FROM challenge_owners WHERE chall_id = $1 AND user_id = $2
+`
+
+type GetOwnerRoleParams struct {
+	ChallID int32 `json:"chall_id"`
+	UserID  int32 `json:"user_id"`
+}
+
+// Retrieve the role of a user among the owners of a challenge
+func (q *Queries) GetOwnerRole(ctx context.Context, arg GetOwnerRoleParams) (OwnerRole, error) {
+	row := q.queryRow(ctx, q.getOwnerRoleStmt, getOwnerRole, arg.ChallID, arg.UserID)
+	var role OwnerRole
+	err := row.Scan(&role)
+	return role, err
+}
+
 const getTotalCategoryChallenges = `-- name: GetTotalCategoryChallenges :many
 SELECT category, COUNT(*)
 FROM challenges
@@ -138,3 +247,20 @@ func (q *Queries) GetTotalCategoryChallenges(ctx context.Context) ([]GetTotalCat
 	}
 	return items, nil
 }
+
+const setChallengeOwner = `-- name: SetChallengeOwner :exec
+INSERT INTO challenge_owners (chall_id, user_id, role) VALUES ($1, $2, $3)
+  ON CONFLICT (chall_id, user_id) DO UPDATE SET role = EXCLUDED.role
+`
+
+type SetChallengeOwnerParams struct {
+	ChallID int32     `json:"chall_id"`
+	UserID  int32     `json:"user_id"`
+	Role    OwnerRole `json:"role"`
+}
+
+// Add a user to the owners of a challenge, or change their role
+func (q *Queries) SetChallengeOwner(ctx context.Context, arg SetChallengeOwnerParams) error {
+	_, err := q.exec(ctx, q.setChallengeOwnerStmt, setChallengeOwner, arg.ChallID, arg.UserID, arg.Role)
+	return err
+}
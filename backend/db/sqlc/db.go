@@ -33,9 +33,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.checkFlagsStmt, err = db.PrepareContext(ctx, checkFlags); err != nil {
 		return nil, fmt.Errorf("error preparing query CheckFlags: %w", err)
 	}
+	if q.claimDueAnnouncementsStmt, err = db.PrepareContext(ctx, claimDueAnnouncements); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimDueAnnouncements: %w", err)
+	}
+	if q.claimExpiredInstancesStmt, err = db.PrepareContext(ctx, claimExpiredInstances); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimExpiredInstances: %w", err)
+	}
+	if q.countInstancesStmt, err = db.PrepareContext(ctx, countInstances); err != nil {
+		return nil, fmt.Errorf("error preparing query CountInstances: %w", err)
+	}
+	if q.countInstancesByChallengeStmt, err = db.PrepareContext(ctx, countInstancesByChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query CountInstancesByChallenge: %w", err)
+	}
+	if q.createAnnouncementStmt, err = db.PrepareContext(ctx, createAnnouncement); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAnnouncement: %w", err)
+	}
 	if q.createAttachmentStmt, err = db.PrepareContext(ctx, createAttachment); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAttachment: %w", err)
 	}
+	if q.createAuditEntryStmt, err = db.PrepareContext(ctx, createAuditEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditEntry: %w", err)
+	}
 	if q.createCategoryStmt, err = db.PrepareContext(ctx, createCategory); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateCategory: %w", err)
 	}
@@ -51,6 +69,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createInstanceStmt, err = db.PrepareContext(ctx, createInstance); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateInstance: %w", err)
 	}
+	if q.createInstanceEventStmt, err = db.PrepareContext(ctx, createInstanceEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInstanceEvent: %w", err)
+	}
+	if q.createInstanceSecretStmt, err = db.PrepareContext(ctx, createInstanceSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInstanceSecret: %w", err)
+	}
+	if q.createRoleStmt, err = db.PrepareContext(ctx, createRole); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRole: %w", err)
+	}
+	if q.createTicketStmt, err = db.PrepareContext(ctx, createTicket); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTicket: %w", err)
+	}
+	if q.createTicketMessageStmt, err = db.PrepareContext(ctx, createTicketMessage); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTicketMessage: %w", err)
+	}
+	if q.deleteAnnouncementStmt, err = db.PrepareContext(ctx, deleteAnnouncement); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAnnouncement: %w", err)
+	}
 	if q.deleteAttachmentStmt, err = db.PrepareContext(ctx, deleteAttachment); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAttachment: %w", err)
 	}
@@ -60,24 +96,60 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteChallengeStmt, err = db.PrepareContext(ctx, deleteChallenge); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteChallenge: %w", err)
 	}
+	if q.deleteDeploymentStmt, err = db.PrepareContext(ctx, deleteDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteDeployment: %w", err)
+	}
+	if q.deleteEmailTemplateStmt, err = db.PrepareContext(ctx, deleteEmailTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEmailTemplate: %w", err)
+	}
+	if q.deleteFeedbackStmt, err = db.PrepareContext(ctx, deleteFeedback); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFeedback: %w", err)
+	}
 	if q.deleteFlagStmt, err = db.PrepareContext(ctx, deleteFlag); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFlag: %w", err)
 	}
 	if q.deleteInstanceStmt, err = db.PrepareContext(ctx, deleteInstance); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteInstance: %w", err)
 	}
+	if q.deleteRoleStmt, err = db.PrepareContext(ctx, deleteRole); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRole: %w", err)
+	}
 	if q.deleteSubmissionStmt, err = db.PrepareContext(ctx, deleteSubmission); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSubmission: %w", err)
 	}
+	if q.deleteWriteupStmt, err = db.PrepareContext(ctx, deleteWriteup); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWriteup: %w", err)
+	}
+	if q.demoteChallengeOwnerStmt, err = db.PrepareContext(ctx, demoteChallengeOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query DemoteChallengeOwner: %w", err)
+	}
+	if q.extendInstanceStmt, err = db.PrepareContext(ctx, extendInstance); err != nil {
+		return nil, fmt.Errorf("error preparing query ExtendInstance: %w", err)
+	}
+	if q.flagInstanceUsageStmt, err = db.PrepareContext(ctx, flagInstanceUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query FlagInstanceUsage: %w", err)
+	}
 	if q.getAdminStatsStmt, err = db.PrepareContext(ctx, getAdminStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAdminStats: %w", err)
 	}
 	if q.getAllChallengesInfoStmt, err = db.PrepareContext(ctx, getAllChallengesInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllChallengesInfo: %w", err)
 	}
+	if q.getAnnouncementByIDStmt, err = db.PrepareContext(ctx, getAnnouncementByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAnnouncementByID: %w", err)
+	}
+	if q.getAnnouncementsStmt, err = db.PrepareContext(ctx, getAnnouncements); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAnnouncements: %w", err)
+	}
 	if q.getAttachmentHashStmt, err = db.PrepareContext(ctx, getAttachmentHash); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAttachmentHash: %w", err)
 	}
+	if q.getAuditEntriesStmt, err = db.PrepareContext(ctx, getAuditEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditEntries: %w", err)
+	}
+	if q.getAuthorDashboardStmt, err = db.PrepareContext(ctx, getAuthorDashboard); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuthorDashboard: %w", err)
+	}
 	if q.getBadgesFromTeamStmt, err = db.PrepareContext(ctx, getBadgesFromTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query GetBadgesFromTeam: %w", err)
 	}
@@ -93,6 +165,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getChallengeByIDStmt, err = db.PrepareContext(ctx, getChallengeByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetChallengeByID: %w", err)
 	}
+	if q.getChallengeFeedbackStmt, err = db.PrepareContext(ctx, getChallengeFeedback); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChallengeFeedback: %w", err)
+	}
+	if q.getChallengeIDByNameStmt, err = db.PrepareContext(ctx, getChallengeIDByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChallengeIDByName: %w", err)
+	}
+	if q.getChallengeOwnersStmt, err = db.PrepareContext(ctx, getChallengeOwners); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChallengeOwners: %w", err)
+	}
+	if q.getChallengeRatingStmt, err = db.PrepareContext(ctx, getChallengeRating); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChallengeRating: %w", err)
+	}
 	if q.getChallengeSolvesStmt, err = db.PrepareContext(ctx, getChallengeSolves); err != nil {
 		return nil, fmt.Errorf("error preparing query GetChallengeSolves: %w", err)
 	}
@@ -102,9 +186,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getConfigsStmt, err = db.PrepareContext(ctx, getConfigs); err != nil {
 		return nil, fmt.Errorf("error preparing query GetConfigs: %w", err)
 	}
+	if q.getDeploymentStmt, err = db.PrepareContext(ctx, getDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeployment: %w", err)
+	}
 	if q.getDockerConfigsByIDStmt, err = db.PrepareContext(ctx, getDockerConfigsByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetDockerConfigsByID: %w", err)
 	}
+	if q.getEmailTemplateStmt, err = db.PrepareContext(ctx, getEmailTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEmailTemplate: %w", err)
+	}
+	if q.getEmailTemplatesStmt, err = db.PrepareContext(ctx, getEmailTemplates); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEmailTemplates: %w", err)
+	}
 	if q.getFlagsByChallengeStmt, err = db.PrepareContext(ctx, getFlagsByChallenge); err != nil {
 		return nil, fmt.Errorf("error preparing query GetFlagsByChallenge: %w", err)
 	}
@@ -114,12 +207,54 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getInstanceStmt, err = db.PrepareContext(ctx, getInstance); err != nil {
 		return nil, fmt.Errorf("error preparing query GetInstance: %w", err)
 	}
+	if q.getInstanceByHostStmt, err = db.PrepareContext(ctx, getInstanceByHost); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstanceByHost: %w", err)
+	}
+	if q.getInstanceEventsStmt, err = db.PrepareContext(ctx, getInstanceEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstanceEvents: %w", err)
+	}
+	if q.getInstanceFlagOwnerStmt, err = db.PrepareContext(ctx, getInstanceFlagOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstanceFlagOwner: %w", err)
+	}
+	if q.getInstanceUsageStmt, err = db.PrepareContext(ctx, getInstanceUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstanceUsage: %w", err)
+	}
 	if q.getInstancesStmt, err = db.PrepareContext(ctx, getInstances); err != nil {
 		return nil, fmt.Errorf("error preparing query GetInstances: %w", err)
 	}
+	if q.getInstancesToReconcileStmt, err = db.PrepareContext(ctx, getInstancesToReconcile); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstancesToReconcile: %w", err)
+	}
+	if q.getInstancesUsageStmt, err = db.PrepareContext(ctx, getInstancesUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInstancesUsage: %w", err)
+	}
 	if q.getNextInstanceToDeleteStmt, err = db.PrepareContext(ctx, getNextInstanceToDelete); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNextInstanceToDelete: %w", err)
 	}
+	if q.getOwnerRoleStmt, err = db.PrepareContext(ctx, getOwnerRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOwnerRole: %w", err)
+	}
+	if q.getPendingNodeReservationsStmt, err = db.PrepareContext(ctx, getPendingNodeReservations); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingNodeReservations: %w", err)
+	}
+	if q.getRoleStmt, err = db.PrepareContext(ctx, getRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRole: %w", err)
+	}
+	if q.getRolesStmt, err = db.PrepareContext(ctx, getRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRoles: %w", err)
+	}
+	if q.getSharedDeploymentsStmt, err = db.PrepareContext(ctx, getSharedDeployments); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSharedDeployments: %w", err)
+	}
+	if q.getStaleDeploymentsStmt, err = db.PrepareContext(ctx, getStaleDeployments); err != nil {
+		return nil, fmt.Errorf("error preparing query GetStaleDeployments: %w", err)
+	}
+	if q.getSubmissionByIDStmt, err = db.PrepareContext(ctx, getSubmissionByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubmissionByID: %w", err)
+	}
+	if q.getSubmissionChallengeStmt, err = db.PrepareContext(ctx, getSubmissionChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubmissionChallenge: %w", err)
+	}
 	if q.getSubmissionsStmt, err = db.PrepareContext(ctx, getSubmissions); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSubmissions: %w", err)
 	}
@@ -129,6 +264,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTeamByNameStmt, err = db.PrepareContext(ctx, getTeamByName); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTeamByName: %w", err)
 	}
+	if q.getTeamEmailsStmt, err = db.PrepareContext(ctx, getTeamEmails); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTeamEmails: %w", err)
+	}
 	if q.getTeamFromUserStmt, err = db.PrepareContext(ctx, getTeamFromUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTeamFromUser: %w", err)
 	}
@@ -153,9 +291,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTeamsScoreboardGraphStmt, err = db.PrepareContext(ctx, getTeamsScoreboardGraph); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTeamsScoreboardGraph: %w", err)
 	}
+	if q.getTicketStmt, err = db.PrepareContext(ctx, getTicket); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicket: %w", err)
+	}
+	if q.getTicketMessagesStmt, err = db.PrepareContext(ctx, getTicketMessages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTicketMessages: %w", err)
+	}
+	if q.getTicketsStmt, err = db.PrepareContext(ctx, getTickets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTickets: %w", err)
+	}
+	if q.getTotalAuditEntriesStmt, err = db.PrepareContext(ctx, getTotalAuditEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTotalAuditEntries: %w", err)
+	}
 	if q.getTotalCategoryChallengesStmt, err = db.PrepareContext(ctx, getTotalCategoryChallenges); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTotalCategoryChallenges: %w", err)
 	}
+	if q.getTotalInstanceEventsStmt, err = db.PrepareContext(ctx, getTotalInstanceEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTotalInstanceEvents: %w", err)
+	}
 	if q.getTotalSubmissionsStmt, err = db.PrepareContext(ctx, getTotalSubmissions); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTotalSubmissions: %w", err)
 	}
@@ -189,24 +342,75 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUsersStmt, err = db.PrepareContext(ctx, getUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUsers: %w", err)
 	}
+	if q.getUsersEmailsStmt, err = db.PrepareContext(ctx, getUsersEmails); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsersEmails: %w", err)
+	}
+	if q.getWriteupByIDStmt, err = db.PrepareContext(ctx, getWriteupByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWriteupByID: %w", err)
+	}
+	if q.getWriteupChallengeStmt, err = db.PrepareContext(ctx, getWriteupChallenge); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWriteupChallenge: %w", err)
+	}
+	if q.getWriteupsStmt, err = db.PrepareContext(ctx, getWriteups); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWriteups: %w", err)
+	}
+	if q.initProxySecretStmt, err = db.PrepareContext(ctx, initProxySecret); err != nil {
+		return nil, fmt.Errorf("error preparing query InitProxySecret: %w", err)
+	}
+	if q.lockTaskStmt, err = db.PrepareContext(ctx, lockTask); err != nil {
+		return nil, fmt.Errorf("error preparing query LockTask: %w", err)
+	}
+	if q.markAnnouncementUnpublishedStmt, err = db.PrepareContext(ctx, markAnnouncementUnpublished); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkAnnouncementUnpublished: %w", err)
+	}
+	if q.moderateWriteupStmt, err = db.PrepareContext(ctx, moderateWriteup); err != nil {
+		return nil, fmt.Errorf("error preparing query ModerateWriteup: %w", err)
+	}
 	if q.registerTeamStmt, err = db.PrepareContext(ctx, registerTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query RegisterTeam: %w", err)
 	}
 	if q.registerUserStmt, err = db.PrepareContext(ctx, registerUser); err != nil {
 		return nil, fmt.Errorf("error preparing query RegisterUser: %w", err)
 	}
+	if q.removeChallengeOwnerStmt, err = db.PrepareContext(ctx, removeChallengeOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveChallengeOwner: %w", err)
+	}
 	if q.resetTeamPasswordStmt, err = db.PrepareContext(ctx, resetTeamPassword); err != nil {
 		return nil, fmt.Errorf("error preparing query ResetTeamPassword: %w", err)
 	}
 	if q.resetUserPasswordStmt, err = db.PrepareContext(ctx, resetUserPassword); err != nil {
 		return nil, fmt.Errorf("error preparing query ResetUserPassword: %w", err)
 	}
+	if q.setChallengeOwnerStmt, err = db.PrepareContext(ctx, setChallengeOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query SetChallengeOwner: %w", err)
+	}
+	if q.setTicketStatusStmt, err = db.PrepareContext(ctx, setTicketStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetTicketStatus: %w", err)
+	}
+	if q.setUserCustomRoleStmt, err = db.PrepareContext(ctx, setUserCustomRole); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserCustomRole: %w", err)
+	}
+	if q.setWriteupAcceptedStmt, err = db.PrepareContext(ctx, setWriteupAccepted); err != nil {
+		return nil, fmt.Errorf("error preparing query SetWriteupAccepted: %w", err)
+	}
 	if q.submitStmt, err = db.PrepareContext(ctx, submit); err != nil {
 		return nil, fmt.Errorf("error preparing query Submit: %w", err)
 	}
+	if q.submitFeedbackStmt, err = db.PrepareContext(ctx, submitFeedback); err != nil {
+		return nil, fmt.Errorf("error preparing query SubmitFeedback: %w", err)
+	}
+	if q.submitWriteupStmt, err = db.PrepareContext(ctx, submitWriteup); err != nil {
+		return nil, fmt.Errorf("error preparing query SubmitWriteup: %w", err)
+	}
 	if q.toggleChallengesHiddenStmt, err = db.PrepareContext(ctx, toggleChallengesHidden); err != nil {
 		return nil, fmt.Errorf("error preparing query ToggleChallengesHidden: %w", err)
 	}
+	if q.tryLockTaskStmt, err = db.PrepareContext(ctx, tryLockTask); err != nil {
+		return nil, fmt.Errorf("error preparing query TryLockTask: %w", err)
+	}
+	if q.updateAnnouncementStmt, err = db.PrepareContext(ctx, updateAnnouncement); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAnnouncement: %w", err)
+	}
 	if q.updateChallengeStmt, err = db.PrepareContext(ctx, updateChallenge); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateChallenge: %w", err)
 	}
@@ -228,12 +432,24 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateInstanceExpireStmt, err = db.PrepareContext(ctx, updateInstanceExpire); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateInstanceExpire: %w", err)
 	}
+	if q.updateInstanceUsageStmt, err = db.PrepareContext(ctx, updateInstanceUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateInstanceUsage: %w", err)
+	}
+	if q.updateRoleStmt, err = db.PrepareContext(ctx, updateRole); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateRole: %w", err)
+	}
 	if q.updateTeamStmt, err = db.PrepareContext(ctx, updateTeam); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateTeam: %w", err)
 	}
 	if q.updateUserStmt, err = db.PrepareContext(ctx, updateUser); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUser: %w", err)
 	}
+	if q.upsertDeploymentStmt, err = db.PrepareContext(ctx, upsertDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertDeployment: %w", err)
+	}
+	if q.upsertEmailTemplateStmt, err = db.PrepareContext(ctx, upsertEmailTemplate); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertEmailTemplate: %w", err)
+	}
 	if q.userExistsByEmailStmt, err = db.PrepareContext(ctx, userExistsByEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query UserExistsByEmail: %w", err)
 	}
@@ -257,11 +473,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing checkFlagsStmt: %w", cerr)
 		}
 	}
+	if q.claimDueAnnouncementsStmt != nil {
+		if cerr := q.claimDueAnnouncementsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimDueAnnouncementsStmt: %w", cerr)
+		}
+	}
+	if q.claimExpiredInstancesStmt != nil {
+		if cerr := q.claimExpiredInstancesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimExpiredInstancesStmt: %w", cerr)
+		}
+	}
+	if q.countInstancesStmt != nil {
+		if cerr := q.countInstancesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countInstancesStmt: %w", cerr)
+		}
+	}
+	if q.countInstancesByChallengeStmt != nil {
+		if cerr := q.countInstancesByChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countInstancesByChallengeStmt: %w", cerr)
+		}
+	}
+	if q.createAnnouncementStmt != nil {
+		if cerr := q.createAnnouncementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAnnouncementStmt: %w", cerr)
+		}
+	}
 	if q.createAttachmentStmt != nil {
 		if cerr := q.createAttachmentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAttachmentStmt: %w", cerr)
 		}
 	}
+	if q.createAuditEntryStmt != nil {
+		if cerr := q.createAuditEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditEntryStmt: %w", cerr)
+		}
+	}
 	if q.createCategoryStmt != nil {
 		if cerr := q.createCategoryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createCategoryStmt: %w", cerr)
@@ -287,6 +533,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createInstanceStmt: %w", cerr)
 		}
 	}
+	if q.createInstanceEventStmt != nil {
+		if cerr := q.createInstanceEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInstanceEventStmt: %w", cerr)
+		}
+	}
+	if q.createInstanceSecretStmt != nil {
+		if cerr := q.createInstanceSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInstanceSecretStmt: %w", cerr)
+		}
+	}
+	if q.createRoleStmt != nil {
+		if cerr := q.createRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRoleStmt: %w", cerr)
+		}
+	}
+	if q.createTicketStmt != nil {
+		if cerr := q.createTicketStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTicketStmt: %w", cerr)
+		}
+	}
+	if q.createTicketMessageStmt != nil {
+		if cerr := q.createTicketMessageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTicketMessageStmt: %w", cerr)
+		}
+	}
+	if q.deleteAnnouncementStmt != nil {
+		if cerr := q.deleteAnnouncementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAnnouncementStmt: %w", cerr)
+		}
+	}
 	if q.deleteAttachmentStmt != nil {
 		if cerr := q.deleteAttachmentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteAttachmentStmt: %w", cerr)
@@ -302,6 +578,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteChallengeStmt: %w", cerr)
 		}
 	}
+	if q.deleteDeploymentStmt != nil {
+		if cerr := q.deleteDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.deleteEmailTemplateStmt != nil {
+		if cerr := q.deleteEmailTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEmailTemplateStmt: %w", cerr)
+		}
+	}
+	if q.deleteFeedbackStmt != nil {
+		if cerr := q.deleteFeedbackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFeedbackStmt: %w", cerr)
+		}
+	}
 	if q.deleteFlagStmt != nil {
 		if cerr := q.deleteFlagStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFlagStmt: %w", cerr)
@@ -312,11 +603,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteInstanceStmt: %w", cerr)
 		}
 	}
+	if q.deleteRoleStmt != nil {
+		if cerr := q.deleteRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRoleStmt: %w", cerr)
+		}
+	}
 	if q.deleteSubmissionStmt != nil {
 		if cerr := q.deleteSubmissionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteSubmissionStmt: %w", cerr)
 		}
 	}
+	if q.deleteWriteupStmt != nil {
+		if cerr := q.deleteWriteupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWriteupStmt: %w", cerr)
+		}
+	}
+	if q.demoteChallengeOwnerStmt != nil {
+		if cerr := q.demoteChallengeOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing demoteChallengeOwnerStmt: %w", cerr)
+		}
+	}
+	if q.extendInstanceStmt != nil {
+		if cerr := q.extendInstanceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing extendInstanceStmt: %w", cerr)
+		}
+	}
+	if q.flagInstanceUsageStmt != nil {
+		if cerr := q.flagInstanceUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing flagInstanceUsageStmt: %w", cerr)
+		}
+	}
 	if q.getAdminStatsStmt != nil {
 		if cerr := q.getAdminStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAdminStatsStmt: %w", cerr)
@@ -327,11 +643,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAllChallengesInfoStmt: %w", cerr)
 		}
 	}
+	if q.getAnnouncementByIDStmt != nil {
+		if cerr := q.getAnnouncementByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAnnouncementByIDStmt: %w", cerr)
+		}
+	}
+	if q.getAnnouncementsStmt != nil {
+		if cerr := q.getAnnouncementsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAnnouncementsStmt: %w", cerr)
+		}
+	}
 	if q.getAttachmentHashStmt != nil {
 		if cerr := q.getAttachmentHashStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAttachmentHashStmt: %w", cerr)
 		}
 	}
+	if q.getAuditEntriesStmt != nil {
+		if cerr := q.getAuditEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditEntriesStmt: %w", cerr)
+		}
+	}
+	if q.getAuthorDashboardStmt != nil {
+		if cerr := q.getAuthorDashboardStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuthorDashboardStmt: %w", cerr)
+		}
+	}
 	if q.getBadgesFromTeamStmt != nil {
 		if cerr := q.getBadgesFromTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getBadgesFromTeamStmt: %w", cerr)
@@ -357,6 +693,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getChallengeByIDStmt: %w", cerr)
 		}
 	}
+	if q.getChallengeFeedbackStmt != nil {
+		if cerr := q.getChallengeFeedbackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChallengeFeedbackStmt: %w", cerr)
+		}
+	}
+	if q.getChallengeIDByNameStmt != nil {
+		if cerr := q.getChallengeIDByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChallengeIDByNameStmt: %w", cerr)
+		}
+	}
+	if q.getChallengeOwnersStmt != nil {
+		if cerr := q.getChallengeOwnersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChallengeOwnersStmt: %w", cerr)
+		}
+	}
+	if q.getChallengeRatingStmt != nil {
+		if cerr := q.getChallengeRatingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChallengeRatingStmt: %w", cerr)
+		}
+	}
 	if q.getChallengeSolvesStmt != nil {
 		if cerr := q.getChallengeSolvesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getChallengeSolvesStmt: %w", cerr)
@@ -372,11 +728,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getConfigsStmt: %w", cerr)
 		}
 	}
+	if q.getDeploymentStmt != nil {
+		if cerr := q.getDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeploymentStmt: %w", cerr)
+		}
+	}
 	if q.getDockerConfigsByIDStmt != nil {
 		if cerr := q.getDockerConfigsByIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getDockerConfigsByIDStmt: %w", cerr)
 		}
 	}
+	if q.getEmailTemplateStmt != nil {
+		if cerr := q.getEmailTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEmailTemplateStmt: %w", cerr)
+		}
+	}
+	if q.getEmailTemplatesStmt != nil {
+		if cerr := q.getEmailTemplatesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEmailTemplatesStmt: %w", cerr)
+		}
+	}
 	if q.getFlagsByChallengeStmt != nil {
 		if cerr := q.getFlagsByChallengeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getFlagsByChallengeStmt: %w", cerr)
@@ -392,16 +763,86 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getInstanceStmt: %w", cerr)
 		}
 	}
+	if q.getInstanceByHostStmt != nil {
+		if cerr := q.getInstanceByHostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstanceByHostStmt: %w", cerr)
+		}
+	}
+	if q.getInstanceEventsStmt != nil {
+		if cerr := q.getInstanceEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstanceEventsStmt: %w", cerr)
+		}
+	}
+	if q.getInstanceFlagOwnerStmt != nil {
+		if cerr := q.getInstanceFlagOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstanceFlagOwnerStmt: %w", cerr)
+		}
+	}
+	if q.getInstanceUsageStmt != nil {
+		if cerr := q.getInstanceUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstanceUsageStmt: %w", cerr)
+		}
+	}
 	if q.getInstancesStmt != nil {
 		if cerr := q.getInstancesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getInstancesStmt: %w", cerr)
 		}
 	}
+	if q.getInstancesToReconcileStmt != nil {
+		if cerr := q.getInstancesToReconcileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstancesToReconcileStmt: %w", cerr)
+		}
+	}
+	if q.getInstancesUsageStmt != nil {
+		if cerr := q.getInstancesUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInstancesUsageStmt: %w", cerr)
+		}
+	}
 	if q.getNextInstanceToDeleteStmt != nil {
 		if cerr := q.getNextInstanceToDeleteStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNextInstanceToDeleteStmt: %w", cerr)
 		}
 	}
+	if q.getOwnerRoleStmt != nil {
+		if cerr := q.getOwnerRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOwnerRoleStmt: %w", cerr)
+		}
+	}
+	if q.getPendingNodeReservationsStmt != nil {
+		if cerr := q.getPendingNodeReservationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingNodeReservationsStmt: %w", cerr)
+		}
+	}
+	if q.getRoleStmt != nil {
+		if cerr := q.getRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRoleStmt: %w", cerr)
+		}
+	}
+	if q.getRolesStmt != nil {
+		if cerr := q.getRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRolesStmt: %w", cerr)
+		}
+	}
+	if q.getSharedDeploymentsStmt != nil {
+		if cerr := q.getSharedDeploymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSharedDeploymentsStmt: %w", cerr)
+		}
+	}
+	if q.getStaleDeploymentsStmt != nil {
+		if cerr := q.getStaleDeploymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getStaleDeploymentsStmt: %w", cerr)
+		}
+	}
+	if q.getSubmissionByIDStmt != nil {
+		if cerr := q.getSubmissionByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubmissionByIDStmt: %w", cerr)
+		}
+	}
+	if q.getSubmissionChallengeStmt != nil {
+		if cerr := q.getSubmissionChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubmissionChallengeStmt: %w", cerr)
+		}
+	}
 	if q.getSubmissionsStmt != nil {
 		if cerr := q.getSubmissionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSubmissionsStmt: %w", cerr)
@@ -417,6 +858,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTeamByNameStmt: %w", cerr)
 		}
 	}
+	if q.getTeamEmailsStmt != nil {
+		if cerr := q.getTeamEmailsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTeamEmailsStmt: %w", cerr)
+		}
+	}
 	if q.getTeamFromUserStmt != nil {
 		if cerr := q.getTeamFromUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTeamFromUserStmt: %w", cerr)
@@ -457,11 +903,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTeamsScoreboardGraphStmt: %w", cerr)
 		}
 	}
+	if q.getTicketStmt != nil {
+		if cerr := q.getTicketStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketStmt: %w", cerr)
+		}
+	}
+	if q.getTicketMessagesStmt != nil {
+		if cerr := q.getTicketMessagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketMessagesStmt: %w", cerr)
+		}
+	}
+	if q.getTicketsStmt != nil {
+		if cerr := q.getTicketsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTicketsStmt: %w", cerr)
+		}
+	}
+	if q.getTotalAuditEntriesStmt != nil {
+		if cerr := q.getTotalAuditEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTotalAuditEntriesStmt: %w", cerr)
+		}
+	}
 	if q.getTotalCategoryChallengesStmt != nil {
 		if cerr := q.getTotalCategoryChallengesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTotalCategoryChallengesStmt: %w", cerr)
 		}
 	}
+	if q.getTotalInstanceEventsStmt != nil {
+		if cerr := q.getTotalInstanceEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTotalInstanceEventsStmt: %w", cerr)
+		}
+	}
 	if q.getTotalSubmissionsStmt != nil {
 		if cerr := q.getTotalSubmissionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTotalSubmissionsStmt: %w", cerr)
@@ -517,6 +988,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUsersStmt: %w", cerr)
 		}
 	}
+	if q.getUsersEmailsStmt != nil {
+		if cerr := q.getUsersEmailsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsersEmailsStmt: %w", cerr)
+		}
+	}
+	if q.getWriteupByIDStmt != nil {
+		if cerr := q.getWriteupByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWriteupByIDStmt: %w", cerr)
+		}
+	}
+	if q.getWriteupChallengeStmt != nil {
+		if cerr := q.getWriteupChallengeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWriteupChallengeStmt: %w", cerr)
+		}
+	}
+	if q.getWriteupsStmt != nil {
+		if cerr := q.getWriteupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWriteupsStmt: %w", cerr)
+		}
+	}
+	if q.initProxySecretStmt != nil {
+		if cerr := q.initProxySecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing initProxySecretStmt: %w", cerr)
+		}
+	}
+	if q.lockTaskStmt != nil {
+		if cerr := q.lockTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockTaskStmt: %w", cerr)
+		}
+	}
+	if q.markAnnouncementUnpublishedStmt != nil {
+		if cerr := q.markAnnouncementUnpublishedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markAnnouncementUnpublishedStmt: %w", cerr)
+		}
+	}
+	if q.moderateWriteupStmt != nil {
+		if cerr := q.moderateWriteupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing moderateWriteupStmt: %w", cerr)
+		}
+	}
 	if q.registerTeamStmt != nil {
 		if cerr := q.registerTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing registerTeamStmt: %w", cerr)
@@ -527,6 +1038,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing registerUserStmt: %w", cerr)
 		}
 	}
+	if q.removeChallengeOwnerStmt != nil {
+		if cerr := q.removeChallengeOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeChallengeOwnerStmt: %w", cerr)
+		}
+	}
 	if q.resetTeamPasswordStmt != nil {
 		if cerr := q.resetTeamPasswordStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing resetTeamPasswordStmt: %w", cerr)
@@ -537,16 +1053,56 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing resetUserPasswordStmt: %w", cerr)
 		}
 	}
+	if q.setChallengeOwnerStmt != nil {
+		if cerr := q.setChallengeOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setChallengeOwnerStmt: %w", cerr)
+		}
+	}
+	if q.setTicketStatusStmt != nil {
+		if cerr := q.setTicketStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setTicketStatusStmt: %w", cerr)
+		}
+	}
+	if q.setUserCustomRoleStmt != nil {
+		if cerr := q.setUserCustomRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserCustomRoleStmt: %w", cerr)
+		}
+	}
+	if q.setWriteupAcceptedStmt != nil {
+		if cerr := q.setWriteupAcceptedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setWriteupAcceptedStmt: %w", cerr)
+		}
+	}
 	if q.submitStmt != nil {
 		if cerr := q.submitStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing submitStmt: %w", cerr)
 		}
 	}
+	if q.submitFeedbackStmt != nil {
+		if cerr := q.submitFeedbackStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing submitFeedbackStmt: %w", cerr)
+		}
+	}
+	if q.submitWriteupStmt != nil {
+		if cerr := q.submitWriteupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing submitWriteupStmt: %w", cerr)
+		}
+	}
 	if q.toggleChallengesHiddenStmt != nil {
 		if cerr := q.toggleChallengesHiddenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing toggleChallengesHiddenStmt: %w", cerr)
 		}
 	}
+	if q.tryLockTaskStmt != nil {
+		if cerr := q.tryLockTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing tryLockTaskStmt: %w", cerr)
+		}
+	}
+	if q.updateAnnouncementStmt != nil {
+		if cerr := q.updateAnnouncementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAnnouncementStmt: %w", cerr)
+		}
+	}
 	if q.updateChallengeStmt != nil {
 		if cerr := q.updateChallengeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateChallengeStmt: %w", cerr)
@@ -582,6 +1138,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateInstanceExpireStmt: %w", cerr)
 		}
 	}
+	if q.updateInstanceUsageStmt != nil {
+		if cerr := q.updateInstanceUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateInstanceUsageStmt: %w", cerr)
+		}
+	}
+	if q.updateRoleStmt != nil {
+		if cerr := q.updateRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateRoleStmt: %w", cerr)
+		}
+	}
 	if q.updateTeamStmt != nil {
 		if cerr := q.updateTeamStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateTeamStmt: %w", cerr)
@@ -592,6 +1158,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserStmt: %w", cerr)
 		}
 	}
+	if q.upsertDeploymentStmt != nil {
+		if cerr := q.upsertDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.upsertEmailTemplateStmt != nil {
+		if cerr := q.upsertEmailTemplateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertEmailTemplateStmt: %w", cerr)
+		}
+	}
 	if q.userExistsByEmailStmt != nil {
 		if cerr := q.userExistsByEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing userExistsByEmailStmt: %w", cerr)
@@ -634,155 +1210,299 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                             DBTX
-	tx                             *sql.Tx
-	addTeamMemberStmt              *sql.Stmt
-	changeUserRoleStmt             *sql.Stmt
-	checkFlagsStmt                 *sql.Stmt
-	createAttachmentStmt           *sql.Stmt
-	createCategoryStmt             *sql.Stmt
-	createChallengeStmt            *sql.Stmt
-	createConfigStmt               *sql.Stmt
-	createFlagStmt                 *sql.Stmt
-	createInstanceStmt             *sql.Stmt
-	deleteAttachmentStmt           *sql.Stmt
-	deleteCategoryStmt             *sql.Stmt
-	deleteChallengeStmt            *sql.Stmt
-	deleteFlagStmt                 *sql.Stmt
-	deleteInstanceStmt             *sql.Stmt
-	deleteSubmissionStmt           *sql.Stmt
-	getAdminStatsStmt              *sql.Stmt
-	getAllChallengesInfoStmt       *sql.Stmt
-	getAttachmentHashStmt          *sql.Stmt
-	getBadgesFromTeamStmt          *sql.Stmt
-	getCategoriesStmt              *sql.Stmt
-	getCategoryStmt                *sql.Stmt
-	getChallDockerConfigStmt       *sql.Stmt
-	getChallengeByIDStmt           *sql.Stmt
-	getChallengeSolvesStmt         *sql.Stmt
-	getConfigStmt                  *sql.Stmt
-	getConfigsStmt                 *sql.Stmt
-	getDockerConfigsByIDStmt       *sql.Stmt
-	getFlagsByChallengeStmt        *sql.Stmt
-	getHiddenAndAttachmentsStmt    *sql.Stmt
-	getInstanceStmt                *sql.Stmt
-	getInstancesStmt               *sql.Stmt
-	getNextInstanceToDeleteStmt    *sql.Stmt
-	getSubmissionsStmt             *sql.Stmt
-	getTeamByIDStmt                *sql.Stmt
-	getTeamByNameStmt              *sql.Stmt
-	getTeamFromUserStmt            *sql.Stmt
-	getTeamIDByEmailStmt           *sql.Stmt
-	getTeamIDByNameStmt            *sql.Stmt
-	getTeamMembersStmt             *sql.Stmt
-	getTeamSolvesStmt              *sql.Stmt
-	getTeamsPreviewStmt            *sql.Stmt
-	getTeamsScoreboardStmt         *sql.Stmt
-	getTeamsScoreboardGraphStmt    *sql.Stmt
-	getTotalCategoryChallengesStmt *sql.Stmt
-	getTotalSubmissionsStmt        *sql.Stmt
-	getTotalTeamsStmt              *sql.Stmt
-	getTotalUsersStmt              *sql.Stmt
-	getUserByEmailStmt             *sql.Stmt
-	getUserByIDStmt                *sql.Stmt
-	getUserByNameStmt              *sql.Stmt
-	getUserByTeamIDStmt            *sql.Stmt
-	getUserIDByEmailStmt           *sql.Stmt
-	getUserIDByNameStmt            *sql.Stmt
-	getUserSolvesStmt              *sql.Stmt
-	getUsersStmt                   *sql.Stmt
-	registerTeamStmt               *sql.Stmt
-	registerUserStmt               *sql.Stmt
-	resetTeamPasswordStmt          *sql.Stmt
-	resetUserPasswordStmt          *sql.Stmt
-	submitStmt                     *sql.Stmt
-	toggleChallengesHiddenStmt     *sql.Stmt
-	updateChallengeStmt            *sql.Stmt
-	updateChallengesCategoryStmt   *sql.Stmt
-	updateConfigStmt               *sql.Stmt
-	updateDockerConfigsStmt        *sql.Stmt
-	updateFlagStmt                 *sql.Stmt
-	updateInstanceDockerIDStmt     *sql.Stmt
-	updateInstanceExpireStmt       *sql.Stmt
-	updateTeamStmt                 *sql.Stmt
-	updateUserStmt                 *sql.Stmt
-	userExistsByEmailStmt          *sql.Stmt
+	db                              DBTX
+	tx                              *sql.Tx
+	addTeamMemberStmt               *sql.Stmt
+	changeUserRoleStmt              *sql.Stmt
+	checkFlagsStmt                  *sql.Stmt
+	claimDueAnnouncementsStmt       *sql.Stmt
+	claimExpiredInstancesStmt       *sql.Stmt
+	countInstancesStmt              *sql.Stmt
+	countInstancesByChallengeStmt   *sql.Stmt
+	createAnnouncementStmt          *sql.Stmt
+	createAttachmentStmt            *sql.Stmt
+	createAuditEntryStmt            *sql.Stmt
+	createCategoryStmt              *sql.Stmt
+	createChallengeStmt             *sql.Stmt
+	createConfigStmt                *sql.Stmt
+	createFlagStmt                  *sql.Stmt
+	createInstanceStmt              *sql.Stmt
+	createInstanceEventStmt         *sql.Stmt
+	createInstanceSecretStmt        *sql.Stmt
+	createRoleStmt                  *sql.Stmt
+	createTicketStmt                *sql.Stmt
+	createTicketMessageStmt         *sql.Stmt
+	deleteAnnouncementStmt          *sql.Stmt
+	deleteAttachmentStmt            *sql.Stmt
+	deleteCategoryStmt              *sql.Stmt
+	deleteChallengeStmt             *sql.Stmt
+	deleteDeploymentStmt            *sql.Stmt
+	deleteEmailTemplateStmt         *sql.Stmt
+	deleteFeedbackStmt              *sql.Stmt
+	deleteFlagStmt                  *sql.Stmt
+	deleteInstanceStmt              *sql.Stmt
+	deleteRoleStmt                  *sql.Stmt
+	deleteSubmissionStmt            *sql.Stmt
+	deleteWriteupStmt               *sql.Stmt
+	demoteChallengeOwnerStmt        *sql.Stmt
+	extendInstanceStmt              *sql.Stmt
+	flagInstanceUsageStmt           *sql.Stmt
+	getAdminStatsStmt               *sql.Stmt
+	getAllChallengesInfoStmt        *sql.Stmt
+	getAnnouncementByIDStmt         *sql.Stmt
+	getAnnouncementsStmt            *sql.Stmt
+	getAttachmentHashStmt           *sql.Stmt
+	getAuditEntriesStmt             *sql.Stmt
+	getAuthorDashboardStmt          *sql.Stmt
+	getBadgesFromTeamStmt           *sql.Stmt
+	getCategoriesStmt               *sql.Stmt
+	getCategoryStmt                 *sql.Stmt
+	getChallDockerConfigStmt        *sql.Stmt
+	getChallengeByIDStmt            *sql.Stmt
+	getChallengeFeedbackStmt        *sql.Stmt
+	getChallengeIDByNameStmt        *sql.Stmt
+	getChallengeOwnersStmt          *sql.Stmt
+	getChallengeRatingStmt          *sql.Stmt
+	getChallengeSolvesStmt          *sql.Stmt
+	getConfigStmt                   *sql.Stmt
+	getConfigsStmt                  *sql.Stmt
+	getDeploymentStmt               *sql.Stmt
+	getDockerConfigsByIDStmt        *sql.Stmt
+	getEmailTemplateStmt            *sql.Stmt
+	getEmailTemplatesStmt           *sql.Stmt
+	getFlagsByChallengeStmt         *sql.Stmt
+	getHiddenAndAttachmentsStmt     *sql.Stmt
+	getInstanceStmt                 *sql.Stmt
+	getInstanceByHostStmt           *sql.Stmt
+	getInstanceEventsStmt           *sql.Stmt
+	getInstanceFlagOwnerStmt        *sql.Stmt
+	getInstanceUsageStmt            *sql.Stmt
+	getInstancesStmt                *sql.Stmt
+	getInstancesToReconcileStmt     *sql.Stmt
+	getInstancesUsageStmt           *sql.Stmt
+	getNextInstanceToDeleteStmt     *sql.Stmt
+	getOwnerRoleStmt                *sql.Stmt
+	getPendingNodeReservationsStmt  *sql.Stmt
+	getRoleStmt                     *sql.Stmt
+	getRolesStmt                    *sql.Stmt
+	getSharedDeploymentsStmt        *sql.Stmt
+	getStaleDeploymentsStmt         *sql.Stmt
+	getSubmissionByIDStmt           *sql.Stmt
+	getSubmissionChallengeStmt      *sql.Stmt
+	getSubmissionsStmt              *sql.Stmt
+	getTeamByIDStmt                 *sql.Stmt
+	getTeamByNameStmt               *sql.Stmt
+	getTeamEmailsStmt               *sql.Stmt
+	getTeamFromUserStmt             *sql.Stmt
+	getTeamIDByEmailStmt            *sql.Stmt
+	getTeamIDByNameStmt             *sql.Stmt
+	getTeamMembersStmt              *sql.Stmt
+	getTeamSolvesStmt               *sql.Stmt
+	getTeamsPreviewStmt             *sql.Stmt
+	getTeamsScoreboardStmt          *sql.Stmt
+	getTeamsScoreboardGraphStmt     *sql.Stmt
+	getTicketStmt                   *sql.Stmt
+	getTicketMessagesStmt           *sql.Stmt
+	getTicketsStmt                  *sql.Stmt
+	getTotalAuditEntriesStmt        *sql.Stmt
+	getTotalCategoryChallengesStmt  *sql.Stmt
+	getTotalInstanceEventsStmt      *sql.Stmt
+	getTotalSubmissionsStmt         *sql.Stmt
+	getTotalTeamsStmt               *sql.Stmt
+	getTotalUsersStmt               *sql.Stmt
+	getUserByEmailStmt              *sql.Stmt
+	getUserByIDStmt                 *sql.Stmt
+	getUserByNameStmt               *sql.Stmt
+	getUserByTeamIDStmt             *sql.Stmt
+	getUserIDByEmailStmt            *sql.Stmt
+	getUserIDByNameStmt             *sql.Stmt
+	getUserSolvesStmt               *sql.Stmt
+	getUsersStmt                    *sql.Stmt
+	getUsersEmailsStmt              *sql.Stmt
+	getWriteupByIDStmt              *sql.Stmt
+	getWriteupChallengeStmt         *sql.Stmt
+	getWriteupsStmt                 *sql.Stmt
+	initProxySecretStmt             *sql.Stmt
+	lockTaskStmt                    *sql.Stmt
+	markAnnouncementUnpublishedStmt *sql.Stmt
+	moderateWriteupStmt             *sql.Stmt
+	registerTeamStmt                *sql.Stmt
+	registerUserStmt                *sql.Stmt
+	removeChallengeOwnerStmt        *sql.Stmt
+	resetTeamPasswordStmt           *sql.Stmt
+	resetUserPasswordStmt           *sql.Stmt
+	setChallengeOwnerStmt           *sql.Stmt
+	setTicketStatusStmt             *sql.Stmt
+	setUserCustomRoleStmt           *sql.Stmt
+	setWriteupAcceptedStmt          *sql.Stmt
+	submitStmt                      *sql.Stmt
+	submitFeedbackStmt              *sql.Stmt
+	submitWriteupStmt               *sql.Stmt
+	toggleChallengesHiddenStmt      *sql.Stmt
+	tryLockTaskStmt                 *sql.Stmt
+	updateAnnouncementStmt          *sql.Stmt
+	updateChallengeStmt             *sql.Stmt
+	updateChallengesCategoryStmt    *sql.Stmt
+	updateConfigStmt                *sql.Stmt
+	updateDockerConfigsStmt         *sql.Stmt
+	updateFlagStmt                  *sql.Stmt
+	updateInstanceDockerIDStmt      *sql.Stmt
+	updateInstanceExpireStmt        *sql.Stmt
+	updateInstanceUsageStmt         *sql.Stmt
+	updateRoleStmt                  *sql.Stmt
+	updateTeamStmt                  *sql.Stmt
+	updateUserStmt                  *sql.Stmt
+	upsertDeploymentStmt            *sql.Stmt
+	upsertEmailTemplateStmt         *sql.Stmt
+	userExistsByEmailStmt           *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                             tx,
-		tx:                             tx,
-		addTeamMemberStmt:              q.addTeamMemberStmt,
-		changeUserRoleStmt:             q.changeUserRoleStmt,
-		checkFlagsStmt:                 q.checkFlagsStmt,
-		createAttachmentStmt:           q.createAttachmentStmt,
-		createCategoryStmt:             q.createCategoryStmt,
-		createChallengeStmt:            q.createChallengeStmt,
-		createConfigStmt:               q.createConfigStmt,
-		createFlagStmt:                 q.createFlagStmt,
-		createInstanceStmt:             q.createInstanceStmt,
-		deleteAttachmentStmt:           q.deleteAttachmentStmt,
-		deleteCategoryStmt:             q.deleteCategoryStmt,
-		deleteChallengeStmt:            q.deleteChallengeStmt,
-		deleteFlagStmt:                 q.deleteFlagStmt,
-		deleteInstanceStmt:             q.deleteInstanceStmt,
-		deleteSubmissionStmt:           q.deleteSubmissionStmt,
-		getAdminStatsStmt:              q.getAdminStatsStmt,
-		getAllChallengesInfoStmt:       q.getAllChallengesInfoStmt,
-		getAttachmentHashStmt:          q.getAttachmentHashStmt,
-		getBadgesFromTeamStmt:          q.getBadgesFromTeamStmt,
-		getCategoriesStmt:              q.getCategoriesStmt,
-		getCategoryStmt:                q.getCategoryStmt,
-		getChallDockerConfigStmt:       q.getChallDockerConfigStmt,
-		getChallengeByIDStmt:           q.getChallengeByIDStmt,
-		getChallengeSolvesStmt:         q.getChallengeSolvesStmt,
-		getConfigStmt:                  q.getConfigStmt,
-		getConfigsStmt:                 q.getConfigsStmt,
-		getDockerConfigsByIDStmt:       q.getDockerConfigsByIDStmt,
-		getFlagsByChallengeStmt:        q.getFlagsByChallengeStmt,
-		getHiddenAndAttachmentsStmt:    q.getHiddenAndAttachmentsStmt,
-		getInstanceStmt:                q.getInstanceStmt,
-		getInstancesStmt:               q.getInstancesStmt,
-		getNextInstanceToDeleteStmt:    q.getNextInstanceToDeleteStmt,
-		getSubmissionsStmt:             q.getSubmissionsStmt,
-		getTeamByIDStmt:                q.getTeamByIDStmt,
-		getTeamByNameStmt:              q.getTeamByNameStmt,
-		getTeamFromUserStmt:            q.getTeamFromUserStmt,
-		getTeamIDByEmailStmt:           q.getTeamIDByEmailStmt,
-		getTeamIDByNameStmt:            q.getTeamIDByNameStmt,
-		getTeamMembersStmt:             q.getTeamMembersStmt,
-		getTeamSolvesStmt:              q.getTeamSolvesStmt,
-		getTeamsPreviewStmt:            q.getTeamsPreviewStmt,
-		getTeamsScoreboardStmt:         q.getTeamsScoreboardStmt,
-		getTeamsScoreboardGraphStmt:    q.getTeamsScoreboardGraphStmt,
-		getTotalCategoryChallengesStmt: q.getTotalCategoryChallengesStmt,
-		getTotalSubmissionsStmt:        q.getTotalSubmissionsStmt,
-		getTotalTeamsStmt:              q.getTotalTeamsStmt,
-		getTotalUsersStmt:              q.getTotalUsersStmt,
-		getUserByEmailStmt:             q.getUserByEmailStmt,
-		getUserByIDStmt:                q.getUserByIDStmt,
-		getUserByNameStmt:              q.getUserByNameStmt,
-		getUserByTeamIDStmt:            q.getUserByTeamIDStmt,
-		getUserIDByEmailStmt:           q.getUserIDByEmailStmt,
-		getUserIDByNameStmt:            q.getUserIDByNameStmt,
-		getUserSolvesStmt:              q.getUserSolvesStmt,
-		getUsersStmt:                   q.getUsersStmt,
-		registerTeamStmt:               q.registerTeamStmt,
-		registerUserStmt:               q.registerUserStmt,
-		resetTeamPasswordStmt:          q.resetTeamPasswordStmt,
-		resetUserPasswordStmt:          q.resetUserPasswordStmt,
-		submitStmt:                     q.submitStmt,
-		toggleChallengesHiddenStmt:     q.toggleChallengesHiddenStmt,
-		updateChallengeStmt:            q.updateChallengeStmt,
-		updateChallengesCategoryStmt:   q.updateChallengesCategoryStmt,
-		updateConfigStmt:               q.updateConfigStmt,
-		updateDockerConfigsStmt:        q.updateDockerConfigsStmt,
-		updateFlagStmt:                 q.updateFlagStmt,
-		updateInstanceDockerIDStmt:     q.updateInstanceDockerIDStmt,
-		updateInstanceExpireStmt:       q.updateInstanceExpireStmt,
-		updateTeamStmt:                 q.updateTeamStmt,
-		updateUserStmt:                 q.updateUserStmt,
-		userExistsByEmailStmt:          q.userExistsByEmailStmt,
+		db:                              tx,
+		tx:                              tx,
+		addTeamMemberStmt:               q.addTeamMemberStmt,
+		changeUserRoleStmt:              q.changeUserRoleStmt,
+		checkFlagsStmt:                  q.checkFlagsStmt,
+		claimDueAnnouncementsStmt:       q.claimDueAnnouncementsStmt,
+		claimExpiredInstancesStmt:       q.claimExpiredInstancesStmt,
+		countInstancesStmt:              q.countInstancesStmt,
+		countInstancesByChallengeStmt:   q.countInstancesByChallengeStmt,
+		createAnnouncementStmt:          q.createAnnouncementStmt,
+		createAttachmentStmt:            q.createAttachmentStmt,
+		createAuditEntryStmt:            q.createAuditEntryStmt,
+		createCategoryStmt:              q.createCategoryStmt,
+		createChallengeStmt:             q.createChallengeStmt,
+		createConfigStmt:                q.createConfigStmt,
+		createFlagStmt:                  q.createFlagStmt,
+		createInstanceStmt:              q.createInstanceStmt,
+		createInstanceEventStmt:         q.createInstanceEventStmt,
+		createInstanceSecretStmt:        q.createInstanceSecretStmt,
+		createRoleStmt:                  q.createRoleStmt,
+		createTicketStmt:                q.createTicketStmt,
+		createTicketMessageStmt:         q.createTicketMessageStmt,
+		deleteAnnouncementStmt:          q.deleteAnnouncementStmt,
+		deleteAttachmentStmt:            q.deleteAttachmentStmt,
+		deleteCategoryStmt:              q.deleteCategoryStmt,
+		deleteChallengeStmt:             q.deleteChallengeStmt,
+		deleteDeploymentStmt:            q.deleteDeploymentStmt,
+		deleteEmailTemplateStmt:         q.deleteEmailTemplateStmt,
+		deleteFeedbackStmt:              q.deleteFeedbackStmt,
+		deleteFlagStmt:                  q.deleteFlagStmt,
+		deleteInstanceStmt:              q.deleteInstanceStmt,
+		deleteRoleStmt:                  q.deleteRoleStmt,
+		deleteSubmissionStmt:            q.deleteSubmissionStmt,
+		deleteWriteupStmt:               q.deleteWriteupStmt,
+		demoteChallengeOwnerStmt:        q.demoteChallengeOwnerStmt,
+		extendInstanceStmt:              q.extendInstanceStmt,
+		flagInstanceUsageStmt:           q.flagInstanceUsageStmt,
+		getAdminStatsStmt:               q.getAdminStatsStmt,
+		getAllChallengesInfoStmt:        q.getAllChallengesInfoStmt,
+		getAnnouncementByIDStmt:         q.getAnnouncementByIDStmt,
+		getAnnouncementsStmt:            q.getAnnouncementsStmt,
+		getAttachmentHashStmt:           q.getAttachmentHashStmt,
+		getAuditEntriesStmt:             q.getAuditEntriesStmt,
+		getAuthorDashboardStmt:          q.getAuthorDashboardStmt,
+		getBadgesFromTeamStmt:           q.getBadgesFromTeamStmt,
+		getCategoriesStmt:               q.getCategoriesStmt,
+		getCategoryStmt:                 q.getCategoryStmt,
+		getChallDockerConfigStmt:        q.getChallDockerConfigStmt,
+		getChallengeByIDStmt:            q.getChallengeByIDStmt,
+		getChallengeFeedbackStmt:        q.getChallengeFeedbackStmt,
+		getChallengeIDByNameStmt:        q.getChallengeIDByNameStmt,
+		getChallengeOwnersStmt:          q.getChallengeOwnersStmt,
+		getChallengeRatingStmt:          q.getChallengeRatingStmt,
+		getChallengeSolvesStmt:          q.getChallengeSolvesStmt,
+		getConfigStmt:                   q.getConfigStmt,
+		getConfigsStmt:                  q.getConfigsStmt,
+		getDeploymentStmt:               q.getDeploymentStmt,
+		getDockerConfigsByIDStmt:        q.getDockerConfigsByIDStmt,
+		getEmailTemplateStmt:            q.getEmailTemplateStmt,
+		getEmailTemplatesStmt:           q.getEmailTemplatesStmt,
+		getFlagsByChallengeStmt:         q.getFlagsByChallengeStmt,
+		getHiddenAndAttachmentsStmt:     q.getHiddenAndAttachmentsStmt,
+		getInstanceStmt:                 q.getInstanceStmt,
+		getInstanceByHostStmt:           q.getInstanceByHostStmt,
+		getInstanceEventsStmt:           q.getInstanceEventsStmt,
+		getInstanceFlagOwnerStmt:        q.getInstanceFlagOwnerStmt,
+		getInstanceUsageStmt:            q.getInstanceUsageStmt,
+		getInstancesStmt:                q.getInstancesStmt,
+		getInstancesToReconcileStmt:     q.getInstancesToReconcileStmt,
+		getInstancesUsageStmt:           q.getInstancesUsageStmt,
+		getNextInstanceToDeleteStmt:     q.getNextInstanceToDeleteStmt,
+		getOwnerRoleStmt:                q.getOwnerRoleStmt,
+		getPendingNodeReservationsStmt:  q.getPendingNodeReservationsStmt,
+		getRoleStmt:                     q.getRoleStmt,
+		getRolesStmt:                    q.getRolesStmt,
+		getSharedDeploymentsStmt:        q.getSharedDeploymentsStmt,
+		getStaleDeploymentsStmt:         q.getStaleDeploymentsStmt,
+		getSubmissionByIDStmt:           q.getSubmissionByIDStmt,
+		getSubmissionChallengeStmt:      q.getSubmissionChallengeStmt,
+		getSubmissionsStmt:              q.getSubmissionsStmt,
+		getTeamByIDStmt:                 q.getTeamByIDStmt,
+		getTeamByNameStmt:               q.getTeamByNameStmt,
+		getTeamEmailsStmt:               q.getTeamEmailsStmt,
+		getTeamFromUserStmt:             q.getTeamFromUserStmt,
+		getTeamIDByEmailStmt:            q.getTeamIDByEmailStmt,
+		getTeamIDByNameStmt:             q.getTeamIDByNameStmt,
+		getTeamMembersStmt:              q.getTeamMembersStmt,
+		getTeamSolvesStmt:               q.getTeamSolvesStmt,
+		getTeamsPreviewStmt:             q.getTeamsPreviewStmt,
+		getTeamsScoreboardStmt:          q.getTeamsScoreboardStmt,
+		getTeamsScoreboardGraphStmt:     q.getTeamsScoreboardGraphStmt,
+		getTicketStmt:                   q.getTicketStmt,
+		getTicketMessagesStmt:           q.getTicketMessagesStmt,
+		getTicketsStmt:                  q.getTicketsStmt,
+		getTotalAuditEntriesStmt:        q.getTotalAuditEntriesStmt,
+		getTotalCategoryChallengesStmt:  q.getTotalCategoryChallengesStmt,
+		getTotalInstanceEventsStmt:      q.getTotalInstanceEventsStmt,
+		getTotalSubmissionsStmt:         q.getTotalSubmissionsStmt,
+		getTotalTeamsStmt:               q.getTotalTeamsStmt,
+		getTotalUsersStmt:               q.getTotalUsersStmt,
+		getUserByEmailStmt:              q.getUserByEmailStmt,
+		getUserByIDStmt:                 q.getUserByIDStmt,
+		getUserByNameStmt:               q.getUserByNameStmt,
+		getUserByTeamIDStmt:             q.getUserByTeamIDStmt,
+		getUserIDByEmailStmt:            q.getUserIDByEmailStmt,
+		getUserIDByNameStmt:             q.getUserIDByNameStmt,
+		getUserSolvesStmt:               q.getUserSolvesStmt,
+		getUsersStmt:                    q.getUsersStmt,
+		getUsersEmailsStmt:              q.getUsersEmailsStmt,
+		getWriteupByIDStmt:              q.getWriteupByIDStmt,
+		getWriteupChallengeStmt:         q.getWriteupChallengeStmt,
+		getWriteupsStmt:                 q.getWriteupsStmt,
+		initProxySecretStmt:             q.initProxySecretStmt,
+		lockTaskStmt:                    q.lockTaskStmt,
+		markAnnouncementUnpublishedStmt: q.markAnnouncementUnpublishedStmt,
+		moderateWriteupStmt:             q.moderateWriteupStmt,
+		registerTeamStmt:                q.registerTeamStmt,
+		registerUserStmt:                q.registerUserStmt,
+		removeChallengeOwnerStmt:        q.removeChallengeOwnerStmt,
+		resetTeamPasswordStmt:           q.resetTeamPasswordStmt,
+		resetUserPasswordStmt:           q.resetUserPasswordStmt,
+		setChallengeOwnerStmt:           q.setChallengeOwnerStmt,
+		setTicketStatusStmt:             q.setTicketStatusStmt,
+		setUserCustomRoleStmt:           q.setUserCustomRoleStmt,
+		setWriteupAcceptedStmt:          q.setWriteupAcceptedStmt,
+		submitStmt:                      q.submitStmt,
+		submitFeedbackStmt:              q.submitFeedbackStmt,
+		submitWriteupStmt:               q.submitWriteupStmt,
+		toggleChallengesHiddenStmt:      q.toggleChallengesHiddenStmt,
+		tryLockTaskStmt:                 q.tryLockTaskStmt,
+		updateAnnouncementStmt:          q.updateAnnouncementStmt,
+		updateChallengeStmt:             q.updateChallengeStmt,
+		updateChallengesCategoryStmt:    q.updateChallengesCategoryStmt,
+		updateConfigStmt:                q.updateConfigStmt,
+		updateDockerConfigsStmt:         q.updateDockerConfigsStmt,
+		updateFlagStmt:                  q.updateFlagStmt,
+		updateInstanceDockerIDStmt:      q.updateInstanceDockerIDStmt,
+		updateInstanceExpireStmt:        q.updateInstanceExpireStmt,
+		updateInstanceUsageStmt:         q.updateInstanceUsageStmt,
+		updateRoleStmt:                  q.updateRoleStmt,
+		updateTeamStmt:                  q.updateTeamStmt,
+		updateUserStmt:                  q.updateUserStmt,
+		upsertDeploymentStmt:            q.upsertDeploymentStmt,
+		upsertEmailTemplateStmt:         q.upsertEmailTemplateStmt,
+		userExistsByEmailStmt:           q.userExistsByEmailStmt,
 	}
 }
@@ -7,10 +7,54 @@ package sqlc
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+type AnnouncementPriority string
+
+const (
+	AnnouncementPriorityLow    AnnouncementPriority = "Low"
+	AnnouncementPriorityNormal AnnouncementPriority = "Normal"
+	AnnouncementPriorityHigh   AnnouncementPriority = "High"
+)
+
+func (e *AnnouncementPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AnnouncementPriority(s)
+	case string:
+		*e = AnnouncementPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AnnouncementPriority: %T", src)
+	}
+	return nil
+}
+
+type NullAnnouncementPriority struct {
+	AnnouncementPriority AnnouncementPriority `json:"announcement_priority"`
+	Valid                bool                 `json:"valid"` // Valid is true if AnnouncementPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAnnouncementPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.AnnouncementPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AnnouncementPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAnnouncementPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AnnouncementPriority), nil
+}
+
 type ConnType string
 
 const (
@@ -98,6 +142,266 @@ func (ns NullDeployType) Value() (driver.Value, error) {
 	return string(ns.DeployType), nil
 }
 
+type DeploymentStatus string
+
+const (
+	DeploymentStatusPending DeploymentStatus = "Pending"
+	DeploymentStatusRunning DeploymentStatus = "Running"
+	DeploymentStatusFailed  DeploymentStatus = "Failed"
+)
+
+func (e *DeploymentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DeploymentStatus(s)
+	case string:
+		*e = DeploymentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DeploymentStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDeploymentStatus struct {
+	DeploymentStatus DeploymentStatus `json:"deployment_status"`
+	Valid            bool             `json:"valid"` // Valid is true if DeploymentStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDeploymentStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DeploymentStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DeploymentStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDeploymentStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DeploymentStatus), nil
+}
+
+type EgressPolicy string
+
+const (
+	EgressPolicyNone EgressPolicy = "None"
+	EgressPolicyDNS  EgressPolicy = "DNS"
+	EgressPolicyFull EgressPolicy = "Full"
+)
+
+func (e *EgressPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EgressPolicy(s)
+	case string:
+		*e = EgressPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EgressPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullEgressPolicy struct {
+	EgressPolicy EgressPolicy `json:"egress_policy"`
+	Valid        bool         `json:"valid"` // Valid is true if EgressPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEgressPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.EgressPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EgressPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEgressPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EgressPolicy), nil
+}
+
+type EmailTemplateName string
+
+const (
+	EmailTemplateNameVerification  EmailTemplateName = "Verification"
+	EmailTemplateNamePasswordReset EmailTemplateName = "PasswordReset"
+	EmailTemplateNameAnnouncement  EmailTemplateName = "Announcement"
+	EmailTemplateNameTicketReply   EmailTemplateName = "TicketReply"
+)
+
+func (e *EmailTemplateName) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EmailTemplateName(s)
+	case string:
+		*e = EmailTemplateName(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EmailTemplateName: %T", src)
+	}
+	return nil
+}
+
+type NullEmailTemplateName struct {
+	EmailTemplateName EmailTemplateName `json:"email_template_name"`
+	Valid             bool              `json:"valid"` // Valid is true if EmailTemplateName is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEmailTemplateName) Scan(value interface{}) error {
+	if value == nil {
+		ns.EmailTemplateName, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EmailTemplateName.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEmailTemplateName) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EmailTemplateName), nil
+}
+
+type FeedbackVote string
+
+const (
+	FeedbackVoteLike    FeedbackVote = "Like"
+	FeedbackVoteDislike FeedbackVote = "Dislike"
+)
+
+func (e *FeedbackVote) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FeedbackVote(s)
+	case string:
+		*e = FeedbackVote(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FeedbackVote: %T", src)
+	}
+	return nil
+}
+
+type NullFeedbackVote struct {
+	FeedbackVote FeedbackVote `json:"feedback_vote"`
+	Valid        bool         `json:"valid"` // Valid is true if FeedbackVote is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFeedbackVote) Scan(value interface{}) error {
+	if value == nil {
+		ns.FeedbackVote, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FeedbackVote.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFeedbackVote) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FeedbackVote), nil
+}
+
+type InstanceEventType string
+
+const (
+	InstanceEventTypeCreate  InstanceEventType = "Create"
+	InstanceEventTypeExtend  InstanceEventType = "Extend"
+	InstanceEventTypeDelete  InstanceEventType = "Delete"
+	InstanceEventTypeExpire  InstanceEventType = "Expire"
+	InstanceEventTypeKill    InstanceEventType = "Kill"
+	InstanceEventTypeFailure InstanceEventType = "Failure"
+)
+
+func (e *InstanceEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InstanceEventType(s)
+	case string:
+		*e = InstanceEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InstanceEventType: %T", src)
+	}
+	return nil
+}
+
+type NullInstanceEventType struct {
+	InstanceEventType InstanceEventType `json:"instance_event_type"`
+	Valid             bool              `json:"valid"` // Valid is true if InstanceEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInstanceEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.InstanceEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InstanceEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInstanceEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InstanceEventType), nil
+}
+
+type OwnerRole string
+
+const (
+	OwnerRoleOwner   OwnerRole = "Owner"
+	OwnerRoleCoOwner OwnerRole = "CoOwner"
+)
+
+func (e *OwnerRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OwnerRole(s)
+	case string:
+		*e = OwnerRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OwnerRole: %T", src)
+	}
+	return nil
+}
+
+type NullOwnerRole struct {
+	OwnerRole OwnerRole `json:"owner_role"`
+	Valid     bool      `json:"valid"` // Valid is true if OwnerRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOwnerRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.OwnerRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OwnerRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOwnerRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OwnerRole), nil
+}
+
 type ScoreType string
 
 const (
@@ -184,6 +488,49 @@ func (ns NullSubmissionStatus) Value() (driver.Value, error) {
 	return string(ns.SubmissionStatus), nil
 }
 
+type TicketStatus string
+
+const (
+	TicketStatusOpen     TicketStatus = "Open"
+	TicketStatusAnswered TicketStatus = "Answered"
+	TicketStatusClosed   TicketStatus = "Closed"
+)
+
+func (e *TicketStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TicketStatus(s)
+	case string:
+		*e = TicketStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TicketStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTicketStatus struct {
+	TicketStatus TicketStatus `json:"ticket_status"`
+	Valid        bool         `json:"valid"` // Valid is true if TicketStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTicketStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TicketStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TicketStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTicketStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TicketStatus), nil
+}
+
 type UserRole string
 
 const (
@@ -228,12 +575,37 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type Announcement struct {
+	ID        int32                `json:"id"`
+	Title     string               `json:"title"`
+	Body      string               `json:"body"`
+	Priority  AnnouncementPriority `json:"priority"`
+	ChallID   sql.NullInt32        `json:"chall_id"`
+	PublishAt time.Time            `json:"publish_at"`
+	Email     bool                 `json:"email"`
+	Published bool                 `json:"published"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
 type Attachment struct {
 	ChallID int32  `json:"chall_id"`
 	Name    string `json:"name"`
 	Hash    string `json:"hash"`
 }
 
+type AuditLog struct {
+	ID        int32           `json:"id"`
+	ActorID   sql.NullInt32   `json:"actor_id"`
+	ActorName string          `json:"actor_name"`
+	Ip        string          `json:"ip"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
 type Badge struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -263,6 +635,22 @@ type Challenge struct {
 	ConnType    ConnType   `json:"conn_type"`
 }
 
+type ChallengeFeedback struct {
+	UserID     int32            `json:"user_id"`
+	ChallID    int32            `json:"chall_id"`
+	Vote       NullFeedbackVote `json:"vote"`
+	Difficulty sql.NullInt32    `json:"difficulty"`
+	Comment    sql.NullString   `json:"comment"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+type ChallengeOwner struct {
+	ChallID int32     `json:"chall_id"`
+	UserID  int32     `json:"user_id"`
+	Role    OwnerRole `json:"role"`
+}
+
 type Config struct {
 	Key         string `json:"key"`
 	Type        string `json:"type"`
@@ -273,15 +661,53 @@ type Config struct {
 	Secret      bool   `json:"secret"`
 }
 
+type Deployment struct {
+	ChallID    int32            `json:"chall_id"`
+	DockerID   sql.NullString   `json:"docker_id"`
+	Host       string           `json:"host"`
+	ConfigHash string           `json:"config_hash"`
+	Status     DeploymentStatus `json:"status"`
+	Error      string           `json:"error"`
+	Restarts   int32            `json:"restarts"`
+	Failures   int32            `json:"failures"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
 type DockerConfig struct {
-	ChallID    int32  `json:"chall_id"`
-	Image      string `json:"image"`
-	Compose    string `json:"compose"`
-	HashDomain bool   `json:"hash_domain"`
-	Lifetime   int32  `json:"lifetime"`
-	Envs       string `json:"envs"`
-	MaxMemory  int32  `json:"max_memory"`
-	MaxCpu     string `json:"max_cpu"`
+	ChallID           int32         `json:"chall_id"`
+	Image             string        `json:"image"`
+	Compose           string        `json:"compose"`
+	HashDomain        bool          `json:"hash_domain"`
+	Lifetime          int32         `json:"lifetime"`
+	Envs              string        `json:"envs"`
+	MaxMemory         int32         `json:"max_memory"`
+	MaxCpu            string        `json:"max_cpu"`
+	TeamOnly          bool          `json:"team_only"`
+	PowDifficulty     int32         `json:"pow_difficulty"`
+	MaxLifetime       sql.NullInt32 `json:"max_lifetime"`
+	MaxExtensions     sql.NullInt32 `json:"max_extensions"`
+	ExtensionCooldown sql.NullInt32 `json:"extension_cooldown"`
+	ExtensionWindow   sql.NullInt32 `json:"extension_window"`
+	InstanceFlag      bool          `json:"instance_flag"`
+	CpuThreshold      int32         `json:"cpu_threshold"`
+	MemoryThreshold   int32         `json:"memory_threshold"`
+	PidsThreshold     int32         `json:"pids_threshold"`
+	NetworkThreshold  int32         `json:"network_threshold"`
+	ThresholdDuration int32         `json:"threshold_duration"`
+	AutoKill          bool          `json:"auto_kill"`
+	Egress            EgressPolicy  `json:"egress"`
+	Shared            bool          `json:"shared"`
+	Placement         []string      `json:"placement"`
+	ReservedMemory    int32         `json:"reserved_memory"`
+	ReservedCpu       string        `json:"reserved_cpu"`
+}
+
+type EmailTemplate struct {
+	Name    EmailTemplateName `json:"name"`
+	Locale  string            `json:"locale"`
+	Subject string            `json:"subject"`
+	Html    string            `json:"html"`
+	Text    string            `json:"text"`
 }
 
 type Flag struct {
@@ -291,12 +717,64 @@ type Flag struct {
 }
 
 type Instance struct {
-	TeamID    int32          `json:"team_id"`
-	ChallID   int32          `json:"chall_id"`
-	ExpiresAt time.Time      `json:"expires_at"`
-	Host      string         `json:"host"`
-	Port      sql.NullInt32  `json:"port"`
-	DockerID  sql.NullString `json:"docker_id"`
+	TeamID     int32          `json:"team_id"`
+	ChallID    int32          `json:"chall_id"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	Host       string         `json:"host"`
+	Port       sql.NullInt32  `json:"port"`
+	DockerID   sql.NullString `json:"docker_id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	TeamOnly   bool           `json:"team_only"`
+	Node       string         `json:"node"`
+	Extensions int32          `json:"extensions"`
+	ExtendedAt sql.NullTime   `json:"extended_at"`
+}
+
+type InstanceEvent struct {
+	ID        int32             `json:"id"`
+	TeamID    sql.NullInt32     `json:"team_id"`
+	ChallID   sql.NullInt32     `json:"chall_id"`
+	Type      InstanceEventType `json:"type"`
+	Duration  int32             `json:"duration"`
+	Host      string            `json:"host"`
+	Port      sql.NullInt32     `json:"port"`
+	Error     string            `json:"error"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+type InstanceSecret struct {
+	ID        int32     `json:"id"`
+	TeamID    int32     `json:"team_id"`
+	ChallID   int32     `json:"chall_id"`
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InstanceUsage struct {
+	TeamID     int32        `json:"team_id"`
+	ChallID    int32        `json:"chall_id"`
+	Samples    int32        `json:"samples"`
+	Cpu        float32      `json:"cpu"`
+	CpuAvg     float32      `json:"cpu_avg"`
+	CpuMax     float32      `json:"cpu_max"`
+	Memory     float32      `json:"memory"`
+	MemoryAvg  float32      `json:"memory_avg"`
+	MemoryMax  float32      `json:"memory_max"`
+	Pids       int32        `json:"pids"`
+	PidsMax    int32        `json:"pids_max"`
+	Network    float32      `json:"network"`
+	NetworkAvg float32      `json:"network_avg"`
+	NetworkMax float32      `json:"network_max"`
+	OverSince  sql.NullTime `json:"over_since"`
+	Flagged    bool         `json:"flagged"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
 }
 
 type Submission struct {
@@ -307,6 +785,7 @@ type Submission struct {
 	FirstBlood bool             `json:"first_blood"`
 	Flag       string           `json:"flag"`
 	Timestamp  time.Time        `json:"timestamp"`
+	FlagOwner  sql.NullInt32    `json:"flag_owner"`
 }
 
 type Team struct {
@@ -324,6 +803,26 @@ type TeamCategorySolf struct {
 	Solves   int32  `json:"solves"`
 }
 
+type Ticket struct {
+	ID        int32         `json:"id"`
+	TeamID    int32         `json:"team_id"`
+	ChallID   sql.NullInt32 `json:"chall_id"`
+	Title     string        `json:"title"`
+	Status    TicketStatus  `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type TicketMessage struct {
+	ID          int32         `json:"id"`
+	TicketID    int32         `json:"ticket_id"`
+	UserID      sql.NullInt32 `json:"user_id"`
+	Staff       bool          `json:"staff"`
+	Body        string        `json:"body"`
+	Attachments []string      `json:"attachments"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
 type User struct {
 	ID           int32          `json:"id"`
 	Name         string         `json:"name"`
@@ -335,4 +834,20 @@ type User struct {
 	Role         UserRole       `json:"role"`
 	TeamID       sql.NullInt32  `json:"team_id"`
 	Country      sql.NullString `json:"country"`
+	Locale       sql.NullString `json:"locale"`
+	CustomRole   sql.NullString `json:"custom_role"`
+}
+
+type Writeup struct {
+	ID        int32         `json:"id"`
+	ChallID   int32         `json:"chall_id"`
+	TeamID    sql.NullInt32 `json:"team_id"`
+	UserID    sql.NullInt32 `json:"user_id"`
+	Content   string        `json:"content"`
+	Url       string        `json:"url"`
+	Accepted  bool          `json:"accepted"`
+	Published bool          `json:"published"`
+	Hidden    bool          `json:"hidden"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
 }
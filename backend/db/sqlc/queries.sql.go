@@ -61,6 +61,137 @@ func (q *Queries) CheckFlags(ctx context.Context, arg CheckFlagsParams) (bool, e
 	return column_1, err
 }
 
+const claimExpiredInstances = `-- name: ClaimExpiredInstances :many
+SELECT team_id, chall_id, docker_id, expires_at
+  FROM instances
+  WHERE expires_at < NOW()
+  ORDER BY expires_at ASC
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+`
+
+type ClaimExpiredInstancesRow struct {
+	TeamID    int32          `json:"team_id"`
+	ChallID   int32          `json:"chall_id"`
+	DockerID  sql.NullString `json:"docker_id"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// Locks a batch of expired instances, skipping the ones locked by other replicas
+func (q *Queries) ClaimExpiredInstances(ctx context.Context, limit int32) ([]ClaimExpiredInstancesRow, error) {
+	rows, err := q.query(ctx, q.claimExpiredInstancesStmt, claimExpiredInstances, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimExpiredInstancesRow
+	for rows.Next() {
+		var i ClaimExpiredInstancesRow
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.ChallID,
+			&i.DockerID,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countInstances = `-- name: CountInstances :one
+SELECT COUNT(*) FROM instances
+`
+
+// Counts the active instances, used to measure the instancer load
+func (q *Queries) CountInstances(ctx context.Context) (int64, error) {
+	row := q.queryRow(ctx, q.countInstancesStmt, countInstances)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countInstancesByChallenge = `-- name: CountInstancesByChallenge :many
+SELECT c.id, c.name, COUNT(*) AS instances
+  FROM instances i
+  JOIN challenges c ON i.chall_id = c.id
+  GROUP BY c.id, c.name
+`
+
+type CountInstancesByChallengeRow struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Instances int64  `json:"instances"`
+}
+
+// Counts the running instances of each challenge
+func (q *Queries) CountInstancesByChallenge(ctx context.Context) ([]CountInstancesByChallengeRow, error) {
+	rows, err := q.query(ctx, q.countInstancesByChallengeStmt, countInstancesByChallenge)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountInstancesByChallengeRow
+	for rows.Next() {
+		var i CountInstancesByChallengeRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Instances); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createAnnouncement = `-- name: CreateAnnouncement :one
+INSERT INTO announcements (title, body, priority, chall_id, publish_at, email)
+  VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    COALESCE($5::TIMESTAMP, CURRENT_TIMESTAMP),
+    $6
+  )
+  RETURNING id
+`
+
+type CreateAnnouncementParams struct {
+	Title     string               `json:"title"`
+	Body      string               `json:"body"`
+	Priority  AnnouncementPriority `json:"priority"`
+	ChallID   sql.NullInt32        `json:"chall_id"`
+	PublishAt sql.NullTime         `json:"publish_at"`
+	Email     bool                 `json:"email"`
+}
+
+// Insert a new announcement, published now unless scheduled
+func (q *Queries) CreateAnnouncement(ctx context.Context, arg CreateAnnouncementParams) (int32, error) {
+	row := q.queryRow(ctx, q.createAnnouncementStmt, createAnnouncement,
+		arg.Title,
+		arg.Body,
+		arg.Priority,
+		arg.ChallID,
+		arg.PublishAt,
+		arg.Email,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const createAttachment = `-- name: CreateAttachment :exec
 INSERT INTO attachments (chall_id, name, hash) VALUES ($1, $2, $3)
 `
@@ -136,20 +267,27 @@ const createInstance = `-- name: CreateInstance :one
 WITH info AS (
     SELECT generate_instance_remote(
       $2,
-      $4::BOOLEAN
+      $7::BOOLEAN,
+      $6::TEXT,
+      $8::TEXT
     ) AS remote
   )
-INSERT INTO instances (team_id, chall_id, expires_at, host, port)
-  VALUES ($1, $2, $3,
-    (SELECT (remote).host FROM info), (SELECT (remote).port FROM info))
+INSERT INTO instances (team_id, chall_id, created_at, expires_at, host, port, team_only, node)
+  VALUES ($1, $2, $3, $4,
+    (SELECT (remote).host FROM info), (SELECT (remote).port FROM info),
+    $5, $6)
 RETURNING host, port
 `
 
 type CreateInstanceParams struct {
 	TeamID     int32     `json:"team_id"`
 	ChallID    int32     `json:"chall_id"`
+	CreatedAt  time.Time `json:"created_at"`
 	ExpiresAt  time.Time `json:"expires_at"`
+	TeamOnly   bool      `json:"team_only"`
+	Node       string    `json:"node"`
 	HashDomain bool      `json:"hash_domain"`
+	NodeHost   string    `json:"node_host"`
 }
 
 type CreateInstanceRow struct {
@@ -162,14 +300,118 @@ func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams)
 	row := q.queryRow(ctx, q.createInstanceStmt, createInstance,
 		arg.TeamID,
 		arg.ChallID,
+		arg.CreatedAt,
 		arg.ExpiresAt,
+		arg.TeamOnly,
+		arg.Node,
 		arg.HashDomain,
+		arg.NodeHost,
 	)
 	var i CreateInstanceRow
 	err := row.Scan(&i.Host, &i.Port)
 	return i, err
 }
 
+const createInstanceEvent = `-- name: CreateInstanceEvent :exec
+INSERT INTO instance_events (team_id, chall_id, type, duration, host, port, error)
+  SELECT $1::INTEGER, $2::INTEGER, $3::instance_event_type,
+      COALESCE(EXTRACT(EPOCH FROM CASE
+        WHEN $3::instance_event_type IN ('Create', 'Extend') THEN i.expires_at - NOW()
+        ELSE NOW() - i.created_at
+      END)::INTEGER, 0),
+      COALESCE(i.host, ''), i.port, $4::TEXT
+    FROM (SELECT 1) AS event
+    LEFT JOIN instances i ON i.team_id = $1 AND i.chall_id = $2
+`
+
+type CreateInstanceEventParams struct {
+	TeamID  int32             `json:"team_id"`
+	ChallID int32             `json:"chall_id"`
+	Type    InstanceEventType `json:"type"`
+	Error   string            `json:"error"`
+}
+
+// Records an instance lifecycle event, taking host, port and duration from
+// the instance if it still exists
+func (q *Queries) CreateInstanceEvent(ctx context.Context, arg CreateInstanceEventParams) error {
+	_, err := q.exec(ctx, q.createInstanceEventStmt, createInstanceEvent,
+		arg.TeamID,
+		arg.ChallID,
+		arg.Type,
+		arg.Error,
+	)
+	return err
+}
+
+const createInstanceSecret = `-- name: CreateInstanceSecret :exec
+INSERT INTO instance_secrets (team_id, chall_id, name, value)
+  VALUES ($1, $2, $3, $4)
+`
+
+type CreateInstanceSecretParams struct {
+	TeamID  int32  `json:"team_id"`
+	ChallID int32  `json:"chall_id"`
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+}
+
+// Stores a secret rendered in the environment of an instance, kept after the
+// instance is deleted
+func (q *Queries) CreateInstanceSecret(ctx context.Context, arg CreateInstanceSecretParams) error {
+	_, err := q.exec(ctx, q.createInstanceSecretStmt, createInstanceSecret,
+		arg.TeamID,
+		arg.ChallID,
+		arg.Name,
+		arg.Value,
+	)
+	return err
+}
+
+const createRole = `-- name: CreateRole :exec
+INSERT INTO roles (name, description, permissions) VALUES ($1, $2, $3)
+`
+
+type CreateRoleParams struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Create a custom role
+func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) error {
+	_, err := q.exec(ctx, q.createRoleStmt, createRole, arg.Name, arg.Description, pq.Array(arg.Permissions))
+	return err
+}
+
+const createTicket = `-- name: CreateTicket :one
+INSERT INTO tickets (team_id, chall_id, title) VALUES ($1, $2, $3) RETURNING id
+`
+
+type CreateTicketParams struct {
+	TeamID  int32         `json:"team_id"`
+	ChallID sql.NullInt32 `json:"chall_id"`
+	Title   string        `json:"title"`
+}
+
+// Open a ticket for a team
+func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (int32, error) {
+	row := q.queryRow(ctx, q.createTicketStmt, createTicket, arg.TeamID, arg.ChallID, arg.Title)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteAnnouncement = `-- name: DeleteAnnouncement :one
+DELETE FROM announcements WHERE id = $1 RETURNING id
+`
+
+// Delete an announcement
+func (q *Queries) DeleteAnnouncement(ctx context.Context, id int32) (int32, error) {
+	row := q.queryRow(ctx, q.deleteAnnouncementStmt, deleteAnnouncement, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
 const deleteAttachment = `-- name: DeleteAttachment :exec
 DELETE FROM attachments WHERE chall_id = $1 AND name = $2
 `
@@ -205,6 +447,50 @@ func (q *Queries) DeleteChallenge(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteDeployment = `-- name: DeleteDeployment :exec
+DELETE FROM deployments WHERE chall_id = $1
+`
+
+// Deletes the state of a shared deployment
+func (q *Queries) DeleteDeployment(ctx context.Context, challID int32) error {
+	_, err := q.exec(ctx, q.deleteDeploymentStmt, deleteDeployment, challID)
+	return err
+}
+
+const deleteEmailTemplate = `-- name: DeleteEmailTemplate :one
+DELETE FROM email_templates WHERE name = $1 AND locale = $2 RETURNING name
+`
+
+type DeleteEmailTemplateParams struct {
+	Name   EmailTemplateName `json:"name"`
+	Locale string            `json:"locale"`
+}
+
+// Delete the override of an email template, restoring the default one
+func (q *Queries) DeleteEmailTemplate(ctx context.Context, arg DeleteEmailTemplateParams) (EmailTemplateName, error) {
+	row := q.queryRow(ctx, q.deleteEmailTemplateStmt, deleteEmailTemplate, arg.Name, arg.Locale)
+	var name EmailTemplateName
+	err := row.Scan(&name)
+	return name, err
+}
+
+const deleteFeedback = `-- name: DeleteFeedback :one
+DELETE FROM challenge_feedbacks WHERE user_id = $1 AND chall_id = $2 RETURNING chall_id
+`
+
+type DeleteFeedbackParams struct {
+	UserID  int32 `json:"user_id"`
+	ChallID int32 `json:"chall_id"`
+}
+
+// Delete the feedback of a user on a challenge
+func (q *Queries) DeleteFeedback(ctx context.Context, arg DeleteFeedbackParams) (int32, error) {
+	row := q.queryRow(ctx, q.deleteFeedbackStmt, deleteFeedback, arg.UserID, arg.ChallID)
+	var challID int32
+	err := row.Scan(&challID)
+	return challID, err
+}
+
 const deleteFlag = `-- name: DeleteFlag :exec
 DELETE FROM flags WHERE chall_id = $1 AND flag = $2
 `
@@ -236,6 +522,17 @@ func (q *Queries) DeleteInstance(ctx context.Context, arg DeleteInstanceParams)
 	return err
 }
 
+const deleteRole = `-- name: DeleteRole :one
+DELETE FROM roles WHERE name = $1 RETURNING name
+`
+
+// Delete a custom role, its users keep only the permissions of their user role
+func (q *Queries) DeleteRole(ctx context.Context, name string) (string, error) {
+	row := q.queryRow(ctx, q.deleteRoleStmt, deleteRole, name)
+	err := row.Scan(&name)
+	return name, err
+}
+
 const deleteSubmission = `-- name: DeleteSubmission :exec
 DELETE FROM submissions WHERE id = $1
 `
@@ -246,6 +543,89 @@ func (q *Queries) DeleteSubmission(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteWriteup = `-- name: DeleteWriteup :one
+DELETE FROM writeups
+  WHERE id = $1
+    AND ($2::BOOLEAN
+      OR ($3::BOOLEAN AND team_id IS NULL)
+      OR team_id = $4)
+  RETURNING id
+`
+
+type DeleteWriteupParams struct {
+	ID       int32         `json:"id"`
+	All      bool          `json:"all"`
+	Official bool          `json:"official"`
+	TeamID   sql.NullInt32 `json:"team_id"`
+}
+
+// Delete a writeup of the team, an official one or any of them
+func (q *Queries) DeleteWriteup(ctx context.Context, arg DeleteWriteupParams) (int32, error) {
+	row := q.queryRow(ctx, q.deleteWriteupStmt, deleteWriteup,
+		arg.ID,
+		arg.All,
+		arg.Official,
+		arg.TeamID,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const demoteChallengeOwner = `-- name: DemoteChallengeOwner :exec
+UPDATE challenge_owners SET role = 'CoOwner' WHERE chall_id = $1 AND role = 'Owner'
+`
+
+// Make the current Owner of a challenge a co-owner
+func (q *Queries) DemoteChallengeOwner(ctx context.Context, challID int32) error {
+	_, err := q.exec(ctx, q.demoteChallengeOwnerStmt, demoteChallengeOwner, challID)
+	return err
+}
+
+const extendInstance = `-- name: ExtendInstance :one
+UPDATE instances
+  SET expires_at = $3, extensions = extensions + 1, extended_at = $5
+  WHERE team_id = $1 AND chall_id = $2 AND extensions = $4
+  RETURNING extensions
+`
+
+type ExtendInstanceParams struct {
+	TeamID     int32        `json:"team_id"`
+	ChallID    int32        `json:"chall_id"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	Extensions int32        `json:"extensions"`
+	ExtendedAt sql.NullTime `json:"extended_at"`
+}
+
+// Extends an instance, only if no other extension happened in the meantime
+func (q *Queries) ExtendInstance(ctx context.Context, arg ExtendInstanceParams) (int32, error) {
+	row := q.queryRow(ctx, q.extendInstanceStmt, extendInstance,
+		arg.TeamID,
+		arg.ChallID,
+		arg.ExpiresAt,
+		arg.Extensions,
+		arg.ExtendedAt,
+	)
+	var extensions int32
+	err := row.Scan(&extensions)
+	return extensions, err
+}
+
+const flagInstanceUsage = `-- name: FlagInstanceUsage :exec
+UPDATE instance_usage SET flagged = TRUE WHERE team_id = $1 AND chall_id = $2
+`
+
+type FlagInstanceUsageParams struct {
+	TeamID  int32 `json:"team_id"`
+	ChallID int32 `json:"chall_id"`
+}
+
+// Flags an instance for exceeding its thresholds for too long
+func (q *Queries) FlagInstanceUsage(ctx context.Context, arg FlagInstanceUsageParams) error {
+	_, err := q.exec(ctx, q.flagInstanceUsageStmt, flagInstanceUsage, arg.TeamID, arg.ChallID)
+	return err
+}
+
 const getAdminStats = `-- name: GetAdminStats :one
 SELECT
   (SELECT COUNT(*) FROM users) AS total_users,
@@ -293,8 +673,15 @@ SELECT
     i.expires_at,
     i.host AS instance_host,
     i.port AS instance_port,
-    i.docker_id
+    i.docker_id,
+    i.team_only AS instance_team_only,
+    COALESCE(d.shared, FALSE) AS shared,
+    dep.host AS deployment_host
   FROM challenges c
+  LEFT JOIN docker_configs d
+    ON d.chall_id = c.id
+  LEFT JOIN deployments dep
+    ON dep.chall_id = c.id
   LEFT JOIN attachments a
     ON a.chall_id = c.id
   LEFT JOIN (
@@ -308,33 +695,36 @@ SELECT
   LEFT JOIN instances i
     ON i.chall_id = c.id
       AND i.team_id = (SELECT team_id FROM tid)
-  GROUP BY c.id, s.first_blood, i.expires_at, i.host, i.port, i.docker_id 
+  GROUP BY c.id, s.first_blood, i.expires_at, i.host, i.port, i.docker_id, i.team_only, d.shared, dep.host
   ORDER BY c.points ASC, c.id ASC
 `
 
 type GetAllChallengesInfoRow struct {
-	ID           int32          `json:"id"`
-	Name         string         `json:"name"`
-	Category     string         `json:"category"`
-	Description  string         `json:"description"`
-	Authors      []string       `json:"authors"`
-	Tags         []string       `json:"tags"`
-	Type         DeployType     `json:"type"`
-	Hidden       bool           `json:"hidden"`
-	MaxPoints    int32          `json:"max_points"`
-	ScoreType    ScoreType      `json:"score_type"`
-	Points       int32          `json:"points"`
-	Solves       int32          `json:"solves"`
-	Host         string         `json:"host"`
-	Port         int32          `json:"port"`
-	ConnType     ConnType       `json:"conn_type"`
-	Solved       bool           `json:"solved"`
-	FirstBlood   bool           `json:"first_blood"`
-	Attachments  []string       `json:"attachments"`
-	ExpiresAt    sql.NullTime   `json:"expires_at"`
-	InstanceHost sql.NullString `json:"instance_host"`
-	InstancePort sql.NullInt32  `json:"instance_port"`
-	DockerID     sql.NullString `json:"docker_id"`
+	ID               int32          `json:"id"`
+	Name             string         `json:"name"`
+	Category         string         `json:"category"`
+	Description      string         `json:"description"`
+	Authors          []string       `json:"authors"`
+	Tags             []string       `json:"tags"`
+	Type             DeployType     `json:"type"`
+	Hidden           bool           `json:"hidden"`
+	MaxPoints        int32          `json:"max_points"`
+	ScoreType        ScoreType      `json:"score_type"`
+	Points           int32          `json:"points"`
+	Solves           int32          `json:"solves"`
+	Host             string         `json:"host"`
+	Port             int32          `json:"port"`
+	ConnType         ConnType       `json:"conn_type"`
+	Solved           bool           `json:"solved"`
+	FirstBlood       bool           `json:"first_blood"`
+	Attachments      []string       `json:"attachments"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	InstanceHost     sql.NullString `json:"instance_host"`
+	InstancePort     sql.NullInt32  `json:"instance_port"`
+	DockerID         sql.NullString `json:"docker_id"`
+	InstanceTeamOnly sql.NullBool   `json:"instance_team_only"`
+	Shared           bool           `json:"shared"`
+	DeploymentHost   sql.NullString `json:"deployment_host"`
 }
 
 // Retrieve all challenges along with first blood status and instance info for a user
@@ -370,6 +760,79 @@ func (q *Queries) GetAllChallengesInfo(ctx context.Context, id int32) ([]GetAllC
 			&i.InstanceHost,
 			&i.InstancePort,
 			&i.DockerID,
+			&i.InstanceTeamOnly,
+			&i.Shared,
+			&i.DeploymentHost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAnnouncements = `-- name: GetAnnouncements :many
+SELECT
+  a.id,
+  a.title,
+  a.body,
+  a.priority,
+  a.chall_id,
+  c.name AS chall_name,
+  a.publish_at,
+  a.updated_at,
+  a.published
+FROM announcements a
+LEFT JOIN challenges c ON c.id = a.chall_id
+WHERE ($1::BOOLEAN OR (a.publish_at <= CURRENT_TIMESTAMP AND c.hidden IS NOT TRUE))
+  AND ($2::TIMESTAMP IS NULL OR a.publish_at > $2 OR a.updated_at > $2)
+ORDER BY a.publish_at DESC, a.id DESC
+`
+
+type GetAnnouncementsParams struct {
+	All   bool         `json:"all"`
+	Since sql.NullTime `json:"since"`
+}
+
+type GetAnnouncementsRow struct {
+	ID        int32                `json:"id"`
+	Title     string               `json:"title"`
+	Body      string               `json:"body"`
+	Priority  AnnouncementPriority `json:"priority"`
+	ChallID   sql.NullInt32        `json:"chall_id"`
+	ChallName sql.NullString       `json:"chall_name"`
+	PublishAt time.Time            `json:"publish_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Published bool                 `json:"published"`
+}
+
+// Retrieve the announcements published or edited after a time, the scheduled
+// ones and the ones about hidden challenges only if requested
+func (q *Queries) GetAnnouncements(ctx context.Context, arg GetAnnouncementsParams) ([]GetAnnouncementsRow, error) {
+	rows, err := q.query(ctx, q.getAnnouncementsStmt, getAnnouncements, arg.All, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAnnouncementsRow
+	for rows.Next() {
+		var i GetAnnouncementsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Body,
+			&i.Priority,
+			&i.ChallID,
+			&i.ChallName,
+			&i.PublishAt,
+			&i.UpdatedAt,
+			&i.Published,
 		); err != nil {
 			return nil, err
 		}
@@ -401,28 +864,58 @@ func (q *Queries) GetAttachmentHash(ctx context.Context, arg GetAttachmentHashPa
 	return hash, err
 }
 
-const getBadgesFromTeam = `-- name: GetBadgesFromTeam :many
-SELECT badges.name, badges.description FROM badges
-  JOIN teams ON teams.id = badges.team_id
-  WHERE teams.id = $1
+const getAuditEntries = `-- name: GetAuditEntries :many
+SELECT id, actor_id, actor_name, ip, action, target, before, after, timestamp
+  FROM audit_log
+  WHERE ($1::INTEGER IS NULL OR actor_id = $1)
+    AND ($2::TEXT IS NULL OR action = $2)
+    AND ($3::TEXT IS NULL OR target = $3)
+    AND ($4::TIMESTAMP IS NULL OR timestamp >= $4)
+    AND ($5::TIMESTAMP IS NULL OR timestamp < $5)
+  ORDER BY id DESC
+  OFFSET $6
+  LIMIT $7
 `
 
-type GetBadgesFromTeamRow struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
-
-// Retrieve all badges associated with a team
-func (q *Queries) GetBadgesFromTeam(ctx context.Context, id int32) ([]GetBadgesFromTeamRow, error) {
-	rows, err := q.query(ctx, q.getBadgesFromTeamStmt, getBadgesFromTeam, id)
+type GetAuditEntriesParams struct {
+	ActorID sql.NullInt32  `json:"actor_id"`
+	Action  sql.NullString `json:"action"`
+	Target  sql.NullString `json:"target"`
+	Since   sql.NullTime   `json:"since"`
+	Until   sql.NullTime   `json:"until"`
+	Offset  int32          `json:"offset"`
+	Limit   sql.NullInt32  `json:"limit"`
+}
+
+// Fetches the audit entries matching the filters, the latest first, with pagination
+func (q *Queries) GetAuditEntries(ctx context.Context, arg GetAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.query(ctx, q.getAuditEntriesStmt, getAuditEntries,
+		arg.ActorID,
+		arg.Action,
+		arg.Target,
+		arg.Since,
+		arg.Until,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetBadgesFromTeamRow
+	var items []AuditLog
 	for rows.Next() {
-		var i GetBadgesFromTeamRow
-		if err := rows.Scan(&i.Name, &i.Description); err != nil {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.ActorName,
+			&i.Ip,
+			&i.Action,
+			&i.Target,
+			&i.Before,
+			&i.After,
+			&i.Timestamp,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -436,21 +929,62 @@ func (q *Queries) GetBadgesFromTeam(ctx context.Context, id int32) ([]GetBadgesF
 	return items, nil
 }
 
-const getCategories = `-- name: GetCategories :many
-SELECT name, visible_challs FROM categories ORDER BY name ASC
+const getAuthorDashboard = `-- name: GetAuthorDashboard :many
+SELECT c.id, c.name, c.category, c.hidden, c.points, c.solves, o.role,
+    COUNT(s.id) FILTER (WHERE s.status = 'Wrong') AS wrong_submissions,
+    COUNT(DISTINCT u.team_id) AS teams,
+    MIN(s.timestamp) FILTER (WHERE s.status = 'Correct') AS first_solve,
+    MAX(s.timestamp) FILTER (WHERE s.status = 'Correct') AS last_solve
+  FROM challenges c
+  LEFT JOIN challenge_owners o ON o.chall_id = c.id AND o.user_id = $1
+  LEFT JOIN (submissions s JOIN users u ON u.id = s.user_id AND u.role = 'Player') ON s.chall_id = c.id
+  WHERE $2::BOOLEAN OR o.user_id IS NOT NULL
+  GROUP BY c.id, o.role
+  ORDER BY c.category ASC, c.name ASC
 `
 
-// Retrieve all categories
-func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
-	rows, err := q.query(ctx, q.getCategoriesStmt, getCategories)
+type GetAuthorDashboardParams struct {
+	UserID int32 `json:"user_id"`
+	All    bool  `json:"all"`
+}
+
+type GetAuthorDashboardRow struct {
+	ID               int32         `json:"id"`
+	Name             string        `json:"name"`
+	Category         string        `json:"category"`
+	Hidden           bool          `json:"hidden"`
+	Points           int32         `json:"points"`
+	Solves           int32         `json:"solves"`
+	Role             NullOwnerRole `json:"role"`
+	WrongSubmissions int64         `json:"wrong_submissions"`
+	Teams            int64         `json:"teams"`
+	FirstSolve       sql.NullTime  `json:"first_solve"`
+	LastSolve        sql.NullTime  `json:"last_solve"`
+}
+
+// Retrieve the solves and the wrong submissions of the players on the challenges of an owner, or on all of them
+func (q *Queries) GetAuthorDashboard(ctx context.Context, arg GetAuthorDashboardParams) ([]GetAuthorDashboardRow, error) {
+	rows, err := q.query(ctx, q.getAuthorDashboardStmt, getAuthorDashboard, arg.UserID, arg.All)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Category
+	var items []GetAuthorDashboardRow
 	for rows.Next() {
-		var i Category
-		if err := rows.Scan(&i.Name, &i.VisibleChalls); err != nil {
+		var i GetAuthorDashboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Category,
+			&i.Hidden,
+			&i.Points,
+			&i.Solves,
+			&i.Role,
+			&i.WrongSubmissions,
+			&i.Teams,
+			&i.FirstSolve,
+			&i.LastSolve,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -464,10 +998,73 @@ func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
 	return items, nil
 }
 
-const getCategory = `-- name: GetCategory :one
-SELECT name, visible_challs FROM categories WHERE name = $1
-`
-
+const getBadgesFromTeam = `-- name: GetBadgesFromTeam :many
+SELECT badges.name, badges.description FROM badges
+  JOIN teams ON teams.id = badges.team_id
+  WHERE teams.id = $1
+`
+
+type GetBadgesFromTeamRow struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Retrieve all badges associated with a team
+func (q *Queries) GetBadgesFromTeam(ctx context.Context, id int32) ([]GetBadgesFromTeamRow, error) {
+	rows, err := q.query(ctx, q.getBadgesFromTeamStmt, getBadgesFromTeam, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBadgesFromTeamRow
+	for rows.Next() {
+		var i GetBadgesFromTeamRow
+		if err := rows.Scan(&i.Name, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategories = `-- name: GetCategories :many
+SELECT name, visible_challs FROM categories ORDER BY name ASC
+`
+
+// Retrieve all categories
+func (q *Queries) GetCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.query(ctx, q.getCategoriesStmt, getCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(&i.Name, &i.VisibleChalls); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategory = `-- name: GetCategory :one
+SELECT name, visible_challs FROM categories WHERE name = $1
+`
+
 // fetch category by name
 func (q *Queries) GetCategory(ctx context.Context, name string) (Category, error) {
 	row := q.queryRow(ctx, q.getCategoryStmt, getCategory, name)
@@ -477,7 +1074,7 @@ func (q *Queries) GetCategory(ctx context.Context, name string) (Category, error
 }
 
 const getChallDockerConfig = `-- name: GetChallDockerConfig :one
-SELECT chall_id, image, compose, hash_domain, lifetime, envs, max_memory, max_cpu FROM docker_configs WHERE chall_id = $1
+SELECT chall_id, image, compose, hash_domain, lifetime, envs, max_memory, max_cpu, team_only, pow_difficulty, max_lifetime, max_extensions, extension_cooldown, extension_window, instance_flag, cpu_threshold, memory_threshold, pids_threshold, network_threshold, threshold_duration, auto_kill, egress, shared, placement, reserved_memory, reserved_cpu FROM docker_configs WHERE chall_id = $1
 `
 
 func (q *Queries) GetChallDockerConfig(ctx context.Context, challID int32) (DockerConfig, error) {
@@ -492,38 +1089,741 @@ func (q *Queries) GetChallDockerConfig(ctx context.Context, challID int32) (Dock
 		&i.Envs,
 		&i.MaxMemory,
 		&i.MaxCpu,
+		&i.TeamOnly,
+		&i.PowDifficulty,
+		&i.MaxLifetime,
+		&i.MaxExtensions,
+		&i.ExtensionCooldown,
+		&i.ExtensionWindow,
+		&i.InstanceFlag,
+		&i.CpuThreshold,
+		&i.MemoryThreshold,
+		&i.PidsThreshold,
+		&i.NetworkThreshold,
+		&i.ThresholdDuration,
+		&i.AutoKill,
+		&i.Egress,
+		&i.Shared,
+		pq.Array(&i.Placement),
+		&i.ReservedMemory,
+		&i.ReservedCpu,
+	)
+	return i, err
+}
+
+const getChallengeFeedback = `-- name: GetChallengeFeedback :many
+SELECT users.id AS user_id, users.name AS user_name, teams.id AS team_id, teams.name AS team_name,
+    challenge_feedbacks.vote, challenge_feedbacks.difficulty, challenge_feedbacks.comment, challenge_feedbacks.updated_at
+  FROM challenge_feedbacks
+  JOIN users ON users.id = challenge_feedbacks.user_id
+  LEFT JOIN teams ON teams.id = users.team_id
+  WHERE challenge_feedbacks.chall_id = $1
+  ORDER BY challenge_feedbacks.updated_at DESC
+`
+
+type GetChallengeFeedbackRow struct {
+	UserID     int32            `json:"user_id"`
+	UserName   string           `json:"user_name"`
+	TeamID     sql.NullInt32    `json:"team_id"`
+	TeamName   sql.NullString   `json:"team_name"`
+	Vote       NullFeedbackVote `json:"vote"`
+	Difficulty sql.NullInt32    `json:"difficulty"`
+	Comment    sql.NullString   `json:"comment"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// Retrieve all the feedback left on a challenge
+func (q *Queries) GetChallengeFeedback(ctx context.Context, challID int32) ([]GetChallengeFeedbackRow, error) {
+	rows, err := q.query(ctx, q.getChallengeFeedbackStmt, getChallengeFeedback, challID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChallengeFeedbackRow
+	for rows.Next() {
+		var i GetChallengeFeedbackRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.TeamID,
+			&i.TeamName,
+			&i.Vote,
+			&i.Difficulty,
+			&i.Comment,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChallengeRating = `-- name: GetChallengeRating :one
+SELECT COUNT(*) FILTER (WHERE vote = 'Like') AS likes,
+    COUNT(*) FILTER (WHERE vote = 'Dislike') AS dislikes,
+    COUNT(difficulty) AS difficulty_votes,
+    COALESCE(ROUND(AVG(difficulty), 2), 0)::FLOAT AS difficulty
+  FROM challenge_feedbacks
+  WHERE chall_id = $1
+`
+
+type GetChallengeRatingRow struct {
+	Likes           int64   `json:"likes"`
+	Dislikes        int64   `json:"dislikes"`
+	DifficultyVotes int64   `json:"difficulty_votes"`
+	Difficulty      float64 `json:"difficulty"`
+}
+
+// Retrieve the aggregated feedback of a challenge
+func (q *Queries) GetChallengeRating(ctx context.Context, challID int32) (GetChallengeRatingRow, error) {
+	row := q.queryRow(ctx, q.getChallengeRatingStmt, getChallengeRating, challID)
+	var i GetChallengeRatingRow
+	err := row.Scan(
+		&i.Likes,
+		&i.Dislikes,
+		&i.DifficultyVotes,
+		&i.Difficulty,
+	)
+	return i, err
+}
+
+const getChallengeSolves = `-- name: GetChallengeSolves :many
+SELECT teams.id, teams.name, submissions.timestamp
+  FROM submissions
+  JOIN users ON users.id = submissions.user_id
+  JOIN teams ON users.team_id = teams.id
+  WHERE users.role = 'Player'
+    AND submissions.chall_id = $1
+    AND submissions.status = 'Correct'
+  ORDER BY submissions.timestamp ASC
+`
+
+type GetChallengeSolvesRow struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Retrieve all teams that solved a challenge
+func (q *Queries) GetChallengeSolves(ctx context.Context, challID int32) ([]GetChallengeSolvesRow, error) {
+	rows, err := q.query(ctx, q.getChallengeSolvesStmt, getChallengeSolves, challID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChallengeSolvesRow
+	for rows.Next() {
+		var i GetChallengeSolvesRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Timestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getConfigs = `-- name: GetConfigs :many
+SELECT key, type, value, name, category, description, secret FROM configs ORDER BY key
+`
+
+// Fetches all configuration settings
+func (q *Queries) GetConfigs(ctx context.Context) ([]Config, error) {
+	rows, err := q.query(ctx, q.getConfigsStmt, getConfigs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Config
+	for rows.Next() {
+		var i Config
+		if err := rows.Scan(
+			&i.Key,
+			&i.Type,
+			&i.Value,
+			&i.Name,
+			&i.Category,
+			&i.Description,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeployment = `-- name: GetDeployment :one
+SELECT status, error, restarts, updated_at FROM deployments WHERE chall_id = $1
+`
+
+type GetDeploymentRow struct {
+	Status    DeploymentStatus `json:"status"`
+	Error     string           `json:"error"`
+	Restarts  int32            `json:"restarts"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Retrieves the state of the shared deployment of a challenge
+func (q *Queries) GetDeployment(ctx context.Context, challID int32) (GetDeploymentRow, error) {
+	row := q.queryRow(ctx, q.getDeploymentStmt, getDeployment, challID)
+	var i GetDeploymentRow
+	err := row.Scan(
+		&i.Status,
+		&i.Error,
+		&i.Restarts,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getEmailTemplates = `-- name: GetEmailTemplates :many
+SELECT name, locale, subject, html, text FROM email_templates ORDER BY name, locale
+`
+
+// Retrieve all the overrides of the email templates
+func (q *Queries) GetEmailTemplates(ctx context.Context) ([]EmailTemplate, error) {
+	rows, err := q.query(ctx, q.getEmailTemplatesStmt, getEmailTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EmailTemplate
+	for rows.Next() {
+		var i EmailTemplate
+		if err := rows.Scan(
+			&i.Name,
+			&i.Locale,
+			&i.Subject,
+			&i.Html,
+			&i.Text,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlagsByChallenge = `-- name: GetFlagsByChallenge :many
+SELECT flag, regex FROM flags WHERE chall_id = $1
+`
+
+type GetFlagsByChallengeRow struct {
+	Flag  string `json:"flag"`
+	Regex bool   `json:"regex"`
+}
+
+// Retrieve all flags associated with a challenge
+func (q *Queries) GetFlagsByChallenge(ctx context.Context, challID int32) ([]GetFlagsByChallengeRow, error) {
+	rows, err := q.query(ctx, q.getFlagsByChallengeStmt, getFlagsByChallenge, challID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFlagsByChallengeRow
+	for rows.Next() {
+		var i GetFlagsByChallengeRow
+		if err := rows.Scan(&i.Flag, &i.Regex); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInstance = `-- name: GetInstance :one
+SELECT team_id, chall_id, expires_at, host, port, docker_id, created_at, team_only, node, extensions, extended_at FROM instances WHERE chall_id = $1 AND team_id = $2
+`
+
+type GetInstanceParams struct {
+	ChallID int32 `json:"chall_id"`
+	TeamID  int32 `json:"team_id"`
+}
+
+// Gets an instance by ID
+func (q *Queries) GetInstance(ctx context.Context, arg GetInstanceParams) (Instance, error) {
+	row := q.queryRow(ctx, q.getInstanceStmt, getInstance, arg.ChallID, arg.TeamID)
+	var i Instance
+	err := row.Scan(
+		&i.TeamID,
+		&i.ChallID,
+		&i.ExpiresAt,
+		&i.Host,
+		&i.Port,
+		&i.DockerID,
+		&i.CreatedAt,
+		&i.TeamOnly,
+		&i.Node,
+		&i.Extensions,
+		&i.ExtendedAt,
+	)
+	return i, err
+}
+
+const getInstanceByHost = `-- name: GetInstanceByHost :one
+SELECT i.team_id, i.chall_id, i.expires_at, i.team_only, c.port, c.conn_type
+  FROM instances i
+  JOIN challenges c ON c.id = i.chall_id
+  WHERE i.host = $1 AND i.port IS NULL
+`
+
+type GetInstanceByHostRow struct {
+	TeamID    int32     `json:"team_id"`
+	ChallID   int32     `json:"chall_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	TeamOnly  bool      `json:"team_only"`
+	Port      int32     `json:"port"`
+	ConnType  ConnType  `json:"conn_type"`
+}
+
+// Retrieves the hash domain instance reachable at a host
+func (q *Queries) GetInstanceByHost(ctx context.Context, host string) (GetInstanceByHostRow, error) {
+	row := q.queryRow(ctx, q.getInstanceByHostStmt, getInstanceByHost, host)
+	var i GetInstanceByHostRow
+	err := row.Scan(
+		&i.TeamID,
+		&i.ChallID,
+		&i.ExpiresAt,
+		&i.TeamOnly,
+		&i.Port,
+		&i.ConnType,
+	)
+	return i, err
+}
+
+const getInstanceEvents = `-- name: GetInstanceEvents :many
+SELECT
+    e.id,
+    COALESCE(e.team_id, 0) AS team_id,
+    COALESCE(t.name, '') AS team_name,
+    COALESCE(e.chall_id, 0) AS chall_id,
+    COALESCE(c.name, '') AS chall_name,
+    e.type,
+    e.duration,
+    e.host,
+    COALESCE(e.port, 0) AS port,
+    e.error,
+    e.timestamp
+  FROM instance_events e
+  LEFT JOIN teams t ON e.team_id = t.id
+  LEFT JOIN challenges c ON e.chall_id = c.id
+  WHERE ($1::INTEGER IS NULL OR e.team_id = $1)
+    AND ($2::INTEGER IS NULL OR e.chall_id = $2)
+    AND ($3::instance_event_type IS NULL OR e.type = $3)
+    AND ($4::INTEGER IS NULL OR e.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = $4))
+  ORDER BY e.id DESC
+  OFFSET $5
+  LIMIT $6
+`
+
+type GetInstanceEventsParams struct {
+	TeamID  sql.NullInt32         `json:"team_id"`
+	ChallID sql.NullInt32         `json:"chall_id"`
+	Type    NullInstanceEventType `json:"type"`
+	Owner   sql.NullInt32         `json:"owner"`
+	Offset  int32                 `json:"offset"`
+	Limit   sql.NullInt32         `json:"limit"`
+}
+
+type GetInstanceEventsRow struct {
+	ID        int32             `json:"id"`
+	TeamID    int32             `json:"team_id"`
+	TeamName  string            `json:"team_name"`
+	ChallID   int32             `json:"chall_id"`
+	ChallName string            `json:"chall_name"`
+	Type      InstanceEventType `json:"type"`
+	Duration  int32             `json:"duration"`
+	Host      string            `json:"host"`
+	Port      int32             `json:"port"`
+	Error     string            `json:"error"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Fetches the instance events matching the filters, with pagination
+func (q *Queries) GetInstanceEvents(ctx context.Context, arg GetInstanceEventsParams) ([]GetInstanceEventsRow, error) {
+	rows, err := q.query(ctx, q.getInstanceEventsStmt, getInstanceEvents,
+		arg.TeamID,
+		arg.ChallID,
+		arg.Type,
+		arg.Owner,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInstanceEventsRow
+	for rows.Next() {
+		var i GetInstanceEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamID,
+			&i.TeamName,
+			&i.ChallID,
+			&i.ChallName,
+			&i.Type,
+			&i.Duration,
+			&i.Host,
+			&i.Port,
+			&i.Error,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInstanceFlagOwner = `-- name: GetInstanceFlagOwner :one
+SELECT team_id FROM instance_secrets
+  WHERE chall_id = $1 AND name = 'Flag' AND value = $2
+  ORDER BY team_id = $3 DESC
+  LIMIT 1
+`
+
+type GetInstanceFlagOwnerParams struct {
+	ChallID int32  `json:"chall_id"`
+	Flag    string `json:"flag"`
+	TeamID  int32  `json:"team_id"`
+}
+
+// Finds the team an instance flag was generated for, preferring the given team
+func (q *Queries) GetInstanceFlagOwner(ctx context.Context, arg GetInstanceFlagOwnerParams) (int32, error) {
+	row := q.queryRow(ctx, q.getInstanceFlagOwnerStmt, getInstanceFlagOwner, arg.ChallID, arg.Flag, arg.TeamID)
+	var teamID int32
+	err := row.Scan(&teamID)
+	return teamID, err
+}
+
+const getInstanceUsage = `-- name: GetInstanceUsage :one
+SELECT team_id, chall_id, samples, cpu, cpu_avg, cpu_max, memory, memory_avg, memory_max, pids, pids_max, network, network_avg, network_max, over_since, flagged, updated_at FROM instance_usage WHERE team_id = $1 AND chall_id = $2
+`
+
+type GetInstanceUsageParams struct {
+	TeamID  int32 `json:"team_id"`
+	ChallID int32 `json:"chall_id"`
+}
+
+// Retrieves the rolling aggregates of an instance
+func (q *Queries) GetInstanceUsage(ctx context.Context, arg GetInstanceUsageParams) (InstanceUsage, error) {
+	row := q.queryRow(ctx, q.getInstanceUsageStmt, getInstanceUsage, arg.TeamID, arg.ChallID)
+	var i InstanceUsage
+	err := row.Scan(
+		&i.TeamID,
+		&i.ChallID,
+		&i.Samples,
+		&i.Cpu,
+		&i.CpuAvg,
+		&i.CpuMax,
+		&i.Memory,
+		&i.MemoryAvg,
+		&i.MemoryMax,
+		&i.Pids,
+		&i.PidsMax,
+		&i.Network,
+		&i.NetworkAvg,
+		&i.NetworkMax,
+		&i.OverSince,
+		&i.Flagged,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInstances = `-- name: GetInstances :many
+SELECT
+  i.team_id,
+  t.name AS team_name,
+  i.chall_id,
+  c.name AS chall_name,
+  i.expires_at,
+  c.conn_type,
+  i.host,
+  COALESCE(i.port, 0) AS port,
+  COALESCE(i.docker_id, '') AS docker_id
+FROM instances i
+JOIN teams t ON i.team_id = t.id
+JOIN challenges c ON i.chall_id = c.id
+ORDER BY i.expires_at ASC
+`
+
+type GetInstancesRow struct {
+	TeamID    int32     `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	ChallID   int32     `json:"chall_id"`
+	ChallName string    `json:"chall_name"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ConnType  ConnType  `json:"conn_type"`
+	Host      string    `json:"host"`
+	Port      int32     `json:"port"`
+	DockerID  string    `json:"docker_id"`
+}
+
+// Get all instances
+func (q *Queries) GetInstances(ctx context.Context) ([]GetInstancesRow, error) {
+	rows, err := q.query(ctx, q.getInstancesStmt, getInstances)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInstancesRow
+	for rows.Next() {
+		var i GetInstancesRow
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.TeamName,
+			&i.ChallID,
+			&i.ChallName,
+			&i.ExpiresAt,
+			&i.ConnType,
+			&i.Host,
+			&i.Port,
+			&i.DockerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInstancesToReconcile = `-- name: GetInstancesToReconcile :many
+SELECT team_id, chall_id, docker_id, created_at
+  FROM instances
+  ORDER BY created_at ASC
+`
+
+type GetInstancesToReconcileRow struct {
+	TeamID    int32          `json:"team_id"`
+	ChallID   int32          `json:"chall_id"`
+	DockerID  sql.NullString `json:"docker_id"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Retrieves all instances to compare them with the running Docker objects
+func (q *Queries) GetInstancesToReconcile(ctx context.Context) ([]GetInstancesToReconcileRow, error) {
+	rows, err := q.query(ctx, q.getInstancesToReconcileStmt, getInstancesToReconcile)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInstancesToReconcileRow
+	for rows.Next() {
+		var i GetInstancesToReconcileRow
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.ChallID,
+			&i.DockerID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInstancesUsage = `-- name: GetInstancesUsage :many
+SELECT
+  u.team_id,
+  t.name AS team_name,
+  u.chall_id,
+  c.name AS chall_name,
+  u.samples,
+  u.cpu,
+  u.cpu_avg,
+  u.cpu_max,
+  u.memory,
+  u.memory_avg,
+  u.memory_max,
+  u.pids,
+  u.pids_max,
+  u.network,
+  u.network_avg,
+  u.network_max,
+  u.over_since,
+  u.flagged,
+  u.updated_at
+FROM instance_usage u
+JOIN teams t ON u.team_id = t.id
+JOIN challenges c ON u.chall_id = c.id
+ORDER BY u.flagged DESC, u.cpu_avg DESC
+`
+
+type GetInstancesUsageRow struct {
+	TeamID     int32        `json:"team_id"`
+	TeamName   string       `json:"team_name"`
+	ChallID    int32        `json:"chall_id"`
+	ChallName  string       `json:"chall_name"`
+	Samples    int32        `json:"samples"`
+	Cpu        float32      `json:"cpu"`
+	CpuAvg     float32      `json:"cpu_avg"`
+	CpuMax     float32      `json:"cpu_max"`
+	Memory     float32      `json:"memory"`
+	MemoryAvg  float32      `json:"memory_avg"`
+	MemoryMax  float32      `json:"memory_max"`
+	Pids       int32        `json:"pids"`
+	PidsMax    int32        `json:"pids_max"`
+	Network    float32      `json:"network"`
+	NetworkAvg float32      `json:"network_avg"`
+	NetworkMax float32      `json:"network_max"`
+	OverSince  sql.NullTime `json:"over_since"`
+	Flagged    bool         `json:"flagged"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Retrieves the resource usage of the running instances, flagged ones first
+func (q *Queries) GetInstancesUsage(ctx context.Context) ([]GetInstancesUsageRow, error) {
+	rows, err := q.query(ctx, q.getInstancesUsageStmt, getInstancesUsage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInstancesUsageRow
+	for rows.Next() {
+		var i GetInstancesUsageRow
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.TeamName,
+			&i.ChallID,
+			&i.ChallName,
+			&i.Samples,
+			&i.Cpu,
+			&i.CpuAvg,
+			&i.CpuMax,
+			&i.Memory,
+			&i.MemoryAvg,
+			&i.MemoryMax,
+			&i.Pids,
+			&i.PidsMax,
+			&i.Network,
+			&i.NetworkAvg,
+			&i.NetworkMax,
+			&i.OverSince,
+			&i.Flagged,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNextInstanceToDelete = `-- name: GetNextInstanceToDelete :one
+SELECT team_id, chall_id, expires_at, docker_id
+  FROM instances
+  WHERE expires_at < NOW() + (
+    (SELECT value
+      FROM configs
+      WHERE key='reclaim-instance-interval'
+    ) || ' seconds')::INTERVAL
+  ORDER BY expires_at ASC
+  LIMIT 1
+`
+
+type GetNextInstanceToDeleteRow struct {
+	TeamID    int32          `json:"team_id"`
+	ChallID   int32          `json:"chall_id"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	DockerID  sql.NullString `json:"docker_id"`
+}
+
+// Retrieves the next instance to delete
+func (q *Queries) GetNextInstanceToDelete(ctx context.Context) (GetNextInstanceToDeleteRow, error) {
+	row := q.queryRow(ctx, q.getNextInstanceToDeleteStmt, getNextInstanceToDelete)
+	var i GetNextInstanceToDeleteRow
+	err := row.Scan(
+		&i.TeamID,
+		&i.ChallID,
+		&i.ExpiresAt,
+		&i.DockerID,
 	)
 	return i, err
 }
 
-const getChallengeSolves = `-- name: GetChallengeSolves :many
-SELECT teams.id, teams.name, submissions.timestamp
-  FROM submissions
-  JOIN users ON users.id = submissions.user_id
-  JOIN teams ON users.team_id = teams.id
-  WHERE users.role = 'Player'
-    AND submissions.chall_id = $1
-    AND submissions.status = 'Correct'
-  ORDER BY submissions.timestamp ASC
+const getPendingNodeReservations = `-- name: GetPendingNodeReservations :many
+SELECT i.node, d.reserved_memory, d.reserved_cpu
+  FROM instances i
+  JOIN docker_configs d ON d.chall_id = i.chall_id
+  WHERE i.node <> '' AND i.docker_id IS NULL
 `
 
-type GetChallengeSolvesRow struct {
-	ID        int32     `json:"id"`
-	Name      string    `json:"name"`
-	Timestamp time.Time `json:"timestamp"`
+type GetPendingNodeReservationsRow struct {
+	Node           string `json:"node"`
+	ReservedMemory int32  `json:"reserved_memory"`
+	ReservedCpu    string `json:"reserved_cpu"`
 }
 
-// Retrieve all teams that solved a challenge
-func (q *Queries) GetChallengeSolves(ctx context.Context, challID int32) ([]GetChallengeSolvesRow, error) {
-	rows, err := q.query(ctx, q.getChallengeSolvesStmt, getChallengeSolves, challID)
+// Retrieves the reservations of the instances scheduled on a Swarm node whose
+// service is not created yet
+func (q *Queries) GetPendingNodeReservations(ctx context.Context) ([]GetPendingNodeReservationsRow, error) {
+	rows, err := q.query(ctx, q.getPendingNodeReservationsStmt, getPendingNodeReservations)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetChallengeSolvesRow
+	var items []GetPendingNodeReservationsRow
 	for rows.Next() {
-		var i GetChallengeSolvesRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.Timestamp); err != nil {
+		var i GetPendingNodeReservationsRow
+		if err := rows.Scan(&i.Node, &i.ReservedMemory, &i.ReservedCpu); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -537,29 +1837,21 @@ func (q *Queries) GetChallengeSolves(ctx context.Context, challID int32) ([]GetC
 	return items, nil
 }
 
-const getConfigs = `-- name: GetConfigs :many
-SELECT key, type, value, name, category, description, secret FROM configs ORDER BY key
+const getRoles = `-- name: GetRoles :many
+SELECT name, description, permissions FROM roles ORDER BY name ASC
 `
 
-// Fetches all configuration settings
-func (q *Queries) GetConfigs(ctx context.Context) ([]Config, error) {
-	rows, err := q.query(ctx, q.getConfigsStmt, getConfigs)
+// Retrieve all the custom roles
+func (q *Queries) GetRoles(ctx context.Context) ([]Role, error) {
+	rows, err := q.query(ctx, q.getRolesStmt, getRoles)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Config
+	var items []Role
 	for rows.Next() {
-		var i Config
-		if err := rows.Scan(
-			&i.Key,
-			&i.Type,
-			&i.Value,
-			&i.Name,
-			&i.Category,
-			&i.Description,
-			&i.Secret,
-		); err != nil {
+		var i Role
+		if err := rows.Scan(&i.Name, &i.Description, pq.Array(&i.Permissions)); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -573,26 +1865,44 @@ func (q *Queries) GetConfigs(ctx context.Context) ([]Config, error) {
 	return items, nil
 }
 
-const getFlagsByChallenge = `-- name: GetFlagsByChallenge :many
-SELECT flag, regex FROM flags WHERE chall_id = $1
+const getSharedDeployments = `-- name: GetSharedDeployments :many
+SELECT d.chall_id, dep.docker_id, COALESCE(dep.config_hash, '') AS config_hash,
+    COALESCE(dep.restarts, 0) AS restarts, COALESCE(dep.failures, 0) AS failures,
+    dep.updated_at
+  FROM docker_configs d
+  JOIN challenges c ON c.id = d.chall_id
+  LEFT JOIN deployments dep ON dep.chall_id = d.chall_id
+  WHERE d.shared AND c.type <> 'Normal'
+  ORDER BY d.chall_id ASC
 `
 
-type GetFlagsByChallengeRow struct {
-	Flag  string `json:"flag"`
-	Regex bool   `json:"regex"`
+type GetSharedDeploymentsRow struct {
+	ChallID    int32          `json:"chall_id"`
+	DockerID   sql.NullString `json:"docker_id"`
+	ConfigHash string         `json:"config_hash"`
+	Restarts   int32          `json:"restarts"`
+	Failures   int32          `json:"failures"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
 }
 
-// Retrieve all flags associated with a challenge
-func (q *Queries) GetFlagsByChallenge(ctx context.Context, challID int32) ([]GetFlagsByChallengeRow, error) {
-	rows, err := q.query(ctx, q.getFlagsByChallengeStmt, getFlagsByChallenge, challID)
+// Retrieves the challenges with a shared deployment and its current state
+func (q *Queries) GetSharedDeployments(ctx context.Context) ([]GetSharedDeploymentsRow, error) {
+	rows, err := q.query(ctx, q.getSharedDeploymentsStmt, getSharedDeployments)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFlagsByChallengeRow
+	var items []GetSharedDeploymentsRow
 	for rows.Next() {
-		var i GetFlagsByChallengeRow
-		if err := rows.Scan(&i.Flag, &i.Regex); err != nil {
+		var i GetSharedDeploymentsRow
+		if err := rows.Scan(
+			&i.ChallID,
+			&i.DockerID,
+			&i.ConfigHash,
+			&i.Restarts,
+			&i.Failures,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -606,80 +1916,30 @@ func (q *Queries) GetFlagsByChallenge(ctx context.Context, challID int32) ([]Get
 	return items, nil
 }
 
-const getInstance = `-- name: GetInstance :one
-SELECT team_id, chall_id, expires_at, host, port, docker_id FROM instances WHERE chall_id = $1 AND team_id = $2
-`
-
-type GetInstanceParams struct {
-	ChallID int32 `json:"chall_id"`
-	TeamID  int32 `json:"team_id"`
-}
-
-// Gets an instance by ID
-func (q *Queries) GetInstance(ctx context.Context, arg GetInstanceParams) (Instance, error) {
-	row := q.queryRow(ctx, q.getInstanceStmt, getInstance, arg.ChallID, arg.TeamID)
-	var i Instance
-	err := row.Scan(
-		&i.TeamID,
-		&i.ChallID,
-		&i.ExpiresAt,
-		&i.Host,
-		&i.Port,
-		&i.DockerID,
-	)
-	return i, err
-}
-
-const getInstances = `-- name: GetInstances :many
-SELECT
-  i.team_id,
-  t.name AS team_name,
-  i.chall_id,
-  c.name AS chall_name,
-  i.expires_at,
-  c.conn_type,
-  i.host,
-  COALESCE(i.port, 0) AS port,
-  COALESCE(i.docker_id, '') AS docker_id
-FROM instances i
-JOIN teams t ON i.team_id = t.id
-JOIN challenges c ON i.chall_id = c.id
-ORDER BY i.expires_at ASC
+const getStaleDeployments = `-- name: GetStaleDeployments :many
+SELECT dep.chall_id, dep.docker_id
+  FROM deployments dep
+  JOIN docker_configs d ON d.chall_id = dep.chall_id
+  JOIN challenges c ON c.id = dep.chall_id
+  WHERE NOT d.shared OR c.type = 'Normal'
 `
 
-type GetInstancesRow struct {
-	TeamID    int32     `json:"team_id"`
-	TeamName  string    `json:"team_name"`
-	ChallID   int32     `json:"chall_id"`
-	ChallName string    `json:"chall_name"`
-	ExpiresAt time.Time `json:"expires_at"`
-	ConnType  ConnType  `json:"conn_type"`
-	Host      string    `json:"host"`
-	Port      int32     `json:"port"`
-	DockerID  string    `json:"docker_id"`
+type GetStaleDeploymentsRow struct {
+	ChallID  int32          `json:"chall_id"`
+	DockerID sql.NullString `json:"docker_id"`
 }
 
-// Get all instances
-func (q *Queries) GetInstances(ctx context.Context) ([]GetInstancesRow, error) {
-	rows, err := q.query(ctx, q.getInstancesStmt, getInstances)
+// Retrieves the deployments of the challenges that are no longer shared
+func (q *Queries) GetStaleDeployments(ctx context.Context) ([]GetStaleDeploymentsRow, error) {
+	rows, err := q.query(ctx, q.getStaleDeploymentsStmt, getStaleDeployments)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetInstancesRow
+	var items []GetStaleDeploymentsRow
 	for rows.Next() {
-		var i GetInstancesRow
-		if err := rows.Scan(
-			&i.TeamID,
-			&i.TeamName,
-			&i.ChallID,
-			&i.ChallName,
-			&i.ExpiresAt,
-			&i.ConnType,
-			&i.Host,
-			&i.Port,
-			&i.DockerID,
-		); err != nil {
+		var i GetStaleDeploymentsRow
+		if err := rows.Scan(&i.ChallID, &i.DockerID); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -693,36 +1953,16 @@ func (q *Queries) GetInstances(ctx context.Context) ([]GetInstancesRow, error) {
 	return items, nil
 }
 
-const getNextInstanceToDelete = `-- name: GetNextInstanceToDelete :one
-SELECT team_id, chall_id, expires_at, docker_id
-  FROM instances
-  WHERE expires_at < NOW() + (
-    (SELECT value
-      FROM configs
-      WHERE key='reclaim-instance-interval'
-    ) || ' seconds')::INTERVAL
-  ORDER BY expires_at ASC
-  LIMIT 1
+const getSubmissionChallenge = `-- name: GetSubmissionChallenge :one
+SELECT chall_id FROM submissions WHERE id = $1
 `
 
-type GetNextInstanceToDeleteRow struct {
-	TeamID    int32          `json:"team_id"`
-	ChallID   int32          `json:"chall_id"`
-	ExpiresAt time.Time      `json:"expires_at"`
-	DockerID  sql.NullString `json:"docker_id"`
-}
-
-// Retrieves the next instance to delete
-func (q *Queries) GetNextInstanceToDelete(ctx context.Context) (GetNextInstanceToDeleteRow, error) {
-	row := q.queryRow(ctx, q.getNextInstanceToDeleteStmt, getNextInstanceToDelete)
-	var i GetNextInstanceToDeleteRow
-	err := row.Scan(
-		&i.TeamID,
-		&i.ChallID,
-		&i.ExpiresAt,
-		&i.DockerID,
-	)
-	return i, err
+// Retrieve the challenge of a submission
+func (q *Queries) GetSubmissionChallenge(ctx context.Context, id int32) (int32, error) {
+	row := q.queryRow(ctx, q.getSubmissionChallengeStmt, getSubmissionChallenge, id)
+	var challID int32
+	err := row.Scan(&challID)
+	return challID, err
 }
 
 const getSubmissions = `-- name: GetSubmissions :many
@@ -736,39 +1976,57 @@ SELECT
     c.name AS chall_name,
     s.status,
     s.first_blood,
-    s.flag,
-    s.timestamp
+    CASE WHEN $1::BOOLEAN THEN s.flag ELSE '' END AS flag,
+    s.timestamp,
+    s.flag_owner,
+    COALESCE(o.name, '') AS flag_owner_name
   FROM submissions s
   JOIN users u ON s.user_id = u.id
   JOIN teams t ON u.team_id = t.id
   JOIN challenges c ON s.chall_id = c.id
+  LEFT JOIN teams o ON s.flag_owner = o.id
+  WHERE (NOT $2::BOOLEAN OR s.flag_owner IS NOT NULL)
+    AND ($3::INTEGER IS NULL
+      OR s.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = $3))
   ORDER BY s.id DESC
-  OFFSET $1
-  LIMIT $2
+  OFFSET $4
+  LIMIT $5
 `
 
 type GetSubmissionsParams struct {
-	Offset int32         `json:"offset"`
-	Limit  sql.NullInt32 `json:"limit"`
+	Flags   bool          `json:"flags"`
+	Flagged bool          `json:"flagged"`
+	Owner   sql.NullInt32 `json:"owner"`
+	Offset  int32         `json:"offset"`
+	Limit   sql.NullInt32 `json:"limit"`
 }
 
 type GetSubmissionsRow struct {
-	ID         int32            `json:"id"`
-	UserID     int32            `json:"user_id"`
-	UserName   string           `json:"user_name"`
-	TeamID     int32            `json:"team_id"`
-	TeamName   string           `json:"team_name"`
-	ChallID    int32            `json:"chall_id"`
-	ChallName  string           `json:"chall_name"`
-	Status     SubmissionStatus `json:"status"`
-	FirstBlood bool             `json:"first_blood"`
-	Flag       string           `json:"flag"`
-	Timestamp  time.Time        `json:"timestamp"`
-}
-
-// fetches all submissions, with pagination
+	ID            int32            `json:"id"`
+	UserID        int32            `json:"user_id"`
+	UserName      string           `json:"user_name"`
+	TeamID        int32            `json:"team_id"`
+	TeamName      string           `json:"team_name"`
+	ChallID       int32            `json:"chall_id"`
+	ChallName     string           `json:"chall_name"`
+	Status        SubmissionStatus `json:"status"`
+	FirstBlood    bool             `json:"first_blood"`
+	Flag          string           `json:"flag"`
+	Timestamp     time.Time        `json:"timestamp"`
+	FlagOwner     sql.NullInt32    `json:"flag_owner"`
+	FlagOwnerName string           `json:"flag_owner_name"`
+}
+
+// fetches all submissions, with pagination, optionally only the flagged ones or the ones of the challenges of an owner,
+// the flags only if requested
 func (q *Queries) GetSubmissions(ctx context.Context, arg GetSubmissionsParams) ([]GetSubmissionsRow, error) {
-	rows, err := q.query(ctx, q.getSubmissionsStmt, getSubmissions, arg.Offset, arg.Limit)
+	rows, err := q.query(ctx, q.getSubmissionsStmt, getSubmissions,
+		arg.Flags,
+		arg.Flagged,
+		arg.Owner,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -788,6 +2046,8 @@ func (q *Queries) GetSubmissions(ctx context.Context, arg GetSubmissionsParams)
 			&i.FirstBlood,
 			&i.Flag,
 			&i.Timestamp,
+			&i.FlagOwner,
+			&i.FlagOwnerName,
 		); err != nil {
 			return nil, err
 		}
@@ -1054,23 +2314,135 @@ type GetTeamsScoreboardRow struct {
 	LastCorrectAt interface{}     `json:"last_correct_at"`
 }
 
-// Retrieve all teams or a subset if specified, ordered by score and last correct submission time
-func (q *Queries) GetTeamsScoreboard(ctx context.Context, arg GetTeamsScoreboardParams) ([]GetTeamsScoreboardRow, error) {
-	rows, err := q.query(ctx, q.getTeamsScoreboardStmt, getTeamsScoreboard, arg.Offset, arg.Limit)
+// Retrieve all teams or a subset if specified, ordered by score and last correct submission time
+func (q *Queries) GetTeamsScoreboard(ctx context.Context, arg GetTeamsScoreboardParams) ([]GetTeamsScoreboardRow, error) {
+	rows, err := q.query(ctx, q.getTeamsScoreboardStmt, getTeamsScoreboard, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamsScoreboardRow
+	for rows.Next() {
+		var i GetTeamsScoreboardRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Score,
+			&i.Country,
+			&i.Badges,
+			&i.LastCorrectAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamsScoreboardGraph = `-- name: GetTeamsScoreboardGraph :many
+SELECT
+    t.id AS team_id,
+    t.name AS team_name,
+    c.id AS chall_id,
+    c.points,
+    s.first_blood,
+    s."timestamp"
+  FROM (
+      SELECT id, name, password_hash, password_salt, score, country FROM teams t
+      ORDER BY t.score DESC
+      LIMIT CAST((SELECT value FROM configs WHERE key='scoreboard-top') AS INT)
+    ) AS t
+  JOIN users u ON u.team_id = t.id
+  JOIN submissions s ON s.user_id = u.id
+  JOIN challenges c ON c.id = s.chall_id
+  WHERE s.status = 'Correct'
+    AND u.role = 'Player'
+  ORDER BY s."timestamp" ASC NULLS LAST
+`
+
+type GetTeamsScoreboardGraphRow struct {
+	TeamID     int32     `json:"team_id"`
+	TeamName   string    `json:"team_name"`
+	ChallID    int32     `json:"chall_id"`
+	Points     int32     `json:"points"`
+	FirstBlood bool      `json:"first_blood"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Get the top N teams along with their correct submissions and challenge points for scoreboard graphing
+func (q *Queries) GetTeamsScoreboardGraph(ctx context.Context) ([]GetTeamsScoreboardGraphRow, error) {
+	rows, err := q.query(ctx, q.getTeamsScoreboardGraphStmt, getTeamsScoreboardGraph)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTeamsScoreboardGraphRow
+	for rows.Next() {
+		var i GetTeamsScoreboardGraphRow
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.TeamName,
+			&i.ChallID,
+			&i.Points,
+			&i.FirstBlood,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTicketMessages = `-- name: GetTicketMessages :many
+SELECT ticket_messages.id, ticket_messages.user_id, users.name AS user_name,
+    ticket_messages.staff, ticket_messages.body, ticket_messages.attachments, ticket_messages.timestamp
+  FROM ticket_messages
+  LEFT JOIN users ON users.id = ticket_messages.user_id
+  WHERE ticket_messages.ticket_id = $1
+  ORDER BY ticket_messages.id ASC
+`
+
+type GetTicketMessagesRow struct {
+	ID          int32          `json:"id"`
+	UserID      sql.NullInt32  `json:"user_id"`
+	UserName    sql.NullString `json:"user_name"`
+	Staff       bool           `json:"staff"`
+	Body        string         `json:"body"`
+	Attachments []string       `json:"attachments"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// Retrieve the messages of a ticket, oldest first
+func (q *Queries) GetTicketMessages(ctx context.Context, ticketID int32) ([]GetTicketMessagesRow, error) {
+	rows, err := q.query(ctx, q.getTicketMessagesStmt, getTicketMessages, ticketID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTeamsScoreboardRow
+	var items []GetTicketMessagesRow
 	for rows.Next() {
-		var i GetTeamsScoreboardRow
+		var i GetTicketMessagesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.Score,
-			&i.Country,
-			&i.Badges,
-			&i.LastCorrectAt,
+			&i.UserID,
+			&i.UserName,
+			&i.Staff,
+			&i.Body,
+			pq.Array(&i.Attachments),
+			&i.Timestamp,
 		); err != nil {
 			return nil, err
 		}
@@ -1085,53 +2457,64 @@ func (q *Queries) GetTeamsScoreboard(ctx context.Context, arg GetTeamsScoreboard
 	return items, nil
 }
 
-const getTeamsScoreboardGraph = `-- name: GetTeamsScoreboardGraph :many
-SELECT
-    t.id AS team_id,
-    t.name AS team_name,
-    c.id AS chall_id,
-    c.points,
-    s.first_blood,
-    s."timestamp"
-  FROM (
-      SELECT id, name, password_hash, password_salt, score, country FROM teams t
-      ORDER BY t.score DESC
-      LIMIT CAST((SELECT value FROM configs WHERE key='scoreboard-top') AS INT)
-    ) AS t
-  JOIN users u ON u.team_id = t.id
-  JOIN submissions s ON s.user_id = u.id
-  JOIN challenges c ON c.id = s.chall_id
-  WHERE s.status = 'Correct'
-    AND u.role = 'Player'
-  ORDER BY s."timestamp" ASC NULLS LAST
+const getTickets = `-- name: GetTickets :many
+SELECT tickets.id, tickets.team_id, teams.name AS team_name,
+    tickets.chall_id, challenges.name AS chall_name,
+    tickets.title, tickets.status, tickets.created_at, tickets.updated_at
+  FROM tickets
+  JOIN teams ON teams.id = tickets.team_id
+  LEFT JOIN challenges ON challenges.id = tickets.chall_id
+  WHERE ($1::ticket_status IS NULL OR tickets.status = $1)
+    AND ($2::BOOLEAN
+      OR tickets.team_id = $3
+      OR tickets.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = $4))
+  ORDER BY tickets.updated_at DESC, tickets.id DESC
 `
 
-type GetTeamsScoreboardGraphRow struct {
-	TeamID     int32     `json:"team_id"`
-	TeamName   string    `json:"team_name"`
-	ChallID    int32     `json:"chall_id"`
-	Points     int32     `json:"points"`
-	FirstBlood bool      `json:"first_blood"`
-	Timestamp  time.Time `json:"timestamp"`
+type GetTicketsParams struct {
+	Status NullTicketStatus `json:"status"`
+	All    bool             `json:"all"`
+	TeamID sql.NullInt32    `json:"team_id"`
+	Owner  sql.NullInt32    `json:"owner"`
 }
 
-// Get the top N teams along with their correct submissions and challenge points for scoreboard graphing
-func (q *Queries) GetTeamsScoreboardGraph(ctx context.Context) ([]GetTeamsScoreboardGraphRow, error) {
-	rows, err := q.query(ctx, q.getTeamsScoreboardGraphStmt, getTeamsScoreboardGraph)
+type GetTicketsRow struct {
+	ID        int32          `json:"id"`
+	TeamID    int32          `json:"team_id"`
+	TeamName  string         `json:"team_name"`
+	ChallID   sql.NullInt32  `json:"chall_id"`
+	ChallName sql.NullString `json:"chall_name"`
+	Title     string         `json:"title"`
+	Status    TicketStatus   `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Retrieve the tickets of a team, the ones about the challenges owned by an author or all of them
+func (q *Queries) GetTickets(ctx context.Context, arg GetTicketsParams) ([]GetTicketsRow, error) {
+	rows, err := q.query(ctx, q.getTicketsStmt, getTickets,
+		arg.Status,
+		arg.All,
+		arg.TeamID,
+		arg.Owner,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTeamsScoreboardGraphRow
+	var items []GetTicketsRow
 	for rows.Next() {
-		var i GetTeamsScoreboardGraphRow
+		var i GetTicketsRow
 		if err := rows.Scan(
+			&i.ID,
 			&i.TeamID,
 			&i.TeamName,
 			&i.ChallID,
-			&i.Points,
-			&i.FirstBlood,
-			&i.Timestamp,
+			&i.ChallName,
+			&i.Title,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1146,13 +2529,83 @@ func (q *Queries) GetTeamsScoreboardGraph(ctx context.Context) ([]GetTeamsScoreb
 	return items, nil
 }
 
+const getTotalAuditEntries = `-- name: GetTotalAuditEntries :one
+SELECT COUNT(*)
+  FROM audit_log
+  WHERE ($1::INTEGER IS NULL OR actor_id = $1)
+    AND ($2::TEXT IS NULL OR action = $2)
+    AND ($3::TEXT IS NULL OR target = $3)
+    AND ($4::TIMESTAMP IS NULL OR timestamp >= $4)
+    AND ($5::TIMESTAMP IS NULL OR timestamp < $5)
+`
+
+type GetTotalAuditEntriesParams struct {
+	ActorID sql.NullInt32  `json:"actor_id"`
+	Action  sql.NullString `json:"action"`
+	Target  sql.NullString `json:"target"`
+	Since   sql.NullTime   `json:"since"`
+	Until   sql.NullTime   `json:"until"`
+}
+
+// Counts the audit entries matching the filters
+func (q *Queries) GetTotalAuditEntries(ctx context.Context, arg GetTotalAuditEntriesParams) (int64, error) {
+	row := q.queryRow(ctx, q.getTotalAuditEntriesStmt, getTotalAuditEntries,
+		arg.ActorID,
+		arg.Action,
+		arg.Target,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getTotalInstanceEvents = `-- name: GetTotalInstanceEvents :one
+SELECT COUNT(*)
+  FROM instance_events e
+  LEFT JOIN challenges c ON e.chall_id = c.id
+  WHERE ($1::INTEGER IS NULL OR e.team_id = $1)
+    AND ($2::INTEGER IS NULL OR e.chall_id = $2)
+    AND ($3::instance_event_type IS NULL OR e.type = $3)
+    AND ($4::INTEGER IS NULL OR e.chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = $4))
+`
+
+type GetTotalInstanceEventsParams struct {
+	TeamID  sql.NullInt32         `json:"team_id"`
+	ChallID sql.NullInt32         `json:"chall_id"`
+	Type    NullInstanceEventType `json:"type"`
+	Owner   sql.NullInt32         `json:"owner"`
+}
+
+// Counts the instance events matching the filters
+func (q *Queries) GetTotalInstanceEvents(ctx context.Context, arg GetTotalInstanceEventsParams) (int64, error) {
+	row := q.queryRow(ctx, q.getTotalInstanceEventsStmt, getTotalInstanceEvents,
+		arg.TeamID,
+		arg.ChallID,
+		arg.Type,
+		arg.Owner,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getTotalSubmissions = `-- name: GetTotalSubmissions :one
 SELECT COUNT(*) FROM submissions
+  WHERE (NOT $1::BOOLEAN OR flag_owner IS NOT NULL)
+    AND ($2::INTEGER IS NULL
+      OR chall_id IN (SELECT chall_id FROM challenge_owners WHERE user_id = $2))
 `
 
-// fetches total number of submissions
-func (q *Queries) GetTotalSubmissions(ctx context.Context) (int64, error) {
-	row := q.queryRow(ctx, q.getTotalSubmissionsStmt, getTotalSubmissions)
+type GetTotalSubmissionsParams struct {
+	Flagged bool          `json:"flagged"`
+	Owner   sql.NullInt32 `json:"owner"`
+}
+
+// fetches total number of submissions, optionally only the flagged ones or the ones of the challenges of an owner
+func (q *Queries) GetTotalSubmissions(ctx context.Context, arg GetTotalSubmissionsParams) (int64, error) {
+	row := q.queryRow(ctx, q.getTotalSubmissionsStmt, getTotalSubmissions, arg.Flagged, arg.Owner)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -1173,7 +2626,7 @@ func (q *Queries) GetTotalUsers(ctx context.Context, isAdmin bool) (int64, error
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password_hash, password_salt, created_at, score, role, team_id, country FROM users WHERE email = $1
+SELECT id, name, email, password_hash, password_salt, created_at, score, role, team_id, country, locale, custom_role FROM users WHERE email = $1
 `
 
 // Retrieve a user by their email address
@@ -1191,6 +2644,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Role,
 		&i.TeamID,
 		&i.Country,
+		&i.Locale,
+		&i.CustomRole,
 	)
 	return i, err
 }
@@ -1337,6 +2792,145 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersR
 	return items, nil
 }
 
+const getWriteupChallenge = `-- name: GetWriteupChallenge :one
+SELECT chall_id FROM writeups WHERE id = $1
+`
+
+// Retrieve the challenge of a writeup
+func (q *Queries) GetWriteupChallenge(ctx context.Context, id int32) (int32, error) {
+	row := q.queryRow(ctx, q.getWriteupChallengeStmt, getWriteupChallenge, id)
+	var challID int32
+	err := row.Scan(&challID)
+	return challID, err
+}
+
+const getWriteups = `-- name: GetWriteups :many
+SELECT writeups.id, writeups.chall_id, challenges.name AS chall_name,
+    writeups.team_id, teams.name AS team_name, writeups.content, writeups.url,
+    writeups.accepted, writeups.published, writeups.hidden, writeups.updated_at,
+    ($1::BOOLEAN AND ($2::INTEGER IS NULL OR owners.user_id IS NOT NULL))::BOOLEAN AS moderated
+  FROM writeups
+  JOIN challenges ON challenges.id = writeups.chall_id
+  LEFT JOIN teams ON teams.id = writeups.team_id
+  LEFT JOIN challenge_owners owners ON owners.chall_id = writeups.chall_id AND owners.user_id = $2
+  WHERE ($3::INTEGER IS NULL OR writeups.chall_id = $3)
+    AND (($1::BOOLEAN AND ($2::INTEGER IS NULL OR owners.user_id IS NOT NULL))
+      OR writeups.team_id = $4
+      OR (NOT writeups.hidden AND NOT challenges.hidden
+        AND ($5::BOOLEAN OR writeups.published)))
+  ORDER BY writeups.chall_id, writeups.team_id IS NOT NULL, writeups.accepted DESC, writeups.id
+`
+
+type GetWriteupsParams struct {
+	All     bool          `json:"all"`
+	Owner   sql.NullInt32 `json:"owner"`
+	ChallID sql.NullInt32 `json:"chall_id"`
+	TeamID  sql.NullInt32 `json:"team_id"`
+	Ended   bool          `json:"ended"`
+}
+
+type GetWriteupsRow struct {
+	ID        int32          `json:"id"`
+	ChallID   int32          `json:"chall_id"`
+	ChallName string         `json:"chall_name"`
+	TeamID    sql.NullInt32  `json:"team_id"`
+	TeamName  sql.NullString `json:"team_name"`
+	Content   string         `json:"content"`
+	Url       string         `json:"url"`
+	Accepted  bool           `json:"accepted"`
+	Published bool           `json:"published"`
+	Hidden    bool           `json:"hidden"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Moderated bool           `json:"moderated"`
+}
+
+// Retrieve the writeups visible to the team, all of them for the moderators
+// (of the challenges they own for an owner), grouped by challenge with the
+// official and accepted ones first
+func (q *Queries) GetWriteups(ctx context.Context, arg GetWriteupsParams) ([]GetWriteupsRow, error) {
+	rows, err := q.query(ctx, q.getWriteupsStmt, getWriteups,
+		arg.All,
+		arg.Owner,
+		arg.ChallID,
+		arg.TeamID,
+		arg.Ended,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWriteupsRow
+	for rows.Next() {
+		var i GetWriteupsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChallID,
+			&i.ChallName,
+			&i.TeamID,
+			&i.TeamName,
+			&i.Content,
+			&i.Url,
+			&i.Accepted,
+			&i.Published,
+			&i.Hidden,
+			&i.UpdatedAt,
+			&i.Moderated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const initProxySecret = `-- name: InitProxySecret :exec
+UPDATE configs SET value = $1 WHERE key = 'proxy-secret' AND value = ''
+`
+
+// Stores the generated proxy secret, unless another replica stored its own first
+func (q *Queries) InitProxySecret(ctx context.Context, value string) error {
+	_, err := q.exec(ctx, q.initProxySecretStmt, initProxySecret, value)
+	return err
+}
+
+const lockTask = `-- name: LockTask :exec
+SELECT pg_advisory_xact_lock($1::BIGINT)
+`
+
+// Waits for the advisory lock of a task, held until the end of the transaction
+func (q *Queries) LockTask(ctx context.Context, key int64) error {
+	_, err := q.exec(ctx, q.lockTaskStmt, lockTask, key)
+	return err
+}
+
+const moderateWriteup = `-- name: ModerateWriteup :one
+UPDATE writeups
+  SET published = COALESCE($1, published),
+    hidden = COALESCE($2, hidden)
+  WHERE id = $3
+  RETURNING id
+`
+
+type ModerateWriteupParams struct {
+	Published sql.NullBool `json:"published"`
+	Hidden    sql.NullBool `json:"hidden"`
+	ID        int32        `json:"id"`
+}
+
+// Publish or hide a writeup
+func (q *Queries) ModerateWriteup(ctx context.Context, arg ModerateWriteupParams) (int32, error) {
+	row := q.queryRow(ctx, q.moderateWriteupStmt, moderateWriteup, arg.Published, arg.Hidden, arg.ID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const registerTeam = `-- name: RegisterTeam :exec
 WITH locked_user AS (
     SELECT id FROM users
@@ -1374,7 +2968,7 @@ func (q *Queries) RegisterTeam(ctx context.Context, arg RegisterTeamParams) erro
 }
 
 const registerUser = `-- name: RegisterUser :one
-INSERT INTO users (name, email, password_hash, password_salt, role) VALUES ($1, $2, $3, $4, $5) RETURNING id, name, email, password_hash, password_salt, created_at, score, role, team_id, country
+INSERT INTO users (name, email, password_hash, password_salt, role) VALUES ($1, $2, $3, $4, $5) RETURNING id, name, email, password_hash, password_salt, created_at, score, role, team_id, country, locale, custom_role
 `
 
 type RegisterUserParams struct {
@@ -1406,10 +3000,27 @@ func (q *Queries) RegisterUser(ctx context.Context, arg RegisterUserParams) (Use
 		&i.Role,
 		&i.TeamID,
 		&i.Country,
+		&i.Locale,
+		&i.CustomRole,
 	)
 	return i, err
 }
 
+const removeChallengeOwner = `-- name: RemoveChallengeOwner :exec
+DELETE FROM challenge_owners WHERE chall_id = $1 AND user_id = $2
+`
+
+type RemoveChallengeOwnerParams struct {
+	ChallID int32 `json:"chall_id"`
+	UserID  int32 `json:"user_id"`
+}
+
+// Remove a user from the owners of a challenge
+func (q *Queries) RemoveChallengeOwner(ctx context.Context, arg RemoveChallengeOwnerParams) error {
+	_, err := q.exec(ctx, q.removeChallengeOwnerStmt, removeChallengeOwner, arg.ChallID, arg.UserID)
+	return err
+}
+
 const resetTeamPassword = `-- name: ResetTeamPassword :exec
 UPDATE teams SET password_hash = $2, password_salt = $3 WHERE id = $1
 `
@@ -1442,21 +3053,71 @@ func (q *Queries) ResetUserPassword(ctx context.Context, arg ResetUserPasswordPa
 	return err
 }
 
+const setTicketStatus = `-- name: SetTicketStatus :exec
+UPDATE tickets SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type SetTicketStatusParams struct {
+	ID     int32        `json:"id"`
+	Status TicketStatus `json:"status"`
+}
+
+// Change the status of a ticket
+func (q *Queries) SetTicketStatus(ctx context.Context, arg SetTicketStatusParams) error {
+	_, err := q.exec(ctx, q.setTicketStatusStmt, setTicketStatus, arg.ID, arg.Status)
+	return err
+}
+
+const setUserCustomRole = `-- name: SetUserCustomRole :one
+UPDATE users SET custom_role = $2 WHERE id = $1 RETURNING id
+`
+
+type SetUserCustomRoleParams struct {
+	ID         int32          `json:"id"`
+	CustomRole sql.NullString `json:"custom_role"`
+}
+
+// Grant a custom role to a user, or revoke it
+func (q *Queries) SetUserCustomRole(ctx context.Context, arg SetUserCustomRoleParams) (int32, error) {
+	row := q.queryRow(ctx, q.setUserCustomRoleStmt, setUserCustomRole, arg.ID, arg.CustomRole)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setWriteupAccepted = `-- name: SetWriteupAccepted :one
+UPDATE writeups SET accepted = $2 WHERE id = $1 RETURNING id
+`
+
+type SetWriteupAcceptedParams struct {
+	ID       int32 `json:"id"`
+	Accepted bool  `json:"accepted"`
+}
+
+// Mark a writeup as accepted by the authors, or remove the mark
+func (q *Queries) SetWriteupAccepted(ctx context.Context, arg SetWriteupAcceptedParams) (int32, error) {
+	row := q.queryRow(ctx, q.setWriteupAcceptedStmt, setWriteupAccepted, arg.ID, arg.Accepted)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const submit = `-- name: Submit :one
 WITH challenge AS (
     SELECT challenges.id FROM challenges
     WHERE challenges.id = $2 FOR UPDATE
   )
-INSERT INTO submissions (user_id, chall_id, status, flag)
-  VALUES ($1, (SELECT id FROM challenge), $4, $3)
+INSERT INTO submissions (user_id, chall_id, status, flag, flag_owner)
+  VALUES ($1, (SELECT id FROM challenge), $4, $3, $5)
   RETURNING status, first_blood
 `
 
 type SubmitParams struct {
-	UserID int32            `json:"user_id"`
-	ID     int32            `json:"id"`
-	Flag   string           `json:"flag"`
-	Status SubmissionStatus `json:"status"`
+	UserID    int32            `json:"user_id"`
+	ID        int32            `json:"id"`
+	Flag      string           `json:"flag"`
+	Status    SubmissionStatus `json:"status"`
+	FlagOwner sql.NullInt32    `json:"flag_owner"`
 }
 
 type SubmitRow struct {
@@ -1471,12 +3132,93 @@ func (q *Queries) Submit(ctx context.Context, arg SubmitParams) (SubmitRow, erro
 		arg.ID,
 		arg.Flag,
 		arg.Status,
+		arg.FlagOwner,
 	)
 	var i SubmitRow
 	err := row.Scan(&i.Status, &i.FirstBlood)
 	return i, err
 }
 
+const submitFeedback = `-- name: SubmitFeedback :one
+INSERT INTO challenge_feedbacks (user_id, chall_id, vote, difficulty, comment)
+  SELECT $1::INTEGER, $2::INTEGER,
+    $3::feedback_vote, $4::INTEGER, $5::TEXT
+  WHERE EXISTS (
+    SELECT 1
+      FROM submissions
+      JOIN users ON users.id = submissions.user_id
+      JOIN teams ON users.team_id = teams.id
+      WHERE users.role = 'Player'
+        AND submissions.chall_id = $2
+        AND submissions.status = 'Correct'
+        AND teams.id = $6
+  )
+  ON CONFLICT (user_id, chall_id) DO UPDATE
+    SET vote = COALESCE(EXCLUDED.vote, challenge_feedbacks.vote),
+      difficulty = COALESCE(EXCLUDED.difficulty, challenge_feedbacks.difficulty),
+      comment = COALESCE(EXCLUDED.comment, challenge_feedbacks.comment),
+      updated_at = CURRENT_TIMESTAMP
+  RETURNING chall_id
+`
+
+type SubmitFeedbackParams struct {
+	UserID     int32            `json:"user_id"`
+	ChallID    int32            `json:"chall_id"`
+	Vote       NullFeedbackVote `json:"vote"`
+	Difficulty sql.NullInt32    `json:"difficulty"`
+	Comment    sql.NullString   `json:"comment"`
+	TeamID     int32            `json:"team_id"`
+}
+
+// Insert or update the feedback of a user, keeping the fields not given, only if their team solved the challenge
+func (q *Queries) SubmitFeedback(ctx context.Context, arg SubmitFeedbackParams) (int32, error) {
+	row := q.queryRow(ctx, q.submitFeedbackStmt, submitFeedback,
+		arg.UserID,
+		arg.ChallID,
+		arg.Vote,
+		arg.Difficulty,
+		arg.Comment,
+		arg.TeamID,
+	)
+	var challID int32
+	err := row.Scan(&challID)
+	return challID, err
+}
+
+const submitWriteup = `-- name: SubmitWriteup :one
+INSERT INTO writeups (chall_id, team_id, user_id, content, url)
+  VALUES ($1, $2, $3, $4, $5)
+  ON CONFLICT (team_id, chall_id) DO UPDATE
+    SET user_id = EXCLUDED.user_id,
+      content = EXCLUDED.content,
+      url = EXCLUDED.url,
+      accepted = FALSE,
+      updated_at = CURRENT_TIMESTAMP
+  RETURNING id
+`
+
+type SubmitWriteupParams struct {
+	ChallID int32         `json:"chall_id"`
+	TeamID  sql.NullInt32 `json:"team_id"`
+	UserID  sql.NullInt32 `json:"user_id"`
+	Content string        `json:"content"`
+	Url     string        `json:"url"`
+}
+
+// Insert the writeup of a team replacing its previous one, or an official one without a team
+func (q *Queries) SubmitWriteup(ctx context.Context, arg SubmitWriteupParams) (int32, error) {
+	row := q.queryRow(ctx, q.submitWriteupStmt, submitWriteup,
+		arg.ChallID,
+		arg.TeamID,
+		arg.UserID,
+		arg.Content,
+		arg.Url,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const toggleChallengesHidden = `-- name: ToggleChallengesHidden :exec
 UPDATE challenges
   SET hidden = NOT hidden
@@ -1488,6 +3230,59 @@ func (q *Queries) ToggleChallengesHidden(ctx context.Context, challIds []int32)
 	return err
 }
 
+const tryLockTask = `-- name: TryLockTask :one
+SELECT pg_try_advisory_xact_lock($1::BIGINT) AS locked
+`
+
+// Takes the advisory lock of a background task until the end of the
+// transaction, false if another replica holds it
+func (q *Queries) TryLockTask(ctx context.Context, key int64) (bool, error) {
+	row := q.queryRow(ctx, q.tryLockTaskStmt, tryLockTask, key)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
+
+const updateAnnouncement = `-- name: UpdateAnnouncement :one
+UPDATE announcements
+SET
+  title = COALESCE($1, title),
+  body = COALESCE($2, body),
+  priority = COALESCE($3, priority),
+  chall_id = CASE WHEN $4::INTEGER IS NULL THEN chall_id ELSE NULLIF($4, 0) END,
+  publish_at = COALESCE($5, publish_at),
+  email = COALESCE($6, email),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $7
+RETURNING id
+`
+
+type UpdateAnnouncementParams struct {
+	Title     sql.NullString           `json:"title"`
+	Body      sql.NullString           `json:"body"`
+	Priority  NullAnnouncementPriority `json:"priority"`
+	ChallID   sql.NullInt32            `json:"chall_id"`
+	PublishAt sql.NullTime             `json:"publish_at"`
+	Email     sql.NullBool             `json:"email"`
+	ID        int32                    `json:"id"`
+}
+
+// Update the details of an announcement, a zero challenge ID removes the target
+func (q *Queries) UpdateAnnouncement(ctx context.Context, arg UpdateAnnouncementParams) (int32, error) {
+	row := q.queryRow(ctx, q.updateAnnouncementStmt, updateAnnouncement,
+		arg.Title,
+		arg.Body,
+		arg.Priority,
+		arg.ChallID,
+		arg.PublishAt,
+		arg.Email,
+		arg.ID,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateChallenge = `-- name: UpdateChallenge :exec
 UPDATE challenges
 SET
@@ -1566,22 +3361,59 @@ SET
   lifetime = COALESCE($4, lifetime),
   envs = COALESCE($5, envs),
   max_memory = COALESCE($6, max_memory),
-  max_cpu = COALESCE($7, max_cpu)
-WHERE chall_id = $8
+  max_cpu = COALESCE($7, max_cpu),
+  team_only = COALESCE($8, team_only),
+  pow_difficulty = COALESCE($9, pow_difficulty),
+  max_lifetime = CASE WHEN $10 = -1 THEN NULL ELSE COALESCE($10, max_lifetime) END,
+  max_extensions = CASE WHEN $11 = -1 THEN NULL ELSE COALESCE($11, max_extensions) END,
+  extension_cooldown = CASE WHEN $12 = -1 THEN NULL ELSE COALESCE($12, extension_cooldown) END,
+  extension_window = CASE WHEN $13 = -1 THEN NULL ELSE COALESCE($13, extension_window) END,
+  instance_flag = COALESCE($14, instance_flag),
+  cpu_threshold = COALESCE($15, cpu_threshold),
+  memory_threshold = COALESCE($16, memory_threshold),
+  pids_threshold = COALESCE($17, pids_threshold),
+  network_threshold = COALESCE($18, network_threshold),
+  threshold_duration = COALESCE($19, threshold_duration),
+  auto_kill = COALESCE($20, auto_kill),
+  egress = COALESCE($21, egress),
+  shared = COALESCE($22, shared),
+  placement = COALESCE($23, placement),
+  reserved_memory = COALESCE($24, reserved_memory),
+  reserved_cpu = COALESCE($25, reserved_cpu)
+WHERE chall_id = $26
 `
 
 type UpdateDockerConfigsParams struct {
-	Image      sql.NullString `json:"image"`
-	Compose    sql.NullString `json:"compose"`
-	HashDomain sql.NullBool   `json:"hash_domain"`
-	Lifetime   sql.NullInt32  `json:"lifetime"`
-	Envs       sql.NullString `json:"envs"`
-	MaxMemory  sql.NullInt32  `json:"max_memory"`
-	MaxCpu     sql.NullString `json:"max_cpu"`
-	ChallID    int32          `json:"chall_id"`
-}
-
-// Updates the Docker configurations for the challenge with the given ID
+	Image             sql.NullString   `json:"image"`
+	Compose           sql.NullString   `json:"compose"`
+	HashDomain        sql.NullBool     `json:"hash_domain"`
+	Lifetime          sql.NullInt32    `json:"lifetime"`
+	Envs              sql.NullString   `json:"envs"`
+	MaxMemory         sql.NullInt32    `json:"max_memory"`
+	MaxCpu            sql.NullString   `json:"max_cpu"`
+	TeamOnly          sql.NullBool     `json:"team_only"`
+	PowDifficulty     sql.NullInt32    `json:"pow_difficulty"`
+	MaxLifetime       sql.NullInt32    `json:"max_lifetime"`
+	MaxExtensions     sql.NullInt32    `json:"max_extensions"`
+	ExtensionCooldown sql.NullInt32    `json:"extension_cooldown"`
+	ExtensionWindow   sql.NullInt32    `json:"extension_window"`
+	InstanceFlag      sql.NullBool     `json:"instance_flag"`
+	CpuThreshold      sql.NullInt32    `json:"cpu_threshold"`
+	MemoryThreshold   sql.NullInt32    `json:"memory_threshold"`
+	PidsThreshold     sql.NullInt32    `json:"pids_threshold"`
+	NetworkThreshold  sql.NullInt32    `json:"network_threshold"`
+	ThresholdDuration sql.NullInt32    `json:"threshold_duration"`
+	AutoKill          sql.NullBool     `json:"auto_kill"`
+	Egress            NullEgressPolicy `json:"egress"`
+	Shared            sql.NullBool     `json:"shared"`
+	Placement         []string         `json:"placement"`
+	ReservedMemory    sql.NullInt32    `json:"reserved_memory"`
+	ReservedCpu       sql.NullString   `json:"reserved_cpu"`
+	ChallID           int32            `json:"chall_id"`
+}
+
+// Updates the Docker configurations for the challenge with the given ID,
+// -1 resets an extension limit to the global one
 func (q *Queries) UpdateDockerConfigs(ctx context.Context, arg UpdateDockerConfigsParams) error {
 	_, err := q.exec(ctx, q.updateDockerConfigsStmt, updateDockerConfigs,
 		arg.Image,
@@ -1591,6 +3423,24 @@ func (q *Queries) UpdateDockerConfigs(ctx context.Context, arg UpdateDockerConfi
 		arg.Envs,
 		arg.MaxMemory,
 		arg.MaxCpu,
+		arg.TeamOnly,
+		arg.PowDifficulty,
+		arg.MaxLifetime,
+		arg.MaxExtensions,
+		arg.ExtensionCooldown,
+		arg.ExtensionWindow,
+		arg.InstanceFlag,
+		arg.CpuThreshold,
+		arg.MemoryThreshold,
+		arg.PidsThreshold,
+		arg.NetworkThreshold,
+		arg.ThresholdDuration,
+		arg.AutoKill,
+		arg.Egress,
+		arg.Shared,
+		pq.Array(arg.Placement),
+		arg.ReservedMemory,
+		arg.ReservedCpu,
 		arg.ChallID,
 	)
 	return err
@@ -1658,6 +3508,101 @@ func (q *Queries) UpdateInstanceExpire(ctx context.Context, arg UpdateInstanceEx
 	return err
 }
 
+const updateInstanceUsage = `-- name: UpdateInstanceUsage :exec
+INSERT INTO instance_usage (team_id, chall_id, samples, cpu, cpu_avg, cpu_max, memory, memory_avg, memory_max,
+    pids, pids_max, network, network_avg, network_max, over_since, updated_at)
+  VALUES ($1, $2, $3, $4, $5, $6,
+    $7, $8, $9, $10, $11,
+    $12, $13, $14, $15, $16)
+  ON CONFLICT (team_id, chall_id) DO UPDATE SET
+    samples = EXCLUDED.samples,
+    cpu = EXCLUDED.cpu,
+    cpu_avg = EXCLUDED.cpu_avg,
+    cpu_max = EXCLUDED.cpu_max,
+    memory = EXCLUDED.memory,
+    memory_avg = EXCLUDED.memory_avg,
+    memory_max = EXCLUDED.memory_max,
+    pids = EXCLUDED.pids,
+    pids_max = EXCLUDED.pids_max,
+    network = EXCLUDED.network,
+    network_avg = EXCLUDED.network_avg,
+    network_max = EXCLUDED.network_max,
+    over_since = EXCLUDED.over_since,
+    updated_at = EXCLUDED.updated_at
+`
+
+type UpdateInstanceUsageParams struct {
+	TeamID     int32        `json:"team_id"`
+	ChallID    int32        `json:"chall_id"`
+	Samples    int32        `json:"samples"`
+	Cpu        float32      `json:"cpu"`
+	CpuAvg     float32      `json:"cpu_avg"`
+	CpuMax     float32      `json:"cpu_max"`
+	Memory     float32      `json:"memory"`
+	MemoryAvg  float32      `json:"memory_avg"`
+	MemoryMax  float32      `json:"memory_max"`
+	Pids       int32        `json:"pids"`
+	PidsMax    int32        `json:"pids_max"`
+	Network    float32      `json:"network"`
+	NetworkAvg float32      `json:"network_avg"`
+	NetworkMax float32      `json:"network_max"`
+	OverSince  sql.NullTime `json:"over_since"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Stores a sample and the rolling aggregates of an instance
+func (q *Queries) UpdateInstanceUsage(ctx context.Context, arg UpdateInstanceUsageParams) error {
+	_, err := q.exec(ctx, q.updateInstanceUsageStmt, updateInstanceUsage,
+		arg.TeamID,
+		arg.ChallID,
+		arg.Samples,
+		arg.Cpu,
+		arg.CpuAvg,
+		arg.CpuMax,
+		arg.Memory,
+		arg.MemoryAvg,
+		arg.MemoryMax,
+		arg.Pids,
+		arg.PidsMax,
+		arg.Network,
+		arg.NetworkAvg,
+		arg.NetworkMax,
+		arg.OverSince,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const updateRole = `-- name: UpdateRole :one
+UPDATE roles
+SET
+  name = COALESCE($1, name),
+  description = COALESCE($2, description),
+  permissions = COALESCE($3::VARCHAR[], permissions)
+WHERE name = $4
+RETURNING name
+`
+
+type UpdateRoleParams struct {
+	NewName     sql.NullString `json:"new_name"`
+	Description sql.NullString `json:"description"`
+	Permissions []string       `json:"permissions"`
+	Name        string         `json:"name"`
+}
+
+// Update a custom role, renaming it also renames it for its users
+func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) (string, error) {
+	row := q.queryRow(ctx, q.updateRoleStmt, updateRole,
+		arg.NewName,
+		arg.Description,
+		pq.Array(arg.Permissions),
+		arg.Name,
+	)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
 const updateTeam = `-- name: UpdateTeam :exec
 UPDATE teams
 SET
@@ -1682,7 +3627,8 @@ const updateUser = `-- name: UpdateUser :exec
 UPDATE users
 SET
   name = COALESCE($2, name),
-  country = COALESCE($3, country)
+  country = COALESCE($3, country),
+  locale = CASE WHEN $4::VARCHAR IS NULL THEN locale ELSE NULLIF($4, '') END
 WHERE id = $1
 `
 
@@ -1690,11 +3636,86 @@ type UpdateUserParams struct {
 	ID      int32          `json:"id"`
 	Name    sql.NullString `json:"name"`
 	Country sql.NullString `json:"country"`
+	Locale  sql.NullString `json:"locale"`
 }
 
 // Update user details
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.exec(ctx, q.updateUserStmt, updateUser, arg.ID, arg.Name, arg.Country)
+	_, err := q.exec(ctx, q.updateUserStmt, updateUser,
+		arg.ID,
+		arg.Name,
+		arg.Country,
+		arg.Locale,
+	)
+	return err
+}
+
+const upsertDeployment = `-- name: UpsertDeployment :exec
+INSERT INTO deployments (chall_id, docker_id, host, config_hash, status, error, restarts, failures, updated_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+  ON CONFLICT (chall_id) DO UPDATE SET
+    docker_id = EXCLUDED.docker_id,
+    host = EXCLUDED.host,
+    config_hash = EXCLUDED.config_hash,
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    restarts = EXCLUDED.restarts,
+    failures = EXCLUDED.failures,
+    updated_at = EXCLUDED.updated_at
+`
+
+type UpsertDeploymentParams struct {
+	ChallID    int32            `json:"chall_id"`
+	DockerID   sql.NullString   `json:"docker_id"`
+	Host       string           `json:"host"`
+	ConfigHash string           `json:"config_hash"`
+	Status     DeploymentStatus `json:"status"`
+	Error      string           `json:"error"`
+	Restarts   int32            `json:"restarts"`
+	Failures   int32            `json:"failures"`
+}
+
+// Records the state of a shared deployment
+func (q *Queries) UpsertDeployment(ctx context.Context, arg UpsertDeploymentParams) error {
+	_, err := q.exec(ctx, q.upsertDeploymentStmt, upsertDeployment,
+		arg.ChallID,
+		arg.DockerID,
+		arg.Host,
+		arg.ConfigHash,
+		arg.Status,
+		arg.Error,
+		arg.Restarts,
+		arg.Failures,
+	)
+	return err
+}
+
+const upsertEmailTemplate = `-- name: UpsertEmailTemplate :exec
+INSERT INTO email_templates (name, locale, subject, html, text)
+  VALUES ($1, $2, $3, $4, $5)
+  ON CONFLICT (name, locale) DO UPDATE SET
+    subject = EXCLUDED.subject,
+    html = EXCLUDED.html,
+    text = EXCLUDED.text
+`
+
+type UpsertEmailTemplateParams struct {
+	Name    EmailTemplateName `json:"name"`
+	Locale  string            `json:"locale"`
+	Subject string            `json:"subject"`
+	Html    string            `json:"html"`
+	Text    string            `json:"text"`
+}
+
+// Insert or replace the override of an email template
+func (q *Queries) UpsertEmailTemplate(ctx context.Context, arg UpsertEmailTemplateParams) error {
+	_, err := q.exec(ctx, q.upsertEmailTemplateStmt, upsertEmailTemplate,
+		arg.Name,
+		arg.Locale,
+		arg.Subject,
+		arg.Html,
+		arg.Text,
+	)
 	return err
 }
 
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -20,6 +21,13 @@ var storage map[string]string
 var storageRWMutex sync.RWMutex
 var Store *session.Store
 
+// Subscribers of the channels of the in-memory storage
+var subscribers = make(map[string]map[chan string]struct{})
+var subscribersMutex sync.Mutex
+
+// Messages buffered for a subscriber, the next ones are dropped until it reads
+const subscriberBuffer = 16
+
 func initStorage(host string, port int, password string) {
 	storeConf := session.Config{
 		Expiration:     30 * 24 * time.Hour,
@@ -143,3 +151,88 @@ func StorageFlush(ctx context.Context) error {
 
 	return nil
 }
+
+// StoragePing returns the round trip time to Redis, zero for the in-memory
+// storage
+func StoragePing(ctx context.Context) (time.Duration, error) {
+	if rdb == nil {
+		return 0, nil
+	}
+
+	start := time.Now()
+	err := rdb.Ping(ctx).Err()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
+
+// StoragePublish sends the message to the subscribers of the channel, on every
+// replica when using Redis
+func StoragePublish(ctx context.Context, channel string, msg string) error {
+	if rdb == nil {
+		subscribersMutex.Lock()
+		defer subscribersMutex.Unlock()
+		for sub := range subscribers[channel] {
+			select {
+			case sub <- msg:
+			default:
+			}
+		}
+		return nil
+	}
+
+	err := rdb.Publish(ctx, channel, msg).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StorageSubscribe returns the messages published on the channel, until the
+// returned function is called
+func StorageSubscribe(ctx context.Context, channel string) (<-chan string, func(), error) {
+	sub := make(chan string, subscriberBuffer)
+
+	if rdb == nil {
+		subscribersMutex.Lock()
+		defer subscribersMutex.Unlock()
+		if subscribers[channel] == nil {
+			subscribers[channel] = make(map[chan string]struct{})
+		}
+		subscribers[channel][sub] = struct{}{}
+
+		return sub, func() {
+			subscribersMutex.Lock()
+			defer subscribersMutex.Unlock()
+			delete(subscribers[channel], sub)
+			close(sub)
+		}, nil
+	}
+
+	// Receiving the confirmation, the messages published next are not missed
+	pubsub := rdb.Subscribe(ctx, channel)
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		return nil, nil, errors.Join(err, pubsub.Close())
+	}
+
+	go func() {
+		defer close(sub)
+		for msg := range pubsub.Channel() {
+			select {
+			case sub <- msg.Payload:
+			default:
+			}
+		}
+	}()
+
+	return sub, func() {
+		err := pubsub.Close()
+		if err != nil {
+			log.Error("Failed to close subscription:", "channel", channel, "err", err)
+		}
+	}, nil
+}
@@ -3,8 +3,12 @@ package composes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
+	"trxd/db/sqlc"
 	"trxd/instancer/infos"
+	"trxd/instancer/networks"
+	"trxd/utils/consts"
 
 	"trxd/utils/log"
 
@@ -13,6 +17,9 @@ import (
 	"github.com/docker/compose/v5/pkg/api"
 )
 
+// Key of the private network of the instance within the compose project
+const privateNetKey = "trxd-private"
+
 func CreateCompose(ctx context.Context, info *infos.InstanceInfo, composeBody string) (string, error) {
 	if ComposeCli == nil {
 		return "", nil
@@ -23,24 +30,33 @@ func CreateCompose(ctx context.Context, info *infos.InstanceInfo, composeBody st
 		return "", err
 	}
 
-	project, err := setupComposeProject(ctx, composeInfo)
+	resolver, err := networks.CreatePrivateNetwork(ctx, info.Name, info.Egress, info.ResolverImage, info.InstanceLabels())
 	if err != nil {
 		return "", err
 	}
 
+	project, err := setupComposeProject(ctx, composeInfo, networks.PrivateNetworkName(info.Name), resolver)
+	if err != nil {
+		return "", errors.Join(err, networks.RemovePrivateNetwork(ctx, info.Name))
+	}
+
 	if log.GetLevel() == log.DebugLevel {
 		debugCompose(project)
 	}
 
 	err = ComposeCli.Up(ctx, project, api.UpOptions{})
 	if err != nil {
-		return "", err
+		return "", errors.Join(err, KillCompose(ctx, project.Name))
 	}
 
 	return project.Name, nil
 }
 
-func setupComposeProject(ctx context.Context, info *infos.ComposeInfo) (*types.Project, error) {
+// setupComposeProject attaches every service only to the private network of
+// the instance, so that the egress policy can't be bypassed with networks
+// declared in the compose. With the Full egress policy the chall service
+// alone joins the ingress network, otherwise a relay does it in its place.
+func setupComposeProject(ctx context.Context, info *infos.ComposeInfo, privateNet string, resolver string) (*types.Project, error) {
 	configDetails := types.ConfigDetails{
 		WorkingDir: "/" + info.Name + "/",
 		ConfigFiles: []types.ConfigFile{
@@ -65,15 +81,47 @@ func setupComposeProject(ctx context.Context, info *infos.ComposeInfo) (*types.P
 			api.ConfigFilesLabel: strings.Join(project.ComposeFiles, ","),
 			api.OneoffLabel:      "False",
 		}
+		for k, v := range info.InstanceLabels() {
+			s.CustomLabels[k] = v
+		}
+
+		switch {
+		case s.NetworkMode == "":
+			// Aliases declared on the dropped networks stay resolvable
+			var aliases []string
+			for _, netConf := range s.Networks {
+				if netConf != nil {
+					aliases = append(aliases, netConf.Aliases...)
+				}
+			}
+			s.Networks = map[string]*types.ServiceNetworkConfig{
+				privateNetKey: {Aliases: aliases},
+			}
+			// The embedded DNS of Docker keeps resolving the services, and
+			// forwards the other queries to the resolver
+			if info.Egress == sqlc.EgressPolicyDNS && resolver != "" {
+				s.DNS = []string{resolver}
+			}
+		case s.NetworkMode == "none", strings.HasPrefix(s.NetworkMode, "service:"):
+			// No network, or the one of another (already isolated) service
+		default:
+			return nil, errors.New("[network mode not allowed]")
+		}
+
+		// Only the ports of the chall service are relayed out of the internal
+		// private network
+		if s.Name != "chall" && len(s.Ports) > 0 && info.Egress != sqlc.EgressPolicyFull {
+			return nil, errors.New("[ports allowed only on the chall service]")
+		}
 
 		if s.Name == "chall" {
 			for k, v := range info.Labels {
 				s.CustomLabels[k] = v
 			}
 
-			if info.NetID != "" {
-				if _, ok := s.Networks[info.NetID]; !ok {
-					s.Networks[info.NetID] = nil
+			if info.NetID != "" && s.NetworkMode == "" && info.Egress == sqlc.EgressPolicyFull {
+				s.Networks[info.NetID] = &types.ServiceNetworkConfig{
+					Aliases: []string{info.Name},
 				}
 			}
 		}
@@ -81,10 +129,27 @@ func setupComposeProject(ctx context.Context, info *infos.ComposeInfo) (*types.P
 		project.Services[i] = s
 	}
 
-	if info.NetID != "" {
-		if _, ok := project.Networks[info.NetID]; !ok {
-			project.Networks[info.NetID] = types.NetworkConfig{
-				Name:     info.NetID,
+	project.Networks = types.Networks{
+		privateNetKey: {
+			Name:     privateNet,
+			External: true,
+		},
+	}
+
+	// The ingress network of instances without a domain only publishes the
+	// ports of the relay, and doesn't let it reach the other instances
+	ingressNet := info.NetID
+	if ingressNet == "" && info.Egress != sqlc.EgressPolicyFull {
+		ingressNet = consts.NetworkExternal
+	}
+	if info.Egress != sqlc.EgressPolicyFull {
+		setupRelay(info, project, ingressNet)
+	}
+
+	for _, s := range project.Services {
+		if _, ok := s.Networks[ingressNet]; ok {
+			project.Networks[ingressNet] = types.NetworkConfig{
+				Name:     ingressNet,
 				External: true,
 			}
 		}
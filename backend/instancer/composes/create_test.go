@@ -0,0 +1,145 @@
+package composes
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"trxd/db/sqlc"
+	"trxd/instancer/infos"
+	"trxd/utils/consts"
+)
+
+const testCompose = `
+services:
+  chall:
+    image: chall
+    ports:
+      - "${INSTANCE_PORT}:1337"
+    networks:
+      - custom
+  db:
+    image: db
+networks:
+  custom:
+`
+
+func testComposeInfo(egress sqlc.EgressPolicy, domain bool) *infos.ComposeInfo {
+	info := &infos.ComposeInfo{
+		InstanceInfo: infos.InstanceInfo{
+			Name:       "chall_1_2",
+			TeamID:     2,
+			ChallID:    1,
+			Egress:     egress,
+			RelayImage: "relay",
+		},
+		ComposeBody: testCompose,
+		Env:         map[string]string{"INSTANCE_PORT": "31337"},
+	}
+	info.Labels = info.InstanceLabels()
+	if domain {
+		info.NetID = consts.NetworkInternal
+		info.Labels["traefik.enable"] = "true"
+	}
+	return info
+}
+
+func TestSetupComposeProject(t *testing.T) {
+	privateNet := "chall_1_2-private"
+
+	for _, egress := range consts.EgressPolicies {
+		for _, domain := range []bool{false, true} {
+			info := testComposeInfo(egress, domain)
+			project, err := setupComposeProject(context.Background(), info, privateNet, "")
+			if err != nil {
+				t.Fatalf("%s (domain %v): %v", egress, domain, err)
+			}
+
+			if project.Networks[privateNetKey].Name != privateNet || !bool(project.Networks[privateNetKey].External) {
+				t.Errorf("%s (domain %v): unexpected private network %+v", egress, domain, project.Networks[privateNetKey])
+			}
+			if _, ok := project.Networks["custom"]; ok {
+				t.Errorf("%s (domain %v): expected the networks of the compose to be dropped", egress, domain)
+			}
+			for name, s := range project.Services {
+				if s.CustomLabels[consts.LabelInstance] != info.Name {
+					t.Errorf("%s (domain %v): expected %s to carry the instance labels", egress, domain, name)
+				}
+			}
+
+			chall := project.Services["chall"]
+			relay, hasRelay := project.Services[relayService]
+
+			if egress == sqlc.EgressPolicyFull {
+				if hasRelay {
+					t.Errorf("%s (domain %v): unexpected relay", egress, domain)
+				}
+				if len(chall.Ports) != 1 {
+					t.Errorf("%s (domain %v): expected chall to publish its port, got %+v", egress, domain, chall.Ports)
+				}
+				_, onIngress := chall.Networks[consts.NetworkInternal]
+				if onIngress != domain {
+					t.Errorf("%s (domain %v): expected chall on the ingress network %v", egress, domain, domain)
+				}
+				continue
+			}
+
+			// Every network the services join, but the relay, is internal
+			for name, s := range project.Services {
+				if name == relayService {
+					continue
+				}
+				for key := range s.Networks {
+					if key != privateNetKey {
+						t.Errorf("%s (domain %v): expected %s only on the private network, got %s", egress, domain, name, key)
+					}
+				}
+			}
+			if len(chall.Ports) != 0 || chall.CustomLabels["traefik.enable"] != "" {
+				t.Errorf("%s (domain %v): expected chall ingress to move to the relay", egress, domain)
+			}
+
+			if !hasRelay {
+				t.Fatalf("%s (domain %v): expected a relay", egress, domain)
+			}
+			ingressNet := consts.NetworkExternal
+			if domain {
+				ingressNet = consts.NetworkInternal
+			}
+			if _, ok := relay.Networks[ingressNet]; !ok || !bool(project.Networks[ingressNet].External) {
+				t.Errorf("%s (domain %v): expected the relay on %s, got %+v", egress, domain, ingressNet, relay.Networks)
+			}
+			if _, ok := relay.Networks[privateNetKey]; !ok {
+				t.Errorf("%s (domain %v): expected the relay on the private network", egress, domain)
+			}
+			if len(relay.Ports) != 1 || relay.Ports[0].Published != "31337" || relay.Ports[0].Target != 1337 {
+				t.Errorf("%s (domain %v): unexpected relay ports %+v", egress, domain, relay.Ports)
+			}
+			if !strings.Contains(relay.Command[0], "TCP-LISTEN:1337,fork,reuseaddr TCP:chall:1337") {
+				t.Errorf("%s (domain %v): unexpected relay command %q", egress, domain, relay.Command)
+			}
+			if domain != (relay.CustomLabels["traefik.enable"] == "true") {
+				t.Errorf("%s (domain %v): unexpected relay labels %v", egress, domain, relay.CustomLabels)
+			}
+			if domain != slices.Contains(relay.Networks[ingressNet].Aliases, info.Name) {
+				t.Errorf("%s (domain %v): unexpected relay aliases %+v", egress, domain, relay.Networks[ingressNet])
+			}
+		}
+	}
+}
+
+func TestSetupComposeProjectPortsOnlyOnChall(t *testing.T) {
+	info := testComposeInfo(sqlc.EgressPolicyNone, false)
+	info.ComposeBody = strings.Replace(testCompose, "    image: db\n", "    image: db\n    ports:\n      - \"5432:5432\"\n", 1)
+
+	_, err := setupComposeProject(context.Background(), info, "chall_1_2-private", "")
+	if err == nil {
+		t.Fatalf("Expected ports on another service to be rejected")
+	}
+
+	info.Egress = sqlc.EgressPolicyFull
+	_, err = setupComposeProject(context.Background(), info, "chall_1_2-private", "")
+	if err != nil {
+		t.Fatalf("Expected ports on another service with the Full egress policy: %v", err)
+	}
+}
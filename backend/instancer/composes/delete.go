@@ -2,6 +2,7 @@ package composes
 
 import (
 	"context"
+	"trxd/instancer/networks"
 
 	"github.com/docker/compose/v5/pkg/api"
 )
@@ -16,5 +17,11 @@ func KillCompose(ctx context.Context, name string) error {
 		return err
 	}
 
+	// The private network is external to the project, so Down leaves it
+	err = networks.RemovePrivateNetwork(ctx, name)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
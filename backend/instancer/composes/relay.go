@@ -0,0 +1,115 @@
+package composes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"trxd/instancer/infos"
+	"trxd/utils/consts"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+// Name of the service relaying the connections to the chall service when the
+// egress policy keeps it on internal networks only
+const relayService = "trxd-ingress"
+
+const (
+	relayMaxMemory = 64 * 1024 * 1024
+	relayMaxCPUs   = 0.25
+)
+
+// relayPorts returns the ports of the chall service the relay must forward:
+// the published ones and, with a domain, the one of the ingress proxy
+func relayPorts(info *infos.ComposeInfo, chall types.ServiceConfig) []types.ServicePortConfig {
+	ports := slices.Clone(chall.Ports)
+
+	if info.NetID != "" {
+		port := uint32(consts.DefaultInstancePort)
+		if info.InternalPort != nil {
+			port = uint32(*info.InternalPort)
+		}
+		if !slices.ContainsFunc(ports, func(p types.ServicePortConfig) bool {
+			return p.Target == port && p.Protocol != "udp"
+		}) {
+			ports = append(ports, types.ServicePortConfig{Target: port, Protocol: "tcp"})
+		}
+	}
+
+	return ports
+}
+
+// relayCommand runs a socat per port, forwarding to the same port of the
+// chall service
+func relayCommand(ports []types.ServicePortConfig) string {
+	forwards := make([]string, 0, len(ports))
+	for _, port := range ports {
+		proto := "TCP"
+		if port.Protocol == "udp" {
+			proto = "UDP"
+		}
+		forwards = append(forwards, fmt.Sprintf("socat %s-LISTEN:%d,fork,reuseaddr %s:chall:%d &",
+			proto, port.Target, proto, port.Target))
+	}
+	forwards = append(forwards, "wait")
+
+	return strings.Join(forwards, " ")
+}
+
+// setupRelay replaces the ingress of the chall service, kept on the internal
+// private network, with a relay joining both the private network and the
+// ingress one: the relay publishes the ports and carries the labels of the
+// ingress proxy, so the chall service never gets a route outside
+func setupRelay(info *infos.ComposeInfo, project *types.Project, ingressNet string) {
+	chall, ok := project.Services["chall"]
+	if !ok || chall.NetworkMode != "" {
+		return
+	}
+
+	ports := relayPorts(info, chall)
+	if len(ports) == 0 {
+		return
+	}
+
+	labels := make(types.Labels, len(chall.CustomLabels))
+	for k, v := range chall.CustomLabels {
+		labels[k] = v
+	}
+	labels[api.ServiceLabel] = relayService
+	// The chall service keeps the instance labels only
+	instanceLabels := info.InstanceLabels()
+	for k := range info.Labels {
+		if _, ok := instanceLabels[k]; !ok {
+			delete(chall.CustomLabels, k)
+		}
+	}
+
+	published := slices.DeleteFunc(slices.Clone(ports), func(p types.ServicePortConfig) bool {
+		return p.Published == ""
+	})
+
+	var aliases []string
+	if info.NetID != "" {
+		aliases = []string{info.Name}
+	}
+
+	project.Services[relayService] = types.ServiceConfig{
+		Name:         relayService,
+		Image:        info.RelayImage,
+		Entrypoint:   types.ShellCommand{"/bin/sh", "-c"},
+		Command:      types.ShellCommand{relayCommand(ports)},
+		Restart:      types.RestartPolicyAlways,
+		MemLimit:     relayMaxMemory,
+		CPUS:         relayMaxCPUs,
+		Ports:        published,
+		CustomLabels: labels,
+		Networks: map[string]*types.ServiceNetworkConfig{
+			privateNetKey: nil,
+			ingressNet:    {Aliases: aliases},
+		},
+	}
+
+	chall.Ports = nil
+	project.Services["chall"] = chall
+}
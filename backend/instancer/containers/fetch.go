@@ -3,11 +3,28 @@ package containers
 import (
 	"context"
 	"fmt"
+	"trxd/utils/consts"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
 )
 
+// Container IDs are 64 lowercase hexadecimal characters
+const containerIDLen = 64
+
+func IsContainerID(id string) bool {
+	if len(id) != containerIDLen {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'f') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 func FetchContainerByName(ctx context.Context, name string) (string, error) {
 	args := filters.NewArgs()
 	args.Add("name", name)
@@ -23,3 +40,51 @@ func FetchContainerByName(ctx context.Context, name string) (string, error) {
 
 	return summary[0].ID, nil
 }
+
+func FetchInstanceContainers(ctx context.Context) ([]container.Summary, error) {
+	if Cli == nil {
+		return nil, nil
+	}
+
+	return FetchInstanceContainersOn(ctx, Cli)
+}
+
+// FetchInstanceContainersOn returns the instance containers of the daemon of cli
+func FetchInstanceContainersOn(ctx context.Context, cli *client.Client) ([]container.Summary, error) {
+	args := filters.NewArgs()
+	args.Add("label", consts.LabelInstance)
+	summary, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// FetchContainersByLabel returns the containers with the label, matching also
+// its value if not empty
+func FetchContainersByLabel(ctx context.Context, key string, value string) ([]container.Summary, error) {
+	if Cli == nil {
+		return nil, nil
+	}
+
+	label := key
+	if value != "" {
+		label += "=" + value
+	}
+
+	args := filters.NewArgs()
+	args.Add("label", label)
+	summary, err := Cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -1,9 +1,21 @@
 package containers
 
-import "github.com/docker/docker/client"
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/docker/docker/client"
+)
 
 var Cli *client.Client
 
+// Clients of the daemons of the other Swarm nodes, by host
+var (
+	nodeClis   = make(map[string]*client.Client)
+	nodeClisMu sync.Mutex
+)
+
 func InitCli() error {
 	var err error
 
@@ -15,10 +27,39 @@ func InitCli() error {
 	return nil
 }
 
+// NodeCli returns a client of the daemon of the Swarm node at addr, listening
+// on port with the TLS settings of the local client
+func NodeCli(addr string, port int) (*client.Client, error) {
+	host := fmt.Sprintf("tcp://%s:%d", addr, port)
+
+	nodeClisMu.Lock()
+	defer nodeClisMu.Unlock()
+
+	if cli, ok := nodeClis[host]; ok {
+		return cli, nil
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithHost(host), client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+	nodeClis[host] = cli
+
+	return cli, nil
+}
+
 func CloseCli() error {
-	if Cli == nil {
-		return nil
+	nodeClisMu.Lock()
+	var errs []error
+	for host, cli := range nodeClis {
+		errs = append(errs, cli.Close())
+		delete(nodeClis, host)
+	}
+	nodeClisMu.Unlock()
+
+	if Cli != nil {
+		errs = append(errs, Cli.Close())
 	}
 
-	return Cli.Close()
+	return errors.Join(errs...)
 }
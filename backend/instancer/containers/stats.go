@@ -0,0 +1,57 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// Stats are the raw counters of a container; CPU and network are cumulative,
+// so rates are computed between two samples
+type Stats struct {
+	Read     time.Time
+	CPUTotal uint64 // nanoseconds
+	Memory   uint64 // bytes, page cache excluded
+	Pids     uint64
+	NetBytes uint64 // received and sent
+}
+
+// FetchContainerStats samples the container on the daemon of cli
+func FetchContainerStats(ctx context.Context, cli *client.Client, id string) (*Stats, error) {
+	resp, err := cli.ContainerStatsOneShot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw container.StatsResponse
+	err = json.NewDecoder(resp.Body).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		Read:     raw.Read,
+		CPUTotal: raw.CPUStats.CPUUsage.TotalUsage,
+		Memory:   raw.MemoryStats.Usage,
+		Pids:     raw.PidsStats.Current,
+	}
+
+	// Same as docker stats: the inactive page cache can be reclaimed
+	cache, ok := raw.MemoryStats.Stats["inactive_file"] // cgroup v2
+	if !ok {
+		cache = raw.MemoryStats.Stats["total_inactive_file"] // cgroup v1
+	}
+	if cache < stats.Memory {
+		stats.Memory -= cache
+	}
+
+	for _, net := range raw.Networks {
+		stats.NetBytes += net.RxBytes + net.TxBytes
+	}
+
+	return stats, nil
+}
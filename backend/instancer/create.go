@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/instancer/composes"
 	"trxd/instancer/containers"
 	"trxd/instancer/infos"
+	"trxd/instancer/services"
 
 	"trxd/utils/consts"
 	"trxd/utils/log"
@@ -28,10 +30,22 @@ type CreateInstanceResult struct {
 	Host       string
 	Port       *int32
 	Expiration time.Time
+	TeamOnly   bool
 }
 
-func recoverBrokenInstance(ctx context.Context, tid int32, challID int32, dockerID string) {
-	err := DeleteInstance(ctx, tid, challID, sql.NullString{String: dockerID, Valid: dockerID != ""})
+// Prefix of the names of the containers and compose projects of the instances
+// and deployments
+const dockerNamePrefix = "chall_"
+
+// InstanceName is the name of the container (or compose project) of an
+// instance, also resolvable as a host on the internal network
+func InstanceName(challID int32, tid int32) string {
+	return fmt.Sprintf(dockerNamePrefix+"%d_%d", challID, tid)
+}
+
+func recoverBrokenInstance(ctx context.Context, tid int32, challID int32, dockerID string, cause error) {
+	err := deleteInstance(ctx, tid, challID, sql.NullString{String: dockerID, Valid: dockerID != ""},
+		sqlc.InstanceEventTypeFailure, cause)
 	if err == nil {
 		return
 	}
@@ -45,8 +59,53 @@ func recoverBrokenInstance(ctx context.Context, tid int32, challID int32, docker
 	log.Error("Failed to expire instance after creation failure", "team", tid, "challenge", challID, "err", err)
 }
 
+// newTemplateData returns the values available to the environment templates
+// of the instance
+func newTemplateData(ctx context.Context, info *infos.InstanceInfo, expiresAt time.Time) (*infos.TemplateData, error) {
+	var teamName string
+	if !info.Shared {
+		team, err := db.GetTeamByID(ctx, info.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if team != nil {
+			teamName = team.Name
+		}
+	}
+
+	flagPrefix, err := db.GetConfig(ctx, "instance-flag-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	return infos.NewTemplateData(info, teamName, expiresAt, flagPrefix), nil
+}
+
+// AccessControlEnabled reports whether every instance is restricted to its
+// team, regardless of the challenge
+func AccessControlEnabled(ctx context.Context) (bool, error) {
+	conf, err := db.GetConfig(ctx, "instance-access-control")
+	if err != nil {
+		return false, err
+	}
+
+	return conf == "true", nil
+}
+
+// instanceAccess returns whether the instance is restricted to its team and
+// whether it is reached on a hash domain. Restricted instances are never
+// published on a port nor routed by Traefik, so that the built-in proxy is the
+// only way to reach them
+func instanceAccess(conf *sqlc.GetDockerConfigsByIDRow, accessControl bool) (teamOnly bool, hashDomain bool) {
+	teamOnly = conf.TeamOnly || accessControl
+	return teamOnly, conf.HashDomain || teamOnly
+}
+
 func makeLabels(info *infos.InstanceInfo, p *CreateInstanceParams) {
-	if !p.DockerConfig.HashDomain {
+	info.Labels = info.InstanceLabels()
+
+	// Team only instances must be reached through the built-in proxy
+	if !info.UseDomain || info.TeamOnly {
 		return
 	}
 
@@ -67,7 +126,7 @@ func makeLabels(info *infos.InstanceInfo, p *CreateInstanceParams) {
 		entrypoint = "web"
 	}
 
-	traefikPort := "1337"
+	traefikPort := fmt.Sprint(consts.DefaultInstancePort)
 	if p.InternalPort != nil {
 		traefikPort = fmt.Sprint(*p.InternalPort)
 	}
@@ -78,14 +137,15 @@ func makeLabels(info *infos.InstanceInfo, p *CreateInstanceParams) {
 	traefikLoadbalancerPort := fmt.Sprintf(loadbalancerPort, protocol, info.Name)
 	traefikRoutersTls := fmt.Sprintf(routersTls, protocol, info.Name)
 
-	info.Labels = map[string]string{
-		"traefik.enable":          "true",
-		"traefik.docker.network":  consts.NetworkInternal,
-		traefikRoutersRule:        fmt.Sprintf(rule, info.Domain),
-		traefikRoutersEntrypoints: entrypoint,
-		traefikRoutersPriority:    "10",
-		traefikLoadbalancerPort:   traefikPort,
+	info.Labels["traefik.enable"] = "true"
+	info.Labels["traefik.docker.network"] = consts.NetworkInternal
+	if info.Node != "" {
+		info.Labels["traefik.swarm.network"] = consts.NetworkInternal
 	}
+	info.Labels[traefikRoutersRule] = fmt.Sprintf(rule, info.Domain)
+	info.Labels[traefikRoutersEntrypoints] = entrypoint
+	info.Labels[traefikRoutersPriority] = "10"
+	info.Labels[traefikLoadbalancerPort] = traefikPort
 
 	if protocol == "tcp" {
 		info.Labels[traefikRoutersTls] = "true"
@@ -100,13 +160,29 @@ func spawnInstance(ctx context.Context, info *infos.InstanceInfo,
 
 	if info.UseDomain {
 		info.NetID = "trxd-shared-internal"
-	} else if deployType == sqlc.DeployTypeContainer {
+	} else if deployType == sqlc.DeployTypeContainer && info.Node == "" {
 		info.NetID = "trxd-shared-external"
 	}
 
 	if deployType == sqlc.DeployTypeContainer && conf.Image != "" {
-		dockerID, err = containers.CreateContainer(ctx, info, conf.Image)
+		if info.Node != "" {
+			dockerID, err = services.CreateService(ctx, info, conf.Image)
+		} else {
+			dockerID, err = containers.CreateContainer(ctx, info, conf.Image)
+		}
 	} else if deployType == sqlc.DeployTypeCompose && conf.Compose != "" {
+		if info.Egress == sqlc.EgressPolicyDNS {
+			info.ResolverImage, err = db.GetConfig(ctx, "dns-resolver-image")
+			if err != nil {
+				return "", err
+			}
+		}
+		if info.Egress != sqlc.EgressPolicyFull {
+			info.RelayImage, err = db.GetConfig(ctx, "ingress-relay-image")
+			if err != nil {
+				return "", err
+			}
+		}
 		dockerID, err = composes.CreateCompose(ctx, info, conf.Compose)
 	} else {
 		return "", errors.New("[no image or compose]")
@@ -118,44 +194,118 @@ func spawnInstance(ctx context.Context, info *infos.InstanceInfo,
 	return dockerID, nil
 }
 
-func CreateInstance(ctx context.Context, p *CreateInstanceParams) (*CreateInstanceResult, error) {
-	var dockerID string
-	cleanup := true
-
-	defer func() {
-		r := recover()
-		if r == nil && !cleanup {
-			return
-		}
-		if r != nil {
-			log.Critical("Recovered instancer create panic", "crit", r)
+// reserveInstance inserts the row of the instance, scheduling it on a Swarm
+// node when in Swarm mode: the scheduling lock is held until the row is
+// inserted, so that concurrent creations see each other's reservations.
+// Compose instances are always deployed on the local node. Returns nil if
+// the team already has an instance.
+func reserveInstance(ctx context.Context, p *CreateInstanceParams, createdAt time.Time, expiresAt time.Time,
+	hashDomain bool, teamOnly bool) (*sqlc.CreateInstanceRow, string, error) {
+
+	swarm := false
+	if p.DeployType == sqlc.DeployTypeContainer {
+		var err error
+		swarm, err = SwarmEnabled(ctx)
+		if err != nil {
+			return nil, "", err
 		}
+	}
+	if !swarm {
+		info, err := dbCreateInstance(ctx, db.Sql, p.Tid, p.ChallID, createdAt, expiresAt, hashDomain, teamOnly, "", "")
+		return info, "", err
+	}
 
-		recoverBrokenInstance(ctx, p.Tid, p.ChallID, dockerID)
-	}()
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer db.Rollback(tx)
+
+	q := db.Sql.WithTx(tx)
+	err = q.LockTask(ctx, lockSchedule)
+	if err != nil {
+		return nil, "", err
+	}
+
+	node, err := pickNode(ctx, q, p.DockerConfig)
+	if err != nil {
+		return nil, "", err
+	}
+
+	info, err := dbCreateInstance(ctx, q, p.Tid, p.ChallID, createdAt, expiresAt, hashDomain, teamOnly, node.ID, node.Host)
+	if err != nil || info == nil {
+		return nil, "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return info, node.ID, nil
+}
+
+func CreateInstance(ctx context.Context, p *CreateInstanceParams) (_ *CreateInstanceResult, err error) {
+	start := time.Now()
+	defer func() { observeOperation("create", start, err) }()
 
 	log.Info("Creating instance:", "chall", p.ChallID, "team", p.Tid)
 
+	// Both timestamps come from the same clock the extension policy uses
 	lifetime := time.Second * time.Duration(p.DockerConfig.Lifetime.(int64))
-	expires_at := time.Now().Add(lifetime)
+	created_at := time.Now()
+	expires_at := created_at.Add(lifetime)
+
+	accessControl, err := AccessControlEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teamOnly, hashDomain := instanceAccess(p.DockerConfig, accessControl)
 
-	creationInfo, err := dbCreateInstance(ctx, p.Tid, p.ChallID, expires_at, p.DockerConfig.HashDomain)
+	creationInfo, node, err := reserveInstance(ctx, p, created_at, expires_at, hashDomain, teamOnly)
 	if err != nil {
+		recordEvent(ctx, p.Tid, p.ChallID, sqlc.InstanceEventTypeFailure, err)
 		return nil, err
 	}
 	if creationInfo == nil {
-		cleanup = false
 		return nil, errors.New("[race condition]")
 	}
 
+	var dockerID string
+	cleanup := true
+
+	defer func() {
+		r := recover()
+		if r == nil && !cleanup {
+			return
+		}
+		cause := err
+		if r != nil {
+			log.Critical("Recovered instancer create panic", "crit", r)
+			cause = fmt.Errorf("[panic] %v", r)
+		}
+
+		recoverBrokenInstance(ctx, p.Tid, p.ChallID, dockerID, cause)
+	}()
+
 	instanceInfo := &infos.InstanceInfo{
-		Name:         fmt.Sprintf("chall_%d_%d", p.ChallID, p.Tid),
+		Name:         InstanceName(p.ChallID, p.Tid),
+		TeamID:       p.Tid,
+		ChallID:      p.ChallID,
 		Domain:       creationInfo.Host,
-		UseDomain:    p.DockerConfig.HashDomain,
+		UseDomain:    hashDomain,
+		TeamOnly:     teamOnly,
 		InternalPort: p.InternalPort,
 		Envs:         p.DockerConfig.Envs,
+		InstanceFlag: p.DockerConfig.InstanceFlag,
+		Egress:       p.DockerConfig.Egress,
 		MaxMemory:    int32(p.DockerConfig.MaxMemory.(int64)),
 		MaxCpu:       p.DockerConfig.MaxCpu.(string),
+
+		Node:           node,
+		Placement:      p.DockerConfig.Placement,
+		ReservedMemory: p.DockerConfig.ReservedMemory,
+		ReservedCpu:    p.DockerConfig.ReservedCpu,
 	}
 
 	if creationInfo.Port.Valid {
@@ -164,6 +314,11 @@ func CreateInstance(ctx context.Context, p *CreateInstanceParams) (*CreateInstan
 
 	makeLabels(instanceInfo, p)
 
+	instanceInfo.Template, err = newTemplateData(ctx, instanceInfo, expires_at)
+	if err != nil {
+		return nil, err
+	}
+
 	dockerID, err = spawnInstance(ctx, instanceInfo, p.DockerConfig, p.DeployType)
 	if err != nil {
 		return nil, err
@@ -174,11 +329,19 @@ func CreateInstance(ctx context.Context, p *CreateInstanceParams) (*CreateInstan
 		return nil, err
 	}
 
+	err = dbCreateInstanceSecrets(ctx, p.Tid, p.ChallID, instanceInfo.Template.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
 	cleanup = false
+	recordEvent(ctx, p.Tid, p.ChallID, sqlc.InstanceEventTypeCreate, nil)
+	wakeReclaimer(expires_at)
 
 	return &CreateInstanceResult{
 		Host:       instanceInfo.Domain,
 		Port:       instanceInfo.ExternalPort,
 		Expiration: expires_at,
+		TeamOnly:   teamOnly,
 	}, nil
 }
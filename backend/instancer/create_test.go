@@ -0,0 +1,40 @@
+package instancer
+
+import (
+	"testing"
+	"trxd/db/sqlc"
+	"trxd/instancer/infos"
+)
+
+func TestInstanceAccess(t *testing.T) {
+	tests := []struct {
+		name          string
+		teamOnly      bool
+		hashDomain    bool
+		accessControl bool
+		restricted    bool
+		useDomain     bool
+	}{
+		{name: "public on a port", restricted: false, useDomain: false},
+		{name: "public on a domain", hashDomain: true, restricted: false, useDomain: true},
+		{name: "team only", teamOnly: true, restricted: true, useDomain: true},
+		{name: "access control on a port", accessControl: true, restricted: true, useDomain: true},
+		{name: "access control on a domain", hashDomain: true, accessControl: true, restricted: true, useDomain: true},
+	}
+
+	for _, test := range tests {
+		conf := &sqlc.GetDockerConfigsByIDRow{TeamOnly: test.teamOnly, HashDomain: test.hashDomain}
+		teamOnly, hashDomain := instanceAccess(conf, test.accessControl)
+		if teamOnly != test.restricted || hashDomain != test.useDomain {
+			t.Errorf("Unexpected access of the %s instance: team only %v, hash domain %v", test.name, teamOnly, hashDomain)
+		}
+
+		// Restricted instances must not be reachable through Traefik
+		info := &infos.InstanceInfo{Name: "chall_1_2", ChallID: 1, TeamID: 2,
+			Domain: "abcdef.example.com", UseDomain: hashDomain, TeamOnly: teamOnly}
+		makeLabels(info, &CreateInstanceParams{ConnType: sqlc.ConnTypeHTTP})
+		if routed := info.Labels["traefik.enable"] == "true"; routed != (hashDomain && !teamOnly) {
+			t.Errorf("Unexpected Traefik routing of the %s instance: %v", test.name, routed)
+		}
+	}
+}
@@ -3,28 +3,89 @@ package instancer
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"trxd/db/sqlc"
 	"trxd/instancer/composes"
 	"trxd/instancer/containers"
+	"trxd/instancer/services"
 
 	"trxd/utils/log"
 )
 
+type dockerKind int
+
+const (
+	kindContainer dockerKind = iota
+	kindService
+	kindCompose
+)
+
+// parseDockerID tells the kind of the Docker object referenced by an ID
+// stored for an instance or deployment: compose projects are named with
+// dockerNamePrefix, which can be neither a service nor a container ID
+func parseDockerID(id string) (dockerKind, error) {
+	switch {
+	case strings.HasPrefix(id, dockerNamePrefix):
+		return kindCompose, nil
+	case services.IsServiceID(id):
+		return kindService, nil
+	case containers.IsContainerID(id):
+		return kindContainer, nil
+	}
+
+	return 0, fmt.Errorf("[unknown docker id] %s", id)
+}
+
+func killDockerObject(ctx context.Context, id string) error {
+	kind, err := parseDockerID(id)
+	if err != nil {
+		return err
+	}
+
+	return killDockerObjectKind(ctx, kind, id)
+}
+
+func killDockerObjectKind(ctx context.Context, kind dockerKind, id string) error {
+	switch kind {
+	case kindService:
+		return services.KillService(ctx, id)
+	case kindCompose:
+		return composes.KillCompose(ctx, id)
+	default:
+		return containers.KillContainer(ctx, id)
+	}
+}
+
+func killInstance(ctx context.Context, dockerID sql.NullString) error {
+	if !dockerID.Valid {
+		return nil
+	}
+	return killDockerObject(ctx, dockerID.String)
+}
+
 func DeleteInstance(ctx context.Context, tid int32, challID int32, dockerID sql.NullString) error {
 	log.Info("Deleting instance:", "chall", challID, "team", tid)
+	return deleteInstance(ctx, tid, challID, dockerID, sqlc.InstanceEventTypeDelete, nil)
+}
+
+// deleteInstance kills the instance and removes it, recording the event just
+// before the row is gone
+func deleteInstance(ctx context.Context, tid int32, challID int32, dockerID sql.NullString,
+	eventType sqlc.InstanceEventType, cause error) (err error) {
+	start := time.Now()
+	defer func() { observeOperation("delete", start, err) }()
 
-	if dockerID.Valid {
-		var err error
-		if len(dockerID.String) == 64 {
-			err = containers.KillContainer(ctx, dockerID.String)
-		} else {
-			err = composes.KillCompose(ctx, dockerID.String)
-		}
-		if err != nil {
-			return err
-		}
+	err = killInstance(ctx, dockerID)
+	if err != nil {
+		recordEvent(ctx, tid, challID, sqlc.InstanceEventTypeFailure, err)
+		return err
 	}
 
-	err := dbDeleteInstance(ctx, tid, challID)
+	recordEvent(ctx, tid, challID, eventType, cause)
+
+	err = dbDeleteInstance(ctx, tid, challID)
 	if err != nil {
 		return err
 	}
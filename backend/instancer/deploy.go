@@ -0,0 +1,335 @@
+package instancer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/instancer/containers"
+	"trxd/instancer/infos"
+	"trxd/utils/consts"
+
+	"trxd/utils/log"
+
+	"github.com/docker/compose/v5/pkg/api"
+)
+
+var deployWake = make(chan struct{}, 1)
+
+const (
+	deployBackoffBase = 30 * time.Second
+	deployBackoffMax  = time.Hour
+)
+
+// WakeDeployer makes the deployer sync the shared deployments right away,
+// e.g. after a challenge configuration change
+func WakeDeployer() {
+	select {
+	case deployWake <- struct{}{}:
+	default:
+	}
+}
+
+// DeploymentName is the name of the container (or compose project) of the
+// shared deployment of a challenge
+func DeploymentName(challID int32) string {
+	return fmt.Sprintf(dockerNamePrefix+"%d_shared", challID)
+}
+
+// deploymentHash identifies the configuration a deployment is created with,
+// any change to it recreates the deployment
+func deploymentHash(chall *db.Chall) (string, error) {
+	data, err := json.Marshal([]any{
+		chall.Info.Type,
+		chall.Info.Host,
+		chall.Info.Port,
+		chall.Info.ConnType,
+		chall.DockerConfig.Image,
+		chall.DockerConfig.Compose,
+		chall.DockerConfig.HashDomain,
+		chall.DockerConfig.Envs,
+		chall.DockerConfig.MaxMemory,
+		chall.DockerConfig.MaxCpu,
+		chall.DockerConfig.Egress,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// deployBackoff is how long to wait before retrying a deployment after the
+// given number of consecutive failures
+func deployBackoff(failures int32) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	backoff := deployBackoffBase
+	for i := int32(1); i < failures && backoff < deployBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, deployBackoffMax)
+}
+
+// shouldDeploy reports whether a failed deployment can be retried, right away
+// if its configuration changed since the last attempt
+func shouldDeploy(row sqlc.GetSharedDeploymentsRow, hash string, now time.Time) bool {
+	if row.Failures == 0 || row.ConfigHash != hash || !row.UpdatedAt.Valid {
+		return true
+	}
+	return !now.Before(row.UpdatedAt.Time.Add(deployBackoff(row.Failures)))
+}
+
+// deploymentHost is the host the deployment is reachable at, the global domain
+// unless the challenge sets its own
+func deploymentHost(ctx context.Context, chall *db.Chall) (string, error) {
+	if chall.Info.Host != "" {
+		return chall.Info.Host, nil
+	}
+	return db.GetConfig(ctx, "domain")
+}
+
+func spawnDeployment(ctx context.Context, chall *db.Chall, host string) (string, error) {
+
+	info := &infos.InstanceInfo{
+		Name:      DeploymentName(chall.Info.ID),
+		ChallID:   chall.Info.ID,
+		Domain:    host,
+		UseDomain: chall.DockerConfig.HashDomain,
+		Shared:    true,
+		Envs:      chall.DockerConfig.Envs,
+		Egress:    chall.DockerConfig.Egress,
+		MaxMemory: int32(chall.DockerConfig.MaxMemory.(int64)),
+		MaxCpu:    chall.DockerConfig.MaxCpu.(string),
+	}
+
+	// The challenge port is published as is, unless routed by domain
+	if chall.Info.Port != 0 {
+		info.InternalPort = &chall.Info.Port
+		if !info.UseDomain {
+			info.ExternalPort = &chall.Info.Port
+		}
+	}
+
+	makeLabels(info, &CreateInstanceParams{
+		ConnType:     chall.Info.ConnType,
+		InternalPort: info.InternalPort,
+	})
+
+	// Shared deployments never expire
+	var err error
+	info.Template, err = newTemplateData(ctx, info, time.Time{})
+	if err != nil {
+		return "", err
+	}
+
+	return spawnInstance(ctx, info, chall.DockerConfig, chall.Info.Type)
+}
+
+// deploymentRunning reports whether every container of the deployment is
+// running
+func deploymentRunning(ctx context.Context, challID int32) (bool, error) {
+	summary, err := containers.FetchContainersByLabel(ctx, consts.LabelDeployment, strconv.Itoa(int(challID)))
+	if err != nil {
+		return false, err
+	}
+	if len(summary) == 0 {
+		return false, nil
+	}
+
+	for _, c := range summary {
+		if c.State != "running" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func deploy(ctx context.Context, row sqlc.GetSharedDeploymentsRow) error {
+	chall, err := db.GetChallenge(ctx, row.ChallID)
+	if err != nil {
+		return err
+	}
+	if chall == nil || chall.DockerConfig == nil {
+		return nil
+	}
+
+	hash, err := deploymentHash(chall)
+	if err != nil {
+		return err
+	}
+	if !shouldDeploy(row, hash, time.Now().UTC()) {
+		return nil
+	}
+
+	restarts := int32(0)
+	if row.DockerID.Valid && row.ConfigHash == hash {
+		running, err := deploymentRunning(ctx, row.ChallID)
+		if err != nil {
+			return err
+		}
+		if running {
+			return nil
+		}
+		restarts = row.Restarts + 1
+		log.Warn("Restarting shared deployment:", "chall", row.ChallID, "restarts", restarts)
+	} else {
+		log.Info("Creating shared deployment:", "chall", row.ChallID)
+	}
+
+	err = killInstance(ctx, row.DockerID)
+	if err != nil {
+		return err
+	}
+
+	host, err := deploymentHost(ctx, chall)
+	if err != nil {
+		return err
+	}
+
+	params := sqlc.UpsertDeploymentParams{
+		ChallID:    row.ChallID,
+		Host:       host,
+		ConfigHash: hash,
+		Status:     sqlc.DeploymentStatusRunning,
+		Restarts:   restarts,
+	}
+
+	dockerID, err := spawnDeployment(ctx, chall, host)
+	if err != nil {
+		params.Status = sqlc.DeploymentStatusFailed
+		params.Error = err.Error()
+		// Retries of the same configuration are delayed more at each failure
+		if row.ConfigHash == hash {
+			params.Failures = row.Failures
+		}
+		params.Failures++
+		log.Error("Failed to create shared deployment:", "chall", row.ChallID, "failures", params.Failures,
+			"retry", deployBackoff(params.Failures), "err", err)
+	}
+	params.DockerID = sql.NullString{String: dockerID, Valid: dockerID != ""}
+
+	return db.Sql.UpsertDeployment(ctx, params)
+}
+
+// killOrphanDeployments removes the deployments of deleted challenges, whose
+// row was deleted in cascade
+func killOrphanDeployments(ctx context.Context, known map[int32]struct{}) error {
+	summary, err := containers.FetchContainersByLabel(ctx, consts.LabelDeployment, "")
+	if err != nil {
+		return err
+	}
+
+	killed := make(map[string]struct{})
+	for _, c := range summary {
+		challID, err := strconv.Atoi(c.Labels[consts.LabelDeployment])
+		if err != nil {
+			continue
+		}
+		if _, ok := known[int32(challID)]; ok {
+			continue
+		}
+
+		id := c.ID
+		if project, ok := c.Labels[api.ProjectLabel]; ok {
+			id = project
+		}
+		if _, ok := killed[id]; ok {
+			continue
+		}
+		killed[id] = struct{}{}
+
+		err = killDockerObject(ctx, id)
+		if err != nil {
+			log.Error("Failed to kill orphaned deployment:", "docker", id, "chall", challID, "err", err)
+			continue
+		}
+		log.Warn("Killed orphaned deployment:", "docker", id, "chall", challID)
+	}
+
+	return nil
+}
+
+// SyncDeployments keeps exactly one running deployment for each shared
+// challenge, recreating it when its configuration changes or it crashes. A
+// single replica syncs at a time.
+func SyncDeployments(ctx context.Context) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	locked, err := runLocked(ctx, lockDeploy, func() error {
+		return syncDeployments(ctx)
+	})
+	if err == nil && !locked {
+		log.Debug("Skipping deployments sync, running on another replica")
+	}
+
+	return err
+}
+
+func syncDeployments(ctx context.Context) error {
+	stale, err := db.Sql.GetStaleDeployments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range stale {
+		log.Info("Deleting shared deployment:", "chall", row.ChallID)
+		err := killInstance(ctx, row.DockerID)
+		if err != nil {
+			log.Error("Failed to kill shared deployment:", "chall", row.ChallID, "err", err)
+			continue
+		}
+		err = db.Sql.DeleteDeployment(ctx, row.ChallID)
+		if err != nil {
+			log.Error("Failed to delete shared deployment:", "chall", row.ChallID, "err", err)
+		}
+	}
+
+	rows, err := db.Sql.GetSharedDeployments(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int32]struct{}, len(rows))
+	for _, row := range rows {
+		known[row.ChallID] = struct{}{}
+		err := deploy(ctx, row)
+		if err != nil {
+			log.Error("Failed to sync shared deployment:", "chall", row.ChallID, "err", err)
+		}
+	}
+
+	return killOrphanDeployments(ctx, known)
+}
+
+func deployLoop(ctx context.Context) {
+	for {
+		runSafe("deploy loop", func() {
+			err := SyncDeployments(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Error("Failed to sync shared deployments:", "err", err)
+			}
+		})
+
+		sleep, err := getIntervalConfig(ctx, "deployment-check-interval")
+		if err != nil {
+			log.Error("Failed to get deployment check interval:", "err", err)
+			sleep = time.Minute
+		}
+		if !sleepCtx(ctx, sleep, deployWake) {
+			return
+		}
+	}
+}
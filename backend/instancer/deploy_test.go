@@ -0,0 +1,58 @@
+package instancer
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+)
+
+func TestDeployBackoff(t *testing.T) {
+	tests := []struct {
+		failures int32
+		backoff  time.Duration
+	}{
+		{failures: 0, backoff: 0},
+		{failures: 1, backoff: deployBackoffBase},
+		{failures: 2, backoff: 2 * deployBackoffBase},
+		{failures: 4, backoff: 8 * deployBackoffBase},
+		{failures: 100, backoff: deployBackoffMax},
+	}
+
+	for _, test := range tests {
+		if backoff := deployBackoff(test.failures); backoff != test.backoff {
+			t.Errorf("Unexpected backoff after %d failures: %s", test.failures, backoff)
+		}
+	}
+}
+
+func TestShouldDeploy(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	row := func(failures int32, hash string, updated time.Time) sqlc.GetSharedDeploymentsRow {
+		return sqlc.GetSharedDeploymentsRow{
+			ChallID:    1,
+			ConfigHash: hash,
+			Failures:   failures,
+			UpdatedAt:  sql.NullTime{Time: updated, Valid: !updated.IsZero()},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		row    sqlc.GetSharedDeploymentsRow
+		deploy bool
+	}{
+		{name: "new", row: row(0, "", time.Time{}), deploy: true},
+		{name: "healthy", row: row(0, "hash", now), deploy: true},
+		{name: "just failed", row: row(1, "hash", now.Add(-time.Second)), deploy: false},
+		{name: "backed off", row: row(1, "hash", now.Add(-deployBackoffBase)), deploy: true},
+		{name: "failing", row: row(3, "hash", now.Add(-2*deployBackoffBase)), deploy: false},
+		{name: "config changed", row: row(3, "old", now.Add(-time.Second)), deploy: true},
+	}
+
+	for _, test := range tests {
+		if deploy := shouldDeploy(test.row, "hash", now); deploy != test.deploy {
+			t.Errorf("Unexpected decision for the %s deployment: %v", test.name, deploy)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package instancer
+
+import (
+	"context"
+	"trxd/db"
+	"trxd/db/sqlc"
+
+	"trxd/utils/log"
+)
+
+// recordEvent adds an event to the instance history, which must be written
+// while the instance row still exists to capture its host, port and lifetime.
+// The history is best effort, so a failure is only logged.
+func recordEvent(ctx context.Context, tid int32, challID int32, eventType sqlc.InstanceEventType, cause error) {
+	err := dbCreateInstanceEvent(ctx, db.Sql, tid, challID, eventType, cause)
+	if err != nil {
+		log.Error("Failed to record instance event:", "team", tid, "chall", challID, "event", eventType, "err", err)
+	}
+}
@@ -25,6 +25,11 @@ func SetupComposeInfo(info *InstanceInfo, composeBody string) (*ComposeInfo, err
 		}
 	}
 
+	err := info.renderEnvs(composeInfo.Env)
+	if err != nil {
+		return nil, err
+	}
+
 	composeInfo.Env["MAX_MEMORY"] = strconv.Itoa(int(info.MaxMemory))
 	composeInfo.Env["MAX_CPUS"] = info.MaxCpu
 	composeInfo.Env["CONTAINER_NAME"] = info.Name
@@ -11,6 +11,7 @@ type ContainerInfo struct {
 	ExternalPortStr string
 	Env             []string
 	MaxCPUs         int64
+	ReservedCPUs    int64
 }
 
 func SetupContainerInfo(info *InstanceInfo, image string) (*ContainerInfo, error) {
@@ -23,16 +24,21 @@ func SetupContainerInfo(info *InstanceInfo, image string) (*ContainerInfo, error
 		containerInfo.ExternalPortStr = strconv.Itoa(int(*info.ExternalPort))
 	}
 
-	containerInfo.Env = make([]string, 0)
+	jsonEnvs := make(map[string]string)
 	if info.Envs != "" {
-		var jsonEnvs map[string]string
 		err := json.Unmarshal([]byte(info.Envs), &jsonEnvs)
 		if err != nil {
 			return nil, err
 		}
-		for k, v := range jsonEnvs {
-			containerInfo.Env = append(containerInfo.Env, k+"="+v)
-		}
+	}
+	err := info.renderEnvs(jsonEnvs)
+	if err != nil {
+		return nil, err
+	}
+
+	containerInfo.Env = make([]string, 0, len(jsonEnvs))
+	for k, v := range jsonEnvs {
+		containerInfo.Env = append(containerInfo.Env, k+"="+v)
 	}
 
 	containerInfo.Env = append(containerInfo.Env, "INSTANCE_DOMAIN="+info.Domain)
@@ -46,5 +52,13 @@ func SetupContainerInfo(info *InstanceInfo, image string) (*ContainerInfo, error
 	}
 	containerInfo.MaxCPUs = int64(maxCPUs * 1e9)
 
+	if info.ReservedCpu != "" {
+		reservedCPUs, err := strconv.ParseFloat(info.ReservedCpu, 64)
+		if err != nil {
+			return nil, err
+		}
+		containerInfo.ReservedCPUs = int64(reservedCPUs * 1e9)
+	}
+
 	return &containerInfo, nil
 }
@@ -1,9 +1,19 @@
 package infos
 
+import (
+	"strconv"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+)
+
 type InstanceInfo struct {
 	Name         string
+	TeamID       int32
+	ChallID      int32
 	Domain       string
 	UseDomain    bool
+	TeamOnly     bool
+	Shared       bool
 	InternalPort *int32
 	ExternalPort *int32
 	Envs         string
@@ -11,4 +21,32 @@ type InstanceInfo struct {
 	MaxCpu       string
 	NetID        string
 	Labels       map[string]string
+	Template     *TemplateData
+	InstanceFlag bool
+	Egress       sqlc.EgressPolicy // Compose only
+
+	ResolverImage string // Compose only, with the DNS egress policy
+	RelayImage    string // Compose only, without the Full egress policy
+
+	// Swarm only
+	Node           string
+	Placement      []string
+	ReservedMemory int32
+	ReservedCpu    string
+}
+
+// Labels used to match Docker objects with their row in the instances (or
+// deployments) table
+func (info *InstanceInfo) InstanceLabels() map[string]string {
+	if info.Shared {
+		return map[string]string{
+			consts.LabelDeployment: strconv.Itoa(int(info.ChallID)),
+		}
+	}
+
+	return map[string]string{
+		consts.LabelInstance: info.Name,
+		consts.LabelTeamID:   strconv.Itoa(int(info.TeamID)),
+		consts.LabelChallID:  strconv.Itoa(int(info.ChallID)),
+	}
 }
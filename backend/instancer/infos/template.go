@@ -0,0 +1,279 @@
+package infos
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"trxd/utils/consts"
+)
+
+// Secret is a random value rendered in the environment of an instance
+type Secret struct {
+	Name  string
+	Value string
+}
+
+// TemplateData is available to the environment values of an instance, e.g.
+// {{.TeamName}} or {{.RandomHex 16}}. The random values it renders are
+// collected in Secrets.
+type TemplateData struct {
+	TeamID    int32
+	TeamName  string
+	ChallID   int32
+	Domain    string
+	Port      int32
+	ExpiresAt string
+
+	Secrets []Secret
+
+	flagPrefix string
+	flag       string
+}
+
+func NewTemplateData(info *InstanceInfo, teamName string, expiresAt time.Time, flagPrefix string) *TemplateData {
+	data := &TemplateData{
+		TeamID:     info.TeamID,
+		TeamName:   teamName,
+		ChallID:    info.ChallID,
+		Domain:     info.Domain,
+		flagPrefix: flagPrefix,
+	}
+	if !expiresAt.IsZero() {
+		data.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	if info.ExternalPort != nil {
+		data.Port = *info.ExternalPort
+	}
+
+	return data
+}
+
+func randomHex(n int) (string, error) {
+	if n <= 0 || n > consts.MaxTemplateRandomLen {
+		return "", fmt.Errorf("random length must be between 1 and %d", consts.MaxTemplateRandomLen)
+	}
+
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// RandomHex renders n random bytes in hex, different at each use
+func (d *TemplateData) RandomHex(n int) (string, error) {
+	value, err := randomHex(n)
+	if err != nil {
+		return "", err
+	}
+
+	d.Secrets = append(d.Secrets, Secret{Name: fmt.Sprintf("RandomHex%d", len(d.Secrets)), Value: value})
+	return value, nil
+}
+
+// Flag renders the random flag of the instance, the same at each use
+func (d *TemplateData) Flag() (string, error) {
+	if d.flag != "" {
+		return d.flag, nil
+	}
+
+	value, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	d.flag = d.flagPrefix + "{" + value + "}"
+	d.Secrets = append(d.Secrets, Secret{Name: consts.FlagSecret, Value: d.flag})
+	return d.flag, nil
+}
+
+// checkNode rejects the template nodes beyond the substitution of fields and
+// variables, and conditionals: templates come from the challenge authors and
+// run at every instance start, so loops, nested templates and functions (e.g.
+// printf with a huge width) could exhaust the resources of the backend
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			err := checkNode(child)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			err := checkNode(cmd)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			err := checkNode(arg)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkNode(n.Pipe)
+	case *parse.IfNode:
+		for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+			err := checkNode(child)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.TextNode, *parse.FieldNode, *parse.VariableNode, *parse.DotNode,
+		*parse.StringNode, *parse.NumberNode, *parse.BoolNode:
+	default:
+		return fmt.Errorf("template action not allowed: %s", node)
+	}
+	return nil
+}
+
+func parseTemplate(value string) (*template.Template, error) {
+	tmpl, err := template.New("env").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkNode(tmpl.Tree.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+var errTemplateTooLong = fmt.Errorf("rendered value longer than %d bytes", consts.MaxTemplateOutputLen)
+
+// limitedBuilder fails the writes past consts.MaxTemplateOutputLen
+type limitedBuilder struct {
+	strings.Builder
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > consts.MaxTemplateOutputLen {
+		return 0, errTemplateTooLong
+	}
+	return b.Builder.Write(p)
+}
+
+// Render executes the template in value, returned as is without actions
+func (d *TemplateData) Render(value string) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := parseTemplate(value)
+	if err != nil {
+		return "", err
+	}
+
+	var sb limitedBuilder
+	err = tmpl.Execute(&sb, d)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// CheckTemplate reports whether value renders, using placeholder data
+func CheckTemplate(value string) error {
+	data := &TemplateData{flagPrefix: "flag"}
+	_, err := data.Render(value)
+	return err
+}
+
+// usesField reports whether the template node references the field name of
+// the data, in any branch
+func usesField(node parse.Node, name string) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if usesField(child, name) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return usesField(n.Pipe, name)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if usesField(cmd, name) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if usesField(arg, name) {
+				return true
+			}
+		}
+	case *parse.FieldNode:
+		return len(n.Ident) > 0 && n.Ident[0] == name
+	case *parse.VariableNode:
+		return len(n.Ident) > 1 && n.Ident[0] == "$" && n.Ident[1] == name
+	case *parse.IfNode:
+		return usesField(n.Pipe, name) || usesField(n.List, name) || usesField(n.ElseList, name)
+	}
+	return false
+}
+
+// UsesFlag reports whether the template in value renders the instance flag
+func UsesFlag(value string) bool {
+	if !strings.Contains(value, "{{") {
+		return false
+	}
+
+	tmpl, err := parseTemplate(value)
+	if err != nil {
+		return false
+	}
+
+	return usesField(tmpl.Tree.Root, "Flag")
+}
+
+func (info *InstanceInfo) renderEnvs(envs map[string]string) error {
+	if info.Template == nil {
+		return nil
+	}
+
+	for k, v := range envs {
+		rendered, err := info.Template.Render(v)
+		if err != nil {
+			return fmt.Errorf("env %s: %w", k, err)
+		}
+		envs[k] = rendered
+	}
+
+	// Challenges validated with per-instance flags always get one, even
+	// when the author didn't reference it in the environment
+	if _, ok := envs[consts.FlagEnv]; info.InstanceFlag && !ok {
+		flag, err := info.Template.Flag()
+		if err != nil {
+			return err
+		}
+		envs[consts.FlagEnv] = flag
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package infos_test
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"trxd/instancer/infos"
+	"trxd/utils/consts"
+)
+
+func TestSetupContainerInfoRendersTemplates(t *testing.T) {
+	port := int32(31337)
+	info := &infos.InstanceInfo{
+		Name:         "chall_1_2",
+		TeamID:       2,
+		ChallID:      1,
+		Domain:       "abcdef.example.com",
+		ExternalPort: &port,
+		Envs:         `{"TEAM": "{{.TeamName}} ({{.TeamID}})", "FLAG": "{{.Flag}}", "FLAG2": "{{.Flag}}", "KEY": "{{.RandomHex 4}}", "EXP": "{{.ExpiresAt}}"}`,
+		MaxCpu:       "1",
+	}
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	info.Template = infos.NewTemplateData(info, "team", expiresAt, "TRX")
+
+	containerInfo, err := infos.SetupContainerInfo(info, "image")
+	if err != nil {
+		t.Fatalf("SetupContainerInfo failed: %v", err)
+	}
+
+	envs := make(map[string]string)
+	for _, env := range containerInfo.Env {
+		k, v, _ := strings.Cut(env, "=")
+		envs[k] = v
+	}
+
+	if envs["TEAM"] != "team (2)" {
+		t.Fatalf("Unexpected TEAM: %q", envs["TEAM"])
+	}
+	if envs["EXP"] != "2026-01-02T03:04:05Z" {
+		t.Fatalf("Unexpected EXP: %q", envs["EXP"])
+	}
+	if !regexp.MustCompile(`^TRX\{[0-9a-f]{32}\}$`).MatchString(envs["FLAG"]) || envs["FLAG"] != envs["FLAG2"] {
+		t.Fatalf("Unexpected FLAG: %q %q", envs["FLAG"], envs["FLAG2"])
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}$`).MatchString(envs["KEY"]) {
+		t.Fatalf("Unexpected KEY: %q", envs["KEY"])
+	}
+
+	secrets := info.Template.Secrets
+	if len(secrets) != 2 {
+		t.Fatalf("Expected 2 secrets, got %+v", secrets)
+	}
+	if !slices.Contains(secrets, infos.Secret{Name: consts.FlagSecret, Value: envs["FLAG"]}) {
+		t.Fatalf("Flag not among secrets: %+v", secrets)
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	valid := []string{"plain", "{{.TeamID}}", "{{.RandomHex 32}}", "{{.Flag}}-{{.Domain}}:{{.Port}}",
+		"{{if .Domain}}{{.Domain}}{{else}}localhost{{end}}", "{{$id := .TeamID}}{{$id}}"}
+	for _, value := range valid {
+		if err := infos.CheckTemplate(value); err != nil {
+			t.Fatalf("Expected %q to be valid: %v", value, err)
+		}
+	}
+
+	invalid := []string{"{{.TeamID", "{{.Missing}}", "{{.RandomHex 0}}", "{{.RandomHex 100000}}",
+		"{{range 1000000000}}x{{end}}", "{{with .TeamID}}{{.}}{{end}}", "{{define \"t\"}}x{{end}}{{template \"t\"}}",
+		"{{printf \"%0999999999d\" 1}}", "{{.TeamName | len}}", strings.Repeat("{{.RandomHex 256}}", 130)}
+	for _, value := range invalid {
+		if err := infos.CheckTemplate(value); err == nil {
+			t.Fatalf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestUsesFlag(t *testing.T) {
+	uses := []string{"{{.Flag}}", "prefix-{{.Flag}}", "{{if .TeamName}}{{.Flag}}{{end}}", "{{if .TeamID}}{{else}}{{$.Flag}}{{end}}"}
+	for _, value := range uses {
+		if !infos.UsesFlag(value) {
+			t.Errorf("Expected %q to use the flag", value)
+		}
+	}
+
+	unused := []string{"", "flag{static}", "{{.TeamID}}", "{{.RandomHex 16}}", "{{.TeamID"}
+	for _, value := range unused {
+		if infos.UsesFlag(value) {
+			t.Errorf("Expected %q not to use the flag", value)
+		}
+	}
+}
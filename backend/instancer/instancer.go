@@ -2,8 +2,9 @@ package instancer
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"strconv"
+	"sync"
 	"time"
 	"trxd/db"
 	"trxd/instancer/composes"
@@ -31,14 +32,18 @@ func InitInstancer() error {
 }
 
 func GetInterval(ctx context.Context) (time.Duration, error) {
-	conf, err := db.GetConfig(ctx, "reclaim-instance-interval")
+	return getIntervalConfig(ctx, "reclaim-instance-interval")
+}
+
+func getIntConfig(ctx context.Context, key string) (int, error) {
+	conf, err := db.GetConfig(ctx, key)
 	if err != nil {
 		return 0, err
 	}
 	if conf == "" {
-		if intervalInterface, ok := consts.DefaultConfigs["reclaim-instance-interval"]; ok {
-			if interval, ok := intervalInterface.Value.(int); ok {
-				return time.Duration(interval) * time.Second, nil
+		if intervalInterface, ok := consts.DefaultConfigs[key]; ok {
+			if value, ok := intervalInterface.Value.(int); ok {
+				return value, nil
 			}
 		}
 	}
@@ -48,14 +53,25 @@ func GetInterval(ctx context.Context) (time.Duration, error) {
 		return 0, err
 	}
 
+	return value, nil
+}
+
+func getIntervalConfig(ctx context.Context, key string) (time.Duration, error) {
+	value, err := getIntConfig(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
 	sleep := time.Duration(value) * time.Second
 	return sleep, nil
 }
 
-func ReclaimLoop() {
+// ReclaimLoop runs the reclaimer and the reconciler until ctx is canceled,
+// waiting for the in-flight deletions before returning
+func ReclaimLoop(ctx context.Context) error {
 	err := InitInstancer()
 	if err != nil {
-		log.Fatal("Failed to initialize instancer:", "err", err)
+		return err
 	}
 	defer func() {
 		err := containers.CloseCli()
@@ -64,66 +80,94 @@ func ReclaimLoop() {
 		}
 	}()
 
-	ctx := context.Background()
-	_, err = networks.CreateNetwork(ctx, consts.NetworkExternal, true)
+	swarm, err := SwarmEnabled(ctx)
 	if err != nil {
-		log.Fatal("Failed to initialize instancer:", "err", err)
+		return err
 	}
 
-	summary, err := networks.FetchNetwork(ctx, consts.NetworkInternal)
-	if err != nil {
-		log.Fatal("Failed to initialize instancer:", "err", err)
-	}
-	if len(summary) == 0 {
-		log.Fatal("Failed to initialize instancer: network not found", "net", consts.NetworkInternal)
+	// The Swarm services of the instances attach to the internal network from
+	// any node, otherwise it is the bridge set up with the deployment. Without
+	// a domain each service gets its own overlay instead of the external one.
+	if swarm {
+		err = networks.CreateOverlayNetwork(ctx, consts.NetworkInternal)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = networks.CreateNetwork(ctx, consts.NetworkExternal, true)
+		if err != nil {
+			return err
+		}
+
+		summary, err := networks.FetchNetwork(ctx, consts.NetworkInternal)
+		if err != nil {
+			return err
+		}
+		if len(summary) == 0 {
+			return errors.New("network not found: " + consts.NetworkInternal)
+		}
 	}
 
-	reclaimLoop()
+	var wg sync.WaitGroup
+	wg.Go(func() { reconcileLoop(ctx) })
+	wg.Go(func() { reclaimLoop(ctx) })
+	wg.Go(func() { deployLoop(ctx) })
+	wg.Go(func() { usageLoop(ctx) })
+	wg.Wait()
+
+	return nil
 }
 
-func reclaimLoop() {
+// runSafe calls fn, logging instead of propagating any panic
+func runSafe(name string, fn func()) {
 	defer func() {
 		r := recover()
 		if r == nil {
 			return
 		}
-		log.Critical("Panic recovered in reclaim loop:", "crit", r)
-		reclaimLoop()
+		log.Critical("Panic recovered in "+name+":", "crit", r)
 	}()
 
-	ctx := context.Background()
-	sleep, err := GetInterval(ctx)
+	fn()
+}
+
+// Keys of the advisory locks letting a single replica run a background task
+// (1337 is taken by the port allocation)
+const (
+	lockReconcile int64 = 1338 + iota
+	lockSchedule
+	lockUsage
+	lockDeploy
+)
+
+// runLocked calls fn holding the advisory lock of the task, skipping it if
+// another replica holds the lock. Returns whether fn was called.
+func runLocked(ctx context.Context, key int64, fn func() error) (bool, error) {
+	tx, err := db.BeginTx(ctx)
 	if err != nil {
-		log.Fatal("Failed to get reclaim interval:", "err", err)
+		return false, err
 	}
+	defer db.Rollback(tx)
 
-	for {
-		time.Sleep(sleep)
-		ctx := context.Background()
-
-		next, err := db.Sql.GetNextInstanceToDelete(ctx)
-		if err != nil {
-			if err != sql.ErrNoRows {
-				log.Error("Failed to get next instance to delete:", "err", err)
-			} else {
-				sleep, err = GetInterval(ctx)
-				if err != nil {
-					log.Fatal("Failed to get reclaim interval:", "err", err)
-				}
-			}
-			continue
-		}
+	locked, err := db.Sql.WithTx(tx).TryLockTask(ctx, key)
+	if err != nil || !locked {
+		return false, err
+	}
 
-		if time.Now().Before(next.ExpiresAt) {
-			sleep = time.Until(next.ExpiresAt)
-			continue
-		} else {
-			sleep = 0
-		}
+	return true, fn()
+}
 
-		err = DeleteInstance(ctx, next.TeamID, next.ChallID, next.DockerID)
-		if err != nil {
-			log.Error("Failed to delete instance:", "err", err)
-		}
+// sleepCtx waits for d, returning false if ctx is canceled first
+func sleepCtx(ctx context.Context, d time.Duration, wake <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-wake:
+		return true
+	case <-timer.C:
+		return true
 	}
 }
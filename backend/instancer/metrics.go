@@ -0,0 +1,54 @@
+package instancer
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"trxd/db"
+	"trxd/utils/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsQueryTimeout = 5 * time.Second
+
+// activeInstances reports the running instances of each challenge, counted
+// from the database at each scrape so that all the replicas agree
+type activeInstances struct {
+	desc *prometheus.Desc
+}
+
+func (a *activeInstances) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.desc
+}
+
+func (a *activeInstances) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsQueryTimeout)
+	defer cancel()
+
+	rows, err := db.Sql.CountInstancesByChallenge(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(a.desc, err)
+		return
+	}
+
+	for _, row := range rows {
+		ch <- prometheus.MustNewConstMetric(a.desc, prometheus.GaugeValue,
+			float64(row.Instances), strconv.Itoa(int(row.ID)), row.Name)
+	}
+}
+
+func init() {
+	prometheus.MustRegister(&activeInstances{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "instancer", "active_instances"),
+			"Number of running instances by challenge",
+			[]string{"chall_id", "chall"}, nil,
+		),
+	})
+}
+
+// observeOperation records the duration and outcome of an instance operation
+func observeOperation(operation string, start time.Time, err error) {
+	metrics.InstanceOperations.WithLabelValues(operation, metrics.Status(err)).Observe(time.Since(start).Seconds())
+}
@@ -3,11 +3,17 @@ package networks
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"strings"
 	"trxd/instancer/containers"
+	"trxd/utils/consts"
 
 	"github.com/docker/docker/api/types/network"
 )
 
+// CreateNetwork creates the bridge if missing, failing if it exists with
+// another driver
 func CreateNetwork(ctx context.Context, name string, disableICC bool) (string, error) {
 	if containers.Cli == nil {
 		return "", nil
@@ -22,6 +28,9 @@ func CreateNetwork(ctx context.Context, name string, disableICC bool) (string, e
 	if len(summary) > 1 {
 		return "", errors.New("multiple networks with the same name") // TODO: test
 	} else if len(summary) == 1 {
+		if summary[0].Driver != "bridge" {
+			return "", fmt.Errorf("network %s must be a bridge network", name)
+		}
 		netID = summary[0].ID
 	} else {
 		options := make(map[string]string)
@@ -41,3 +50,73 @@ func CreateNetwork(ctx context.Context, name string, disableICC bool) (string, e
 
 	return netID, nil
 }
+
+// CreateOverlayNetwork creates the network as an attachable overlay spanning
+// the Swarm nodes if missing, failing if it exists with another driver
+func CreateOverlayNetwork(ctx context.Context, name string) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	summary, err := FetchNetwork(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, net := range summary {
+		if net.Name != name {
+			continue
+		}
+		if net.Driver != "overlay" || !net.Attachable {
+			return fmt.Errorf("network %s must be an attachable overlay network in Swarm mode", name)
+		}
+		return nil
+	}
+
+	_, err = containers.Cli.NetworkCreate(ctx, name, network.CreateOptions{
+		Driver:     "overlay",
+		Attachable: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateServiceNetwork creates the overlay private to the Swarm service of
+// the instance name. Overlay networks can't disable the traffic between
+// their containers, so a network shared by the services of the teams would
+// let them reach each other; this one is removed along with the service.
+func CreateServiceNetwork(ctx context.Context, name string, labels map[string]string) (string, error) {
+	if containers.Cli == nil {
+		return "", nil
+	}
+
+	netLabels := maps.Clone(labels)
+	netLabels[consts.LabelPrivate] = name
+
+	net, err := containers.Cli.NetworkCreate(ctx, PrivateNetworkName(name), network.CreateOptions{
+		Driver: "overlay",
+		Labels: netLabels,
+	})
+	if err == nil {
+		return net.ID, nil
+	}
+	if !strings.Contains(err.Error(), "already exists") {
+		return "", err
+	}
+
+	// Left by a previous attempt at creating the service
+	summary, err := FetchNetwork(ctx, PrivateNetworkName(name))
+	if err != nil {
+		return "", err
+	}
+	for _, net := range summary {
+		if net.Name == PrivateNetworkName(name) {
+			return net.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("network not found (%s)", PrivateNetworkName(name))
+}
@@ -0,0 +1,153 @@
+package networks
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"time"
+	"trxd/db/sqlc"
+	"trxd/instancer/containers"
+	"trxd/utils/consts"
+
+	"trxd/utils/log"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+)
+
+// PrivateNetworkName is the name of the network private to an instance, a
+// bridge for compose instances and an overlay for Swarm services
+func PrivateNetworkName(name string) string {
+	return name + "-private"
+}
+
+func privateNetworkOptions(name string, egress sqlc.EgressPolicy, labels map[string]string) network.CreateOptions {
+	netLabels := maps.Clone(labels)
+	netLabels[consts.LabelPrivate] = name
+
+	return network.CreateOptions{
+		Driver:   "bridge",
+		Internal: egress != sqlc.EgressPolicyFull,
+		Options: map[string]string{
+			"com.docker.network.bridge.enable_icc": "true",
+		},
+		Labels: netLabels,
+	}
+}
+
+// CreatePrivateNetwork creates the bridge shared only by the services of the
+// instance name, returning the address of the resolver its services must use
+// (empty to keep the default one). Egress is enforced on the bridge itself:
+//   - None: an internal network, with no route outside of it
+//   - DNS: an internal network too, whose only way out is a resolver
+//     forwarding the queries to the ones of the host
+//   - Full: a plain bridge with internet access
+//
+// Internal networks route neither to the host nor to its LAN, nor publish
+// ports: without the Full policy, a relay joins the ingress network in place
+// of the services.
+func CreatePrivateNetwork(ctx context.Context, name string, egress sqlc.EgressPolicy,
+	resolverImage string, labels map[string]string) (string, error) {
+
+	if containers.Cli == nil {
+		return "", nil
+	}
+
+	_, err := containers.Cli.NetworkCreate(ctx, PrivateNetworkName(name), privateNetworkOptions(name, egress, labels))
+	if err != nil {
+		return "", err
+	}
+	if egress != sqlc.EgressPolicyDNS {
+		return "", nil
+	}
+
+	resolver, err := startResolver(ctx, name, resolverImage)
+	if err != nil {
+		return "", errors.Join(err, RemovePrivateNetwork(ctx, name))
+	}
+
+	return resolver, nil
+}
+
+// RemovePrivateNetwork removes the private network of the instance name and
+// its resolver, ignoring them if already gone
+func RemovePrivateNetwork(ctx context.Context, name string) error {
+	err := containers.KillContainer(ctx, ResolverName(name))
+	if err != nil {
+		return err
+	}
+
+	return RemoveNetwork(ctx, PrivateNetworkName(name))
+}
+
+// RemoveNetwork removes the network, ignoring it if already gone
+func RemoveNetwork(ctx context.Context, name string) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	err := containers.Cli.NetworkRemove(ctx, name)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orphanedNetwork reports whether nothing but the resolver of the instance
+// name is attached to its private network
+func orphanedNetwork(name string, endpoints map[string]network.EndpointResource) bool {
+	for _, endpoint := range endpoints {
+		if endpoint.Name != ResolverName(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// PrunePrivateNetworks removes the private networks created before the given
+// time whose instance is gone, e.g. after a crash while creating or killing it
+func PrunePrivateNetworks(ctx context.Context, before time.Time) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	args := filters.NewArgs()
+	args.Add("label", consts.LabelPrivate)
+	summary, err := containers.Cli.NetworkList(ctx, network.ListOptions{
+		Filters: args,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, net := range summary {
+		name := net.Labels[consts.LabelPrivate]
+		if name == "" || !net.Created.Before(before) {
+			continue
+		}
+
+		// The list doesn't include the attached containers, nor the services
+		// of the overlay networks, whose containers may run on other nodes
+		inspect, err := containers.Cli.NetworkInspect(ctx, net.ID, network.InspectOptions{Verbose: true})
+		if err != nil {
+			log.Error("Failed to inspect private network:", "network", net.Name, "err", err)
+			continue
+		}
+		if len(inspect.Services) > 0 || !orphanedNetwork(name, inspect.Containers) {
+			continue
+		}
+
+		err = RemovePrivateNetwork(ctx, name)
+		if err != nil {
+			log.Error("Failed to remove orphaned private network:", "network", net.Name, "err", err)
+			continue
+		}
+		log.Warn("Removed orphaned private network:", "network", net.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package networks
+
+import (
+	"testing"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+func TestOrphanedNetwork(t *testing.T) {
+	name := "chall_1_2"
+
+	if !orphanedNetwork(name, nil) {
+		t.Errorf("Expected a network without containers to be orphaned")
+	}
+	if !orphanedNetwork(name, map[string]network.EndpointResource{"a": {Name: ResolverName(name)}}) {
+		t.Errorf("Expected a network with only its resolver to be orphaned")
+	}
+	if orphanedNetwork(name, map[string]network.EndpointResource{
+		"a": {Name: ResolverName(name)},
+		"b": {Name: name + "-chall-1"},
+	}) {
+		t.Errorf("Expected a network with a service to be in use")
+	}
+	if orphanedNetwork(name, map[string]network.EndpointResource{"a": {Name: ResolverName("chall_1_3")}}) {
+		t.Errorf("Expected the resolver of another instance to count as a container")
+	}
+}
+
+func TestPrivateNetworkOptions(t *testing.T) {
+	name := "chall_1_2"
+	labels := map[string]string{consts.LabelInstance: name}
+
+	tests := []struct {
+		egress   sqlc.EgressPolicy
+		internal bool
+	}{
+		{sqlc.EgressPolicyNone, true},
+		{sqlc.EgressPolicyDNS, true},
+		{sqlc.EgressPolicyFull, false},
+	}
+	for _, test := range tests {
+		options := privateNetworkOptions(name, test.egress, labels)
+		if options.Driver != "bridge" {
+			t.Errorf("%s: expected a bridge, got %q", test.egress, options.Driver)
+		}
+		if options.Internal != test.internal {
+			t.Errorf("%s: expected internal %v, got %v", test.egress, test.internal, options.Internal)
+		}
+		if options.Labels[consts.LabelPrivate] != name || options.Labels[consts.LabelInstance] != name {
+			t.Errorf("%s: unexpected labels %v", test.egress, options.Labels)
+		}
+	}
+
+	if _, ok := labels[consts.LabelPrivate]; ok {
+		t.Errorf("Expected the instance labels to be left untouched")
+	}
+}
@@ -0,0 +1,119 @@
+package networks
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"trxd/instancer/containers"
+	"trxd/utils/consts"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// The resolver forwards every query to the resolvers of its own upstream
+// network, which is the only one with a route outside of the host
+const corefile = `. {
+	forward . /etc/resolv.conf
+	cache 30
+}
+`
+
+const (
+	resolverMaxMemory = 64 * 1024 * 1024
+	resolverMaxCPUs   = 250_000_000 // a quarter of a CPU
+)
+
+// resolverUpstream is the network the resolver reaches the host resolvers from
+const resolverUpstream = "bridge"
+
+// ResolverName is the name of the resolver of the instance name, running only
+// with the DNS egress policy
+func ResolverName(name string) string {
+	return name + "-resolver"
+}
+
+func corefileArchive() (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	err := tw.WriteHeader(&tar.Header{
+		Name: "Corefile",
+		Mode: 0o644,
+		Size: int64(len(corefile)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = tw.Write([]byte(corefile))
+	if err != nil {
+		return nil, err
+	}
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// startResolver runs the resolver of the instance name, attached to its
+// private network first so that it gets an address before the services of
+// the instance. Returns the address of the resolver on the private network.
+func startResolver(ctx context.Context, name string, image string) (string, error) {
+	privateNet := PrivateNetworkName(name)
+
+	// The resolver doesn't carry the instance labels, or the reconciler would
+	// take it for an instance
+	resp, err := containers.Cli.ContainerCreate(ctx, &container.Config{
+		Image:  image,
+		Cmd:    []string{"-conf", "/Corefile"},
+		Labels: map[string]string{consts.LabelPrivate: name},
+	}, &container.HostConfig{
+		RestartPolicy: container.RestartPolicy{
+			Name: container.RestartPolicyAlways,
+		},
+		Resources: container.Resources{
+			Memory:   resolverMaxMemory,
+			NanoCPUs: resolverMaxCPUs,
+		},
+	}, &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			privateNet: {},
+		},
+	}, nil, ResolverName(name))
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := corefileArchive()
+	if err != nil {
+		return "", err
+	}
+	err = containers.Cli.CopyToContainer(ctx, resp.ID, "/", archive, container.CopyToContainerOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	err = containers.Cli.NetworkConnect(ctx, resolverUpstream, resp.ID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	err = containers.Cli.ContainerStart(ctx, resp.ID, container.StartOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	inspect, err := containers.Cli.ContainerInspect(ctx, resp.ID)
+	if err != nil {
+		return "", err
+	}
+	if inspect.NetworkSettings == nil || inspect.NetworkSettings.Networks[privateNet] == nil ||
+		inspect.NetworkSettings.Networks[privateNet].IPAddress == "" {
+		return "", errors.New("[resolver without address]")
+	}
+
+	return inspect.NetworkSettings.Networks[privateNet].IPAddress, nil
+}
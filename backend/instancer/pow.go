@@ -0,0 +1,235 @@
+package instancer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/db"
+	"trxd/utils/consts"
+)
+
+const powNonceLen = 16
+
+// PowChallenge is a hashcash-like challenge: the solution is any string such
+// that sha256(nonce + solution) starts with Difficulty zero bits
+type PowChallenge struct {
+	Nonce      string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+func powKey(nonce string) string {
+	return "pow:" + nonce
+}
+
+func powUsedKey(nonce string) string {
+	return "pow:used:" + nonce
+}
+
+func powSlotKey(tid int32, slot int) string {
+	return fmt.Sprintf("pow:team:%d:%d", tid, slot)
+}
+
+// ErrTooManyPows is returned when the team has as many pending challenges as
+// allowed, until one of them is solved or expires
+var ErrTooManyPows = errors.New("too many pending proofs of work")
+
+// reservePowSlot reserves one of the slots of the pending challenges of the
+// team until expiresAt, storing the nonce of the challenge in it
+func reservePowSlot(ctx context.Context, tid int32, nonce string, expiresAt time.Time) (int, error) {
+	val := fmt.Sprintf("%s:%d", nonce, expiresAt.Unix())
+	for slot := range consts.MaxPendingPows {
+		key := powSlotKey(tid, slot)
+		ok, err := db.StorageSetNX(ctx, key, val, time.Until(expiresAt))
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return slot, nil
+		}
+
+		// The in-memory storage ignores the expiration of the keys
+		taken, err := db.StorageGet(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if taken == nil || !powSlotExpired(*taken, time.Now()) {
+			continue
+		}
+		err = db.StorageDelete(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		ok, err = db.StorageSetNX(ctx, key, val, time.Until(expiresAt))
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return slot, nil
+		}
+	}
+
+	return 0, ErrTooManyPows
+}
+
+func powSlotExpired(val string, now time.Time) bool {
+	_, expiry, _ := strings.Cut(val, ":")
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	return err != nil || now.Unix() > expiresAt
+}
+
+// PowDifficulty returns the difficulty for a challenge with the given base
+// difficulty, adding one bit every time the active instances double over
+// the configured load step
+func PowDifficulty(ctx context.Context, base int) (int, error) {
+	step, err := getIntConfig(ctx, "instance-pow-load-step")
+	if err != nil {
+		return 0, err
+	}
+
+	difficulty := max(base, 0)
+	if step > 0 {
+		count, err := dbCountInstances(ctx)
+		if err != nil {
+			return 0, err
+		}
+		difficulty += bits.Len64(uint64(count / int64(step)))
+	}
+	if difficulty == 0 {
+		return 0, nil
+	}
+
+	maxDifficulty, err := getIntConfig(ctx, "instance-pow-max-difficulty")
+	if err != nil {
+		return 0, err
+	}
+	if maxDifficulty > 0 {
+		difficulty = min(difficulty, max(maxDifficulty, base))
+	}
+
+	return difficulty, nil
+}
+
+// IssuePow stores a new challenge bound to the team and the challenge
+func IssuePow(ctx context.Context, tid, challID int32, difficulty int) (*PowChallenge, error) {
+	timeout, err := getIntervalConfig(ctx, "instance-pow-timeout")
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, powNonceLen)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	pow := &PowChallenge{
+		Nonce:      hex.EncodeToString(nonce),
+		Difficulty: difficulty,
+		ExpiresAt:  time.Now().Add(timeout),
+	}
+
+	slot, err := reservePowSlot(ctx, tid, pow.Nonce, pow.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	val := fmt.Sprintf("%d:%d:%d:%d:%d", tid, challID, difficulty, pow.ExpiresAt.Unix(), slot)
+	ok, err := db.StorageSetNX(ctx, powKey(pow.Nonce), val, timeout)
+	if err == nil && !ok {
+		err = fmt.Errorf("nonce collision")
+	}
+	if err != nil {
+		_ = db.StorageDelete(ctx, powSlotKey(tid, slot))
+		return nil, err
+	}
+
+	return pow, nil
+}
+
+// VerifyPow checks the solution and consumes the challenge, returning false
+// if it doesn't belong to the team and challenge, is expired, wrong or
+// already used
+func VerifyPow(ctx context.Context, tid, challID int32, nonce, solution string) (bool, error) {
+	val, err := db.StorageGet(ctx, powKey(nonce))
+	if err != nil {
+		return false, err
+	}
+	if val == nil {
+		return false, nil
+	}
+
+	var powTid, powChallID int32
+	var difficulty, slot int
+	var expiresAt int64
+	_, err = fmt.Sscanf(*val, "%d:%d:%d:%d:%d", &powTid, &powChallID, &difficulty, &expiresAt, &slot)
+	if err != nil {
+		return false, err
+	}
+	if powTid != tid || powChallID != challID {
+		return false, nil
+	}
+	if time.Now().Unix() > expiresAt {
+		return false, releasePow(ctx, tid, slot, nonce)
+	}
+	if !CheckPow(nonce, solution, difficulty) {
+		return false, nil
+	}
+
+	// Two concurrent requests may both read the nonce, only one can mark it
+	ttl := time.Until(time.Unix(expiresAt, 0)) + time.Second
+	ok, err := db.StorageSetNX(ctx, powUsedKey(nonce), "1", ttl)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+
+	err = releasePow(ctx, tid, slot, nonce)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// releasePow deletes the challenge and frees its slot, unless the slot has
+// already been reserved again by a newer challenge
+func releasePow(ctx context.Context, tid int32, slot int, nonce string) error {
+	err := db.StorageDelete(ctx, powKey(nonce))
+	if err != nil {
+		return err
+	}
+
+	val, err := db.StorageGet(ctx, powSlotKey(tid, slot))
+	if err != nil || val == nil || !strings.HasPrefix(*val, nonce+":") {
+		return err
+	}
+
+	return db.StorageDelete(ctx, powSlotKey(tid, slot))
+}
+
+// CheckPow reports whether sha256(nonce + solution) starts with difficulty
+// zero bits
+func CheckPow(nonce, solution string, difficulty int) bool {
+	hash := sha256.Sum256([]byte(nonce + solution))
+
+	zeros := 0
+	for _, b := range hash {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+
+	return zeros >= difficulty
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 	"trxd/db"
 	"trxd/db/sqlc"
+	"trxd/instancer/infos"
 	"trxd/utils/consts"
 
 	"github.com/lib/pq"
@@ -27,19 +28,23 @@ func GetInstance(ctx context.Context, challID, teamID int32) (*sqlc.Instance, er
 	return &instance, nil
 }
 
-func dbCreateInstance(ctx context.Context, teamID, challID int32,
-	expiresAt time.Time, hashDomain bool) (*sqlc.CreateInstanceRow, error) {
+func dbCreateInstance(ctx context.Context, q *sqlc.Queries, teamID, challID int32, createdAt time.Time,
+	expiresAt time.Time, hashDomain bool, teamOnly bool, node string, nodeHost string) (*sqlc.CreateInstanceRow, error) {
 
-	info, err := db.Sql.CreateInstance(ctx, sqlc.CreateInstanceParams{
+	info, err := q.CreateInstance(ctx, sqlc.CreateInstanceParams{
 		TeamID:     teamID,
 		ChallID:    challID,
+		CreatedAt:  createdAt,
 		ExpiresAt:  expiresAt,
+		TeamOnly:   teamOnly,
+		Node:       node,
 		HashDomain: hashDomain,
+		NodeHost:   nodeHost,
 	})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == consts.PGUniqueViolation {
-				if pqErr.Constraint == "instances_port_key" {
+				if pqErr.Constraint == "instances_node_port_key" {
 					return nil, errors.New("[port conflict]")
 				}
 				return nil, nil
@@ -78,7 +83,11 @@ func UpdateInstanceExpire(ctx context.Context, tid int32, challID int32, expires
 }
 
 func dbDeleteInstance(ctx context.Context, tid int32, challID int32) error {
-	err := db.Sql.DeleteInstance(ctx, sqlc.DeleteInstanceParams{
+	return dbDeleteInstanceTx(ctx, db.Sql, tid, challID)
+}
+
+func dbDeleteInstanceTx(ctx context.Context, q *sqlc.Queries, tid int32, challID int32) error {
+	err := q.DeleteInstance(ctx, sqlc.DeleteInstanceParams{
 		TeamID:  tid,
 		ChallID: challID,
 	})
@@ -88,3 +97,38 @@ func dbDeleteInstance(ctx context.Context, tid int32, challID int32) error {
 
 	return nil
 }
+
+func dbCountInstances(ctx context.Context) (int64, error) {
+	return db.Sql.CountInstances(ctx)
+}
+
+func dbCreateInstanceEvent(ctx context.Context, q *sqlc.Queries, tid int32, challID int32,
+	eventType sqlc.InstanceEventType, cause error) error {
+
+	params := sqlc.CreateInstanceEventParams{
+		TeamID:  tid,
+		ChallID: challID,
+		Type:    eventType,
+	}
+	if cause != nil {
+		params.Error = cause.Error()
+	}
+
+	return q.CreateInstanceEvent(ctx, params)
+}
+
+func dbCreateInstanceSecrets(ctx context.Context, tid int32, challID int32, secrets []infos.Secret) error {
+	for _, secret := range secrets {
+		err := db.Sql.CreateInstanceSecret(ctx, sqlc.CreateInstanceSecretParams{
+			TeamID:  tid,
+			ChallID: challID,
+			Name:    secret.Name,
+			Value:   secret.Value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
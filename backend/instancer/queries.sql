@@ -14,17 +14,56 @@ SELECT team_id, chall_id, expires_at, docker_id
   ORDER BY expires_at ASC
   LIMIT 1;
 
+-- name: ClaimExpiredInstances :many
+-- Locks a batch of expired instances, skipping the ones locked by other replicas
+SELECT team_id, chall_id, docker_id, expires_at
+  FROM instances
+  WHERE expires_at < NOW()
+  ORDER BY expires_at ASC
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED;
+
+-- name: GetInstancesToReconcile :many
+-- Retrieves all instances to compare them with the running Docker objects
+SELECT team_id, chall_id, docker_id, created_at
+  FROM instances
+  ORDER BY created_at ASC;
+
+-- name: TryLockTask :one
+-- Takes the advisory lock of a background task until the end of the
+-- transaction, false if another replica holds it
+SELECT pg_try_advisory_xact_lock(sqlc.arg(key)::BIGINT) AS locked;
+
+-- name: LockTask :exec
+-- Waits for the advisory lock of a task, held until the end of the transaction
+SELECT pg_advisory_xact_lock(sqlc.arg(key)::BIGINT);
+
+-- name: GetPendingNodeReservations :many
+-- Retrieves the reservations of the instances scheduled on a Swarm node whose
+-- service is not created yet
+SELECT i.node, d.reserved_memory, d.reserved_cpu
+  FROM instances i
+  JOIN docker_configs d ON d.chall_id = i.chall_id
+  WHERE i.node <> '' AND i.docker_id IS NULL;
+
+-- name: CountInstances :one
+-- Counts the active instances, used to measure the instancer load
+SELECT COUNT(*) FROM instances;
+
 -- name: CreateInstance :one
 -- Creates a new instance for a team
 WITH info AS (
     SELECT generate_instance_remote(
       sqlc.arg(chall_id),
-      sqlc.arg(hash_domain)::BOOLEAN
+      sqlc.arg(hash_domain)::BOOLEAN,
+      sqlc.arg(node)::TEXT,
+      sqlc.arg(node_host)::TEXT
     ) AS remote
   )
-INSERT INTO instances (team_id, chall_id, expires_at, host, port)
-  VALUES (sqlc.arg(team_id), sqlc.arg(chall_id), sqlc.arg(expires_at),
-    (SELECT (remote).host FROM info), (SELECT (remote).port FROM info))
+INSERT INTO instances (team_id, chall_id, created_at, expires_at, host, port, team_only, node)
+  VALUES (sqlc.arg(team_id), sqlc.arg(chall_id), sqlc.arg(created_at), sqlc.arg(expires_at),
+    (SELECT (remote).host FROM info), (SELECT (remote).port FROM info),
+    sqlc.arg(team_only), sqlc.arg(node))
 RETURNING host, port;
 
 -- name: UpdateInstanceDockerID :exec
@@ -39,8 +78,108 @@ UPDATE instances
   SET expires_at = $3
   WHERE team_id = $1 AND chall_id = $2;
 
+-- name: ExtendInstance :one
+-- Extends an instance, only if no other extension happened in the meantime
+UPDATE instances
+  SET expires_at = $3, extensions = extensions + 1, extended_at = $5
+  WHERE team_id = $1 AND chall_id = $2 AND extensions = $4
+  RETURNING extensions;
+
+-- name: CreateInstanceSecret :exec
+-- Stores a secret rendered in the environment of an instance, kept after the
+-- instance is deleted
+INSERT INTO instance_secrets (team_id, chall_id, name, value)
+  VALUES ($1, $2, $3, $4);
+
 -- name: DeleteInstance :exec
 -- Delete an instance
 DELETE FROM instances
   WHERE team_id = $1 AND chall_id = $2;
 
+-- name: GetSharedDeployments :many
+-- Retrieves the challenges with a shared deployment and its current state
+SELECT d.chall_id, dep.docker_id, COALESCE(dep.config_hash, '') AS config_hash,
+    COALESCE(dep.restarts, 0) AS restarts, COALESCE(dep.failures, 0) AS failures,
+    dep.updated_at
+  FROM docker_configs d
+  JOIN challenges c ON c.id = d.chall_id
+  LEFT JOIN deployments dep ON dep.chall_id = d.chall_id
+  WHERE d.shared AND c.type <> 'Normal'
+  ORDER BY d.chall_id ASC;
+
+-- name: GetStaleDeployments :many
+-- Retrieves the deployments of the challenges that are no longer shared
+SELECT dep.chall_id, dep.docker_id
+  FROM deployments dep
+  JOIN docker_configs d ON d.chall_id = dep.chall_id
+  JOIN challenges c ON c.id = dep.chall_id
+  WHERE NOT d.shared OR c.type = 'Normal';
+
+-- name: UpsertDeployment :exec
+-- Records the state of a shared deployment
+INSERT INTO deployments (chall_id, docker_id, host, config_hash, status, error, restarts, failures, updated_at)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+  ON CONFLICT (chall_id) DO UPDATE SET
+    docker_id = EXCLUDED.docker_id,
+    host = EXCLUDED.host,
+    config_hash = EXCLUDED.config_hash,
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    restarts = EXCLUDED.restarts,
+    failures = EXCLUDED.failures,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: DeleteDeployment :exec
+-- Deletes the state of a shared deployment
+DELETE FROM deployments WHERE chall_id = $1;
+
+-- name: CreateInstanceEvent :exec
+-- Records an instance lifecycle event, taking host, port and duration from
+-- the instance if it still exists
+INSERT INTO instance_events (team_id, chall_id, type, duration, host, port, error)
+  SELECT sqlc.arg(team_id)::INTEGER, sqlc.arg(chall_id)::INTEGER, sqlc.arg(type)::instance_event_type,
+      COALESCE(EXTRACT(EPOCH FROM CASE
+        WHEN sqlc.arg(type)::instance_event_type IN ('Create', 'Extend') THEN i.expires_at - NOW()
+        ELSE NOW() - i.created_at
+      END)::INTEGER, 0),
+      COALESCE(i.host, ''), i.port, sqlc.arg(error)::TEXT
+    FROM (SELECT 1) AS event
+    LEFT JOIN instances i ON i.team_id = sqlc.arg(team_id) AND i.chall_id = sqlc.arg(chall_id);
+
+-- name: GetInstanceUsage :one
+-- Retrieves the rolling aggregates of an instance
+SELECT * FROM instance_usage WHERE team_id = $1 AND chall_id = $2;
+
+-- name: UpdateInstanceUsage :exec
+-- Stores a sample and the rolling aggregates of an instance
+INSERT INTO instance_usage (team_id, chall_id, samples, cpu, cpu_avg, cpu_max, memory, memory_avg, memory_max,
+    pids, pids_max, network, network_avg, network_max, over_since, updated_at)
+  VALUES (sqlc.arg('team_id'), sqlc.arg('chall_id'), sqlc.arg('samples'), sqlc.arg('cpu'), sqlc.arg('cpu_avg'), sqlc.arg('cpu_max'),
+    sqlc.arg('memory'), sqlc.arg('memory_avg'), sqlc.arg('memory_max'), sqlc.arg('pids'), sqlc.arg('pids_max'),
+    sqlc.arg('network'), sqlc.arg('network_avg'), sqlc.arg('network_max'), sqlc.narg('over_since'), sqlc.arg('updated_at'))
+  ON CONFLICT (team_id, chall_id) DO UPDATE SET
+    samples = EXCLUDED.samples,
+    cpu = EXCLUDED.cpu,
+    cpu_avg = EXCLUDED.cpu_avg,
+    cpu_max = EXCLUDED.cpu_max,
+    memory = EXCLUDED.memory,
+    memory_avg = EXCLUDED.memory_avg,
+    memory_max = EXCLUDED.memory_max,
+    pids = EXCLUDED.pids,
+    pids_max = EXCLUDED.pids_max,
+    network = EXCLUDED.network,
+    network_avg = EXCLUDED.network_avg,
+    network_max = EXCLUDED.network_max,
+    over_since = EXCLUDED.over_since,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: FlagInstanceUsage :exec
+-- Flags an instance for exceeding its thresholds for too long
+UPDATE instance_usage SET flagged = TRUE WHERE team_id = $1 AND chall_id = $2;
+
+-- name: CountInstancesByChallenge :many
+-- Counts the running instances of each challenge
+SELECT c.id, c.name, COUNT(*) AS instances
+  FROM instances i
+  JOIN challenges c ON i.chall_id = c.id
+  GROUP BY c.id, c.name;
@@ -0,0 +1,178 @@
+package instancer
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+
+	"trxd/utils/log"
+	"trxd/utils/metrics"
+)
+
+const (
+	reclaimBatchSize  = 64
+	reclaimMinDelay   = time.Second
+	reclaimRetryDelay = 10 * time.Second
+)
+
+var (
+	reclaimWake = make(chan struct{}, 1)
+	nextReclaim atomic.Int64 // unix nanoseconds of the next scheduled run
+)
+
+// wakeReclaimer reschedules the reclaimer if an instance expires before its
+// next run
+func wakeReclaimer(expiresAt time.Time) {
+	if expiresAt.UnixNano() >= nextReclaim.Load() {
+		return
+	}
+
+	select {
+	case reclaimWake <- struct{}{}:
+	default:
+	}
+}
+
+func reclaimLoop(ctx context.Context) {
+	for {
+		var sleep time.Duration
+		runSafe("reclaim loop", func() {
+			// The batch must not be interrupted halfway by a shutdown
+			sleep = reclaimStep(context.WithoutCancel(ctx))
+		})
+		if sleep == 0 {
+			sleep = reclaimRetryDelay
+		}
+
+		nextReclaim.Store(time.Now().Add(sleep).UnixNano())
+		if !sleepCtx(ctx, sleep, reclaimWake) {
+			return
+		}
+	}
+}
+
+// reclaimStep deletes all the expired instances and returns how long to wait
+// before the next run
+func reclaimStep(ctx context.Context) time.Duration {
+	interval, err := GetInterval(ctx)
+	if err != nil {
+		log.Error("Failed to get reclaim interval:", "err", err)
+		return reclaimRetryDelay
+	}
+
+	for {
+		claimed, failed, err := reclaimExpired(ctx)
+		if err != nil {
+			log.Error("Failed to reclaim expired instances:", "err", err)
+			return reclaimRetryDelay
+		}
+		if failed > 0 {
+			return reclaimRetryDelay
+		}
+		if claimed < reclaimBatchSize {
+			break
+		}
+	}
+
+	next, err := db.Sql.GetNextInstanceToDelete(ctx)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Error("Failed to get next instance to delete:", "err", err)
+			return reclaimRetryDelay
+		}
+		return interval
+	}
+
+	// An already expired instance is being reclaimed by another replica
+	return max(time.Until(next.ExpiresAt), reclaimMinDelay)
+}
+
+// reclaimExpired claims a batch of expired instances, deleting their rows in
+// a short transaction so that other replicas and users skip them, then kills
+// them in parallel. An instance failing to be killed is left to the reconciler
+// as an orphaned object. Returns the number of claimed and failed instances.
+func reclaimExpired(ctx context.Context) (int, int, error) {
+	workers, err := getIntConfig(ctx, "reclaim-instance-workers")
+	if err != nil {
+		return 0, 0, err
+	}
+	workers = max(workers, 1)
+
+	expired, err := claimExpired(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(expired) == 0 {
+		metrics.ReclaimLag.Set(0)
+		return 0, 0, nil
+	}
+
+	var lag time.Duration
+	for _, instance := range expired {
+		lag = max(lag, time.Since(instance.ExpiresAt))
+	}
+	metrics.ReclaimLag.Set(lag.Seconds())
+
+	var failed atomic.Int64
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, instance := range expired {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			runSafe("reclaim worker", func() {
+				log.Info("Reclaiming instance:", "chall", instance.ChallID, "team", instance.TeamID)
+				start := time.Now()
+				err := killInstance(ctx, instance.DockerID)
+				observeOperation("reclaim", start, err)
+				if err != nil {
+					failed.Add(1)
+					recordEvent(ctx, instance.TeamID, instance.ChallID, sqlc.InstanceEventTypeFailure, err)
+					log.Error("Failed to reclaim instance:", "chall", instance.ChallID, "team", instance.TeamID, "err", err)
+				}
+			})
+		})
+	}
+	wg.Wait()
+
+	return len(expired), int(failed.Load()), nil
+}
+
+// claimExpired locks a batch of expired instances, skipping the ones locked
+// by other replicas, and deletes them recording their expiration
+func claimExpired(ctx context.Context) ([]sqlc.ClaimExpiredInstancesRow, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Rollback(tx)
+
+	q := db.Sql.WithTx(tx)
+	expired, err := q.ClaimExpiredInstances(ctx, reclaimBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range expired {
+		err := dbCreateInstanceEvent(ctx, q, instance.TeamID, instance.ChallID, sqlc.InstanceEventTypeExpire, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = dbDeleteInstanceTx(ctx, q, instance.TeamID, instance.ChallID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
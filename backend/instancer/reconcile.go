@@ -0,0 +1,307 @@
+package instancer
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/instancer/containers"
+	"trxd/instancer/networks"
+	"trxd/instancer/services"
+	"trxd/utils/consts"
+	"trxd/utils/metrics"
+
+	"trxd/utils/log"
+
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// Objects and rows younger than this are skipped, as they may belong to an
+// instance that is still being created
+const reconcileGracePeriod = 2 * time.Minute
+
+const (
+	actionKillOrphan     = "kill_orphan"
+	actionDeleteDangling = "delete_dangling"
+)
+
+type dockerObject struct {
+	ID      string // container ID, service ID or compose project name
+	Kind    dockerKind
+	TeamID  int32
+	ChallID int32
+	Created time.Time
+	Running bool // a compose project runs if any of its containers does
+}
+
+func fetchDockerObjects(ctx context.Context) (map[string]*dockerObject, error) {
+	summary, err := containers.FetchInstanceContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]*dockerObject)
+	for _, c := range summary {
+		// Task containers are handled through their service
+		if _, ok := c.Labels[services.ServiceIDLabel]; ok {
+			continue
+		}
+
+		tid, err1 := strconv.Atoi(c.Labels[consts.LabelTeamID])
+		challID, err2 := strconv.Atoi(c.Labels[consts.LabelChallID])
+		if err1 != nil || err2 != nil {
+			log.Warn("Skipping container with invalid instance labels:", "container", c.ID)
+			continue
+		}
+
+		id := c.ID
+		kind := kindContainer
+		if project, ok := c.Labels[api.ProjectLabel]; ok {
+			id = project
+			kind = kindCompose
+		}
+
+		created := time.Unix(c.Created, 0)
+		running := c.State == container.StateRunning || c.State == container.StateRestarting
+		if obj, ok := objects[id]; ok {
+			if created.After(obj.Created) {
+				obj.Created = created
+			}
+			obj.Running = obj.Running || running
+			continue
+		}
+
+		objects[id] = &dockerObject{
+			ID:      id,
+			Kind:    kind,
+			TeamID:  int32(tid),
+			ChallID: int32(challID),
+			Created: created,
+			Running: running,
+		}
+	}
+
+	swarm, err := SwarmEnabled(ctx)
+	if err != nil || !swarm {
+		return objects, err
+	}
+
+	serviceSummary, err := services.FetchInstanceServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range serviceSummary {
+		tid, err1 := strconv.Atoi(s.Spec.Labels[consts.LabelTeamID])
+		challID, err2 := strconv.Atoi(s.Spec.Labels[consts.LabelChallID])
+		if err1 != nil || err2 != nil {
+			log.Warn("Skipping service with invalid instance labels:", "service", s.ID)
+			continue
+		}
+
+		// The tasks of a service are restarted by Swarm
+		objects[s.ID] = &dockerObject{
+			ID:      s.ID,
+			Kind:    kindService,
+			TeamID:  int32(tid),
+			ChallID: int32(challID),
+			Created: s.CreatedAt,
+			Running: true,
+		}
+	}
+
+	return objects, nil
+}
+
+func recordReconcile(action string, err error) {
+	metrics.InstancesReconciled.WithLabelValues(action, metrics.Status(err)).Inc()
+}
+
+// planReconcile matches the Docker objects with the rows of the instances
+// table, returning the rows without a running object and the objects without
+// a matching row. Rows and objects younger than the grace period are skipped.
+func planReconcile(objects map[string]*dockerObject, rows []sqlc.GetInstancesToReconcileRow,
+	now time.Time) ([]sqlc.GetInstancesToReconcileRow, []*dockerObject) {
+
+	orphans := maps.Clone(objects)
+	var dangling []sqlc.GetInstancesToReconcileRow
+	for _, row := range rows {
+		if row.DockerID.Valid {
+			obj, ok := orphans[row.DockerID.String]
+			if ok && obj.TeamID == row.TeamID && obj.ChallID == row.ChallID && obj.Running {
+				delete(orphans, row.DockerID.String)
+				continue
+			}
+		}
+
+		if now.Sub(row.CreatedAt) < reconcileGracePeriod {
+			if row.DockerID.Valid {
+				delete(orphans, row.DockerID.String)
+			}
+			continue
+		}
+
+		// A stopped object is left among the orphans, to be removed with its row
+		dangling = append(dangling, row)
+	}
+
+	var killable []*dockerObject
+	for _, obj := range orphans {
+		if now.Sub(obj.Created) < reconcileGracePeriod {
+			continue
+		}
+		killable = append(killable, obj)
+	}
+	slices.SortFunc(killable, func(a, b *dockerObject) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return dangling, killable
+}
+
+// Reconcile compares the Docker objects labeled as instances with the rows of
+// the instances table, killing the objects without a matching row and deleting
+// the rows without a running object, then removes the private networks left
+// behind. A single replica reconciles at a time.
+func Reconcile(ctx context.Context) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	locked, err := runLocked(ctx, lockReconcile, func() error {
+		return reconcile(ctx)
+	})
+	if err == nil && !locked {
+		log.Debug("Skipping reconcile, running on another replica")
+	}
+
+	return err
+}
+
+// dockerObjectExists reports whether the Docker object of the ID exists,
+// whatever its labels
+func dockerObjectExists(ctx context.Context, id string) (bool, error) {
+	kind, err := parseDockerID(id)
+	if err != nil {
+		return false, err
+	}
+
+	switch kind {
+	case kindService:
+		_, _, err = containers.Cli.ServiceInspectWithRaw(ctx, id, swarm.ServiceInspectOptions{})
+	case kindCompose:
+		var summary []container.Summary
+		summary, err = containers.FetchContainersByLabel(ctx, api.ProjectLabel, id)
+		if err == nil && len(summary) == 0 {
+			return false, nil
+		}
+	default:
+		_, err = containers.Cli.ContainerInspect(ctx, id)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "No such container") || strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// skipLegacy leaves out the dangling rows whose object exists without the
+// instance labels, as created before the instances were labeled: the
+// reconciler can't reap those objects, so deleting their row would leave them
+// running forever. They are killed by the reclaimer once expired.
+func skipLegacy(dangling []sqlc.GetInstancesToReconcileRow, objects map[string]*dockerObject,
+	exists func(id string) (bool, error)) []sqlc.GetInstancesToReconcileRow {
+
+	var kept []sqlc.GetInstancesToReconcileRow
+	for _, row := range dangling {
+		if row.DockerID.Valid {
+			if _, ok := objects[row.DockerID.String]; !ok {
+				legacy, err := exists(row.DockerID.String)
+				if err != nil {
+					log.Error("Failed to check instance object:", "team", row.TeamID, "chall", row.ChallID, "err", err)
+					continue
+				}
+				if legacy {
+					log.Debug("Skipping unlabeled instance:", "team", row.TeamID, "chall", row.ChallID, "docker", row.DockerID.String)
+					continue
+				}
+			}
+		}
+		kept = append(kept, row)
+	}
+
+	return kept
+}
+
+func reconcile(ctx context.Context) error {
+	// Docker objects must be fetched before the rows, so that an instance
+	// created in between is seen as a row without object (and skipped as young)
+	objects, err := fetchDockerObjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Sql.GetInstancesToReconcile(ctx)
+	if err != nil {
+		return err
+	}
+
+	dangling, orphans := planReconcile(objects, rows, time.Now())
+	dangling = skipLegacy(dangling, objects, func(id string) (bool, error) {
+		return dockerObjectExists(ctx, id)
+	})
+
+	for _, row := range dangling {
+		recordEvent(ctx, row.TeamID, row.ChallID, sqlc.InstanceEventTypeDelete, errors.New("[docker object missing]"))
+		err := dbDeleteInstance(ctx, row.TeamID, row.ChallID)
+		recordReconcile(actionDeleteDangling, err)
+		if err != nil {
+			log.Error("Failed to delete dangling instance:", "team", row.TeamID, "chall", row.ChallID, "err", err)
+			continue
+		}
+		log.Warn("Deleted dangling instance:", "team", row.TeamID, "chall", row.ChallID, "docker", row.DockerID.String)
+	}
+
+	for _, obj := range orphans {
+		err := killDockerObjectKind(ctx, obj.Kind, obj.ID)
+		recordReconcile(actionKillOrphan, err)
+		if err != nil {
+			log.Error("Failed to kill orphaned instance:", "docker", obj.ID, "team", obj.TeamID, "chall", obj.ChallID, "err", err)
+			continue
+		}
+		log.Warn("Killed orphaned instance:", "docker", obj.ID, "team", obj.TeamID, "chall", obj.ChallID)
+	}
+
+	// Private networks outlive their instance if it's killed halfway
+	return networks.PrunePrivateNetworks(ctx, time.Now().Add(-reconcileGracePeriod))
+}
+
+func reconcileLoop(ctx context.Context) {
+	for {
+		sleep, err := getIntervalConfig(ctx, "reconcile-instance-interval")
+		if err != nil {
+			log.Error("Failed to get reconcile interval:", "err", err)
+			sleep = time.Minute
+		}
+		if !sleepCtx(ctx, sleep, nil) {
+			return
+		}
+
+		runSafe("reconcile loop", func() {
+			err := Reconcile(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Error("Failed to reconcile instances:", "err", err)
+			}
+		})
+	}
+}
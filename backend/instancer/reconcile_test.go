@@ -0,0 +1,133 @@
+package instancer
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+)
+
+func TestPlanReconcile(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := now.Add(-time.Hour)
+	young := now.Add(-time.Second)
+
+	row := func(tid, challID int32, dockerID string, created time.Time) sqlc.GetInstancesToReconcileRow {
+		return sqlc.GetInstancesToReconcileRow{
+			TeamID:    tid,
+			ChallID:   challID,
+			DockerID:  sql.NullString{String: dockerID, Valid: dockerID != ""},
+			CreatedAt: created,
+		}
+	}
+	objects := map[string]*dockerObject{
+		"running":  {ID: "running", TeamID: 1, ChallID: 1, Created: old, Running: true},
+		"stopped":  {ID: "stopped", TeamID: 2, ChallID: 1, Created: old},
+		"orphan":   {ID: "orphan", TeamID: 3, ChallID: 1, Created: old, Running: true},
+		"creating": {ID: "creating", TeamID: 4, ChallID: 1, Created: young, Running: true},
+		"mismatch": {ID: "mismatch", TeamID: 5, ChallID: 2, Created: old, Running: true},
+		"starting": {ID: "starting", TeamID: 6, ChallID: 1, Created: old},
+	}
+	rows := []sqlc.GetInstancesToReconcileRow{
+		row(1, 1, "running", old),
+		row(2, 1, "stopped", old),
+		row(5, 1, "mismatch", old),
+		row(6, 1, "starting", young),
+		row(7, 1, "", old),
+		row(8, 1, "", young),
+	}
+
+	dangling, orphans := planReconcile(objects, rows, now)
+
+	var danglingTeams []int32
+	for _, r := range dangling {
+		danglingTeams = append(danglingTeams, r.TeamID)
+	}
+	if len(danglingTeams) != 3 || danglingTeams[0] != 2 || danglingTeams[1] != 5 || danglingTeams[2] != 7 {
+		t.Fatalf("Unexpected dangling rows: %v", danglingTeams)
+	}
+
+	var orphanIDs []string
+	for _, obj := range orphans {
+		orphanIDs = append(orphanIDs, obj.ID)
+	}
+	if strings.Join(orphanIDs, ",") != "mismatch,orphan,stopped" {
+		t.Fatalf("Unexpected orphans: %v", orphanIDs)
+	}
+
+	if len(objects) != 6 {
+		t.Fatalf("The objects must not be modified, got %d", len(objects))
+	}
+}
+
+func TestParseDockerID(t *testing.T) {
+	tests := []struct {
+		id   string
+		kind dockerKind
+		err  bool
+	}{
+		{id: InstanceName(1, 2), kind: kindCompose},
+		{id: DeploymentName(1), kind: kindCompose},
+		{id: "y1bd9aqt2ef6cdfrf2gmwb4xq", kind: kindService},
+		{id: strings.Repeat("0123456789abcdef", 4), kind: kindContainer},
+		{id: strings.Repeat("0123456789ABCDEF", 4), err: true},
+		{id: strings.Repeat("a", 63), err: true},
+		{id: "", err: true},
+	}
+
+	for _, test := range tests {
+		kind, err := parseDockerID(test.id)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected an error for %q", test.id)
+			}
+			continue
+		}
+		if err != nil || kind != test.kind {
+			t.Errorf("Unexpected kind for %q: %v %v", test.id, kind, err)
+		}
+	}
+}
+
+func TestSkipLegacy(t *testing.T) {
+	row := func(tid int32, dockerID string) sqlc.GetInstancesToReconcileRow {
+		return sqlc.GetInstancesToReconcileRow{
+			TeamID:   tid,
+			ChallID:  1,
+			DockerID: sql.NullString{String: dockerID, Valid: dockerID != ""},
+		}
+	}
+	objects := map[string]*dockerObject{
+		"stopped": {ID: "stopped", TeamID: 1, ChallID: 1},
+	}
+	unlabeled := map[string]bool{"legacy": true}
+	exists := func(id string) (bool, error) {
+		if id == "stopped" {
+			t.Errorf("Expected the labeled objects not to be checked")
+		}
+		if id == "broken" {
+			return false, errors.New("docker down")
+		}
+		return unlabeled[id], nil
+	}
+
+	dangling := []sqlc.GetInstancesToReconcileRow{
+		row(1, "stopped"),
+		row(2, "legacy"),
+		row(3, "gone"),
+		row(4, ""),
+		row(5, "broken"),
+	}
+	kept := skipLegacy(dangling, objects, exists)
+
+	var tids []int32
+	for _, r := range kept {
+		tids = append(tids, r.TeamID)
+	}
+	if !slices.Equal(tids, []int32{1, 3, 4}) {
+		t.Errorf("Expected the rows of teams 1, 3 and 4 to be deleted, got %v", tids)
+	}
+}
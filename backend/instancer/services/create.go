@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"trxd/instancer/containers"
+	"trxd/instancer/infos"
+	"trxd/instancer/networks"
+	"trxd/utils/consts"
+
+	"trxd/utils/log"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+func CreateService(ctx context.Context, info *infos.InstanceInfo, image string) (string, error) {
+	if info.ExternalPort != nil && info.InternalPort == nil {
+		return "", errors.New("[missing internal port]")
+	}
+
+	if containers.Cli == nil {
+		return "", nil
+	}
+
+	containerInfo, err := infos.SetupContainerInfo(info, image)
+	if err != nil {
+		return "", err
+	}
+
+	// Without a domain the service is reached through its published port only
+	if containerInfo.NetID == "" {
+		containerInfo.NetID, err = networks.CreateServiceNetwork(ctx, containerInfo.Name, containerInfo.InstanceLabels())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	spec := setupServiceSpec(containerInfo)
+
+	if log.GetLevel() == log.DebugLevel {
+		debugService(spec)
+	}
+
+	resp, err := containers.Cli.ServiceCreate(ctx, spec, swarm.ServiceCreateOptions{})
+	if err == nil {
+		return resp.ID, nil
+	}
+	if !strings.Contains(err.Error(), "already exists") {
+		return "", err
+	}
+
+	service, err := FetchServiceByName(ctx, containerInfo.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return service.ID, nil
+}
+
+func setupServiceSpec(info *infos.ContainerInfo) swarm.ServiceSpec {
+	labels := make(map[string]string, len(info.Labels)+1)
+	for k, v := range info.Labels {
+		labels[k] = v
+	}
+	labels[consts.LabelNode] = info.Node
+
+	// The node is chosen by the instancer, as ports are allocated per node
+	constraints := append([]string{"node.id==" + info.Node}, info.Placement...)
+
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name:   info.Name,
+			Labels: labels,
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image:    info.Image,
+				Hostname: info.Name,
+				Env:      info.Env,
+				Labels:   info.InstanceLabels(),
+			},
+			Resources: &swarm.ResourceRequirements{
+				Limits: &swarm.Limit{
+					NanoCPUs:    info.MaxCPUs,
+					MemoryBytes: int64(info.MaxMemory) * 1024 * 1024,
+				},
+				Reservations: &swarm.Resources{
+					NanoCPUs:    info.ReservedCPUs,
+					MemoryBytes: int64(info.ReservedMemory) * 1024 * 1024,
+				},
+			},
+			Placement: &swarm.Placement{
+				Constraints: constraints,
+			},
+			RestartPolicy: &swarm.RestartPolicy{
+				Condition: swarm.RestartPolicyConditionAny,
+			},
+		},
+		Mode: swarm.ServiceMode{
+			Replicated: &swarm.ReplicatedService{Replicas: new(uint64(1))},
+		},
+		EndpointSpec: &swarm.EndpointSpec{
+			Mode: swarm.ResolutionModeVIP,
+		},
+	}
+
+	if info.NetID != "" {
+		spec.TaskTemplate.Networks = []swarm.NetworkAttachmentConfig{{
+			Target:  info.NetID,
+			Aliases: []string{info.Name},
+		}}
+	}
+
+	if info.ExternalPort != nil {
+		spec.EndpointSpec.Ports = []swarm.PortConfig{{
+			Protocol:      swarm.PortConfigProtocolTCP,
+			TargetPort:    uint32(*info.InternalPort),
+			PublishedPort: uint32(*info.ExternalPort),
+			PublishMode:   swarm.PortConfigPublishModeHost,
+		}}
+	}
+
+	return spec
+}
+
+func debugService(spec swarm.ServiceSpec) {
+	tmp, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		log.Error("Created service:", "err", err)
+	} else {
+		log.Debug("Created service:", "service", string(tmp))
+	}
+}
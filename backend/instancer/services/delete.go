@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"trxd/instancer/containers"
+	"trxd/instancer/networks"
+
+	"trxd/utils/log"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+func KillService(ctx context.Context, id string) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	service, _, err := containers.Cli.ServiceInspectWithRaw(ctx, id, swarm.ServiceInspectOptions{})
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		return nil
+	}
+
+	err = containers.Cli.ServiceRemove(ctx, id)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+	}
+
+	// Left to the reconciler if the tasks are still attached
+	err = networks.RemovePrivateNetwork(ctx, service.Spec.Name)
+	if err != nil {
+		log.Warn("Failed to remove service network:", "service", service.Spec.Name, "err", err)
+	}
+
+	return nil
+}
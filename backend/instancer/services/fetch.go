@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"trxd/instancer/containers"
+	"trxd/utils/consts"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// Swarm object IDs are 25 lowercase alphanumeric characters
+const serviceIDLen = 25
+
+// Label set by Swarm on the containers of a service task
+const ServiceIDLabel = "com.docker.swarm.service.id"
+
+func IsServiceID(id string) bool {
+	if len(id) != serviceIDLen {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func FetchServiceByName(ctx context.Context, name string) (*swarm.Service, error) {
+	args := filters.NewArgs()
+	args.Add("name", name)
+	summary, err := containers.Cli.ServiceList(ctx, swarm.ServiceListOptions{
+		Filters: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range summary {
+		if s.Spec.Name == name {
+			return &s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("service not found (%s)", name)
+}
+
+func FetchInstanceServices(ctx context.Context) ([]swarm.Service, error) {
+	if containers.Cli == nil {
+		return nil, nil
+	}
+
+	args := filters.NewArgs()
+	args.Add("label", consts.LabelInstance)
+	summary, err := containers.Cli.ServiceList(ctx, swarm.ServiceListOptions{
+		Filters: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
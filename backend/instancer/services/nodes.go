@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"trxd/instancer/containers"
+	"trxd/utils/consts"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+type NodeCapacity struct {
+	ID             string `json:"id"`
+	Hostname       string `json:"hostname"`
+	Host           string `json:"host"`
+	Ready          bool   `json:"ready"`
+	Active         bool   `json:"active"`
+	Instances      int    `json:"instances"`
+	NanoCPUs       int64  `json:"nano_cpus"`
+	MemoryBytes    int64  `json:"memory_bytes"`
+	ReservedCPUs   int64  `json:"reserved_nano_cpus"`
+	ReservedMemory int64  `json:"reserved_memory_bytes"`
+
+	node swarm.Node
+}
+
+func (n *NodeCapacity) FreeCPUs() int64 {
+	return n.NanoCPUs - n.ReservedCPUs
+}
+
+func (n *NodeCapacity) FreeMemory() int64 {
+	return n.MemoryBytes - n.ReservedMemory
+}
+
+// Schedulable reports whether the node can host a new instance
+func (n *NodeCapacity) Schedulable() bool {
+	return n.Ready && n.Active
+}
+
+// FetchNodesCapacity returns the resources of every node and the ones
+// reserved by the instances scheduled on it
+func FetchNodesCapacity(ctx context.Context) ([]*NodeCapacity, error) {
+	if containers.Cli == nil {
+		return nil, nil
+	}
+
+	nodes, err := containers.Cli.NodeList(ctx, swarm.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := FetchInstanceServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := make([]*NodeCapacity, 0, len(nodes))
+	byID := make(map[string]*NodeCapacity, len(nodes))
+	for _, node := range nodes {
+		host := node.Spec.Labels[consts.LabelNodeHost]
+		n := &NodeCapacity{
+			ID:          node.ID,
+			Hostname:    node.Description.Hostname,
+			Host:        host,
+			Ready:       node.Status.State == swarm.NodeStateReady,
+			Active:      node.Spec.Availability == swarm.NodeAvailabilityActive,
+			NanoCPUs:    node.Description.Resources.NanoCPUs,
+			MemoryBytes: node.Description.Resources.MemoryBytes,
+			node:        node,
+		}
+		capacity = append(capacity, n)
+		byID[node.ID] = n
+	}
+
+	for _, s := range services {
+		n, ok := byID[s.Spec.Labels[consts.LabelNode]]
+		if !ok {
+			continue
+		}
+		n.Instances++
+
+		resources := s.Spec.TaskTemplate.Resources
+		if resources == nil || resources.Reservations == nil {
+			continue
+		}
+		n.ReservedCPUs += resources.Reservations.NanoCPUs
+		n.ReservedMemory += resources.Reservations.MemoryBytes
+	}
+
+	return capacity, nil
+}
+
+// FetchRemoteNodeAddrs returns the addresses of the ready Swarm nodes other
+// than the local one, whose tasks the local daemon can't sample
+func FetchRemoteNodeAddrs(ctx context.Context) ([]string, error) {
+	if containers.Cli == nil {
+		return nil, nil
+	}
+
+	info, err := containers.Cli.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := containers.Cli.NodeList(ctx, swarm.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, node := range nodes {
+		if node.ID == info.Swarm.NodeID || node.Status.State != swarm.NodeStateReady || node.Status.Addr == "" {
+			continue
+		}
+		addrs = append(addrs, node.Status.Addr)
+	}
+
+	return addrs, nil
+}
+
+// MatchConstraints evaluates the placement constraints supported by Swarm
+// (e.g. node.labels.zone==eu) against the node. Unknown attributes are left
+// to the Swarm scheduler.
+func (n *NodeCapacity) MatchConstraints(constraints []string) bool {
+	for _, constraint := range constraints {
+		key, value, equal, ok := parseConstraint(constraint)
+		if !ok {
+			continue
+		}
+
+		actual, found, known := n.attribute(key)
+		if !known {
+			continue
+		}
+
+		matches := found && strings.EqualFold(actual, value)
+		if matches != equal {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseConstraint(constraint string) (string, string, bool, bool) {
+	if key, value, ok := strings.Cut(constraint, "=="); ok {
+		return strings.TrimSpace(key), strings.TrimSpace(value), true, true
+	}
+	if key, value, ok := strings.Cut(constraint, "!="); ok {
+		return strings.TrimSpace(key), strings.TrimSpace(value), false, true
+	}
+	return "", "", false, false
+}
+
+// attribute returns the value of the node attribute, whether it is set and
+// whether the attribute is known
+func (n *NodeCapacity) attribute(key string) (string, bool, bool) {
+	switch key {
+	case "node.id":
+		return n.node.ID, true, true
+	case "node.hostname":
+		return n.node.Description.Hostname, true, true
+	case "node.role":
+		return string(n.node.Spec.Role), true, true
+	case "node.platform.os":
+		return n.node.Description.Platform.OS, true, true
+	case "node.platform.arch":
+		return n.node.Description.Platform.Architecture, true, true
+	}
+
+	if label, ok := strings.CutPrefix(key, "node.labels."); ok {
+		value, found := n.node.Spec.Labels[label]
+		return value, found, true
+	}
+	if label, ok := strings.CutPrefix(key, "engine.labels."); ok {
+		value, found := n.node.Description.Engine.Labels[label]
+		return value, found, true
+	}
+
+	return "", false, false
+}
@@ -0,0 +1,123 @@
+package instancer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/instancer/services"
+)
+
+func SwarmEnabled(ctx context.Context) (bool, error) {
+	conf, err := db.GetConfig(ctx, "swarm-mode")
+	if err != nil {
+		return false, err
+	}
+
+	return conf == "true", nil
+}
+
+// NodesCapacity returns the capacity of the Swarm nodes, nil when the Swarm
+// mode is disabled
+func NodesCapacity(ctx context.Context) ([]*services.NodeCapacity, error) {
+	swarm, err := SwarmEnabled(ctx)
+	if err != nil || !swarm {
+		return nil, err
+	}
+
+	return services.FetchNodesCapacity(ctx)
+}
+
+// parseCPUs converts the CPUs reservation of a challenge to nano CPUs
+func parseCPUs(cpus string) (int64, error) {
+	if cpus == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(cpus, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(value * 1e9), nil
+}
+
+// addPendingReservations accounts the instances scheduled on the nodes whose
+// service is not created yet, so they are not seen by Docker
+func addPendingReservations(nodes []*services.NodeCapacity, pending []sqlc.GetPendingNodeReservationsRow) error {
+	byID := make(map[string]*services.NodeCapacity, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+
+	for _, reservation := range pending {
+		node, ok := byID[reservation.Node]
+		if !ok {
+			continue
+		}
+
+		cpus, err := parseCPUs(reservation.ReservedCpu)
+		if err != nil {
+			return err
+		}
+
+		node.Instances++
+		node.ReservedMemory += int64(reservation.ReservedMemory) * 1024 * 1024
+		node.ReservedCPUs += cpus
+	}
+
+	return nil
+}
+
+// selectNode chooses the schedulable node matching the placement constraints
+// with enough free resources for the reservations, preferring the one with
+// the most free memory
+func selectNode(nodes []*services.NodeCapacity, conf *sqlc.GetDockerConfigsByIDRow) (*services.NodeCapacity, error) {
+	reservedMemory := int64(conf.ReservedMemory) * 1024 * 1024
+	reservedCPUs, err := parseCPUs(conf.ReservedCpu)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *services.NodeCapacity
+	for _, node := range nodes {
+		if !node.Schedulable() || !node.MatchConstraints(conf.Placement) {
+			continue
+		}
+		if node.FreeMemory() < reservedMemory || node.FreeCPUs() < reservedCPUs {
+			continue
+		}
+		if best == nil || node.FreeMemory() > best.FreeMemory() ||
+			(node.FreeMemory() == best.FreeMemory() && node.Instances < best.Instances) {
+			best = node
+		}
+	}
+	if best == nil {
+		return nil, errors.New("[no node available]")
+	}
+
+	return best, nil
+}
+
+// pickNode chooses the node for the instance among the ones reported by
+// Swarm, counting also the instances being created. It must be called holding
+// the scheduling lock until the row of the instance is inserted.
+func pickNode(ctx context.Context, q *sqlc.Queries, conf *sqlc.GetDockerConfigsByIDRow) (*services.NodeCapacity, error) {
+	nodes, err := services.FetchNodesCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := q.GetPendingNodeReservations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addPendingReservations(nodes, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	return selectNode(nodes, conf)
+}
@@ -0,0 +1,84 @@
+package instancer
+
+import (
+	"testing"
+	"trxd/db/sqlc"
+	"trxd/instancer/services"
+)
+
+const gb = 1024 * 1024 * 1024
+
+func testNodes() []*services.NodeCapacity {
+	return []*services.NodeCapacity{
+		{ID: "a", Ready: true, Active: true, NanoCPUs: 4e9, MemoryBytes: 4 * gb},
+		{ID: "b", Ready: true, Active: true, NanoCPUs: 4e9, MemoryBytes: 3 * gb},
+		{ID: "down", Ready: false, Active: true, NanoCPUs: 64e9, MemoryBytes: 64 * gb},
+		{ID: "drained", Ready: true, Active: false, NanoCPUs: 64e9, MemoryBytes: 64 * gb},
+	}
+}
+
+func TestSelectNode(t *testing.T) {
+	conf := &sqlc.GetDockerConfigsByIDRow{ReservedMemory: 1024, ReservedCpu: "1.5"}
+
+	node, err := selectNode(testNodes(), conf)
+	if err != nil || node.ID != "a" {
+		t.Fatalf("Expected the node with the most free memory, got %+v %v", node, err)
+	}
+
+	conf.ReservedMemory = 8 * 1024
+	_, err = selectNode(testNodes(), conf)
+	if err == nil || err.Error() != "[no node available]" {
+		t.Fatalf("Expected no node available, got %v", err)
+	}
+
+	conf = &sqlc.GetDockerConfigsByIDRow{ReservedCpu: "invalid"}
+	_, err = selectNode(testNodes(), conf)
+	if err == nil {
+		t.Fatalf("Expected an error for an invalid CPUs reservation")
+	}
+}
+
+func TestPendingReservationsSpreadInstances(t *testing.T) {
+	conf := &sqlc.GetDockerConfigsByIDRow{ReservedMemory: 1024, ReservedCpu: "1"}
+
+	// Simulates concurrent creations, each seeing the instances scheduled
+	// before it whose service is not running yet
+	var pending []sqlc.GetPendingNodeReservationsRow
+	placed := make(map[string]int)
+	for range 7 {
+		nodes := testNodes()
+		err := addPendingReservations(nodes, pending)
+		if err != nil {
+			t.Fatalf("addPendingReservations failed: %v", err)
+		}
+
+		node, err := selectNode(nodes, conf)
+		if err != nil {
+			t.Fatalf("selectNode failed after %d instances: %v", len(pending), err)
+		}
+		placed[node.ID]++
+		pending = append(pending, sqlc.GetPendingNodeReservationsRow{
+			Node:           node.ID,
+			ReservedMemory: conf.ReservedMemory,
+			ReservedCpu:    conf.ReservedCpu,
+		})
+	}
+
+	if placed["a"] != 4 || placed["b"] != 3 {
+		t.Fatalf("Expected the instances to fill both nodes, got %v", placed)
+	}
+
+	nodes := testNodes()
+	err := addPendingReservations(nodes, pending)
+	if err != nil {
+		t.Fatalf("addPendingReservations failed: %v", err)
+	}
+	if nodes[0].Instances != 4 || nodes[0].FreeMemory() != 0 || nodes[0].FreeCPUs() != 0 {
+		t.Fatalf("Unexpected capacity of node a: %+v", nodes[0])
+	}
+
+	_, err = selectNode(nodes, conf)
+	if err == nil {
+		t.Fatalf("Expected the full nodes to be skipped")
+	}
+}
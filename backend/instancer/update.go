@@ -0,0 +1,153 @@
+package instancer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+
+	"trxd/utils/log"
+)
+
+// ExtensionPolicy limits how long a team can keep an instance alive, a zero
+// value disables the corresponding limit
+type ExtensionPolicy struct {
+	Lifetime      time.Duration
+	MaxLifetime   time.Duration
+	MaxExtensions int
+	Cooldown      time.Duration
+	Window        time.Duration
+}
+
+// ExtensionBudget is what is left of the extension policy of an instance,
+// nil values are unlimited
+type ExtensionBudget struct {
+	ExtensionsLeft *int `json:"extensions_left"`
+	LifetimeLeft   *int `json:"lifetime_left"`  // seconds the expiration can still be moved by
+	NextExtension  int  `json:"next_extension"` // seconds before the next extension is allowed
+}
+
+func seconds(value any) time.Duration {
+	v, _ := value.(int64)
+	return time.Duration(v) * time.Second
+}
+
+func NewExtensionPolicy(conf *sqlc.GetDockerConfigsByIDRow) *ExtensionPolicy {
+	maxExtensions, _ := conf.MaxExtensions.(int64)
+
+	return &ExtensionPolicy{
+		Lifetime:      seconds(conf.Lifetime),
+		MaxLifetime:   seconds(conf.MaxLifetime),
+		MaxExtensions: int(maxExtensions),
+		Cooldown:      seconds(conf.ExtensionCooldown),
+		Window:        seconds(conf.ExtensionWindow),
+	}
+}
+
+// Unlimited returns the policy extending by the same lifetime, without limits
+func (p *ExtensionPolicy) Unlimited() *ExtensionPolicy {
+	return &ExtensionPolicy{Lifetime: p.Lifetime}
+}
+
+// nextExtension returns when the instance can be extended again. The
+// timestamps of the instance are all written from the clock of the backend.
+func (p *ExtensionPolicy) nextExtension(instance *sqlc.Instance) time.Time {
+	var next time.Time
+	if p.Window > 0 {
+		next = instance.ExpiresAt.Add(-p.Window)
+	}
+	if p.Cooldown > 0 && instance.ExtendedAt.Valid {
+		cooldown := instance.ExtendedAt.Time.Add(p.Cooldown)
+		if cooldown.After(next) {
+			next = cooldown
+		}
+	}
+	return next
+}
+
+// Budget returns what is left of the policy for the instance
+func (p *ExtensionPolicy) Budget(instance *sqlc.Instance) *ExtensionBudget {
+	return p.budget(instance, time.Now())
+}
+
+func (p *ExtensionPolicy) budget(instance *sqlc.Instance, now time.Time) *ExtensionBudget {
+	budget := &ExtensionBudget{}
+
+	if p.MaxExtensions > 0 {
+		budget.ExtensionsLeft = new(max(p.MaxExtensions-int(instance.Extensions), 0))
+	}
+	if p.MaxLifetime > 0 {
+		left := instance.CreatedAt.Add(p.MaxLifetime).Sub(instance.ExpiresAt)
+		budget.LifetimeLeft = new(max(int(left.Seconds()), 0))
+	}
+
+	wait := p.nextExtension(instance).Sub(now)
+	budget.NextExtension = max(int(math.Ceil(wait.Seconds())), 0)
+
+	return budget
+}
+
+// expiration checks the policy and returns the new expiration of the instance
+func (p *ExtensionPolicy) expiration(instance *sqlc.Instance, now time.Time) (time.Time, error) {
+	if p.MaxExtensions > 0 && int(instance.Extensions) >= p.MaxExtensions {
+		return time.Time{}, errors.New("[max extensions]")
+	}
+	if p.Window > 0 && instance.ExpiresAt.Sub(now) > p.Window {
+		return time.Time{}, errors.New("[extension window]")
+	}
+	if p.Cooldown > 0 && instance.ExtendedAt.Valid && now.Sub(instance.ExtendedAt.Time) < p.Cooldown {
+		return time.Time{}, errors.New("[extension cooldown]")
+	}
+
+	expiresAt := now.Add(p.Lifetime)
+	if p.MaxLifetime > 0 {
+		limit := instance.CreatedAt.Add(p.MaxLifetime)
+		if !instance.ExpiresAt.Before(limit) {
+			return time.Time{}, errors.New("[max lifetime]")
+		}
+		if expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+
+	return expiresAt, nil
+}
+
+// ExtendInstance moves the expiration of an instance as allowed by the policy,
+// recording the extension in its history. Returns the new expiration and the
+// remaining budget.
+func ExtendInstance(ctx context.Context, instance *sqlc.Instance, policy *ExtensionPolicy) (time.Time, *ExtensionBudget, error) {
+	now := time.Now()
+	expiresAt, err := policy.expiration(instance, now)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	log.Info("Extending instance:", "chall", instance.ChallID, "team", instance.TeamID)
+
+	extensions, err := db.Sql.ExtendInstance(ctx, sqlc.ExtendInstanceParams{
+		TeamID:     instance.TeamID,
+		ChallID:    instance.ChallID,
+		ExpiresAt:  expiresAt,
+		Extensions: instance.Extensions,
+		ExtendedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil, errors.New("[race condition]")
+		}
+		return time.Time{}, nil, err
+	}
+
+	recordEvent(ctx, instance.TeamID, instance.ChallID, sqlc.InstanceEventTypeExtend, nil)
+
+	extended := *instance
+	extended.ExpiresAt = expiresAt
+	extended.Extensions = extensions
+	extended.ExtendedAt = sql.NullTime{Time: now, Valid: true}
+
+	return expiresAt, policy.budget(&extended, now), nil
+}
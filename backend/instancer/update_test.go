@@ -0,0 +1,91 @@
+package instancer
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+)
+
+func TestExtensionPolicy(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	instance := &sqlc.Instance{
+		CreatedAt:  now.Add(-50 * time.Minute),
+		ExpiresAt:  now.Add(4 * time.Minute),
+		Extensions: 2,
+		ExtendedAt: sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+	}
+
+	tests := []struct {
+		name      string
+		policy    ExtensionPolicy
+		expiresAt time.Time
+		err       string
+	}{
+		{name: "no limits", policy: ExtensionPolicy{Lifetime: time.Hour}, expiresAt: now.Add(time.Hour)},
+		{name: "max extensions", policy: ExtensionPolicy{Lifetime: time.Hour, MaxExtensions: 2}, err: "[max extensions]"},
+		{name: "window", policy: ExtensionPolicy{Lifetime: time.Hour, Window: time.Minute}, err: "[extension window]"},
+		{name: "cooldown", policy: ExtensionPolicy{Lifetime: time.Hour, Cooldown: time.Minute}, err: "[extension cooldown]"},
+		{name: "capped", policy: ExtensionPolicy{Lifetime: time.Hour, MaxLifetime: time.Hour}, expiresAt: now.Add(10 * time.Minute)},
+		{name: "max lifetime", policy: ExtensionPolicy{Lifetime: time.Hour, MaxLifetime: 54 * time.Minute}, err: "[max lifetime]"},
+	}
+
+	for _, test := range tests {
+		expiresAt, err := test.policy.expiration(instance, now)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("Expected %s for the %s policy, got %v", test.err, test.name, err)
+			}
+			continue
+		}
+		if err != nil || !expiresAt.Equal(test.expiresAt) {
+			t.Errorf("Unexpected expiration for the %s policy: %s %v", test.name, expiresAt, err)
+		}
+	}
+}
+
+func TestExtensionBudget(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	instance := &sqlc.Instance{
+		CreatedAt:  now.Add(-50 * time.Minute),
+		ExpiresAt:  now.Add(4 * time.Minute),
+		Extensions: 1,
+		ExtendedAt: sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+	}
+
+	budget := (&ExtensionPolicy{}).budget(instance, now)
+	if budget.ExtensionsLeft != nil || budget.LifetimeLeft != nil || budget.NextExtension != 0 {
+		t.Errorf("Expected an unlimited budget, got %+v", budget)
+	}
+
+	policy := &ExtensionPolicy{MaxLifetime: time.Hour, MaxExtensions: 3, Cooldown: time.Minute, Window: time.Minute}
+	budget = policy.budget(instance, now)
+	if budget.ExtensionsLeft == nil || *budget.ExtensionsLeft != 2 {
+		t.Errorf("Expected 2 extensions left, got %v", budget.ExtensionsLeft)
+	}
+	if budget.LifetimeLeft == nil || *budget.LifetimeLeft != 6*60 {
+		t.Errorf("Expected 6 minutes of lifetime left, got %v", budget.LifetimeLeft)
+	}
+	if budget.NextExtension != 3*60 {
+		t.Errorf("Expected the next extension in 3 minutes, got %d", budget.NextExtension)
+	}
+}
+
+func TestUnlimitedExtensionPolicy(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	instance := &sqlc.Instance{
+		CreatedAt:  now.Add(-50 * time.Minute),
+		ExpiresAt:  now.Add(4 * time.Minute),
+		Extensions: 2,
+		ExtendedAt: sql.NullTime{Time: now.Add(-30 * time.Second), Valid: true},
+	}
+
+	policy := &ExtensionPolicy{Lifetime: time.Hour, MaxLifetime: 54 * time.Minute, MaxExtensions: 2, Cooldown: time.Minute, Window: time.Minute}
+	expiresAt, err := policy.Unlimited().expiration(instance, now)
+	if err != nil {
+		t.Fatalf("Expected the unlimited policy to extend, got %v", err)
+	}
+	if !expiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the expiration in the lifetime, got %v", expiresAt)
+	}
+}
@@ -0,0 +1,404 @@
+package instancer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/instancer/containers"
+	"trxd/instancer/services"
+	"trxd/utils/consts"
+
+	"trxd/utils/log"
+
+	"github.com/docker/docker/client"
+	"github.com/lib/pq"
+)
+
+// Number of samples the rolling averages are computed over
+const usageWindow = 20
+
+type instanceKey struct {
+	TeamID  int32
+	ChallID int32
+}
+
+// instanceUsage is the usage of an instance, summed over its containers
+type instanceUsage struct {
+	CPU     float64 // percent of a CPU
+	Memory  float64 // MB
+	Pids    uint64
+	Network float64 // KB/s
+	Rates   bool    // CPU and network were computed from a previous sample
+}
+
+// UsageThresholds are the limits an instance can exceed only for Duration, a
+// zero value disables the corresponding limit
+type UsageThresholds struct {
+	CPU      float64
+	Memory   float64
+	Pids     uint64
+	Network  float64
+	Duration time.Duration
+	AutoKill bool
+}
+
+// Samples of the previous collection, by container ID. Only accessed by the
+// usage loop
+var lastStats = make(map[string]*containers.Stats)
+
+func toInt64(value any) int64 {
+	v, _ := value.(int64)
+	return v
+}
+
+func NewUsageThresholds(conf *sqlc.GetDockerConfigsByIDRow) *UsageThresholds {
+	return &UsageThresholds{
+		CPU:      float64(toInt64(conf.CpuThreshold)),
+		Memory:   float64(toInt64(conf.MemoryThreshold)),
+		Pids:     uint64(toInt64(conf.PidsThreshold)),
+		Network:  float64(toInt64(conf.NetworkThreshold)),
+		Duration: seconds(conf.ThresholdDuration),
+		AutoKill: conf.AutoKill,
+	}
+}
+
+// exceeded returns the resources over their threshold
+func (t *UsageThresholds) exceeded(usage *instanceUsage) []string {
+	var over []string
+	if t.CPU > 0 && usage.CPU > t.CPU {
+		over = append(over, "cpu")
+	}
+	if t.Memory > 0 && usage.Memory > t.Memory {
+		over = append(over, "memory")
+	}
+	if t.Pids > 0 && usage.Pids > t.Pids {
+		over = append(over, "pids")
+	}
+	if t.Network > 0 && usage.Network > t.Network {
+		over = append(over, "network")
+	}
+	return over
+}
+
+// addStats adds the sample of a container to the usage of its instance,
+// computing the rates from its previous sample (nil if it's the first)
+func addStats(usage *instanceUsage, stats *containers.Stats, prev *containers.Stats) {
+	usage.Memory += float64(stats.Memory) / (1 << 20)
+	usage.Pids += stats.Pids
+
+	if prev == nil || !stats.Read.After(prev.Read) ||
+		stats.CPUTotal < prev.CPUTotal || stats.NetBytes < prev.NetBytes {
+		return
+	}
+
+	elapsed := stats.Read.Sub(prev.Read)
+	usage.CPU += float64(stats.CPUTotal-prev.CPUTotal) / float64(elapsed.Nanoseconds()) * 100
+	usage.Network += float64(stats.NetBytes-prev.NetBytes) / 1024 / elapsed.Seconds()
+	usage.Rates = true
+}
+
+// sampleDaemon adds the running instance containers of the daemon of cli to
+// usages, marking them as seen
+func sampleDaemon(ctx context.Context, cli *client.Client, usages map[instanceKey]*instanceUsage,
+	seen map[string]struct{}) error {
+
+	summary, err := containers.FetchInstanceContainersOn(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range summary {
+		if c.State != "running" {
+			continue
+		}
+
+		// Task containers carry the instance labels too, so Swarm instances
+		// are sampled like the others
+		tid, err1 := strconv.Atoi(c.Labels[consts.LabelTeamID])
+		challID, err2 := strconv.Atoi(c.Labels[consts.LabelChallID])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		stats, err := containers.FetchContainerStats(ctx, cli, c.ID)
+		if err != nil {
+			log.Error("Failed to fetch container stats:", "container", c.ID, "err", err)
+			continue
+		}
+		seen[c.ID] = struct{}{}
+
+		key := instanceKey{TeamID: int32(tid), ChallID: int32(challID)}
+		usage, ok := usages[key]
+		if !ok {
+			usage = &instanceUsage{}
+			usages[key] = usage
+		}
+
+		addStats(usage, stats, lastStats[c.ID])
+		lastStats[c.ID] = stats
+	}
+
+	return nil
+}
+
+// nodeClis returns the clients of the other Swarm nodes, whose tasks are not
+// reachable through the local daemon
+func nodeClis(ctx context.Context) ([]*client.Client, error) {
+	swarm, err := SwarmEnabled(ctx)
+	if err != nil || !swarm {
+		return nil, err
+	}
+
+	port, err := getIntConfig(ctx, "swarm-docker-port")
+	if err != nil || port <= 0 {
+		return nil, err
+	}
+
+	addrs, err := services.FetchRemoteNodeAddrs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clis := make([]*client.Client, 0, len(addrs))
+	for _, addr := range addrs {
+		cli, err := containers.NodeCli(addr, port)
+		if err != nil {
+			log.Error("Failed to create node client:", "node", addr, "err", err)
+			continue
+		}
+		clis = append(clis, cli)
+	}
+
+	return clis, nil
+}
+
+// collectUsage samples the running instance containers of this node and, in
+// Swarm mode, of the other nodes
+func collectUsage(ctx context.Context) (map[instanceKey]*instanceUsage, error) {
+	seen := make(map[string]struct{})
+	usages := make(map[instanceKey]*instanceUsage)
+
+	err := sampleDaemon(ctx, containers.Cli, usages, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	clis, err := nodeClis(ctx)
+	if err != nil {
+		log.Error("Failed to fetch the Swarm nodes:", "err", err)
+	}
+	for _, cli := range clis {
+		err := sampleDaemon(ctx, cli, usages, seen)
+		if err != nil {
+			log.Error("Failed to sample Swarm node:", "node", cli.DaemonHost(), "err", err)
+		}
+	}
+
+	for id := range lastStats {
+		if _, ok := seen[id]; !ok {
+			delete(lastStats, id)
+		}
+	}
+
+	return usages, nil
+}
+
+// rollingAverage adds the n-th sample to an average over the last usageWindow
+// samples, approximated as an exponential moving average once the window is full
+func rollingAverage(avg float32, sample float32, n int32) float32 {
+	return avg + (sample-avg)/float32(min(n, usageWindow))
+}
+
+// nextUsage adds a sample to the aggregates of an instance (nil before its
+// first sample), tracking since when it exceeds its thresholds
+func nextUsage(prev *sqlc.InstanceUsage, key instanceKey, usage *instanceUsage,
+	exceeded bool, now time.Time) sqlc.UpdateInstanceUsageParams {
+
+	if prev == nil {
+		prev = &sqlc.InstanceUsage{}
+	}
+
+	sample := sqlc.UpdateInstanceUsageParams{
+		TeamID:    key.TeamID,
+		ChallID:   key.ChallID,
+		Samples:   prev.Samples + 1,
+		Cpu:       float32(usage.CPU),
+		Memory:    float32(usage.Memory),
+		Pids:      int32(min(usage.Pids, math.MaxInt32)),
+		Network:   float32(usage.Network),
+		UpdatedAt: now,
+	}
+
+	sample.CpuAvg = rollingAverage(prev.CpuAvg, sample.Cpu, sample.Samples)
+	sample.CpuMax = max(prev.CpuMax, sample.Cpu)
+	sample.MemoryAvg = rollingAverage(prev.MemoryAvg, sample.Memory, sample.Samples)
+	sample.MemoryMax = max(prev.MemoryMax, sample.Memory)
+	sample.PidsMax = max(prev.PidsMax, sample.Pids)
+	sample.NetworkAvg = rollingAverage(prev.NetworkAvg, sample.Network, sample.Samples)
+	sample.NetworkMax = max(prev.NetworkMax, sample.Network)
+
+	if exceeded {
+		sample.OverSince = prev.OverSince
+		if !sample.OverSince.Valid {
+			sample.OverSince = sql.NullTime{Time: now, Valid: true}
+		}
+	}
+
+	return sample
+}
+
+type usageAction int
+
+const (
+	usageOK usageAction = iota
+	usageFlag
+	usageKill
+)
+
+// action decides what to do with an instance exceeding its thresholds since
+// overSince (NULL if within them)
+func (t *UsageThresholds) action(overSince sql.NullTime, now time.Time) usageAction {
+	if !overSince.Valid || now.Sub(overSince.Time) < t.Duration {
+		return usageOK
+	}
+	if t.AutoKill {
+		return usageKill
+	}
+	return usageFlag
+}
+
+// CheckUsage records the resource usage of the instances, flagging (or
+// killing) the ones exceeding their thresholds for too long. A single replica
+// checks them at a time.
+func CheckUsage(ctx context.Context) error {
+	if containers.Cli == nil {
+		return nil
+	}
+
+	locked, err := runLocked(ctx, lockUsage, func() error {
+		return checkUsage(ctx)
+	})
+	if err == nil && !locked {
+		log.Debug("Skipping usage check, running on another replica")
+	}
+
+	return err
+}
+
+func checkUsage(ctx context.Context) error {
+	usages, err := collectUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	thresholds := make(map[int32]*UsageThresholds)
+	for key, usage := range usages {
+		// The first sample of an instance has no rates yet
+		if !usage.Rates {
+			continue
+		}
+
+		t, ok := thresholds[key.ChallID]
+		if !ok {
+			conf, err := db.GetDockerConfigsByID(ctx, key.ChallID)
+			if err != nil {
+				log.Error("Failed to fetch docker configs:", "chall", key.ChallID, "err", err)
+				continue
+			}
+			if conf == nil {
+				continue
+			}
+			t = NewUsageThresholds(conf)
+			thresholds[key.ChallID] = t
+		}
+
+		err := checkInstanceUsage(ctx, key, usage, t, time.Now())
+		if err != nil {
+			log.Error("Failed to check instance usage:", "team", key.TeamID, "chall", key.ChallID, "err", err)
+		}
+	}
+
+	return nil
+}
+
+func checkInstanceUsage(ctx context.Context, key instanceKey, usage *instanceUsage, t *UsageThresholds, now time.Time) error {
+	over := t.exceeded(usage)
+
+	var prev *sqlc.InstanceUsage
+	row, err := db.Sql.GetInstanceUsage(ctx, sqlc.GetInstanceUsageParams{
+		TeamID:  key.TeamID,
+		ChallID: key.ChallID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	} else {
+		prev = &row
+	}
+
+	sample := nextUsage(prev, key, usage, len(over) > 0, now)
+	err = db.Sql.UpdateInstanceUsage(ctx, sample)
+	if err != nil {
+		// The instance may have been deleted in the meantime
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == consts.PGForeignKeyViolation {
+			return nil
+		}
+		return err
+	}
+
+	cause := fmt.Errorf("[resource abuse] %s", strings.Join(over, ", "))
+	switch t.action(sample.OverSince, now) {
+	case usageFlag:
+		log.Warn("Flagged instance exceeding its thresholds:", "team", key.TeamID, "chall", key.ChallID, "err", cause)
+		return db.Sql.FlagInstanceUsage(ctx, sqlc.FlagInstanceUsageParams{
+			TeamID:  key.TeamID,
+			ChallID: key.ChallID,
+		})
+	case usageKill:
+		instance, err := GetInstance(ctx, key.ChallID, key.TeamID)
+		if err != nil || instance == nil {
+			return err
+		}
+
+		log.Warn("Killing instance exceeding its thresholds:", "team", key.TeamID, "chall", key.ChallID, "err", cause)
+		return deleteInstance(ctx, key.TeamID, key.ChallID, instance.DockerID, sqlc.InstanceEventTypeKill, cause)
+	}
+
+	return nil
+}
+
+func usageLoop(ctx context.Context) {
+	for {
+		sleep, err := getIntervalConfig(ctx, "usage-instance-interval")
+		if err != nil {
+			log.Error("Failed to get usage interval:", "err", err)
+			sleep = time.Minute
+		}
+		disabled := sleep <= 0
+		if disabled {
+			sleep = time.Minute
+		}
+		if !sleepCtx(ctx, sleep, nil) {
+			return
+		}
+		if disabled {
+			continue
+		}
+
+		runSafe("usage loop", func() {
+			err := CheckUsage(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Error("Failed to check instances usage:", "err", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package instancer
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+	"trxd/instancer/containers"
+)
+
+func TestAddStats(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	prev := &containers.Stats{Read: now.Add(-2 * time.Second), CPUTotal: 1e9, NetBytes: 1024}
+	stats := &containers.Stats{Read: now, CPUTotal: 2e9, Memory: 64 << 20, Pids: 3, NetBytes: 5 * 1024}
+
+	usage := &instanceUsage{}
+	addStats(usage, stats, nil)
+	if usage.Rates || usage.CPU != 0 || usage.Memory != 64 || usage.Pids != 3 {
+		t.Errorf("Expected only memory and pids from a first sample, got %+v", usage)
+	}
+
+	usage = &instanceUsage{}
+	addStats(usage, stats, prev)
+	addStats(usage, stats, prev)
+	if !usage.Rates || usage.CPU != 100 || usage.Network != 4 || usage.Memory != 128 || usage.Pids != 6 {
+		t.Errorf("Expected the usage summed over the containers, got %+v", usage)
+	}
+
+	// A restarted container resets its counters
+	usage = &instanceUsage{}
+	addStats(usage, &containers.Stats{Read: now, CPUTotal: 1}, prev)
+	if usage.Rates {
+		t.Errorf("Expected no rates from reset counters, got %+v", usage)
+	}
+}
+
+func TestRollingAverage(t *testing.T) {
+	var avg float32
+	for n := int32(1); n <= usageWindow; n++ {
+		avg = rollingAverage(avg, float32(n), n)
+	}
+	if math.Abs(float64(avg)-(usageWindow+1)/2.0) > 1e-4 {
+		t.Errorf("Expected the mean of the window, got %f", avg)
+	}
+
+	avg = rollingAverage(avg, avg+usageWindow, usageWindow+10)
+	if math.Abs(float64(avg)-(usageWindow+3)/2.0) > 1e-4 {
+		t.Errorf("Expected a sample to weigh 1/%d once the window is full, got %f", usageWindow, avg)
+	}
+}
+
+func TestNextUsage(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := instanceKey{TeamID: 1, ChallID: 2}
+
+	sample := nextUsage(nil, key, &instanceUsage{CPU: 50, Memory: 10, Pids: math.MaxUint64}, true, now)
+	if sample.Samples != 1 || sample.CpuAvg != 50 || sample.CpuMax != 50 || sample.Pids != math.MaxInt32 {
+		t.Errorf("Unexpected first sample: %+v", sample)
+	}
+	if !sample.OverSince.Valid || !sample.OverSince.Time.Equal(now) {
+		t.Errorf("Expected to exceed the thresholds since now, got %v", sample.OverSince)
+	}
+
+	since := sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+	prev := &sqlc.InstanceUsage{Samples: 1, Cpu: 50, CpuAvg: 50, CpuMax: 50, OverSince: since}
+	sample = nextUsage(prev, key, &instanceUsage{CPU: 10}, true, now)
+	if sample.Samples != 2 || sample.CpuAvg != 30 || sample.CpuMax != 50 || sample.OverSince != since {
+		t.Errorf("Unexpected second sample: %+v", sample)
+	}
+
+	sample = nextUsage(prev, key, &instanceUsage{CPU: 10}, false, now)
+	if sample.OverSince.Valid {
+		t.Errorf("Expected a sample within the thresholds to reset them, got %v", sample.OverSince)
+	}
+}
+
+func TestUsageAction(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	since := func(d time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-d), Valid: true}
+	}
+
+	tests := []struct {
+		name      string
+		overSince sql.NullTime
+		autoKill  bool
+		action    usageAction
+	}{
+		{name: "within thresholds", overSince: sql.NullTime{}, action: usageOK},
+		{name: "recently exceeding", overSince: since(time.Minute), action: usageOK},
+		{name: "exceeding", overSince: since(5 * time.Minute), action: usageFlag},
+		{name: "recently exceeding with auto kill", overSince: since(time.Minute), autoKill: true, action: usageOK},
+		{name: "exceeding with auto kill", overSince: since(10 * time.Minute), autoKill: true, action: usageKill},
+	}
+
+	for _, test := range tests {
+		thresholds := &UsageThresholds{Duration: 5 * time.Minute, AutoKill: test.autoKill}
+		if action := thresholds.action(test.overSince, now); action != test.action {
+			t.Errorf("Unexpected action for the %s instance: %d", test.name, action)
+		}
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	thresholds := &UsageThresholds{CPU: 100, Pids: 10}
+	over := thresholds.exceeded(&instanceUsage{CPU: 150, Memory: 1 << 20, Pids: 10, Network: 1 << 20})
+	if len(over) != 1 || over[0] != "cpu" {
+		t.Errorf("Expected only cpu over its threshold, got %v", over)
+	}
+}
@@ -6,14 +6,19 @@ import (
 	"flag"
 	"io/fs"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
 	"trxd/api"
 	"trxd/api/routes/teams_register"
 	"trxd/api/routes/users_register"
 	"trxd/db"
 	"trxd/db/sqlc"
 	"trxd/instancer"
+	"trxd/proxy"
 	"trxd/utils"
+	"trxd/utils/announcements"
 	"trxd/utils/consts"
 	"trxd/utils/crypto_utils"
 	"trxd/utils/log"
@@ -160,6 +165,15 @@ func insertTestData(ctx context.Context) {
 	}
 }
 
+func generateCA(dir string) {
+	err := proxy.GenerateCA(dir)
+	if err != nil {
+		log.Fatal("Error generating the proxy CA", "err", err)
+	}
+
+	log.Info("Proxy CA generated:", "dir", dir)
+}
+
 func parseFlags(ctx context.Context) {
 	var (
 		help               bool
@@ -168,12 +182,14 @@ func parseFlags(ctx context.Context) {
 		toggleRegisterFlag bool
 		flushCacheFlag     bool
 		insertTestDataFlag bool
+		caDir              string
 	)
 	flag.BoolVar(&help, "help", false, "Show help")
 	flag.BoolVar(&h, "h", false, "Show help")
 	flag.BoolVar(&toggleRegisterFlag, "t", false, "Toggle the allow-register config")
 	flag.StringVar(&user, "r", "", "Register a new admin user with 'username:email:password'")
 	flag.BoolVar(&flushCacheFlag, "f", false, "Flush the system cache")
+	flag.StringVar(&caDir, "gen-ca", "", "Generate a self-signed CA for the instances proxy into the given directory")
 	flag.BoolVar(&insertTestDataFlag, "test-data-WARNING-DO-NOT-USE-IN-PRODUCTION", false, "Inserts mocks data into the db")
 	flag.Parse()
 
@@ -188,6 +204,8 @@ func parseFlags(ctx context.Context) {
 		flushCache(ctx)
 	case insertTestDataFlag:
 		insertTestData(ctx)
+	case caDir != "":
+		generateCA(caDir)
 	default:
 		return
 	}
@@ -227,18 +245,39 @@ func main() {
 	}
 	defer db.CloseDBSafe()
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	parseFlags(ctx)
 
-	go instancer.ReclaimLoop()
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		err := instancer.ReclaimLoop(ctx)
+		if err != nil {
+			log.Fatal("Error initializing instancer", "err", err)
+		}
+	})
+	wg.Go(func() {
+		announcements.PublishLoop(ctx)
+	})
+	wg.Go(func() {
+		err := proxy.Serve(ctx)
+		if err != nil {
+			log.Error("Failed to start the instances proxy:", "err", err)
+		}
+	})
 
-	for {
+	for ctx.Err() == nil {
 		log.Info("Starting server")
 
 		app := api.SetupApp(ctx)
+		stopShutdown := context.AfterFunc(ctx, func() { api.Shutdown(app) })
 		err = app.Listen(":1337")
+		stopShutdown()
 		if err != nil {
 			log.Fatal("Error starting server", "err", err)
 		}
 	}
+
+	log.Info("Shutting down, waiting for the instancer and the proxy")
+	wg.Wait()
 }
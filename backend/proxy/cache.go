@@ -0,0 +1,86 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// ttlCache is a map whose entries expire, holding at most size entries: when
+// full, the expired entries are evicted first, then the ones expiring sooner
+type ttlCache[V any] struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry[V]
+}
+
+func newTTLCache[V any](size int) *ttlCache[V] {
+	return &ttlCache[V]{
+		size:    size,
+		entries: make(map[string]cacheEntry[V], size),
+	}
+}
+
+func (c *ttlCache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !time.Now().Before(entry.expires) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+func (c *ttlCache[V]) Set(key string, value V, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		c.evict()
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expires: expires}
+}
+
+func (c *ttlCache[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+func (c *ttlCache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// evict makes room for one entry, must be called holding the lock
+func (c *ttlCache[V]) evict() {
+	now := time.Now()
+	var soonest string
+	var soonestExpires time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if soonest == "" || entry.expires.Before(soonestExpires) {
+			soonest = key
+			soonestExpires = entry.expires
+		}
+	}
+
+	if len(c.entries) >= c.size {
+		delete(c.entries, soonest)
+	}
+}
@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTLCacheExpires(t *testing.T) {
+	cache := newTTLCache[int](4)
+
+	cache.Set("expired", 1, time.Now().Add(-time.Second))
+	cache.Set("valid", 2, time.Now().Add(time.Minute))
+
+	if _, ok := cache.Get("expired"); ok {
+		t.Errorf("Expected the expired entry to be missing")
+	}
+	if v, ok := cache.Get("valid"); !ok || v != 2 {
+		t.Errorf("Unexpected valid entry: %d %v", v, ok)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected the expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestTTLCacheBounded(t *testing.T) {
+	cache := newTTLCache[int](8)
+
+	now := time.Now()
+	for i := range 100 {
+		cache.Set(strconv.Itoa(i), i, now.Add(time.Duration(i+1)*time.Minute))
+	}
+
+	if cache.Len() != 8 {
+		t.Fatalf("Expected 8 entries, got %d", cache.Len())
+	}
+	// The entries expiring sooner are evicted first
+	for i := 92; i < 100; i++ {
+		if v, ok := cache.Get(strconv.Itoa(i)); !ok || v != i {
+			t.Errorf("Expected entry %d to be kept", i)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package proxy
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"trxd/utils/consts"
+)
+
+const (
+	leafCertLifetime = 7 * 24 * time.Hour
+	leafCertRenewal  = 24 * time.Hour
+	caCertLifetime   = 10 * 365 * 24 * time.Hour
+	leafCacheSize    = 1024
+)
+
+// HostFilter reports whether a certificate can be issued for the host
+type HostFilter func(ctx context.Context, host string) (bool, error)
+
+// CertManager serves the static certificate when it matches the requested
+// host, otherwise it issues (and caches) a per-host certificate with the CA,
+// only for the hosts accepted by the filter
+type CertManager struct {
+	static *tls.Certificate
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+
+	allow  HostFilter
+	leaves *ttlCache[*tls.Certificate]
+}
+
+func NewCertManager(certFile, keyFile, caCertFile, caKeyFile string, allow HostFilter) (*CertManager, error) {
+	m := &CertManager{
+		allow:  allow,
+		leaves: newTTLCache[*tls.Certificate](leafCacheSize),
+	}
+
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		m.static = &cert
+	}
+
+	if caCertFile != "" && caKeyFile != "" {
+		ca, err := tls.LoadX509KeyPair(caCertFile, caKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		m.ca, err = x509.ParseCertificate(ca.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := ca.PrivateKey.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported CA private key")
+		}
+		m.caKey = signer
+	}
+
+	if m.static == nil && m.ca == nil {
+		return nil, errors.New("no TLS certificate or CA configured")
+	}
+
+	return m, nil
+}
+
+func (m *CertManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	host := strings.ToLower(hello.ServerName)
+
+	if m.static != nil && (m.ca == nil || hello.SupportsCertificate(m.static) == nil) {
+		return m.static, nil
+	}
+	if host == "" {
+		return nil, errors.New("missing SNI")
+	}
+
+	cert, ok := m.leaves.Get(host)
+	if ok {
+		return cert, nil
+	}
+
+	ctx := hello.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	allowed, err := m.allow(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("unknown host")
+	}
+
+	cert, err = m.issue(host)
+	if err != nil {
+		return nil, err
+	}
+	m.leaves.Set(host, cert, cert.Leaf.NotAfter.Add(-leafCertRenewal))
+
+	return cert, nil
+}
+
+func (m *CertManager) issue(host string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(leafCertLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, m.ca, &key.PublicKey, m.caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der, m.ca.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// GenerateCA writes a self-signed CA (ca.crt and ca.key) into dir, useful to
+// test the proxy locally
+func GenerateCA(dir string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: consts.Name + " Instances CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caCertLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	err = os.WriteFile(filepath.Join(dir, "ca.crt"), certPem, 0o644)
+	if err != nil {
+		return err
+	}
+
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+	err = os.WriteFile(filepath.Join(dir, "ca.key"), keyPem, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
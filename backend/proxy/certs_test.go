@@ -0,0 +1,67 @@
+package proxy_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"trxd/proxy"
+)
+
+func TestCertManagerIssuesWithCA(t *testing.T) {
+	dir := t.TempDir()
+	err := proxy.GenerateCA(dir)
+	if err != nil {
+		t.Fatalf("GenerateCA failed: %v", err)
+	}
+
+	host := "abcdef123456.example.com"
+	allow := func(ctx context.Context, h string) (bool, error) {
+		return h == host, nil
+	}
+	certs, err := proxy.NewCertManager("", "", filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"), allow)
+	if err != nil {
+		t.Fatalf("NewCertManager failed: %v", err)
+	}
+
+	caPem, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		t.Fatalf("Failed to read CA: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		t.Fatal("Failed to parse CA")
+	}
+
+	cert, err := certs.GetCertificate(&tls.ClientHelloInfo{ServerName: host})
+	if err != nil {
+		t.Fatalf("GetCertificate failed: %v", err)
+	}
+
+	_, err = cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool})
+	if err != nil {
+		t.Errorf("Issued certificate does not verify: %v", err)
+	}
+
+	cached, err := certs.GetCertificate(&tls.ClientHelloInfo{ServerName: host})
+	if err != nil {
+		t.Fatalf("GetCertificate failed: %v", err)
+	}
+	if cached != cert {
+		t.Errorf("Expected the cached certificate to be reused")
+	}
+
+	_, err = certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "123456abcdef.example.com"})
+	if err == nil {
+		t.Errorf("Expected no certificate for a host rejected by the filter")
+	}
+}
+
+func TestCertManagerMissingConfig(t *testing.T) {
+	_, err := proxy.NewCertManager("", "", "", "", nil)
+	if err == nil {
+		t.Errorf("Expected an error without certificates")
+	}
+}
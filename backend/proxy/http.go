@@ -0,0 +1,173 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+	"trxd/db/sqlc"
+
+	"trxd/utils/log"
+)
+
+const (
+	TokenParam  = "trxd_token"
+	TokenCookie = "trxd_instance"
+
+	shutdownTimeout = 10 * time.Second
+)
+
+type httpProxy struct {
+	*backend
+}
+
+// ServeHTTP forwards each request to the instance whose hash domain matches
+// the Host header, until ctx is canceled
+func ServeHTTP(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           &httpProxy{newBackend()},
+		ReadHeaderTimeout: handshakeTimeout,
+	}
+	context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Error("HTTP proxy shutdown failed:", "err", err)
+		}
+	})
+
+	log.Info("HTTP proxy listening:", "addr", addr)
+
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func requestHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	return strings.ToLower(host)
+}
+
+func (p *httpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := requestHost(r)
+	instance, err := p.lookup(r.Context(), host)
+	if err != nil {
+		log.Error("HTTP proxy failed to fetch instance:", "host", host, "err", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if instance == nil || instance.ConnType == sqlc.ConnTypeTCP {
+		http.Error(w, "Instance not found", http.StatusNotFound)
+		return
+	}
+
+	if !p.checkAccess(w, r, host, instance) {
+		return
+	}
+
+	target := &url.URL{Scheme: "http", Host: p.upstream(instance.ChallID, instance.TeamID, instancePort(instance))}
+
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			pr.Out.Host = pr.In.Host
+			stripAccessToken(pr.Out)
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Debug("HTTP proxy failed to reach instance:", "host", host, "err", err)
+			http.Error(w, "Instance unreachable", http.StatusBadGateway)
+		},
+	}
+	rp.ServeHTTP(w, r)
+}
+
+// checkAccess accepts the team token either as query parameter, which is
+// then moved into a cookie, or as cookie. Writes the response and returns
+// false when the access is denied.
+func (p *httpProxy) checkAccess(w http.ResponseWriter, r *http.Request, host string, instance *sqlc.GetInstanceByHostRow) bool {
+	restricted, err := p.restricted(r.Context(), instance.TeamOnly)
+	if err != nil {
+		log.Error("HTTP proxy failed to fetch access control config:", "err", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return false
+	}
+	if !restricted {
+		return true
+	}
+
+	if token := r.URL.Query().Get(TokenParam); token != "" {
+		valid, err := p.checkToken(r.Context(), host, instance.TeamID, token)
+		if err != nil {
+			log.Error("HTTP proxy failed to check token:", "err", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return false
+		}
+		if !valid {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return false
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     TokenCookie,
+			Value:    token,
+			Path:     "/",
+			Expires:  instance.ExpiresAt,
+			Secure:   isHTTPS(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		redirect := *r.URL
+		query := redirect.Query()
+		query.Del(TokenParam)
+		redirect.RawQuery = query.Encode()
+		http.Redirect(w, r, redirect.RequestURI(), http.StatusSeeOther)
+		return false
+	}
+
+	cookie, err := r.Cookie(TokenCookie)
+	if err == nil {
+		valid, err := p.checkToken(r.Context(), host, instance.TeamID, cookie.Value)
+		if err != nil {
+			log.Error("HTTP proxy failed to check token:", "err", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return false
+		}
+		if valid {
+			return true
+		}
+	}
+
+	http.Error(w, "Forbidden", http.StatusForbidden)
+	return false
+}
+
+// isHTTPS reports whether the client reached the proxy over TLS, directly or
+// through the TLS terminating proxy in front of it
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// stripAccessToken hides the access cookie from the instance
+func stripAccessToken(r *http.Request) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != TokenCookie {
+			r.AddCookie(cookie)
+		}
+	}
+}
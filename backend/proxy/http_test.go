@@ -0,0 +1,224 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+)
+
+const testHost = "abcdef123456.example.com"
+
+var testSecret = []byte("secret")
+
+// newTestProxy returns a proxy to a test instance owned by team 1, and the
+// last request received by the instance
+func newTestProxy(t *testing.T, restricted bool) (*httpProxy, func() *http.Request) {
+	var upstreamReq *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamReq = r
+		_, _ = w.Write([]byte("instance"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	instance := &sqlc.GetInstanceByHostRow{
+		TeamID:    1,
+		ChallID:   2,
+		ExpiresAt: time.Now().Add(time.Hour),
+		Port:      8080,
+		ConnType:  sqlc.ConnTypeHTTP,
+	}
+
+	p := &httpProxy{&backend{
+		lookup: func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+			if host != testHost {
+				return nil, nil
+			}
+			return instance, nil
+		},
+		restricted: func(ctx context.Context, teamOnly bool) (bool, error) {
+			return restricted, nil
+		},
+		checkToken: func(ctx context.Context, host string, tid int32, token string) (bool, error) {
+			return verifyToken(testSecret, host, tid, token, time.Now()), nil
+		},
+		upstream: func(challID, tid, port int32) string {
+			return strings.TrimPrefix(upstream.URL, "http://")
+		},
+	}}
+
+	return p, func() *http.Request { return upstreamReq }
+}
+
+func serve(p *httpProxy, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, r)
+	return w
+}
+
+func TestHTTPProxyForwards(t *testing.T) {
+	p, upstreamReq := newTestProxy(t, false)
+
+	w := serve(p, "http://unknown.example.com/")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown host, got %d", w.Code)
+	}
+
+	w = serve(p, "http://"+testHost+"/path?x=1", &http.Cookie{Name: "session", Value: "abc"})
+	if w.Code != http.StatusOK || w.Body.String() != "instance" {
+		t.Fatalf("Unexpected response: %d %q", w.Code, w.Body.String())
+	}
+	if upstreamReq().Host != testHost || upstreamReq().URL.RequestURI() != "/path?x=1" {
+		t.Errorf("Unexpected upstream request: %s %s", upstreamReq().Host, upstreamReq().URL.RequestURI())
+	}
+	if cookie, err := upstreamReq().Cookie("session"); err != nil || cookie.Value != "abc" {
+		t.Errorf("Expected the instance cookies to be forwarded")
+	}
+}
+
+func TestHTTPProxyCheckAccess(t *testing.T) {
+	p, upstreamReq := newTestProxy(t, true)
+
+	expiresAt := time.Now().Add(time.Hour)
+	token := signToken(testSecret, testHost, 1, expiresAt)
+	otherTeam := signToken(testSecret, testHost, 3, expiresAt)
+	expired := signToken(testSecret, testHost, 1, time.Now().Add(-time.Second))
+	otherHost := signToken(testSecret, "123456abcdef.example.com", 1, expiresAt)
+
+	if w := serve(p, "http://"+testHost+"/"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without token, got %d", w.Code)
+	}
+	for name, bad := range map[string]string{
+		"other team": otherTeam,
+		"expired":    expired,
+		"other host": otherHost,
+		"malformed":  "abc",
+	} {
+		if w := serve(p, "http://"+testHost+"/?"+TokenParam+"="+url.QueryEscape(bad)); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 with the %s token as parameter, got %d", name, w.Code)
+		}
+		if w := serve(p, "http://"+testHost+"/", &http.Cookie{Name: TokenCookie, Value: bad}); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 with the %s token as cookie, got %d", name, w.Code)
+		}
+	}
+	if upstreamReq() != nil {
+		t.Fatalf("No request must reach the instance without a valid token")
+	}
+
+	w := serve(p, "http://"+testHost+"/path?x=1&"+TokenParam+"="+url.QueryEscape(token))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect with a valid token, got %d", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "/path?x=1" {
+		t.Errorf("Expected the token to be removed from the redirect, got %q", location)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != TokenCookie || cookies[0].Value != token {
+		t.Fatalf("Expected the token cookie, got %+v", cookies)
+	}
+	if !cookies[0].Secure || !cookies[0].HttpOnly {
+		t.Errorf("Expected a secure and HTTP only cookie, got %+v", cookies[0])
+	}
+
+	w = serve(p, "http://"+testHost+"/path", cookies[0], &http.Cookie{Name: "session", Value: "abc"})
+	if w.Code != http.StatusOK || w.Body.String() != "instance" {
+		t.Fatalf("Unexpected response with the token cookie: %d %q", w.Code, w.Body.String())
+	}
+	if _, err := upstreamReq().Cookie(TokenCookie); err == nil {
+		t.Errorf("The token cookie must not reach the instance")
+	}
+	if cookie, err := upstreamReq().Cookie("session"); err != nil || cookie.Value != "abc" {
+		t.Errorf("Expected the instance cookies to be forwarded")
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	now := time.Now()
+	token := signToken(testSecret, testHost, 1, now.Add(time.Minute))
+
+	if !verifyToken(testSecret, testHost, 1, token, now) {
+		t.Errorf("Expected the token to be valid")
+	}
+	if verifyToken(testSecret, testHost, 1, token, now.Add(2*time.Minute)) {
+		t.Errorf("Expected the token to expire")
+	}
+	if verifyToken([]byte("other"), testHost, 1, token, now) {
+		t.Errorf("Expected the token to be bound to the secret")
+	}
+
+	// Moving the expiration forward invalidates the signature
+	_, signature, _ := strings.Cut(token, ".")
+	forged := "7fffffff." + signature
+	if verifyToken(testSecret, testHost, 1, forged, now) {
+		t.Errorf("Expected a forged expiration to be rejected")
+	}
+}
+
+func TestHTTPProxyTeamOnly(t *testing.T) {
+	p, upstreamReq := newTestProxy(t, false)
+	lookup := p.lookup
+	p.lookup = func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+		instance, err := lookup(ctx, host)
+		if instance != nil {
+			teamOnly := *instance
+			teamOnly.TeamOnly = true
+			instance = &teamOnly
+		}
+		return instance, err
+	}
+	// Team only instances are restricted regardless of the global access control
+	p.restricted = isRestricted
+
+	otherTeam := signToken(testSecret, testHost, 3, time.Now().Add(time.Hour))
+	if w := serve(p, "http://"+testHost+"/"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without token, got %d", w.Code)
+	}
+	if w := serve(p, "http://"+testHost+"/", &http.Cookie{Name: TokenCookie, Value: otherTeam}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another team, got %d", w.Code)
+	}
+	if upstreamReq() != nil {
+		t.Fatalf("No request of another team must reach the instance")
+	}
+
+	token := signToken(testSecret, testHost, 1, time.Now().Add(time.Hour))
+	if w := serve(p, "http://"+testHost+"/", &http.Cookie{Name: TokenCookie, Value: token}); w.Code != http.StatusOK {
+		t.Errorf("Expected the owning team to reach the instance, got %d", w.Code)
+	}
+}
+
+func TestHTTPProxyDefaultPort(t *testing.T) {
+	p, _ := newTestProxy(t, false)
+	lookup, upstream := p.lookup, p.upstream
+	p.lookup = func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+		instance, err := lookup(ctx, host)
+		if instance != nil {
+			noPort := *instance
+			noPort.Port = 0
+			instance = &noPort
+		}
+		return instance, err
+	}
+	var dialed int32
+	p.upstream = func(challID, tid, port int32) string {
+		dialed = port
+		return upstream(challID, tid, port)
+	}
+
+	if w := serve(p, "http://"+testHost+"/"); w.Code != http.StatusOK {
+		t.Fatalf("Expected an instance without port to be reachable, got %d", w.Code)
+	}
+	if dialed != consts.DefaultInstancePort {
+		t.Errorf("Expected the default port %d, got %d", consts.DefaultInstancePort, dialed)
+	}
+}
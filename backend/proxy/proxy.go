@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"trxd/db"
+	"trxd/db/sqlc"
+
+	"trxd/utils/consts"
+	"trxd/utils/log"
+)
+
+// backend resolves the instances behind the proxies and checks their access
+// tokens through its fields, replaced in the tests
+type backend struct {
+	lookup     func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error)
+	restricted func(ctx context.Context, teamOnly bool) (bool, error)
+	checkToken func(ctx context.Context, host string, tid int32, token string) (bool, error)
+	upstream   func(challID, tid, port int32) string
+}
+
+func newBackend() *backend {
+	return &backend{
+		lookup:     lookupInstance,
+		restricted: isRestricted,
+		checkToken: checkToken,
+		upstream:   instanceAddr,
+	}
+}
+
+// instancePort returns the port the instance listens on, the shared default
+// when the challenge doesn't set one
+func instancePort(instance *sqlc.GetInstanceByHostRow) int32 {
+	if instance.Port == 0 {
+		return consts.DefaultInstancePort
+	}
+	return instance.Port
+}
+
+func getPort(ctx context.Context, key string) (int, error) {
+	conf, err := db.GetConfig(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if conf == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(conf)
+}
+
+func loadCertManager(ctx context.Context) (*CertManager, error) {
+	var paths [4]string
+	for i, key := range []string{"proxy-tls-cert", "proxy-tls-key", "proxy-tls-ca-cert", "proxy-tls-ca-key"} {
+		conf, err := db.GetConfig(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = conf
+	}
+
+	return NewCertManager(paths[0], paths[1], paths[2], paths[3], hasTCPInstance)
+}
+
+// Serve runs the enabled built-in proxies until ctx is canceled
+func Serve(ctx context.Context) error {
+	tcpPort, err := getPort(ctx, "tcp-proxy-port")
+	if err != nil {
+		return err
+	}
+
+	httpPort, err := getPort(ctx, "http-proxy-port")
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	if httpPort != 0 {
+		wg.Go(func() {
+			err := ServeHTTP(ctx, ":"+strconv.Itoa(httpPort))
+			if err != nil {
+				log.Error("HTTP proxy stopped:", "err", err)
+			}
+		})
+	}
+	if tcpPort != 0 {
+		certs, err := loadCertManager(ctx)
+		if err != nil {
+			return err
+		}
+
+		wg.Go(func() {
+			err := ServeTCP(ctx, ":"+strconv.Itoa(tcpPort), certs)
+			if err != nil {
+				log.Error("TCP proxy stopped:", "err", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+)
+
+const (
+	lookupCacheTTL  = 5 * time.Second
+	lookupCacheSize = 4096
+)
+
+// Only the hosts of existing instances are cached, so that requests for
+// random hosts cannot fill it
+var lookupCache = newTTLCache[*sqlc.GetInstanceByHostRow](lookupCacheSize)
+
+func GetInstanceByHost(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+	instance, err := db.Sql.GetInstanceByHost(ctx, host)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &instance, nil
+}
+
+// lookupInstance is GetInstanceByHost with a short lived cache, to avoid a
+// query for every proxied request
+func lookupInstance(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+	if instance, ok := lookupCache.Get(host); ok {
+		return instance, nil
+	}
+
+	instance, err := GetInstanceByHost(ctx, host)
+	if err != nil || instance == nil {
+		return nil, err
+	}
+	lookupCache.Set(host, instance, time.Now().Add(lookupCacheTTL))
+
+	return instance, nil
+}
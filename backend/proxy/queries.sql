@@ -0,0 +1,10 @@
+-- name: GetInstanceByHost :one
+-- Retrieves the hash domain instance reachable at a host
+SELECT i.team_id, i.chall_id, i.expires_at, i.team_only, c.port, c.conn_type
+  FROM instances i
+  JOIN challenges c ON c.id = i.chall_id
+  WHERE i.host = $1 AND i.port IS NULL;
+
+-- name: InitProxySecret :exec
+-- Stores the generated proxy secret, unless another replica stored its own first
+UPDATE configs SET value = $1 WHERE key = 'proxy-secret' AND value = '';
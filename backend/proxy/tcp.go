@@ -0,0 +1,217 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"trxd/db/sqlc"
+	"trxd/instancer"
+
+	"trxd/utils/log"
+)
+
+const (
+	handshakeTimeout = 10 * time.Second
+	passwordTimeout  = 30 * time.Second
+	dialTimeout      = 5 * time.Second
+)
+
+func instanceAddr(challID, tid, port int32) string {
+	return net.JoinHostPort(instancer.InstanceName(challID, tid), strconv.Itoa(int(port)))
+}
+
+// isHash reports whether the label is made of the lowercase hexadecimal
+// characters of the random hash of the instance domains
+func isHash(label string) bool {
+	if label == "" {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'f') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTCPInstance reports whether a hash domain TCP instance is
+// reachable at host, the only hosts the proxy issues certificates for
+func hasTCPInstance(ctx context.Context, host string) (bool, error) {
+	label, _, ok := strings.Cut(host, ".")
+	if !ok || !isHash(label) {
+		return false, nil
+	}
+
+	instance, err := lookupInstance(ctx, host)
+	if err != nil {
+		return false, err
+	}
+
+	return instance != nil && instance.ConnType == sqlc.ConnTypeTCP, nil
+}
+
+// ServeTCP terminates TLS and forwards each connection to the instance whose
+// hash domain matches the SNI, until ctx is canceled
+func ServeTCP(ctx context.Context, addr string, certs *CertManager) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	context.AfterFunc(ctx, func() { _ = ln.Close() })
+
+	tlsConf := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	log.Info("TCP proxy listening:", "addr", addr)
+
+	b := newBackend()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Error("TCP proxy accept failed:", "err", err)
+			continue
+		}
+
+		wg.Go(func() {
+			b.handleTCP(ctx, tls.Server(conn, tlsConf))
+		})
+	}
+}
+
+func (b *backend) handleTCP(ctx context.Context, conn *tls.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	handshakeCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	err := conn.HandshakeContext(handshakeCtx)
+	if err != nil {
+		log.Debug("TCP proxy handshake failed:", "remote", conn.RemoteAddr(), "err", err)
+		return
+	}
+
+	host := strings.ToLower(conn.ConnectionState().ServerName)
+	instance, err := b.lookup(ctx, host)
+	if err != nil {
+		log.Error("TCP proxy failed to fetch instance:", "host", host, "err", err)
+		return
+	}
+	if instance == nil || instance.ConnType != sqlc.ConnTypeTCP {
+		log.Debug("TCP proxy unknown host:", "host", host)
+		return
+	}
+
+	var client net.Conn = conn
+	restricted, err := b.restricted(ctx, instance.TeamOnly)
+	if err != nil {
+		log.Error("TCP proxy failed to fetch access control config:", "err", err)
+		return
+	}
+	if restricted {
+		client, err = b.promptPassword(ctx, conn, host, instance.TeamID)
+		if err != nil {
+			log.Debug("TCP proxy access denied:", "host", host, "remote", conn.RemoteAddr(), "err", err)
+			return
+		}
+	}
+
+	upstream, err := net.DialTimeout("tcp", b.upstream(instance.ChallID, instance.TeamID, instancePort(instance)), dialTimeout)
+	if err != nil {
+		log.Debug("TCP proxy failed to reach instance:", "host", host, "err", err)
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+
+	pipe(ctx, client, upstream)
+}
+
+// bufferedConn keeps the bytes read past the password line
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c *bufferedConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
+
+// promptPassword asks for the instance token before forwarding the connection
+func (b *backend) promptPassword(ctx context.Context, conn net.Conn, host string, tid int32) (net.Conn, error) {
+	err := conn.SetDeadline(time.Now().Add(passwordTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(conn, "Instance password: ")
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReaderSize(conn, 256)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := b.checkToken(ctx, host, tid, strings.TrimSpace(line))
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		_, _ = io.WriteString(conn, "Wrong password\n")
+		return nil, errors.New("wrong password")
+	}
+
+	err = conn.SetDeadline(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// pipe copies data in both directions until one side closes or ctx is canceled
+func pipe(ctx context.Context, a net.Conn, b net.Conn) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = a.Close()
+		_ = b.Close()
+	})
+	defer stop()
+
+	done := make(chan struct{}, 2)
+	cp := func(dst net.Conn, src net.Conn) {
+		_, err := io.Copy(dst, src)
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Debug("TCP proxy copy failed:", "err", err)
+		}
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go cp(a, b)
+	go cp(b, a)
+
+	<-done
+	<-done
+}
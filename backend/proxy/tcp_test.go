@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+)
+
+// newTestTCPProxy returns a proxy to a team only echo instance owned by team 1
+func newTestTCPProxy(t *testing.T) (*backend, *tls.Config) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = echo.Close() })
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	dir := t.TempDir()
+	err = GenerateCA(dir)
+	if err != nil {
+		t.Fatalf("GenerateCA failed: %v", err)
+	}
+	allow := func(ctx context.Context, host string) (bool, error) {
+		return host == testHost, nil
+	}
+	certs, err := NewCertManager("", "", filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"), allow)
+	if err != nil {
+		t.Fatalf("NewCertManager failed: %v", err)
+	}
+
+	b := &backend{
+		lookup: func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+			if host != testHost {
+				return nil, nil
+			}
+			return &sqlc.GetInstanceByHostRow{
+				TeamID:    1,
+				ChallID:   2,
+				ExpiresAt: time.Now().Add(time.Hour),
+				TeamOnly:  true,
+				Port:      1337,
+				ConnType:  sqlc.ConnTypeTCP,
+			}, nil
+		},
+		restricted: isRestricted,
+		checkToken: func(ctx context.Context, host string, tid int32, token string) (bool, error) {
+			return verifyToken(testSecret, host, tid, token, time.Now()), nil
+		},
+		upstream: func(challID, tid, port int32) string {
+			return echo.Addr().String()
+		},
+	}
+
+	return b, &tls.Config{GetCertificate: certs.GetCertificate}
+}
+
+// dialTCP connects to the proxy, answers the password prompt and returns what
+// the instance echoes back
+func dialTCP(t *testing.T, b *backend, serverConf *tls.Config, password string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		b.handleTCP(t.Context(), tls.Server(server, serverConf))
+	}()
+	defer func() { <-done }()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+
+	conn := tls.Client(client, &tls.Config{ServerName: testHost, InsecureSkipVerify: true})
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	prompt := make([]byte, len("Instance password: "))
+	_, err = io.ReadFull(reader, prompt)
+	if err != nil || string(prompt) != "Instance password: " {
+		t.Fatalf("Expected the password prompt, got %q %v", prompt, err)
+	}
+
+	_, err = io.WriteString(conn, password+"\nping\n")
+	if err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func TestTCPProxyTeamOnly(t *testing.T) {
+	b, serverConf := newTestTCPProxy(t)
+
+	otherTeam := signToken(testSecret, testHost, 3, time.Now().Add(time.Hour))
+	if reply := dialTCP(t, b, serverConf, otherTeam); reply != "Wrong password" {
+		t.Errorf("Expected another team to be denied, got %q", reply)
+	}
+
+	token := signToken(testSecret, testHost, 1, time.Now().Add(time.Hour))
+	if reply := dialTCP(t, b, serverConf, token); reply != "ping" {
+		t.Errorf("Expected the owning team to reach the instance, got %q", reply)
+	}
+}
+
+func TestTCPProxyDefaultPort(t *testing.T) {
+	b, serverConf := newTestTCPProxy(t)
+	lookup, upstream := b.lookup, b.upstream
+	b.lookup = func(ctx context.Context, host string) (*sqlc.GetInstanceByHostRow, error) {
+		instance, err := lookup(ctx, host)
+		if instance != nil {
+			instance.Port = 0
+		}
+		return instance, err
+	}
+	var dialed int32
+	b.upstream = func(challID, tid, port int32) string {
+		dialed = port
+		return upstream(challID, tid, port)
+	}
+
+	token := signToken(testSecret, testHost, 1, time.Now().Add(time.Hour))
+	if reply := dialTCP(t, b, serverConf, token); reply != "ping" {
+		t.Errorf("Expected an instance without port to be reachable, got %q", reply)
+	}
+	if dialed != consts.DefaultInstancePort {
+		t.Errorf("Expected the default port %d, got %d", consts.DefaultInstancePort, dialed)
+	}
+}
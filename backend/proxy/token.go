@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"trxd/db"
+	"trxd/instancer"
+	"trxd/utils/crypto_utils"
+)
+
+// Length of the token signature in bytes, kept short to be typed at the TCP
+// prompt
+const tokenLen = 16
+
+func getSecret(ctx context.Context) ([]byte, error) {
+	secret, err := db.GetConfig(ctx, "proxy-secret")
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" {
+		return []byte(secret), nil
+	}
+
+	generated, err := crypto_utils.GeneratePassword()
+	if err != nil {
+		return nil, err
+	}
+	err = db.Sql.InitProxySecret(ctx, generated)
+	if err != nil {
+		return nil, err
+	}
+
+	// Concurrent requests (or replicas) may have stored their secret first
+	config, err := db.GetCompleteConfig(ctx, "proxy-secret")
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || config.Value == "" {
+		return nil, errors.New("proxy secret not stored")
+	}
+
+	return []byte(config.Value), nil
+}
+
+// signToken signs the host of an instance with its team and the expiration of
+// the token, which is prepended to the signature in hexadecimal
+func signToken(secret []byte, host string, tid int32, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 16)
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = fmt.Fprintf(mac, "%s|%d|%s", host, tid, expiry)
+
+	return expiry + "." + hex.EncodeToString(mac.Sum(nil)[:tokenLen])
+}
+
+func verifyToken(secret []byte, host string, tid int32, token string, now time.Time) bool {
+	expiry, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(expiry, 16, 64)
+	if err != nil || now.Unix() >= unix {
+		return false
+	}
+
+	expected := signToken(secret, host, tid, time.Unix(unix, 0))
+	return hmac.Equal([]byte(expected), []byte(token))
+}
+
+// InstanceToken returns the token required by the proxy when the instance is
+// restricted to its team, valid until the instance expires
+func InstanceToken(ctx context.Context, host string, tid int32, expiresAt time.Time) (string, error) {
+	secret, err := getSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return signToken(secret, host, tid, expiresAt), nil
+}
+
+func checkToken(ctx context.Context, host string, tid int32, token string) (bool, error) {
+	secret, err := getSecret(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return verifyToken(secret, host, tid, token, time.Now()), nil
+}
+
+// isRestricted reports whether only the owning team can access the instance,
+// either because of the challenge or because of the global access control
+func isRestricted(ctx context.Context, teamOnly bool) (bool, error) {
+	if teamOnly {
+		return true, nil
+	}
+
+	return instancer.AccessControlEnabled(ctx)
+}
+
+// AccessToken returns the token a team needs to reach its instance until it
+// expires, or an empty string if the instance is not protected by the proxy:
+// instances published on a port are never restricted, as they are created
+// public only while the access control is disabled
+func AccessToken(ctx context.Context, host string, port *int32, tid int32, teamOnly bool, expiresAt time.Time) (string, error) {
+	if port != nil {
+		return "", nil
+	}
+
+	restricted, err := isRestricted(ctx, teamOnly)
+	if err != nil {
+		return "", err
+	}
+	if !restricted {
+		return "", nil
+	}
+
+	return InstanceToken(ctx, host, tid, expiresAt)
+}
@@ -1,6 +1,11 @@
 -- get_random_available_port
 
-CREATE OR REPLACE FUNCTION get_random_available_port_from_range(min_port INTEGER, max_port INTEGER)
+DROP FUNCTION IF EXISTS get_random_available_port_from_range(INTEGER, INTEGER);
+DROP FUNCTION IF EXISTS get_random_available_port();
+
+-- Ports are allocated per node, as Swarm instances publish them on the node
+-- hosting the task (node is empty when not in Swarm mode)
+CREATE OR REPLACE FUNCTION get_random_available_port_from_range(min_port INTEGER, max_port INTEGER, node_id TEXT)
 RETURNS INTEGER AS $$
 DECLARE
   candidate INTEGER;
@@ -9,19 +14,19 @@ BEGIN
 
   SELECT port INTO candidate
     FROM generate_series(min_port, max_port) AS g(port)
-    WHERE port NOT IN (SELECT i.port FROM instances i WHERE i.port IS NOT NULL)
+    WHERE port NOT IN (SELECT i.port FROM instances i WHERE i.port IS NOT NULL AND i.node = node_id)
     ORDER BY random()
     LIMIT 1;
 
   IF candidate IS NULL THEN
-    RAISE EXCEPTION 'No available ports in range % - %', min_port, max_port;
+    RAISE EXCEPTION 'No available ports in range % - % on node "%"', min_port, max_port, node_id;
   END IF;
 
   RETURN candidate;
 END;
 $$ LANGUAGE plpgsql;
 
-CREATE OR REPLACE FUNCTION get_random_available_port()
+CREATE OR REPLACE FUNCTION get_random_available_port(node_id TEXT)
 RETURNS INTEGER AS $$
 DECLARE
   min_port INTEGER;
@@ -29,7 +34,7 @@ DECLARE
 BEGIN
   min_port := CAST((SELECT value FROM configs WHERE key = 'min-port') AS INT);
   max_port := CAST((SELECT value FROM configs WHERE key = 'max-port') AS INT);
-  RETURN get_random_available_port_from_range(min_port, max_port);
+  RETURN get_random_available_port_from_range(min_port, max_port, node_id);
 END;
 $$ LANGUAGE plpgsql;
 
@@ -38,7 +43,11 @@ $$ LANGUAGE plpgsql;
 
 CREATE EXTENSION IF NOT EXISTS pgcrypto;
 
-CREATE OR REPLACE FUNCTION generate_instance_remote(chall_id INTEGER, hash_domain BOOLEAN)
+DROP FUNCTION IF EXISTS generate_instance_remote(INTEGER, BOOLEAN);
+
+-- node_host, if not empty, replaces the challenge host of the instances
+-- published on a port, as they are reachable only through their node
+CREATE OR REPLACE FUNCTION generate_instance_remote(chall_id INTEGER, hash_domain BOOLEAN, node_id TEXT, node_host TEXT)
 RETURNS TABLE(host TEXT, port INTEGER) AS $$
 DECLARE
   len INTEGER;
@@ -55,7 +64,10 @@ BEGIN
     hash := SUBSTRING(encode(gen_random_bytes((len+1)/2),'hex'), 0, len+1);
     host := hash || '.' || host;
   ELSE
-    port := get_random_available_port();
+    IF node_host <> '' THEN
+      host := node_host;
+    END IF;
+    port := get_random_available_port(node_id);
   END IF;
 
   RETURN NEXT;
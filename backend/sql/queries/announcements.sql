@@ -0,0 +1,20 @@
+-- name: ClaimDueAnnouncements :many
+-- Mark as published and retrieve the announcements due for publication,
+-- skipping the ones about hidden challenges and the ones being claimed by
+-- another replica
+UPDATE announcements SET published = TRUE
+WHERE id IN (
+  SELECT a.id FROM announcements a
+  LEFT JOIN challenges c ON c.id = a.chall_id
+  WHERE a.published = FALSE AND a.publish_at <= CURRENT_TIMESTAMP AND c.hidden IS NOT TRUE
+  FOR UPDATE OF a SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkAnnouncementUnpublished :exec
+-- Mark an announcement as not published, to retry its publication
+UPDATE announcements SET published = FALSE WHERE id = $1;
+
+-- name: GetUsersEmails :many
+-- Retrieve the email and the chosen locale of every user
+SELECT email, locale FROM users ORDER BY id;
@@ -0,0 +1,20 @@
+-- name: CreateAuditEntry :exec
+-- Record a mutation made by the staff, keeping the name of the actor
+INSERT INTO audit_log (actor_id, actor_name, ip, action, target, before, after)
+  VALUES ($1, (SELECT name FROM users WHERE id = $1), $2, $3, $4, $5, $6);
+
+-- name: GetChallengeIDByName :one
+-- Retrieve the ID of a challenge by its name
+SELECT id FROM challenges WHERE name = $1;
+
+-- name: GetSubmissionByID :one
+-- Retrieve a submission by its ID
+SELECT * FROM submissions WHERE id = $1;
+
+-- name: GetAnnouncementByID :one
+-- Retrieve an announcement by its ID
+SELECT * FROM announcements WHERE id = $1;
+
+-- name: GetWriteupByID :one
+-- Retrieve a writeup by its ID
+SELECT * FROM writeups WHERE id = $1;
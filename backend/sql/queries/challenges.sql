@@ -11,7 +11,25 @@ SELECT
   envs,
   COALESCE(NULLIF(lifetime, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-lifetime')) AS lifetime,
   COALESCE(NULLIF(max_memory, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-max-memory')) AS max_memory,
-  COALESCE(NULLIF(max_cpu, ''), (SELECT value FROM configs WHERE key='instance-max-cpu')) AS max_cpu
+  COALESCE(NULLIF(max_cpu, ''), (SELECT value FROM configs WHERE key='instance-max-cpu')) AS max_cpu,
+  team_only,
+  COALESCE(NULLIF(pow_difficulty, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-pow-difficulty')) AS pow_difficulty,
+  COALESCE(max_lifetime, (SELECT value::INTEGER FROM configs WHERE key='instance-max-lifetime')) AS max_lifetime,
+  COALESCE(max_extensions, (SELECT value::INTEGER FROM configs WHERE key='instance-max-extensions')) AS max_extensions,
+  COALESCE(extension_cooldown, (SELECT value::INTEGER FROM configs WHERE key='instance-extension-cooldown')) AS extension_cooldown,
+  COALESCE(extension_window, (SELECT value::INTEGER FROM configs WHERE key='instance-extension-window')) AS extension_window,
+  instance_flag,
+  COALESCE(NULLIF(cpu_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-cpu-threshold')) AS cpu_threshold,
+  COALESCE(NULLIF(memory_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-memory-threshold')) AS memory_threshold,
+  COALESCE(NULLIF(pids_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-pids-threshold')) AS pids_threshold,
+  COALESCE(NULLIF(network_threshold, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-network-threshold')) AS network_threshold,
+  COALESCE(NULLIF(threshold_duration, 0), (SELECT value::INTEGER FROM configs WHERE key='instance-threshold-duration')) AS threshold_duration,
+  auto_kill,
+  egress,
+  shared,
+  placement,
+  reserved_memory,
+  reserved_cpu
 FROM docker_configs
 WHERE chall_id = $1;
 
@@ -33,3 +51,20 @@ FROM challenges
 WHERE hidden = FALSE
 GROUP BY category
 ORDER BY category ASC;
+
+-- name: GetOwnerRole :one
+-- Retrieve the role of a user among the owners of a challenge
+SELECT role FROM challenge_owners WHERE chall_id = $1 AND user_id = $2;
+
+-- name: SetChallengeOwner :exec
+-- Add a user to the owners of a challenge, or change their role
+INSERT INTO challenge_owners (chall_id, user_id, role) VALUES ($1, $2, $3)
+  ON CONFLICT (chall_id, user_id) DO UPDATE SET role = EXCLUDED.role;
+
+-- name: GetChallengeOwners :many
+-- Retrieve the owners of a challenge, the Owner first
+SELECT users.id, users.name, challenge_owners.role
+  FROM challenge_owners
+  JOIN users ON users.id = challenge_owners.user_id
+  WHERE challenge_owners.chall_id = $1
+  ORDER BY challenge_owners.role ASC, users.name ASC;
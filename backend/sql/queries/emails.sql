@@ -0,0 +1,3 @@
+-- name: GetEmailTemplate :one
+-- Retrieve the override of an email template in a locale
+SELECT * FROM email_templates WHERE name = $1 AND locale = $2;
@@ -0,0 +1,3 @@
+-- name: GetRole :one
+-- Retrieve a custom role by its name
+SELECT * FROM roles WHERE name = $1;
@@ -0,0 +1,25 @@
+-- name: GetTicket :one
+-- Retrieve a ticket with the name of its challenge
+SELECT tickets.id, tickets.team_id, teams.name AS team_name,
+    tickets.chall_id, challenges.name AS chall_name,
+    tickets.title, tickets.status, tickets.created_at, tickets.updated_at
+  FROM tickets
+  JOIN teams ON teams.id = tickets.team_id
+  LEFT JOIN challenges ON challenges.id = tickets.chall_id
+  WHERE tickets.id = $1;
+
+-- name: CreateTicketMessage :one
+-- Add a message to a ticket, reopening it for the teams and answering it for the organizers
+WITH ticket AS (
+  UPDATE tickets
+    SET status = CASE WHEN sqlc.arg('staff')::BOOLEAN THEN 'Answered'::ticket_status ELSE 'Open'::ticket_status END,
+      updated_at = CURRENT_TIMESTAMP
+    WHERE id = sqlc.arg('ticket_id')
+)
+INSERT INTO ticket_messages (ticket_id, user_id, staff, body, attachments)
+  VALUES (sqlc.arg('ticket_id'), sqlc.arg('user_id'), sqlc.arg('staff'), sqlc.arg('body'), sqlc.arg('attachments'))
+  RETURNING id;
+
+-- name: GetTeamEmails :many
+-- Retrieve the addresses of the members of a team, with their locale
+SELECT email, locale FROM users WHERE team_id = $1;
@@ -30,6 +30,56 @@ CREATE TYPE conn_type AS ENUM (
   'HTTPS'
 );
 
+CREATE TYPE deployment_status AS ENUM (
+  'Pending',
+  'Running',
+  'Failed'
+);
+
+CREATE TYPE egress_policy AS ENUM (
+  'None',
+  'DNS',
+  'Full'
+);
+
+CREATE TYPE instance_event_type AS ENUM (
+  'Create',
+  'Extend',
+  'Delete',
+  'Expire',
+  'Kill',
+  'Failure'
+);
+
+CREATE TYPE email_template_name AS ENUM (
+  'Verification',
+  'PasswordReset',
+  'Announcement',
+  'TicketReply'
+);
+
+CREATE TYPE announcement_priority AS ENUM (
+  'Low',
+  'Normal',
+  'High'
+);
+
+CREATE TYPE feedback_vote AS ENUM (
+  'Like',
+  'Dislike'
+);
+
+CREATE TYPE ticket_status AS ENUM (
+  'Open',
+  'Answered',
+  'Closed'
+);
+
+CREATE TYPE owner_role AS ENUM (
+  'Owner',
+  'CoOwner'
+);
+
 CREATE TABLE IF NOT EXISTS configs (
   key TEXT NOT NULL,
   type TEXT NOT NULL DEFAULT 'string',
@@ -41,6 +91,16 @@ CREATE TABLE IF NOT EXISTS configs (
   PRIMARY KEY(key)
 );
 
+-- Overrides of the default email templates, edited by the admins
+CREATE TABLE IF NOT EXISTS email_templates (
+  name email_template_name NOT NULL,
+  locale VARCHAR(8) NOT NULL,
+  subject TEXT NOT NULL,
+  html TEXT NOT NULL,
+  text TEXT NOT NULL,
+  PRIMARY KEY(name, locale)
+);
+
 CREATE TABLE IF NOT EXISTS teams (
   id SERIAL NOT NULL,
   name VARCHAR(64) UNIQUE NOT NULL,
@@ -51,6 +111,14 @@ CREATE TABLE IF NOT EXISTS teams (
   PRIMARY KEY(id)
 );
 
+-- Custom roles bundling permissions, granted on top of the ones of the user role
+CREATE TABLE IF NOT EXISTS roles (
+  name VARCHAR(32) NOT NULL,
+  description VARCHAR(1024) NOT NULL DEFAULT '',
+  permissions VARCHAR(32)[] NOT NULL DEFAULT '{}',
+  PRIMARY KEY(name)
+);
+
 CREATE TABLE IF NOT EXISTS users (
   id SERIAL NOT NULL,
   name VARCHAR(64) UNIQUE NOT NULL,
@@ -64,8 +132,11 @@ CREATE TABLE IF NOT EXISTS users (
   team_id INTEGER,
 
   country VARCHAR(3),
+  locale VARCHAR(8),
+  custom_role VARCHAR(32),
 
   FOREIGN KEY(team_id) REFERENCES teams(id),
+  FOREIGN KEY(custom_role) REFERENCES roles(name) ON UPDATE CASCADE ON DELETE SET NULL,
   PRIMARY KEY(id)
 );
 
@@ -124,6 +195,24 @@ CREATE TABLE IF NOT EXISTS docker_configs (
   envs TEXT NOT NULL DEFAULT '', -- Environment variables in JSON format
   max_memory INTEGER NOT NULL DEFAULT 0, -- Memory in MB (e.g., '512' for 512 MB)
   max_cpu VARCHAR(16) NOT NULL DEFAULT '', -- CPUs as float (e.g., '1.5' for 1.5 CPUs)
+  team_only BOOLEAN NOT NULL DEFAULT FALSE, -- Only the owning team can access the instance (through the built-in proxy)
+  pow_difficulty INTEGER NOT NULL DEFAULT 0, -- Proof-of-work difficulty in bits (0 to use the global one)
+  max_lifetime INTEGER, -- Maximum total lifetime in seconds, extensions included (NULL to use the global one, 0 for no limit)
+  max_extensions INTEGER, -- Maximum number of extensions (NULL to use the global one, 0 for no limit)
+  extension_cooldown INTEGER, -- Seconds between two extensions (NULL to use the global one, 0 for no limit)
+  extension_window INTEGER, -- Extensions are allowed only in the last seconds of the instance (NULL to use the global one, 0 for no limit)
+  instance_flag BOOLEAN NOT NULL DEFAULT FALSE, -- A random flag is generated per instance, injected as FLAG and bound to the team
+  cpu_threshold INTEGER NOT NULL DEFAULT 0, -- Usage in percent of a CPU tolerated (0 to use the global one)
+  memory_threshold INTEGER NOT NULL DEFAULT 0, -- Memory in MB tolerated (0 to use the global one)
+  pids_threshold INTEGER NOT NULL DEFAULT 0, -- Processes tolerated (0 to use the global one)
+  network_threshold INTEGER NOT NULL DEFAULT 0, -- Network traffic in KB/s tolerated (0 to use the global one)
+  threshold_duration INTEGER NOT NULL DEFAULT 0, -- Seconds an instance can exceed the thresholds before being flagged (0 to use the global one)
+  auto_kill BOOLEAN NOT NULL DEFAULT FALSE, -- Instances exceeding the thresholds are killed instead of flagged
+  egress egress_policy NOT NULL DEFAULT 'Full', -- Outbound traffic allowed from the private network of compose instances
+  shared BOOLEAN NOT NULL DEFAULT FALSE, -- A single deployment managed by the platform instead of per-team instances
+  placement TEXT[] NOT NULL DEFAULT '{}', -- Swarm placement constraints (e.g. 'node.labels.zone==eu')
+  reserved_memory INTEGER NOT NULL DEFAULT 0, -- Swarm memory reservation in MB
+  reserved_cpu VARCHAR(16) NOT NULL DEFAULT '', -- Swarm CPUs reservation as float
   FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
   PRIMARY KEY(chall_id)
 );
@@ -136,6 +225,16 @@ CREATE TABLE IF NOT EXISTS attachments (
   PRIMARY KEY(chall_id, name)
 );
 
+-- Authors allowed to manage a challenge, the Owner also chooses the co-owners
+CREATE TABLE IF NOT EXISTS challenge_owners (
+  chall_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  role owner_role NOT NULL DEFAULT 'CoOwner',
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  PRIMARY KEY(chall_id, user_id)
+);
+
 CREATE TABLE IF NOT EXISTS flags (
   flag VARCHAR(256) UNIQUE NOT NULL,
   chall_id INTEGER NOT NULL,
@@ -149,13 +248,82 @@ CREATE TABLE IF NOT EXISTS instances (
   chall_id INTEGER NOT NULL,
   expires_at TIMESTAMP NOT NULL,
   host TEXT NOT NULL,
-  port INTEGER UNIQUE CHECK (port >= 0 AND port <= 65535),
-  docker_id VARCHAR(64), -- Docker instance ID (container ID or compose project name)
+  port INTEGER CHECK (port >= 0 AND port <= 65535),
+  docker_id VARCHAR(64), -- Docker instance ID (container ID, service ID or compose project name)
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  team_only BOOLEAN NOT NULL DEFAULT FALSE,
+  node VARCHAR(64) NOT NULL DEFAULT '', -- Swarm node ID hosting the instance (empty if not in Swarm mode)
+  extensions INTEGER NOT NULL DEFAULT 0,
+  extended_at TIMESTAMP,
+  UNIQUE(node, port),
   FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
   FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
   PRIMARY KEY(team_id, chall_id)
 );
 
+CREATE TABLE IF NOT EXISTS instance_secrets (
+  id SERIAL NOT NULL,
+  team_id INTEGER NOT NULL,
+  chall_id INTEGER NOT NULL,
+  name VARCHAR(64) NOT NULL, -- Template value that rendered the secret (e.g. 'Flag')
+  value TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS instance_usage (
+  team_id INTEGER NOT NULL,
+  chall_id INTEGER NOT NULL,
+  samples INTEGER NOT NULL DEFAULT 0,
+  cpu REAL NOT NULL DEFAULT 0, -- Percent of a CPU
+  cpu_avg REAL NOT NULL DEFAULT 0,
+  cpu_max REAL NOT NULL DEFAULT 0,
+  memory REAL NOT NULL DEFAULT 0, -- MB
+  memory_avg REAL NOT NULL DEFAULT 0,
+  memory_max REAL NOT NULL DEFAULT 0,
+  pids INTEGER NOT NULL DEFAULT 0,
+  pids_max INTEGER NOT NULL DEFAULT 0,
+  network REAL NOT NULL DEFAULT 0, -- KB/s, received and sent
+  network_avg REAL NOT NULL DEFAULT 0,
+  network_max REAL NOT NULL DEFAULT 0,
+  over_since TIMESTAMP, -- Since when the instance exceeds its thresholds
+  flagged BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(team_id, chall_id) REFERENCES instances(team_id, chall_id) ON DELETE CASCADE,
+  PRIMARY KEY(team_id, chall_id)
+);
+
+CREATE TABLE IF NOT EXISTS deployments (
+  chall_id INTEGER NOT NULL,
+  docker_id VARCHAR(64), -- Docker deployment ID (container ID or compose project name)
+  host TEXT NOT NULL DEFAULT '', -- Host the deployment is reachable at
+  config_hash VARCHAR(64) NOT NULL DEFAULT '', -- Hash of the configuration the deployment was created with
+  status deployment_status NOT NULL DEFAULT 'Pending',
+  error TEXT NOT NULL DEFAULT '',
+  restarts INTEGER NOT NULL DEFAULT 0,
+  failures INTEGER NOT NULL DEFAULT 0, -- Consecutive failed attempts, delaying the next one
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  PRIMARY KEY(chall_id)
+);
+
+CREATE TABLE IF NOT EXISTS instance_events (
+  id SERIAL NOT NULL,
+  team_id INTEGER, -- NULL once the team is deleted, the history is kept
+  chall_id INTEGER, -- NULL once the challenge is deleted, the history is kept
+  type instance_event_type NOT NULL,
+  duration INTEGER NOT NULL DEFAULT 0, -- Seconds granted (Create, Extend) or lived (Delete, Expire, Kill, Failure)
+  host TEXT NOT NULL DEFAULT '',
+  port INTEGER,
+  error TEXT NOT NULL DEFAULT '',
+  timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE SET NULL,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE SET NULL,
+  PRIMARY KEY(id)
+);
+
 CREATE TABLE IF NOT EXISTS submissions (
   id SERIAL NOT NULL,
   user_id INTEGER NOT NULL,
@@ -164,8 +332,98 @@ CREATE TABLE IF NOT EXISTS submissions (
   first_blood BOOLEAN NOT NULL DEFAULT FALSE,
   flag TEXT NOT NULL,
   timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  flag_owner INTEGER, -- Team owning the submitted instance flag, when it is not the submitter's one (flagged for review)
   FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(flag_owner) REFERENCES teams(id) ON DELETE SET NULL,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS announcements (
+  id SERIAL NOT NULL,
+  title VARCHAR(128) NOT NULL,
+  body TEXT NOT NULL, -- Markdown
+  priority announcement_priority NOT NULL DEFAULT 'Normal',
+  chall_id INTEGER, -- Challenge the announcement is about, if any
+  publish_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Hidden until then
+  email BOOLEAN NOT NULL DEFAULT FALSE, -- Emailed to all the users when published
+  published BOOLEAN NOT NULL DEFAULT FALSE, -- Pushed to the notifiers
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE SET NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS challenge_feedbacks (
+  user_id INTEGER NOT NULL,
+  chall_id INTEGER NOT NULL,
+  vote feedback_vote,
+  difficulty INTEGER, -- From 1 (easy) to 5 (hard)
+  comment TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  PRIMARY KEY(user_id, chall_id)
+);
+
+CREATE TABLE IF NOT EXISTS writeups (
+  id SERIAL NOT NULL,
+  chall_id INTEGER NOT NULL,
+  team_id INTEGER, -- NULL for the official writeups of the authors
+  user_id INTEGER, -- Who submitted it
+  content TEXT NOT NULL DEFAULT '', -- Markdown
+  url TEXT NOT NULL DEFAULT '', -- External link, instead of the content
+  accepted BOOLEAN NOT NULL DEFAULT FALSE, -- Marked as good by the authors
+  published BOOLEAN NOT NULL DEFAULT FALSE, -- Visible before the end of the CTF
+  hidden BOOLEAN NOT NULL DEFAULT FALSE, -- Removed by the admins
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE CASCADE,
+  FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE SET NULL,
+  UNIQUE(team_id, chall_id),
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS tickets (
+  id SERIAL NOT NULL,
+  team_id INTEGER NOT NULL,
+  chall_id INTEGER, -- Challenge the ticket is about, if any
+  title VARCHAR(128) NOT NULL,
+  status ticket_status NOT NULL DEFAULT 'Open',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(team_id) REFERENCES teams(id) ON DELETE CASCADE,
+  FOREIGN KEY(chall_id) REFERENCES challenges(id) ON DELETE SET NULL,
+  PRIMARY KEY(id)
+);
+
+CREATE TABLE IF NOT EXISTS ticket_messages (
+  id SERIAL NOT NULL,
+  ticket_id INTEGER NOT NULL,
+  user_id INTEGER,
+  staff BOOLEAN NOT NULL DEFAULT FALSE, -- Sent by an author or an admin
+  body TEXT NOT NULL, -- Markdown
+  attachments TEXT[] NOT NULL DEFAULT '{}', -- {file_hash}/{file_name} under attachments/tickets/{ticket_id}
+  timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE SET NULL,
+  PRIMARY KEY(id)
+);
+
+-- Mutations made by the staff, each with the fields it changed
+CREATE TABLE IF NOT EXISTS audit_log (
+  id SERIAL NOT NULL,
+  actor_id INTEGER, -- NULL once the user is deleted
+  actor_name VARCHAR(64) NOT NULL,
+  ip VARCHAR(64) NOT NULL DEFAULT '',
+  action VARCHAR(64) NOT NULL, -- Method and path of the endpoint, e.g. PATCH /api/flags
+  target TEXT NOT NULL DEFAULT '', -- e.g. challenge:1
+  before JSONB NOT NULL DEFAULT 'null', -- The changed fields before the mutation, null if created
+  after JSONB NOT NULL DEFAULT 'null', -- The changed fields after the mutation, null if deleted
+  timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL,
   PRIMARY KEY(id)
 );
 
@@ -174,5 +432,30 @@ CREATE INDEX IF NOT EXISTS idx_teams_name ON teams(name);
 CREATE INDEX IF NOT EXISTS idx_users_team_id ON users(team_id);
 CREATE INDEX IF NOT EXISTS idx_challenges_category ON challenges(category);
 CREATE INDEX IF NOT EXISTS idx_attachments_chall_id ON attachments(chall_id);
+CREATE INDEX IF NOT EXISTS idx_challenge_owners_user_id ON challenge_owners(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_challenge_owners_owner ON challenge_owners(chall_id) WHERE role = 'Owner';
 CREATE INDEX IF NOT EXISTS idx_submissions_user_id ON submissions(user_id);
 CREATE INDEX IF NOT EXISTS idx_submissions_chall_id ON submissions(chall_id);
+CREATE INDEX IF NOT EXISTS idx_instance_secrets_team_chall ON instance_secrets(team_id, chall_id);
+CREATE INDEX IF NOT EXISTS idx_instance_secrets_chall_value ON instance_secrets(chall_id, value);
+CREATE INDEX IF NOT EXISTS idx_instance_events_team_id ON instance_events(team_id);
+CREATE INDEX IF NOT EXISTS idx_instance_events_chall_id ON instance_events(chall_id);
+CREATE INDEX IF NOT EXISTS idx_announcements_publish_at ON announcements(publish_at);
+CREATE INDEX IF NOT EXISTS idx_challenge_feedbacks_chall_id ON challenge_feedbacks(chall_id);
+CREATE INDEX IF NOT EXISTS idx_writeups_chall_id ON writeups(chall_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_team_id ON tickets(team_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_messages_ticket_id ON ticket_messages(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target);
+
+-- Links the challenges to the accounts named in their authors, the first one
+-- as Owner, when they have no owners yet. Databases created before the owners
+-- existed need it run once by hand, after creating challenge_owners
+INSERT INTO challenge_owners (chall_id, user_id, role)
+  SELECT c.id, u.id,
+      CASE WHEN ROW_NUMBER() OVER (PARTITION BY c.id ORDER BY a.pos) = 1
+        THEN 'Owner'::owner_role ELSE 'CoOwner'::owner_role END
+    FROM challenges c
+    CROSS JOIN LATERAL unnest(c.authors) WITH ORDINALITY AS a(name, pos)
+    JOIN users u ON u.name = a.name
+    WHERE NOT EXISTS (SELECT 1 FROM challenge_owners o WHERE o.chall_id = c.id)
+  ON CONFLICT DO NOTHING;
@@ -13,18 +13,31 @@ $$ LANGUAGE plpgsql;
 CREATE OR REPLACE FUNCTION delete_all()
 RETURNS VOID AS $$
 BEGIN
+  DELETE FROM audit_log;
+  DELETE FROM announcements;
+  DELETE FROM challenge_feedbacks;
+  DELETE FROM writeups;
+  DELETE FROM ticket_messages;
+  DELETE FROM tickets;
   DELETE FROM submissions;
+  DELETE FROM instance_usage;
   DELETE FROM instances;
+  DELETE FROM deployments;
+  DELETE FROM instance_events;
+  DELETE FROM instance_secrets;
   DELETE FROM flags;
   DELETE FROM attachments;
+  DELETE FROM challenge_owners;
   DELETE FROM docker_configs;
   DELETE FROM challenges;
   DELETE FROM team_category_solves;
   DELETE FROM categories;
   DELETE FROM badges;
   DELETE FROM users;
+  DELETE FROM roles;
   DELETE FROM teams;
   DELETE FROM configs;
+  DELETE FROM email_templates;
 END;
 $$ LANGUAGE plpgsql;
 
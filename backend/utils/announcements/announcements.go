@@ -0,0 +1,186 @@
+package announcements
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/discord"
+	"trxd/utils/email"
+	"trxd/utils/events"
+	"trxd/utils/log"
+)
+
+// Interval between the checks of the scheduled announcements
+const publishInterval = 30 * time.Second
+
+// Wakes up the loop when an announcement may be due before the next check
+var wake = make(chan struct{}, 1)
+
+// Wake makes the loop publish the due announcements right away
+func Wake() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// PublishLoop pushes the announcements to the notifiers once their publish
+// time is reached
+func PublishLoop(ctx context.Context) {
+	ticker := time.NewTicker(publishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+
+		err := PublishDue(context.WithoutCancel(ctx))
+		if err != nil {
+			log.Error("Failed to publish announcements:", "err", err)
+		}
+	}
+}
+
+// Event is an announcement on the live event stream, shaped like the ones of
+// the public list
+type Event struct {
+	ID        int32                     `json:"id"`
+	Title     string                    `json:"title"`
+	Body      string                    `json:"body"`
+	Priority  sqlc.AnnouncementPriority `json:"priority"`
+	ChallID   *int32                    `json:"chall_id"`
+	ChallName *string                   `json:"chall_name"`
+	PublishAt time.Time                 `json:"publish_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+// PublishDue publishes the announcements due and not published yet. They are
+// claimed first, so that another replica doesn't publish them too, and
+// released for the next check if Discord or the event stream fail. The
+// ones about hidden challenges wait for them to be visible.
+func PublishDue(ctx context.Context) error {
+	due, err := db.Sql.ClaimDueAnnouncements(ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(due, func(a, b sqlc.Announcement) int {
+		return cmp.Or(a.PublishAt.Compare(b.PublishAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	for _, announcement := range due {
+		err := publish(ctx, &announcement)
+		if err == nil {
+			continue
+		}
+		log.Error("Failed to publish announcement:", "id", announcement.ID, "err", err)
+
+		err = db.Sql.MarkAnnouncementUnpublished(ctx, announcement.ID)
+		if err != nil {
+			log.Error("Failed to release announcement:", "id", announcement.ID, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// publish sends the announcement to Discord and the event stream, then emails
+// it if requested: the emails are best effort, as retrying them would send
+// the announcement again on the other notifiers
+func publish(ctx context.Context, announcement *sqlc.Announcement) error {
+	event := Event{
+		ID:        announcement.ID,
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		Priority:  announcement.Priority,
+		PublishAt: announcement.PublishAt,
+		UpdatedAt: announcement.UpdatedAt,
+	}
+
+	var challenge string
+	if announcement.ChallID.Valid {
+		chall, err := db.GetChallengeByID(ctx, announcement.ChallID.Int32)
+		if err != nil {
+			return err
+		}
+		if chall != nil {
+			// Hidden since it was claimed, nothing about it may leak
+			if chall.Hidden {
+				return errors.New("challenge hidden")
+			}
+			challenge = chall.Name
+			event.ChallID = &chall.ID
+			event.ChallName = &chall.Name
+		}
+	}
+
+	// The webhook is the one likely to fail, so that the live event is not
+	// sent again at every retry
+	err := discord.BroadcastAnnouncement(ctx, announcement, challenge)
+	if err != nil {
+		return err
+	}
+
+	err = events.Publish(ctx, events.TypeAnnouncement, event)
+	if err != nil {
+		return err
+	}
+
+	if announcement.Email {
+		err := sendEmails(ctx, announcement)
+		if err != nil {
+			log.Error("Failed to email announcement:", "id", announcement.ID, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// sendEmails sends the announcement to every user, in the locale they chose
+func sendEmails(ctx context.Context, announcement *sqlc.Announcement) error {
+	err := email.InitEmailClientFromConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	users, err := db.Sql.GetUsersEmails(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := &email.TemplateData{
+		Title: announcement.Title,
+		Body:  announcement.Body,
+	}
+	messages := make(map[string]*email.Message)
+	for _, user := range users {
+		locale := consts.DefaultLocale
+		if user.Locale.Valid {
+			locale = user.Locale.String
+		}
+
+		msg, ok := messages[locale]
+		if !ok {
+			msg, err = email.RenderTemplate(ctx, sqlc.EmailTemplateNameAnnouncement, locale, data)
+			if err != nil {
+				return err
+			}
+			messages[locale] = msg
+		}
+
+		err := email.SendEmail(ctx, user.Email, msg.Subject, msg.Html, msg.Text)
+		if err != nil {
+			log.Error("Failed to send announcement email:", "id", announcement.ID, "to", user.Email, "err", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,471 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Snapshot returns the target of a request and its current state, nil if it
+// does not exist (yet or anymore)
+type Snapshot func(c *fiber.Ctx) (string, any, error)
+
+// targets are the fields through which the audited requests name what they
+// mutate, the request itself is validated by the route
+type targets struct {
+	ID       *int32  `json:"id"`
+	ChallID  *int32  `json:"chall_id" form:"chall_id"`
+	ChallIDs []int32 `json:"chall_ids"`
+	Name     string  `json:"name"`
+	NewName  string  `json:"new_name"`
+	Locale   string  `json:"locale"`
+	Key      string  `json:"key"`
+	UserID   *int32  `json:"user_id"`
+	SubID    *int32  `json:"sub_id"`
+}
+
+func parseTargets(c *fiber.Ctx) targets {
+	var t targets
+	_ = c.BodyParser(&t)
+	return t
+}
+
+type challenge struct {
+	sqlc.Challenge
+	DockerConfig *sqlc.DockerConfig            `json:"docker_config"`
+	Flags        []sqlc.GetFlagsByChallengeRow `json:"flags"`
+	Attachments  []string                      `json:"attachments"`
+	Owners       []sqlc.GetChallengeOwnersRow  `json:"owners"`
+}
+
+func getChallenge(ctx context.Context, id int32) (*challenge, error) {
+	info, err := db.GetChallengeByID(ctx, id)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	chall := &challenge{Challenge: *info}
+
+	dockerConfig, err := db.Sql.GetChallDockerConfig(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		chall.DockerConfig = &dockerConfig
+	}
+
+	chall.Flags, err = db.Sql.GetFlagsByChallenge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := db.GetHiddenAndAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachments != nil {
+		chall.Attachments = attachments.Attachments
+	}
+
+	chall.Owners, err = db.Sql.GetChallengeOwners(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return chall, nil
+}
+
+// Challenge snapshots the challenges named by chall_id or chall_ids, with
+// their flags, attachments and owners, or the one being created by its name
+func Challenge(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+
+	if len(t.ChallIDs) > 0 {
+		ids := make([]string, len(t.ChallIDs))
+		challs := make(map[string]*challenge, len(t.ChallIDs))
+		for i, id := range t.ChallIDs {
+			chall, err := getChallenge(c.Context(), id)
+			if err != nil {
+				return "", nil, err
+			}
+			ids[i] = fmt.Sprint(id)
+			challs[ids[i]] = chall
+		}
+		return "challenge:" + strings.Join(ids, ","), challs, nil
+	}
+
+	if t.ChallID == nil {
+		if t.Name == "" {
+			return "", nil, nil
+		}
+		id, err := db.Sql.GetChallengeIDByName(c.Context(), t.Name)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", nil, nil
+			}
+			return "", nil, err
+		}
+		t.ChallID = &id
+	}
+
+	chall, err := getChallenge(c.Context(), *t.ChallID)
+	if err != nil {
+		return "", nil, err
+	}
+	if chall == nil {
+		return "", nil, nil
+	}
+
+	return fmt.Sprintf("challenge:%d", *t.ChallID), chall, nil
+}
+
+// Markers recorded in place of the values of the secret configurations
+const (
+	redacted = "[redacted]"
+	changed  = "[changed]"
+)
+
+// secretConfig is a secret configuration, whose value is only compared
+type secretConfig struct {
+	sqlc.Config
+	value string
+}
+
+// redact replaces the values of the secret configurations by markers, telling
+// only whether they changed
+func redact(before, after any) (any, any) {
+	b, isBefore := before.(*secretConfig)
+	a, isAfter := after.(*secretConfig)
+	if isBefore {
+		b.Value = redacted
+	}
+	if isAfter {
+		a.Value = redacted
+		if !isBefore || a.value != b.value {
+			a.Value = changed
+		}
+	}
+	return before, after
+}
+
+// Config snapshots the configuration named by key, the secret ones without
+// their value
+func Config(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.Key == "" {
+		return "", nil, nil
+	}
+
+	config, err := db.Sql.GetConfig(c.Context(), t.Key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	if config.Secret {
+		return "config:" + t.Key, &secretConfig{Config: config, value: config.Value}, nil
+	}
+
+	return "config:" + t.Key, config, nil
+}
+
+type user struct {
+	ID         int32         `json:"id"`
+	Name       string        `json:"name"`
+	Role       sqlc.UserRole `json:"role"`
+	CustomRole *string       `json:"custom_role"`
+}
+
+// User snapshots the roles of the user named by user_id
+func User(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.UserID == nil {
+		return "", nil, nil
+	}
+
+	u, err := db.GetUserByID(c.Context(), *t.UserID)
+	if err != nil || u == nil {
+		return "", nil, err
+	}
+
+	state := user{
+		ID:   u.ID,
+		Name: u.Name,
+		Role: u.Role,
+	}
+	if u.CustomRole.Valid {
+		state.CustomRole = &u.CustomRole.String
+	}
+
+	return fmt.Sprintf("user:%d", u.ID), state, nil
+}
+
+type submission struct {
+	sqlc.Submission
+	FlagOwner *int32 `json:"flag_owner"`
+}
+
+// Submission snapshots the submission named by sub_id
+func Submission(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.SubID == nil {
+		return "", nil, nil
+	}
+
+	sub, err := db.Sql.GetSubmissionByID(c.Context(), *t.SubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	state := submission{Submission: sub}
+	if sub.FlagOwner.Valid {
+		state.FlagOwner = &sub.FlagOwner.Int32
+	}
+
+	return fmt.Sprintf("submission:%d", sub.ID), state, nil
+}
+
+// renamed returns the name of the target, the new one once it was renamed
+func renamed[T any](c *fiber.Ctx, get func(context.Context, string) (T, error)) (string, *T, error) {
+	t := parseTargets(c)
+	for _, name := range []string{t.Name, t.NewName} {
+		if name == "" {
+			continue
+		}
+		state, err := get(c.Context(), name)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return "", nil, err
+		}
+		return name, &state, nil
+	}
+	return "", nil, nil
+}
+
+// Category snapshots the category named by name, or new_name once renamed
+func Category(c *fiber.Ctx) (string, any, error) {
+	name, category, err := renamed(c, db.Sql.GetCategory)
+	if err != nil || category == nil {
+		return "", nil, err
+	}
+
+	return "category:" + name, category, nil
+}
+
+// Role snapshots the custom role named by name, or new_name once renamed
+func Role(c *fiber.Ctx) (string, any, error) {
+	name, role, err := renamed(c, db.Sql.GetRole)
+	if err != nil || role == nil {
+		return "", nil, err
+	}
+
+	return "role:" + name, role, nil
+}
+
+// EmailTemplate snapshots the override of the email template named by name
+// and locale
+func EmailTemplate(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.Name == "" || t.Locale == "" {
+		return "", nil, nil
+	}
+
+	template, err := db.Sql.GetEmailTemplate(c.Context(), sqlc.GetEmailTemplateParams{
+		Name:   sqlc.EmailTemplateName(t.Name),
+		Locale: t.Locale,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("email-template:%s:%s", t.Name, t.Locale), template, nil
+}
+
+type announcement struct {
+	sqlc.Announcement
+	ChallID *int32 `json:"chall_id"`
+}
+
+// Announcement snapshots the announcement named by id, or the one just
+// created by the id in the response
+func Announcement(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.ID == nil {
+		var created struct {
+			ID *int32 `json:"id"`
+		}
+		if json.Unmarshal(c.Response().Body(), &created) != nil || created.ID == nil {
+			return "", nil, nil
+		}
+		t.ID = created.ID
+	}
+
+	a, err := db.Sql.GetAnnouncementByID(c.Context(), *t.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	state := announcement{Announcement: a}
+	if a.ChallID.Valid {
+		state.ChallID = &a.ChallID.Int32
+	}
+
+	return fmt.Sprintf("announcement:%d", a.ID), state, nil
+}
+
+type writeup struct {
+	sqlc.Writeup
+	TeamID *int32 `json:"team_id"`
+	UserID *int32 `json:"user_id"`
+}
+
+// Writeup snapshots the writeup named by id
+func Writeup(c *fiber.Ctx) (string, any, error) {
+	t := parseTargets(c)
+	if t.ID == nil {
+		return "", nil, nil
+	}
+
+	w, err := db.Sql.GetWriteupByID(c.Context(), *t.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	state := writeup{Writeup: w}
+	if w.TeamID.Valid {
+		state.TeamID = &w.TeamID.Int32
+	}
+	if w.UserID.Valid {
+		state.UserID = &w.UserID.Int32
+	}
+
+	return fmt.Sprintf("writeup:%d", w.ID), state, nil
+}
+
+// Diff strips the two states down to the fields that differ between them,
+// comparing the nested objects field by field
+func Diff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	before, after = redact(before, after)
+
+	b, err := normalize(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := normalize(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, a = diff(b, a)
+
+	beforeJSON, err := json.Marshal(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := json.Marshal(a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+// normalize turns a state into the maps, slices and values of its JSON
+func normalize(state any) (any, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+func diff(before, after any) (any, any) {
+	beforeMap, ok := before.(map[string]any)
+	if !ok {
+		return before, after
+	}
+	afterMap, ok := after.(map[string]any)
+	if !ok {
+		return before, after
+	}
+
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for key, b := range beforeMap {
+		a, ok := afterMap[key]
+		if !ok {
+			changedBefore[key] = b
+			continue
+		}
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		changedBefore[key], changedAfter[key] = diff(b, a)
+	}
+	for key, a := range afterMap {
+		if _, ok := beforeMap[key]; !ok {
+			changedAfter[key] = a
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+type Entry struct {
+	ActorID int32
+	IP      string
+	Action  string
+	Target  string
+	Before  any
+	After   any
+}
+
+// Record adds a mutation to the audit log, keeping only the fields it
+// changed. The mutation already happened, so a failure is only logged.
+func Record(ctx context.Context, entry Entry) {
+	before, after, err := Diff(entry.Before, entry.After)
+	if err == nil {
+		err = db.Sql.CreateAuditEntry(ctx, sqlc.CreateAuditEntryParams{
+			ActorID: sql.NullInt32{Int32: entry.ActorID, Valid: true},
+			Ip:      entry.IP,
+			Action:  entry.Action,
+			Target:  entry.Target,
+			Before:  before,
+			After:   after,
+		})
+	}
+	if err != nil {
+		log.Error("Failed to record audit entry:", "actor", entry.ActorID, "action", entry.Action, "target", entry.Target, "err", err)
+	}
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"testing"
+	"trxd/db/sqlc"
+)
+
+func TestDiff(t *testing.T) {
+	type flag struct {
+		Flag  string `json:"flag"`
+		Regex bool   `json:"regex"`
+	}
+	type chall struct {
+		Name   string            `json:"name"`
+		Hidden bool              `json:"hidden"`
+		Flags  []flag            `json:"flags"`
+		Config map[string]string `json:"config"`
+	}
+
+	old := chall{
+		Name:   "chall",
+		Hidden: true,
+		Flags:  []flag{{Flag: "flag{old}"}},
+		Config: map[string]string{"image": "a", "envs": ""},
+	}
+	updated := old
+	updated.Hidden = false
+	updated.Flags = []flag{{Flag: "flag{new}"}}
+	updated.Config = map[string]string{"image": "b", "envs": ""}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   [2]string
+	}{
+		{"created", nil, old, [2]string{`null`, `{"config":{"envs":"","image":"a"},"flags":[{"flag":"flag{old}","regex":false}],"hidden":true,"name":"chall"}`}},
+		{"deleted", old, nil, [2]string{`{"config":{"envs":"","image":"a"},"flags":[{"flag":"flag{old}","regex":false}],"hidden":true,"name":"chall"}`, `null`}},
+		{"unchanged", old, old, [2]string{`{}`, `{}`}},
+		{"updated", old, updated, [2]string{
+			`{"config":{"image":"a"},"flags":[{"flag":"flag{old}","regex":false}],"hidden":true}`,
+			`{"config":{"image":"b"},"flags":[{"flag":"flag{new}","regex":false}],"hidden":false}`,
+		}},
+		{"fields", map[string]any{"a": 1}, map[string]any{"b": 2}, [2]string{`{"a":1}`, `{"b":2}`}},
+	}
+
+	for _, test := range tests {
+		before, after, err := Diff(test.before, test.after)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(before) != test.want[0] || string(after) != test.want[1] {
+			t.Errorf("%s: got %s -> %s, want %s -> %s", test.name, before, after, test.want[0], test.want[1])
+		}
+	}
+}
+
+func TestDiffSecretConfig(t *testing.T) {
+	secret := func(value string) *secretConfig {
+		config := sqlc.Config{Key: "email-passwd", Value: value, Secret: true}
+		return &secretConfig{Config: config, value: value}
+	}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   [2]string
+	}{
+		{"unchanged", secret("old"), secret("old"), [2]string{`{}`, `{}`}},
+		{"updated", secret("old"), secret("new"), [2]string{`{"value":"[redacted]"}`, `{"value":"[changed]"}`}},
+	}
+
+	for _, test := range tests {
+		before, after, err := Diff(test.before, test.after)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(before) != test.want[0] || string(after) != test.want[1] {
+			t.Errorf("%s: got %s -> %s, want %s -> %s", test.name, before, after, test.want[0], test.want[1])
+		}
+	}
+}
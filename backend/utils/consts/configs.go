@@ -10,6 +10,10 @@ import (
 
 var AntiPanic = true
 
+// Addresses or ranges of the reverse proxies, whose X-Real-IP header is
+// trusted as the address of the client
+var TrustedProxies []string
+
 type Config struct {
 	Name        string
 	Value       any
@@ -60,6 +64,118 @@ var DefaultConfigs map[string]Config = map[string]Config{
 		Description: "the interval for reclaiming instances in seconds",
 		Secret:      false,
 	},
+	"reclaim-instance-workers": {
+		Name:        "Reclaim Instance Workers",
+		Value:       8,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the maximum number of expired instances deleted in parallel",
+		Secret:      false,
+	},
+	"reconcile-instance-interval": {
+		Name:        "Reconcile Instance Interval",
+		Value:       10 * 60, // 10 minutes
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the interval for reconciling Docker objects with the instances in seconds",
+		Secret:      false,
+	},
+	"instance-max-lifetime": {
+		Name:        "Instance Max Lifetime",
+		Value:       0,
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the maximum total lifetime of an instance in seconds, extensions included (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-max-extensions": {
+		Name:        "Instance Max Extensions",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the maximum number of times an instance can be extended (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-extension-cooldown": {
+		Name:        "Instance Extension Cooldown",
+		Value:       0,
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the minimum time in seconds between two extensions of an instance (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-extension-window": {
+		Name:        "Instance Extension Window",
+		Value:       0,
+		Type:        "duration",
+		Category:    "instances",
+		Description: "an instance can only be extended in its last seconds of life (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-flag-prefix": {
+		Name:        "Instance Flag Prefix",
+		Value:       "flag",
+		Type:        "string",
+		Category:    "instances",
+		Description: "the prefix of the random flags rendered with {{.Flag}} in the instances environment (e.g. flag{...})",
+		Secret:      false,
+	},
+	"instance-pow-difficulty": {
+		Name:        "Instance PoW Difficulty",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the proof-of-work difficulty in leading zero bits required to create an instance (0 to disable)",
+		Secret:      false,
+	},
+	"instance-pow-max-difficulty": {
+		Name:        "Instance PoW Max Difficulty",
+		Value:       24,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the maximum proof-of-work difficulty reachable when it rises under load",
+		Secret:      false,
+	},
+	"instance-pow-load-step": {
+		Name:        "Instance PoW Load Step",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the number of active instances after which the proof-of-work difficulty rises by one bit, and again at each doubling (0 to disable)",
+		Secret:      false,
+	},
+	"instance-pow-timeout": {
+		Name:        "Instance PoW Timeout",
+		Value:       5 * 60, // 5 minutes
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the validity of a proof-of-work challenge in seconds",
+		Secret:      false,
+	},
+	"deployment-check-interval": {
+		Name:        "Deployment Check Interval",
+		Value:       30,
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the interval for checking (and restarting) the shared challenge deployments in seconds",
+		Secret:      false,
+	},
+	"swarm-mode": {
+		Name:        "Swarm Mode",
+		Value:       false,
+		Type:        "bool",
+		Category:    "instances",
+		Description: "if enabled container instances are created as Docker Swarm services scheduled across the nodes",
+		Secret:      false,
+	},
+	"swarm-docker-port": {
+		Name:        "Swarm Docker Port",
+		Value:       2376,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the port the Docker daemons of the other Swarm nodes listen on, with the TLS settings of the local client, to sample the usage of their instances (0 to disable)",
+		Secret:      false,
+	},
 	"instance-max-memory": {
 		Name:        "Instance Max Memory",
 		Value:       512,
@@ -76,6 +192,70 @@ var DefaultConfigs map[string]Config = map[string]Config{
 		Description: "the maximum CPU allocation for each instance",
 		Secret:      false,
 	},
+	"dns-resolver-image": {
+		Name:        "DNS Resolver Image",
+		Value:       "coredns/coredns:1.12.1",
+		Type:        "string",
+		Category:    "instances",
+		Description: "the CoreDNS image forwarding the queries of compose instances with the DNS egress policy",
+		Secret:      false,
+	},
+	"ingress-relay-image": {
+		Name:        "Ingress Relay Image",
+		Value:       "alpine/socat:1.8.0.1",
+		Type:        "string",
+		Category:    "instances",
+		Description: "the socat image forwarding the connections to compose instances without the Full egress policy",
+		Secret:      false,
+	},
+	"usage-instance-interval": {
+		Name:        "Usage Instance Interval",
+		Value:       30,
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the interval for collecting the resource usage of the instances in seconds (0 to disable)",
+		Secret:      false,
+	},
+	"instance-cpu-threshold": {
+		Name:        "Instance CPU Threshold",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the CPU usage in percent of a CPU an instance can sustain (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-memory-threshold": {
+		Name:        "Instance Memory Threshold",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the memory usage in MB an instance can sustain (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-pids-threshold": {
+		Name:        "Instance PIDs Threshold",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the number of processes an instance can sustain (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-network-threshold": {
+		Name:        "Instance Network Threshold",
+		Value:       0,
+		Type:        "int",
+		Category:    "instances",
+		Description: "the network traffic in KB/s an instance can sustain (0 for no limit)",
+		Secret:      false,
+	},
+	"instance-threshold-duration": {
+		Name:        "Instance Threshold Duration",
+		Value:       5 * 60, // 5 minutes
+		Type:        "duration",
+		Category:    "instances",
+		Description: "the time in seconds an instance can exceed its thresholds before being flagged or killed",
+		Secret:      false,
+	},
 	"min-port": {
 		Name:        "Min Port",
 		Value:       10000,
@@ -116,6 +296,14 @@ var DefaultConfigs map[string]Config = map[string]Config{
 		Description: "the Discord webhook URL for first blood notifications",
 		Secret:      false,
 	},
+	"tickets-webhook": {
+		Name:        "Tickets Webhook",
+		Value:       "",
+		Type:        "url",
+		Category:    "",
+		Description: "the Discord webhook URL notified of the messages of the teams in the tickets, keep its channel private to the organizers",
+		Secret:      true,
+	},
 	"user-mode": {
 		Name:        "Single User Mode",
 		Value:       false,
@@ -196,6 +384,78 @@ var DefaultConfigs map[string]Config = map[string]Config{
 		Description: "the password for the email account used for sending verification emails",
 		Secret:      true,
 	},
+	"tcp-proxy-port": {
+		Name:        "TCP Proxy Port",
+		Value:       0,
+		Type:        "port",
+		Category:    "proxy",
+		Description: "the port of the built-in TLS proxy routing TCP instances by SNI (0 to disable)",
+		Secret:      false,
+	},
+	"http-proxy-port": {
+		Name:        "HTTP Proxy Port",
+		Value:       0,
+		Type:        "port",
+		Category:    "proxy",
+		Description: "the port of the built-in reverse proxy routing HTTP instances by host (0 to disable)",
+		Secret:      false,
+	},
+	"instance-access-control": {
+		Name:        "Instance Access Control",
+		Value:       false,
+		Type:        "bool",
+		Category:    "proxy",
+		Description: "if enabled the new instances are reachable only through the built-in proxy with the team token",
+		Secret:      false,
+	},
+	"proxy-secret": {
+		Name:        "Proxy Secret",
+		Value:       "",
+		Type:        "string",
+		Category:    "proxy",
+		Description: "the secret key used for signing the instance access tokens (generated if empty)",
+		Secret:      true,
+	},
+	"proxy-tls-cert": {
+		Name:        "Proxy TLS Certificate",
+		Value:       "",
+		Type:        "string",
+		Category:    "proxy",
+		Description: "the path of the PEM certificate (e.g. wildcard *.domain.com) served by the built-in proxy",
+		Secret:      false,
+	},
+	"proxy-tls-key": {
+		Name:        "Proxy TLS Key",
+		Value:       "",
+		Type:        "string",
+		Category:    "proxy",
+		Description: "the path of the PEM private key of the proxy TLS certificate",
+		Secret:      false,
+	},
+	"proxy-tls-ca-cert": {
+		Name:        "Proxy TLS CA Certificate",
+		Value:       "",
+		Type:        "string",
+		Category:    "proxy",
+		Description: "the path of the PEM CA certificate used to issue per-host certificates when no certificate matches",
+		Secret:      false,
+	},
+	"proxy-tls-ca-key": {
+		Name:        "Proxy TLS CA Key",
+		Value:       "",
+		Type:        "string",
+		Category:    "proxy",
+		Description: "the path of the PEM private key of the proxy TLS CA",
+		Secret:      false,
+	},
+	"metrics-token": {
+		Name:        "Metrics Token",
+		Value:       "",
+		Type:        "string",
+		Category:    "",
+		Description: "the bearer token required to scrape /metrics (if empty, only internal addresses can scrape it)",
+		Secret:      true,
+	},
 }
 
 // var DefaultConfigs = map[string]any{
@@ -229,6 +489,13 @@ func LoadEnvConfigs() {
 		log.Warn("Anti Panic disabled")
 	}
 
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy != "" {
+			TrustedProxies = append(TrustedProxies, proxy)
+		}
+	}
+
 	for name, conf := range DefaultConfigs {
 		envName := strings.ReplaceAll(name, "-", "_")
 		envName = strings.ToUpper(envName)
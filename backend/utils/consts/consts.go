@@ -9,6 +9,23 @@ const Name = "TRXd"
 const NetworkExternal = "trxd-shared-external"
 const NetworkInternal = "trxd-shared-internal"
 
+const (
+	LabelInstance   = "trxd.instance"
+	LabelTeamID     = "trxd.team_id"
+	LabelChallID    = "trxd.chall_id"
+	LabelNode       = "trxd.node"
+	LabelDeployment = "trxd.deployment"
+	LabelNodeHost   = "trxd.host"    // Swarm node label with the public host of the node
+	LabelPrivate    = "trxd.private" // Private network of a compose instance and its resolver
+)
+
+const DefaultLocale = "en"
+
+var Locales = []string{DefaultLocale, "it"}
+
+const FlagSecret = "Flag" // Name of the instance flag among its rendered secrets
+const FlagEnv = "FLAG"    // Variable receiving the instance flag when not set by the author
+
 var Roles = []sqlc.UserRole{sqlc.UserRoleSpectator, sqlc.UserRolePlayer, sqlc.UserRoleAuthor, sqlc.UserRoleAdmin}
 var RolesStr = []string{string(sqlc.UserRoleSpectator), string(sqlc.UserRolePlayer), string(sqlc.UserRoleAuthor), string(sqlc.UserRoleAdmin)}
 var DeployTypes = []sqlc.DeployType{sqlc.DeployTypeNormal, sqlc.DeployTypeContainer, sqlc.DeployTypeCompose}
@@ -17,6 +34,68 @@ var ScoreTypes = []sqlc.ScoreType{sqlc.ScoreTypeStatic, sqlc.ScoreTypeDynamic}
 var ScoreTypesStr = []string{string(sqlc.ScoreTypeStatic), string(sqlc.ScoreTypeDynamic)}
 var ConnTypes = []sqlc.ConnType{sqlc.ConnTypeNONE, sqlc.ConnTypeTCP, sqlc.ConnTypeHTTP, sqlc.ConnTypeHTTPS}
 var ConnTypesStr = []string{string(sqlc.ConnTypeNONE), string(sqlc.ConnTypeTCP), string(sqlc.ConnTypeHTTP), string(sqlc.ConnTypeHTTPS)}
+var EgressPolicies = []sqlc.EgressPolicy{sqlc.EgressPolicyNone, sqlc.EgressPolicyDNS, sqlc.EgressPolicyFull}
+var EgressPoliciesStr = []string{string(sqlc.EgressPolicyNone), string(sqlc.EgressPolicyDNS), string(sqlc.EgressPolicyFull)}
+var AnnouncementPrioritiesStr = []string{string(sqlc.AnnouncementPriorityLow), string(sqlc.AnnouncementPriorityNormal), string(sqlc.AnnouncementPriorityHigh)}
+var FeedbackVotesStr = []string{string(sqlc.FeedbackVoteLike), string(sqlc.FeedbackVoteDislike)}
+var TicketStatusesStr = []string{string(sqlc.TicketStatusOpen), string(sqlc.TicketStatusAnswered), string(sqlc.TicketStatusClosed)}
+var OwnerRolesStr = []string{string(sqlc.OwnerRoleOwner), string(sqlc.OwnerRoleCoOwner)}
+var EmailTemplateNamesStr = []string{string(sqlc.EmailTemplateNameVerification), string(sqlc.EmailTemplateNamePasswordReset), string(sqlc.EmailTemplateNameAnnouncement), string(sqlc.EmailTemplateNameTicketReply)}
+
+// Permissions checked by the route guards, the custom roles grant them on top
+// of the ones of the user role
+const (
+	PermAnnouncementsWrite = "announcements.write"
+	PermAuditRead          = "audit.read"
+	PermChallengesRead     = "challenges.read" // Hidden challenges, their flags and configs
+	PermChallengesWrite    = "challenges.write"
+	PermConfigsRead        = "configs.read"
+	PermConfigsWrite       = "configs.write"
+	PermInstancesRead      = "instances.read"
+	PermInstancesManage    = "instances.manage" // Instances of every team
+	PermRolesWrite         = "roles.write"
+	PermStatsRead          = "stats.read"
+	PermSubmissionsRead    = "submissions.read"
+	PermSubmissionsWrite   = "submissions.write"
+	PermTicketsManage      = "tickets.manage" // Tickets of every team
+	PermWriteupsModerate   = "writeups.moderate"
+	PermWriteupsWrite      = "writeups.write"
+)
+
+var Permissions = []string{
+	PermAnnouncementsWrite,
+	PermAuditRead,
+	PermChallengesRead,
+	PermChallengesWrite,
+	PermConfigsRead,
+	PermConfigsWrite,
+	PermInstancesRead,
+	PermInstancesManage,
+	PermRolesWrite,
+	PermStatsRead,
+	PermSubmissionsRead,
+	PermSubmissionsWrite,
+	PermTicketsManage,
+	PermWriteupsModerate,
+	PermWriteupsWrite,
+}
+
+// RolePermissions are granted by the user roles, the authors only use them
+// on the challenges they own
+var RolePermissions = map[sqlc.UserRole][]string{
+	sqlc.UserRoleSpectator: {},
+	sqlc.UserRolePlayer:    {},
+	sqlc.UserRoleAuthor: {
+		PermChallengesRead,
+		PermChallengesWrite,
+		PermInstancesRead,
+		PermSubmissionsRead,
+		PermSubmissionsWrite,
+		PermTicketsManage,
+		PermWriteupsModerate,
+	},
+	sqlc.UserRoleAdmin: Permissions,
+}
 
 const (
 	PGForeignKeyViolation          = "23503"
@@ -26,22 +105,41 @@ const (
 )
 
 const (
-	MaxAttachmentNameLen = 128
-	MaxBioLen            = 10240
-	MaxCategoryLen       = 32
-	MaxChallDescLen      = 10240
-	MaxChallNameLen      = 128
-	MaxEmailLen          = 256
-	MaxFlagLen           = 256
-	MaxImageLen          = 1024
-	MaxUserNameLen       = 64
-	MaxTeamNameLen       = 64
-	MaxPasswordLen       = 64
-	MaxPort              = 65535
-	MaxAuthorNameLen     = 64
-	MaxTagNameLen        = 32
-	MinPasswordLen       = 8
-	MinPort              = 0
+	MaxAnnouncementBodyLen  = 10240
+	MaxAnnouncementTitleLen = 128
+	MaxAttachmentNameLen    = 128
+	MaxBioLen               = 10240
+	MaxCategoryLen          = 32
+	MaxChallDescLen         = 10240
+	MaxChallNameLen         = 128
+	MaxEmailLen             = 256
+	MaxFeedbackCommentLen   = 1024
+	MaxFeedbackDifficulty   = 5
+	MaxFlagLen              = 256
+	MaxImageLen             = 1024
+	MaxUserNameLen          = 64
+	MaxTeamNameLen          = 64
+	MaxPasswordLen          = 64
+	MaxPort                 = 65535
+	DefaultInstancePort     = 1337 // Port the instances without one are reached on
+	MaxPowDifficulty        = 64
+	MaxPowSolutionLen       = 128
+	MaxPendingPows          = 8  // Per team
+	MaxPowRequests          = 30 // Per team every minute
+	MaxTemplateRandomLen    = 256
+	MaxTemplateOutputLen    = 64 * 1024
+	MaxPlacementLen         = 256
+	MaxAuthorNameLen        = 64
+	MaxTagNameLen           = 32
+	MaxRoleNameLen          = 32
+	MaxRoleDescriptionLen   = 1024
+	MaxTicketTitleLen       = 128
+	MaxTicketMessageLen     = 10240
+	MaxWriteupLen           = 65536
+	MaxWriteupUrlLen        = 1024
+	MinFeedbackDifficulty   = 1
+	MinPasswordLen          = 8
+	MinPort                 = 0
 )
 
 const (
@@ -49,17 +147,29 @@ const (
 	Forbidden           = "Forbidden"
 	NotFound            = "Not Found"
 	InternalServerError = "Internal Server Error"
+	TooManyRequests     = "Too many requests"
 
 	AlreadyAnActiveInstance = "Already an active instance"
+	AlreadyExtended         = "Instance already being extended"
 	AlreadyInTeam           = "Already in a team"
 	AlreadyLoggedIn         = "Already logged in"
 	AlreadyRegistered       = "Already registered"
 
 	ChallengeNotInstanciable = "Challenge is not instanciable"
+	ChallengeNotSolved       = "Challenge not solved yet"
+	NotChallengeOwner        = "Not an owner of the challenge"
+	OwnerCannotBeRemoved     = "The owner cannot be removed, transfer the ownership first"
+	UserNotAuthor            = "The user is not an author"
+
+	ExtensionCooldown      = "Instance extended too recently"
+	ExtensionNotAllowedYet = "Instance cannot be extended yet"
+	MaxExtensionsReached   = "Maximum number of extensions reached"
+	MaxLifetimeReached     = "Maximum instance lifetime reached"
 
 	DisabledRegistrations = "Registrations are disabled"
 
 	ErrorBeginningTransaction     = "Error beginning transaction"
+	ErrorCreatingAnnouncement     = "Error creating announcement"
 	ErrorChangingUserRole         = "Error changing user role"
 	ErrorCommittingTransaction    = "Error committing transaction"
 	ErrorCreatingAttachments      = "Error creating attachments"
@@ -68,48 +178,75 @@ const (
 	ErrorCreatingChallenge        = "Error creating challenge"
 	ErrorCreatingFlag             = "Error creating flag"
 	ErrorCreatingInstance         = "Error creating instance"
+	ErrorCreatingRole             = "Error creating role"
+	ErrorCreatingTicket           = "Error creating ticket"
+	ErrorDeletingAnnouncement     = "Error deleting announcement"
 	ErrorDeletingAttachment       = "Error deleting attachment"
 	ErrorDeletingCategory         = "Error deleting category"
 	ErrorDeletingChallenge        = "Error deleting challenge"
+	ErrorDeletingEmailTemplate    = "Error deleting email template"
+	ErrorDeletingFeedback         = "Error deleting feedback"
 	ErrorDeletingFlag             = "Error deleting flag"
 	ErrorDeletingInstance         = "Error deleting instance"
+	ErrorDeletingRole             = "Error deleting role"
+	ErrorDeletingWriteup          = "Error deleting writeup"
 	ErrorDestroyingSession        = "Error destroying session"
+	ErrorFetchingAnnouncements    = "Error fetching announcements"
+	ErrorSubscribingEvents        = "Error subscribing to the events"
 	ErrorDeletingSubmission       = "Error deleting submission"
 	ErrorFetchingAttachment       = "Error fetching attachment"
+	ErrorFetchingAuditLog         = "Error fetching audit log"
 	ErrorFetchingCategories       = "Error fetching categories"
 	ErrorFetchingCategory         = "Error fetching category"
 	ErrorFetchingChallenge        = "Error fetching challenge"
 	ErrorFetchingChallenges       = "Error fetching challenges"
 	ErrorFetchingConfig           = "Error fetching configuration"
 	ErrorFetchingConfigs          = "Error fetching configurations"
+	ErrorFetchingEmailTemplates   = "Error fetching email templates"
 	ErrorFetchingInstance         = "Error fetching instance"
+	ErrorFetchingInstanceEvents   = "Error fetching instance events"
 	ErrorFetchingInstances        = "Error fetching instances"
+	ErrorFetchingProofOfWork      = "Error fetching proof of work"
+	ErrorFetchingRoles            = "Error fetching roles"
 	ErrorFetchingScoreboardGraph  = "Error fetching scoreboard graph"
 	ErrorFetchingSession          = "Error fetching session"
 	ErrorFetchingStats            = "Error fetching stats"
 	ErrorFetchingSubmissions      = "Error fetching submissions"
 	ErrorFetchingTeam             = "Error fetching team"
+	ErrorFetchingTickets          = "Error fetching tickets"
 	ErrorFetchingUser             = "Error fetching user"
 	ErrorFetchingUsers            = "Error fetching users"
+	ErrorFetchingWriteups         = "Error fetching writeups"
 	ErrorGeneratingPassword       = "Error generating random password"
 	ErrorHashingFile              = "Error hashing file"
 	ErrorInitializingEmailClient  = "Error initializing email client"
 	ErrorLoggingIn                = "Error logging in"
 	ErrorParsingTime              = "Error parsing time"
+	ErrorRecordingAudit           = "Error recording audit entry"
 	ErrorRegisteringTeam          = "Error registering team"
 	ErrorRegisteringUser          = "Error registering user"
+	ErrorRenderingEmail           = "Error rendering email"
 	ErrorResettingTeamPassword    = "Error resetting team password"
 	ErrorResettingUserPassword    = "Error resetting user password"
 	ErrorSavingFile               = "Error saving file"
 	ErrorSavingSession            = "Error saving session"
+	ErrorSendingTicketMessage     = "Error sending ticket message"
 	ErrorSendingVerificationEmail = "Error sending verification email"
 	ErrorSigningVerificationToken = "Error signing verification token"
+	ErrorSubmittingFeedback       = "Error submitting feedback"
 	ErrorSubmittingFlag           = "Error submitting flag"
+	ErrorSubmittingWriteup        = "Error submitting writeup"
+	ErrorUpdatingAnnouncement     = "Error updating announcement"
 	ErrorUpdatingCategory         = "Error updating category"
 	ErrorUpdatingChallenge        = "Error updating challenge"
 	ErrorUpdatingConfig           = "Error updating configuration"
+	ErrorUpdatingEmailTemplate    = "Error updating email template"
+	ErrorUpdatingOwners           = "Error updating challenge owners"
+	ErrorUpdatingRole             = "Error updating role"
 	ErrorUpdatingTeam             = "Error updating team"
+	ErrorUpdatingTicket           = "Error updating ticket"
 	ErrorUpdatingUser             = "Error updating user"
+	ErrorUpdatingWriteup          = "Error updating writeup"
 
 	InvalidChallengeID      = "Invalid challenge ID, must be non negative"
 	InvalidCountry          = "Invalid country code, must be ISO3166-1 alpha-3"
@@ -127,14 +264,19 @@ const (
 	InvalidMaxCpu           = "Invalid Max CPU, must be a positive 32-bit integer"
 	InvalidMultipartForm    = "Invalid multipart form"
 	InvalidParam            = "Invalid parameter"
+	InvalidPlacement        = "Invalid placement constraint, must be in the form attribute==value or attribute!=value"
+	InvalidProofOfWork      = "Invalid or already used proof of work"
+	InvalidReservedCpu      = "Invalid Reserved CPU, must be a positive 32-bit integer"
 	InvalidRole             = "Invalid role"
 	InvalidSigningAlgorithm = "invalid signing algorithm"
 	InvalidSigningMethod    = "invalid signing method"
 	InvalidTeamCredentials  = "Invalid name or password"
 	InvalidTeamID           = "Invalid team ID, must be non negative"
+	InvalidTemplate         = "Invalid template"
 	InvalidToken            = "invalid token"
 	InvalidUserID           = "Invalid user ID, must be non negative"
 	InvalidUserName         = "Invalid user name"
+	InvalidWriteup          = "Invalid writeup, must have either a content or a url"
 
 	MaxError   = "{0} must not exceed {1}"
 	MinError   = "{0} must be at least {1}"
@@ -146,20 +288,38 @@ const (
 	ChallengeNameAlreadyExists = "Challenge name already exists"
 	FlagAlreadyExists          = "Flag already exists"
 	NameAlreadyTaken           = "Name already taken"
+	RoleAlreadyExists          = "Role already exists"
 	TeamAlreadyExists          = "Team already exists"
 	UserAlreadyExists          = "User already exists"
 
-	AttachmentNotFound = "Attachment not found"
-	CategoryNotFound   = "Category not found"
-	ChallengeNotFound  = "Challenge not found"
-	ConfigNotFound     = "Configuration not found"
-	InstanceNotFound   = "Instance not found"
-	TeamNotFound       = "Team not found"
-	UserNotFound       = "User not found"
+	AnnouncementNotFound  = "Announcement not found"
+	AttachmentNotFound    = "Attachment not found"
+	CategoryNotFound      = "Category not found"
+	ChallengeNotFound     = "Challenge not found"
+	ConfigNotFound        = "Configuration not found"
+	EmailTemplateNotFound = "Email template not found"
+	FeedbackNotFound      = "Feedback not found"
+	InstanceNotFound      = "Instance not found"
+	OwnerNotFound         = "Owner not found"
+	RoleNotFound          = "Role not found"
+	SubmissionNotFound    = "Submission not found"
+	TeamNotFound          = "Team not found"
+	TicketNotFound        = "Ticket not found"
+	UserNotFound          = "User not found"
+	WriteupNotFound       = "Writeup not found"
 
 	MissingLifetime           = "global lifetime is missing"
+	MissingProofOfWork        = "Proof of work required"
+	TooManyPendingPows        = "Too many pending proofs of work, solve or wait for the previous ones"
 	MissingRequiredFields     = "Missing required fields"
 	NoDataToUpdate            = "No data provided to update"
+	NoNodeAvailable           = "No node available for the instance"
+	TeamOnlyRequiresProxy     = "Team only instances require the built-in proxy for their connection type"
+	EmailNotConfigured        = "Emails cannot be sent until the email server is configured"
+	SharedInstanceFlag        = "Shared deployments cannot use per-instance flags"
+	InstanceFlagNotRendered   = "The FLAG variable must render {{.Flag}} when per-instance flags are enabled"
+	PermissionNotHeld         = "Cannot grant permissions you do not hold"
+	AdminRoleProtected        = "Only admins can change the roles of an admin"
 	NotLoggedIn               = "Not logged in"
 	NotStartedYet             = "Not started yet"
 	AlreadyEnded              = "Already ended"
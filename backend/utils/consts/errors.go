@@ -0,0 +1,200 @@
+package consts
+
+// UnknownError is the code of the messages without one
+const UnknownError = "unknown_error"
+
+// ErrorCodes maps each error message to its stable machine-readable code,
+// clients should match on the codes since the messages may be reworded
+var ErrorCodes = map[string]string{
+	Unauthorized:        "unauthorized",
+	Forbidden:           "forbidden",
+	NotFound:            "not_found",
+	InternalServerError: "internal_server_error",
+	TooManyRequests:     "too_many_requests",
+
+	AlreadyAnActiveInstance: "already_an_active_instance",
+	AlreadyExtended:         "already_extended",
+	AlreadyInTeam:           "already_in_team",
+	AlreadyLoggedIn:         "already_logged_in",
+	AlreadyRegistered:       "already_registered",
+
+	ChallengeNotInstanciable: "challenge_not_instanciable",
+	ChallengeNotSolved:       "challenge_not_solved",
+	NotChallengeOwner:        "not_challenge_owner",
+	OwnerCannotBeRemoved:     "owner_cannot_be_removed",
+	UserNotAuthor:            "user_not_author",
+
+	ExtensionCooldown:      "extension_cooldown",
+	ExtensionNotAllowedYet: "extension_not_allowed_yet",
+	MaxExtensionsReached:   "max_extensions_reached",
+	MaxLifetimeReached:     "max_lifetime_reached",
+
+	DisabledRegistrations: "disabled_registrations",
+
+	ErrorBeginningTransaction:     "error_beginning_transaction",
+	ErrorCreatingAnnouncement:     "error_creating_announcement",
+	ErrorChangingUserRole:         "error_changing_user_role",
+	ErrorCommittingTransaction:    "error_committing_transaction",
+	ErrorCreatingAttachments:      "error_creating_attachments",
+	ErrorCreatingAttachmentsDir:   "error_creating_attachments_dir",
+	ErrorCreatingCategory:         "error_creating_category",
+	ErrorCreatingChallenge:        "error_creating_challenge",
+	ErrorCreatingFlag:             "error_creating_flag",
+	ErrorCreatingInstance:         "error_creating_instance",
+	ErrorCreatingRole:             "error_creating_role",
+	ErrorCreatingTicket:           "error_creating_ticket",
+	ErrorDeletingAnnouncement:     "error_deleting_announcement",
+	ErrorDeletingAttachment:       "error_deleting_attachment",
+	ErrorDeletingCategory:         "error_deleting_category",
+	ErrorDeletingChallenge:        "error_deleting_challenge",
+	ErrorDeletingEmailTemplate:    "error_deleting_email_template",
+	ErrorDeletingFeedback:         "error_deleting_feedback",
+	ErrorDeletingFlag:             "error_deleting_flag",
+	ErrorDeletingInstance:         "error_deleting_instance",
+	ErrorDeletingRole:             "error_deleting_role",
+	ErrorDeletingWriteup:          "error_deleting_writeup",
+	ErrorDestroyingSession:        "error_destroying_session",
+	ErrorFetchingAnnouncements:    "error_fetching_announcements",
+	ErrorSubscribingEvents:        "error_subscribing_events",
+	ErrorDeletingSubmission:       "error_deleting_submission",
+	ErrorFetchingAttachment:       "error_fetching_attachment",
+	ErrorFetchingAuditLog:         "error_fetching_audit_log",
+	ErrorFetchingCategories:       "error_fetching_categories",
+	ErrorFetchingCategory:         "error_fetching_category",
+	ErrorFetchingChallenge:        "error_fetching_challenge",
+	ErrorFetchingChallenges:       "error_fetching_challenges",
+	ErrorFetchingConfig:           "error_fetching_config",
+	ErrorFetchingConfigs:          "error_fetching_configs",
+	ErrorFetchingEmailTemplates:   "error_fetching_email_templates",
+	ErrorFetchingInstance:         "error_fetching_instance",
+	ErrorFetchingInstanceEvents:   "error_fetching_instance_events",
+	ErrorFetchingInstances:        "error_fetching_instances",
+	ErrorFetchingProofOfWork:      "error_fetching_proof_of_work",
+	ErrorFetchingRoles:            "error_fetching_roles",
+	ErrorFetchingScoreboardGraph:  "error_fetching_scoreboard_graph",
+	ErrorFetchingSession:          "error_fetching_session",
+	ErrorFetchingStats:            "error_fetching_stats",
+	ErrorFetchingSubmissions:      "error_fetching_submissions",
+	ErrorFetchingTeam:             "error_fetching_team",
+	ErrorFetchingTickets:          "error_fetching_tickets",
+	ErrorFetchingUser:             "error_fetching_user",
+	ErrorFetchingUsers:            "error_fetching_users",
+	ErrorFetchingWriteups:         "error_fetching_writeups",
+	ErrorGeneratingPassword:       "error_generating_password",
+	ErrorHashingFile:              "error_hashing_file",
+	ErrorInitializingEmailClient:  "error_initializing_email_client",
+	ErrorLoggingIn:                "error_logging_in",
+	ErrorParsingTime:              "error_parsing_time",
+	ErrorRecordingAudit:           "error_recording_audit",
+	ErrorRegisteringTeam:          "error_registering_team",
+	ErrorRegisteringUser:          "error_registering_user",
+	ErrorRenderingEmail:           "error_rendering_email",
+	ErrorResettingTeamPassword:    "error_resetting_team_password",
+	ErrorResettingUserPassword:    "error_resetting_user_password",
+	ErrorSavingFile:               "error_saving_file",
+	ErrorSavingSession:            "error_saving_session",
+	ErrorSendingTicketMessage:     "error_sending_ticket_message",
+	ErrorSendingVerificationEmail: "error_sending_verification_email",
+	ErrorSigningVerificationToken: "error_signing_verification_token",
+	ErrorSubmittingFeedback:       "error_submitting_feedback",
+	ErrorSubmittingFlag:           "error_submitting_flag",
+	ErrorSubmittingWriteup:        "error_submitting_writeup",
+	ErrorUpdatingAnnouncement:     "error_updating_announcement",
+	ErrorUpdatingCategory:         "error_updating_category",
+	ErrorUpdatingChallenge:        "error_updating_challenge",
+	ErrorUpdatingConfig:           "error_updating_config",
+	ErrorUpdatingEmailTemplate:    "error_updating_email_template",
+	ErrorUpdatingOwners:           "error_updating_owners",
+	ErrorUpdatingRole:             "error_updating_role",
+	ErrorUpdatingTeam:             "error_updating_team",
+	ErrorUpdatingTicket:           "error_updating_ticket",
+	ErrorUpdatingUser:             "error_updating_user",
+	ErrorUpdatingWriteup:          "error_updating_writeup",
+
+	InvalidChallengeID:      "invalid_challenge_id",
+	InvalidCountry:          "invalid_country",
+	InvalidCredentials:      "invalid_credentials",
+	InvalidDomain:           "invalid_domain",
+	InvalidEmail:            "invalid_email",
+	InvalidEnvs:             "invalid_envs",
+	InvalidFilePath:         "invalid_file_path",
+	InvalidFormData:         "invalid_form_data",
+	InvalidHttpUrl:          "invalid_http_url",
+	InvalidImage:            "invalid_image",
+	InvalidJSON:             "invalid_json",
+	InvalidJWT:              "invalid_jwt",
+	InvalidJWTSecret:        "invalid_jwt_secret",
+	InvalidMaxCpu:           "invalid_max_cpu",
+	InvalidMultipartForm:    "invalid_multipart_form",
+	InvalidParam:            "invalid_param",
+	InvalidPlacement:        "invalid_placement",
+	InvalidProofOfWork:      "invalid_proof_of_work",
+	InvalidReservedCpu:      "invalid_reserved_cpu",
+	InvalidRole:             "invalid_role",
+	InvalidSigningAlgorithm: "invalid_signing_algorithm",
+	InvalidSigningMethod:    "invalid_signing_method",
+	InvalidTeamCredentials:  "invalid_team_credentials",
+	InvalidTeamID:           "invalid_team_id",
+	InvalidTemplate:         "invalid_template",
+	InvalidToken:            "invalid_token",
+	InvalidUserID:           "invalid_user_id",
+	InvalidUserName:         "invalid_user_name",
+	InvalidWriteup:          "invalid_writeup",
+
+	MaxError:   "max_error",
+	MinError:   "min_error",
+	OneOfError: "one_of_error",
+
+	AttachmentAlreadyExists:    "attachment_already_exists",
+	CategoryAlreadyExists:      "category_already_exists",
+	ChallengeAlreadyExists:     "challenge_already_exists",
+	ChallengeNameAlreadyExists: "challenge_name_already_exists",
+	FlagAlreadyExists:          "flag_already_exists",
+	NameAlreadyTaken:           "name_already_taken",
+	RoleAlreadyExists:          "role_already_exists",
+	TeamAlreadyExists:          "team_already_exists",
+	UserAlreadyExists:          "user_already_exists",
+
+	AnnouncementNotFound:  "announcement_not_found",
+	AttachmentNotFound:    "attachment_not_found",
+	CategoryNotFound:      "category_not_found",
+	ChallengeNotFound:     "challenge_not_found",
+	ConfigNotFound:        "config_not_found",
+	EmailTemplateNotFound: "email_template_not_found",
+	FeedbackNotFound:      "feedback_not_found",
+	InstanceNotFound:      "instance_not_found",
+	OwnerNotFound:         "owner_not_found",
+	RoleNotFound:          "role_not_found",
+	SubmissionNotFound:    "submission_not_found",
+	TeamNotFound:          "team_not_found",
+	TicketNotFound:        "ticket_not_found",
+	UserNotFound:          "user_not_found",
+	WriteupNotFound:       "writeup_not_found",
+
+	MissingLifetime:           "missing_lifetime",
+	MissingProofOfWork:        "missing_proof_of_work",
+	TooManyPendingPows:        "too_many_pending_pows",
+	MissingRequiredFields:     "missing_required_fields",
+	NoDataToUpdate:            "no_data_to_update",
+	NoNodeAvailable:           "no_node_available",
+	TeamOnlyRequiresProxy:     "team_only_requires_proxy",
+	EmailNotConfigured:        "email_not_configured",
+	SharedInstanceFlag:        "shared_instance_flag",
+	InstanceFlagNotRendered:   "instance_flag_not_rendered",
+	PermissionNotHeld:         "permission_not_held",
+	AdminRoleProtected:        "admin_role_protected",
+	NotLoggedIn:               "not_logged_in",
+	NotStartedYet:             "not_started_yet",
+	AlreadyEnded:              "already_ended",
+	EmailClientNotInitialized: "email_client_not_initialized",
+	VerificationAlreadySent:   "verification_already_sent",
+}
+
+// ErrorCode returns the code of an error message
+func ErrorCode(message string) string {
+	code, ok := ErrorCodes[message]
+	if !ok {
+		return UnknownError
+	}
+	return code
+}
@@ -67,3 +67,53 @@ func BroadcastFirstBlood(ctx context.Context, challenge *sqlc.Challenge, uid int
 		log.Error("Failed to send webhook:", "err", err)
 	}
 }
+
+// Maximum length of the content of a webhook message
+const maxContentLen = 2000
+
+func BroadcastAnnouncement(ctx context.Context, announcement *sqlc.Announcement, challenge string) error {
+	conf, err := db.GetConfig(ctx, "discord-webhook")
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook url: %w", err)
+	}
+	if conf == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("📢 **%s**", announcement.Title)
+	if announcement.Priority == sqlc.AnnouncementPriorityHigh {
+		msg = "@everyone " + msg
+	}
+	if challenge != "" {
+		msg += fmt.Sprintf(" (`%s`)", strings.ReplaceAll(challenge, "`", "'"))
+	}
+	msg += "\n\n" + announcement.Body
+	if len([]rune(msg)) > maxContentLen {
+		msg = string([]rune(msg)[:maxContentLen-1]) + "…"
+	}
+
+	return BroadcastWebhook(conf, map[string]string{"content": msg})
+}
+
+// BroadcastTicketMessage forwards the message of a team to the organizers
+func BroadcastTicketMessage(ctx context.Context, ticket *sqlc.GetTicketRow, user string, body string) error {
+	conf, err := db.GetConfig(ctx, "tickets-webhook")
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook url: %w", err)
+	}
+	if conf == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("🎫 **#%d %s**", ticket.ID, ticket.Title)
+	if ticket.ChallName.Valid {
+		msg += fmt.Sprintf(" (`%s`)", strings.ReplaceAll(ticket.ChallName.String, "`", "'"))
+	}
+	msg += fmt.Sprintf("\n`%s` from `%s`:\n\n%s",
+		strings.ReplaceAll(user, "`", "'"), strings.ReplaceAll(ticket.TeamName, "`", "'"), body)
+	if len([]rune(msg)) > maxContentLen {
+		msg = string([]rune(msg)[:maxContentLen-1]) + "…"
+	}
+
+	return BroadcastWebhook(conf, map[string]string{"content": msg})
+}
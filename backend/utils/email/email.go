@@ -18,36 +18,65 @@ var clientInitMutex sync.Mutex
 var client *gomail.Client
 var fromAddr *mail.Address
 
-func InitEmailClientFromConfigs(ctx context.Context) error {
+type emailConfig struct {
+	server string
+	port   int
+	addr   string
+	passwd string
+}
+
+func getEmailConfig(ctx context.Context) (*emailConfig, error) {
 	server, err := db.GetConfig(ctx, "email-server")
 	if err != nil {
-		return fmt.Errorf("failed to get email-server config: %w", err)
+		return nil, fmt.Errorf("failed to get email-server config: %w", err)
 	}
 
 	portStr, err := db.GetConfig(ctx, "email-port")
 	if err != nil {
-		return fmt.Errorf("failed to get email-port config: %w", err)
+		return nil, fmt.Errorf("failed to get email-port config: %w", err)
 	}
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
-		return fmt.Errorf("invalid email-port config: %w", err)
+		return nil, fmt.Errorf("invalid email-port config: %w", err)
 	}
 
 	addr, err := db.GetConfig(ctx, "email-addr")
 	if err != nil {
-		return fmt.Errorf("failed to get email-addr config: %w", err)
+		return nil, fmt.Errorf("failed to get email-addr config: %w", err)
 	}
 
 	passwd, err := db.GetConfig(ctx, "email-passwd")
 	if err != nil {
-		return fmt.Errorf("failed to get email-passwd config: %w", err)
+		return nil, fmt.Errorf("failed to get email-passwd config: %w", err)
+	}
+
+	return &emailConfig{server: server, port: port, addr: addr, passwd: passwd}, nil
+}
+
+func (conf *emailConfig) complete() bool {
+	return conf.server != "" && conf.port != 0 && conf.addr != ""
+}
+
+// Configured reports whether the emails can be sent, without connecting
+func Configured(ctx context.Context) (bool, error) {
+	conf, err := getEmailConfig(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	if server == "" || port == 0 || addr == "" {
+	return conf.complete(), nil
+}
+
+func InitEmailClientFromConfigs(ctx context.Context) error {
+	conf, err := getEmailConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !conf.complete() {
 		return errors.New("email configuration is incomplete")
 	}
 
-	err = InitEmailClient(server, port, addr, passwd)
+	err = InitEmailClient(conf.server, conf.port, conf.addr, conf.passwd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize email client: %w", err)
 	}
@@ -85,8 +114,9 @@ func InitEmailClient(server string, port int, addr string, passwd string) error
 	return nil
 }
 
-// The client has an internal mutex to make this thread-safe.
-func SendEmail(ctx context.Context, to string, subject string, body string) error {
+// The client has an internal mutex to make this thread-safe. The html body is
+// sent as an alternative to the plain text one, if any
+func SendEmail(ctx context.Context, to string, subject string, html string, text string) error {
 	if client == nil {
 		return errors.New(consts.EmailClientNotInitialized)
 	}
@@ -100,7 +130,10 @@ func SendEmail(ctx context.Context, to string, subject string, body string) erro
 	message.FromMailAddress(fromAddr)
 	message.ToMailAddress(toAddr)
 	message.Subject(subject)
-	message.SetBodyString(gomail.TypeTextPlain, body)
+	message.SetBodyString(gomail.TypeTextPlain, text)
+	if html != "" {
+		message.AddAlternativeString(gomail.TypeTextHTML, html)
+	}
 
 	err = client.DialAndSendWithContext(ctx, message)
 	if err != nil {
@@ -0,0 +1,228 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"slices"
+	texttemplate "text/template"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+)
+
+// TemplateData is the data available to the email templates
+type TemplateData struct {
+	Link  string // Verification, password reset or ticket link
+	Title string // Title of the announcement or of the ticket
+	Body  string // Body of the announcement or of the reply
+}
+
+// Message is a rendered email
+type Message struct {
+	Subject string
+	Html    string
+	Text    string
+}
+
+// Data used to check that the edited templates can be rendered
+var sampleData = TemplateData{
+	Link:  "http://example.com/token",
+	Title: "Title",
+	Body:  "Body",
+}
+
+const htmlLayout = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; line-height: 1.5;">
+%s
+</body>
+</html>
+`
+
+// Names of the templates, in the order they are listed
+var names = []sqlc.EmailTemplateName{
+	sqlc.EmailTemplateNameVerification,
+	sqlc.EmailTemplateNamePasswordReset,
+	sqlc.EmailTemplateNameAnnouncement,
+	sqlc.EmailTemplateNameTicketReply,
+}
+
+// Default templates, by name and locale, replaced by the ones edited by the
+// admins if present
+var defaults = map[sqlc.EmailTemplateName]map[string]sqlc.EmailTemplate{
+	sqlc.EmailTemplateNameVerification: {
+		"en": {
+			Subject: "Email Verification Required",
+			Html: `<p>Hello,</p>
+<p>To confirm your email address, please click the link below:</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>
+<p>Thank you!</p>`,
+			Text: "Hello,\n\nTo confirm your email address, please click the link below:\n{{.Link}}\n\nThank you!",
+		},
+		"it": {
+			Subject: "Verifica dell'email richiesta",
+			Html: `<p>Ciao,</p>
+<p>Per confermare il tuo indirizzo email, clicca sul link qui sotto:</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>
+<p>Grazie!</p>`,
+			Text: "Ciao,\n\nPer confermare il tuo indirizzo email, clicca sul link qui sotto:\n{{.Link}}\n\nGrazie!",
+		},
+	},
+	sqlc.EmailTemplateNamePasswordReset: {
+		"en": {
+			Subject: "Password Reset",
+			Html: `<p>Hello,</p>
+<p>A password reset was requested for your account, click the link below to choose a new password:</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>
+<p>If you did not request it, you can ignore this email.</p>`,
+			Text: "Hello,\n\nA password reset was requested for your account, click the link below to choose a new password:\n{{.Link}}\n\nIf you did not request it, you can ignore this email.",
+		},
+		"it": {
+			Subject: "Ripristino della password",
+			Html: `<p>Ciao,</p>
+<p>È stato richiesto il ripristino della password del tuo account, clicca sul link qui sotto per sceglierne una nuova:</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>
+<p>Se non l'hai richiesto, puoi ignorare questa email.</p>`,
+			Text: "Ciao,\n\nÈ stato richiesto il ripristino della password del tuo account, clicca sul link qui sotto per sceglierne una nuova:\n{{.Link}}\n\nSe non l'hai richiesto, puoi ignorare questa email.",
+		},
+	},
+	sqlc.EmailTemplateNameAnnouncement: {
+		"en": {
+			Subject: "Announcement: {{.Title}}",
+			Html: `<h2>{{.Title}}</h2>
+<p style="white-space: pre-wrap;">{{.Body}}</p>`,
+			Text: "{{.Title}}\n\n{{.Body}}",
+		},
+		"it": {
+			Subject: "Annuncio: {{.Title}}",
+			Html: `<h2>{{.Title}}</h2>
+<p style="white-space: pre-wrap;">{{.Body}}</p>`,
+			Text: "{{.Title}}\n\n{{.Body}}",
+		},
+	},
+	sqlc.EmailTemplateNameTicketReply: {
+		"en": {
+			Subject: "New reply to your ticket: {{.Title}}",
+			Html: `<p>The organizers replied to your ticket <b>{{.Title}}</b>:</p>
+<p style="white-space: pre-wrap;">{{.Body}}</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>`,
+			Text: "The organizers replied to your ticket \"{{.Title}}\":\n\n{{.Body}}\n\n{{.Link}}",
+		},
+		"it": {
+			Subject: "Nuova risposta al tuo ticket: {{.Title}}",
+			Html: `<p>Gli organizzatori hanno risposto al tuo ticket <b>{{.Title}}</b>:</p>
+<p style="white-space: pre-wrap;">{{.Body}}</p>
+<p><a href="{{.Link}}">{{.Link}}</a></p>`,
+			Text: "Gli organizzatori hanno risposto al tuo ticket \"{{.Title}}\":\n\n{{.Body}}\n\n{{.Link}}",
+		},
+	},
+}
+
+// DefaultTemplates returns the default templates of every name and locale
+func DefaultTemplates() []sqlc.EmailTemplate {
+	var templates []sqlc.EmailTemplate
+	for _, name := range names {
+		for _, locale := range consts.Locales {
+			templates = append(templates, DefaultTemplate(name, locale))
+		}
+	}
+	return templates
+}
+
+// DefaultTemplate returns the default template of a name in a locale,
+// falling back to the default locale
+func DefaultTemplate(name sqlc.EmailTemplateName, locale string) sqlc.EmailTemplate {
+	if !slices.Contains(consts.Locales, locale) {
+		locale = consts.DefaultLocale
+	}
+	tmpl, ok := defaults[name][locale]
+	if !ok {
+		tmpl = defaults[name][consts.DefaultLocale]
+	}
+	tmpl.Name = name
+	tmpl.Locale = locale
+	return tmpl
+}
+
+// GetTemplate returns the template of a name in a locale, the one edited by
+// the admins if present
+func GetTemplate(ctx context.Context, name sqlc.EmailTemplateName, locale string) (*sqlc.EmailTemplate, error) {
+	tmpl := DefaultTemplate(name, locale)
+
+	override, err := db.Sql.GetEmailTemplate(ctx, sqlc.GetEmailTemplateParams{
+		Name:   name,
+		Locale: tmpl.Locale,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &tmpl, nil
+		}
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+// Render executes a template, the html body is wrapped in a minimal document
+func Render(tmpl *sqlc.EmailTemplate, data *TemplateData) (*Message, error) {
+	subject, err := renderText(tmpl.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := renderText(tmpl.Text, data)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := htmltemplate.New("html").Option("missingkey=error").Parse(tmpl.Html)
+	if err != nil {
+		return nil, err
+	}
+	var html bytes.Buffer
+	err = h.Execute(&html, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Subject: subject,
+		Html:    fmt.Sprintf(htmlLayout, html.String()),
+		Text:    text,
+	}, nil
+}
+
+func renderText(source string, data *TemplateData) (string, error) {
+	t, err := texttemplate.New("text").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// ValidTemplate reports whether a template can be rendered
+func ValidTemplate(tmpl *sqlc.EmailTemplate) bool {
+	_, err := Render(tmpl, &sampleData)
+	return err == nil
+}
+
+// RenderTemplate renders the template of a name in a locale
+func RenderTemplate(ctx context.Context, name sqlc.EmailTemplateName, locale string, data *TemplateData) (*Message, error) {
+	tmpl, err := GetTemplate(ctx, name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(tmpl, data)
+}
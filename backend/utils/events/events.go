@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"trxd/db"
+)
+
+// Storage channel the live events are published on
+const channel = "events"
+
+const (
+	TypeAnnouncement = "announcement"
+)
+
+// Event is a message of the live event stream
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Closed on shutdown, ending the open streams
+var (
+	closing      = make(chan struct{})
+	closingMutex sync.Mutex
+)
+
+// Publish sends the event to the streams open on every replica
+func Publish(ctx context.Context, eventType string, data any) error {
+	msg, err := json.Marshal(Event{Type: eventType, Data: data})
+	if err != nil {
+		return err
+	}
+
+	return db.StoragePublish(ctx, channel, string(msg))
+}
+
+// Subscribe returns the events encoded as JSON, until the returned function
+// is called
+func Subscribe(ctx context.Context) (<-chan string, func(), error) {
+	return db.StorageSubscribe(ctx, channel)
+}
+
+// Closing is closed when the server shuts down
+func Closing() <-chan struct{} {
+	closingMutex.Lock()
+	defer closingMutex.Unlock()
+
+	return closing
+}
+
+// Close ends the open streams, which would otherwise keep the server from
+// shutting down
+func Close() {
+	closingMutex.Lock()
+	defer closingMutex.Unlock()
+
+	close(closing)
+	closing = make(chan struct{})
+}
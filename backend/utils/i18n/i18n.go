@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"trxd/utils/consts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Translations of the messages, by locale. The English messages are the keys
+// and are returned for the missing entries
+var catalogs = map[string]map[string]string{
+	"it": italian,
+}
+
+// T translates a message into a locale
+func T(locale string, message string) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Negotiate picks the supported locale preferred by an Accept-Language header,
+// matching on the primary language subtag
+func Negotiate(header string) string {
+	best := consts.DefaultLocale
+	bestQuality := 0.0
+	for entry := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if quality > bestQuality && slices.Contains(consts.Locales, language) {
+			best = language
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// Locale returns the locale of a request: the one chosen by the logged in
+// user if any, otherwise the one negotiated from Accept-Language
+func Locale(c *fiber.Ctx) string {
+	if c == nil {
+		return consts.DefaultLocale
+	}
+	if locale, ok := c.Locals("locale").(string); ok && locale != "" {
+		return locale
+	}
+	return Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// Translate translates a message into the locale of a request
+func Translate(c *fiber.Ctx, message string) string {
+	return T(Locale(c), message)
+}
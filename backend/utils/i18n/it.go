@@ -0,0 +1,188 @@
+package i18n
+
+import "trxd/utils/consts"
+
+var italian = map[string]string{
+	consts.Unauthorized:        "Non autorizzato",
+	consts.Forbidden:           "Accesso negato",
+	consts.NotFound:            "Non trovato",
+	consts.InternalServerError: "Errore interno del server",
+	consts.TooManyRequests:     "Troppe richieste",
+
+	consts.AlreadyAnActiveInstance: "Hai già un'istanza attiva",
+	consts.AlreadyExtended:         "L'istanza è già in fase di estensione",
+	consts.AlreadyInTeam:           "Sei già in un team",
+	consts.AlreadyLoggedIn:         "Hai già effettuato l'accesso",
+	consts.AlreadyRegistered:       "Sei già registrato",
+
+	consts.ChallengeNotInstanciable: "La challenge non prevede istanze",
+	consts.ChallengeNotSolved:       "La challenge non è ancora stata risolta",
+	consts.NotChallengeOwner:        "Non sei tra i proprietari della challenge",
+	consts.OwnerCannotBeRemoved:     "Il proprietario non può essere rimosso, trasferisci prima la proprietà",
+	consts.UserNotAuthor:            "L'utente non è un autore",
+
+	consts.ExtensionCooldown:      "L'istanza è stata estesa troppo di recente",
+	consts.ExtensionNotAllowedYet: "L'istanza non può ancora essere estesa",
+	consts.MaxExtensionsReached:   "Numero massimo di estensioni raggiunto",
+	consts.MaxLifetimeReached:     "Durata massima dell'istanza raggiunta",
+
+	consts.DisabledRegistrations: "Le registrazioni sono disabilitate",
+
+	consts.ErrorBeginningTransaction:     "Errore nell'avvio della transazione",
+	consts.ErrorCreatingAnnouncement:     "Errore nella creazione dell'annuncio",
+	consts.ErrorChangingUserRole:         "Errore nella modifica del ruolo dell'utente",
+	consts.ErrorCommittingTransaction:    "Errore nel completamento della transazione",
+	consts.ErrorCreatingAttachments:      "Errore nella creazione degli allegati",
+	consts.ErrorCreatingAttachmentsDir:   "Errore nella creazione della cartella degli allegati",
+	consts.ErrorCreatingCategory:         "Errore nella creazione della categoria",
+	consts.ErrorCreatingChallenge:        "Errore nella creazione della challenge",
+	consts.ErrorCreatingFlag:             "Errore nella creazione della flag",
+	consts.ErrorCreatingInstance:         "Errore nella creazione dell'istanza",
+	consts.ErrorCreatingRole:             "Errore nella creazione del ruolo",
+	consts.ErrorCreatingTicket:           "Errore nella creazione del ticket",
+	consts.ErrorDeletingAnnouncement:     "Errore nell'eliminazione dell'annuncio",
+	consts.ErrorDeletingAttachment:       "Errore nell'eliminazione dell'allegato",
+	consts.ErrorDeletingCategory:         "Errore nell'eliminazione della categoria",
+	consts.ErrorDeletingChallenge:        "Errore nell'eliminazione della challenge",
+	consts.ErrorDeletingEmailTemplate:    "Errore nell'eliminazione del modello email",
+	consts.ErrorDeletingFeedback:         "Errore nell'eliminazione del feedback",
+	consts.ErrorDeletingFlag:             "Errore nell'eliminazione della flag",
+	consts.ErrorDeletingInstance:         "Errore nell'eliminazione dell'istanza",
+	consts.ErrorDeletingRole:             "Errore nell'eliminazione del ruolo",
+	consts.ErrorDeletingWriteup:          "Errore nell'eliminazione del writeup",
+	consts.ErrorDestroyingSession:        "Errore nella chiusura della sessione",
+	consts.ErrorFetchingAnnouncements:    "Errore nel recupero degli annunci",
+	consts.ErrorSubscribingEvents:        "Errore nella sottoscrizione agli eventi",
+	consts.ErrorDeletingSubmission:       "Errore nell'eliminazione della sottomissione",
+	consts.ErrorFetchingAttachment:       "Errore nel recupero dell'allegato",
+	consts.ErrorFetchingAuditLog:         "Errore nel recupero del registro di audit",
+	consts.ErrorFetchingCategories:       "Errore nel recupero delle categorie",
+	consts.ErrorFetchingCategory:         "Errore nel recupero della categoria",
+	consts.ErrorFetchingChallenge:        "Errore nel recupero della challenge",
+	consts.ErrorFetchingChallenges:       "Errore nel recupero delle challenge",
+	consts.ErrorFetchingConfig:           "Errore nel recupero della configurazione",
+	consts.ErrorFetchingConfigs:          "Errore nel recupero delle configurazioni",
+	consts.ErrorFetchingEmailTemplates:   "Errore nel recupero dei modelli email",
+	consts.ErrorFetchingInstance:         "Errore nel recupero dell'istanza",
+	consts.ErrorFetchingInstanceEvents:   "Errore nel recupero degli eventi delle istanze",
+	consts.ErrorFetchingInstances:        "Errore nel recupero delle istanze",
+	consts.ErrorFetchingProofOfWork:      "Errore nel recupero della proof of work",
+	consts.ErrorFetchingRoles:            "Errore nel recupero dei ruoli",
+	consts.ErrorFetchingScoreboardGraph:  "Errore nel recupero del grafico della classifica",
+	consts.ErrorFetchingSession:          "Errore nel recupero della sessione",
+	consts.ErrorFetchingStats:            "Errore nel recupero delle statistiche",
+	consts.ErrorFetchingSubmissions:      "Errore nel recupero delle sottomissioni",
+	consts.ErrorFetchingTeam:             "Errore nel recupero del team",
+	consts.ErrorFetchingTickets:          "Errore nel recupero dei ticket",
+	consts.ErrorFetchingUser:             "Errore nel recupero dell'utente",
+	consts.ErrorFetchingUsers:            "Errore nel recupero degli utenti",
+	consts.ErrorFetchingWriteups:         "Errore nel recupero dei writeup",
+	consts.ErrorGeneratingPassword:       "Errore nella generazione della password casuale",
+	consts.ErrorHashingFile:              "Errore nel calcolo dell'hash del file",
+	consts.ErrorInitializingEmailClient:  "Errore nell'inizializzazione del client email",
+	consts.ErrorLoggingIn:                "Errore durante l'accesso",
+	consts.ErrorParsingTime:              "Errore nella lettura dell'orario",
+	consts.ErrorRecordingAudit:           "Errore nella registrazione della voce di audit",
+	consts.ErrorRegisteringTeam:          "Errore nella registrazione del team",
+	consts.ErrorRegisteringUser:          "Errore nella registrazione dell'utente",
+	consts.ErrorRenderingEmail:           "Errore nella composizione dell'email",
+	consts.ErrorResettingTeamPassword:    "Errore nel ripristino della password del team",
+	consts.ErrorResettingUserPassword:    "Errore nel ripristino della password dell'utente",
+	consts.ErrorSavingFile:               "Errore nel salvataggio del file",
+	consts.ErrorSavingSession:            "Errore nel salvataggio della sessione",
+	consts.ErrorSendingTicketMessage:     "Errore nell'invio del messaggio del ticket",
+	consts.ErrorSendingVerificationEmail: "Errore nell'invio dell'email di verifica",
+	consts.ErrorSigningVerificationToken: "Errore nella firma del token di verifica",
+	consts.ErrorSubmittingFeedback:       "Errore nell'invio del feedback",
+	consts.ErrorSubmittingFlag:           "Errore nell'invio della flag",
+	consts.ErrorSubmittingWriteup:        "Errore nell'invio del writeup",
+	consts.ErrorUpdatingAnnouncement:     "Errore nell'aggiornamento dell'annuncio",
+	consts.ErrorUpdatingCategory:         "Errore nell'aggiornamento della categoria",
+	consts.ErrorUpdatingChallenge:        "Errore nell'aggiornamento della challenge",
+	consts.ErrorUpdatingConfig:           "Errore nell'aggiornamento della configurazione",
+	consts.ErrorUpdatingEmailTemplate:    "Errore nell'aggiornamento del modello email",
+	consts.ErrorUpdatingOwners:           "Errore nell'aggiornamento dei proprietari della challenge",
+	consts.ErrorUpdatingRole:             "Errore nell'aggiornamento del ruolo",
+	consts.ErrorUpdatingTeam:             "Errore nell'aggiornamento del team",
+	consts.ErrorUpdatingTicket:           "Errore nell'aggiornamento del ticket",
+	consts.ErrorUpdatingUser:             "Errore nell'aggiornamento dell'utente",
+	consts.ErrorUpdatingWriteup:          "Errore nell'aggiornamento del writeup",
+
+	consts.InvalidChallengeID:      "ID della challenge non valido, non deve essere negativo",
+	consts.InvalidCountry:          "Codice paese non valido, deve essere ISO3166-1 alpha-3",
+	consts.InvalidCredentials:      "Email o password non validi",
+	consts.InvalidDomain:           "Dominio non valido",
+	consts.InvalidEmail:            "Formato email non valido",
+	consts.InvalidEnvs:             "Variabili d'ambiente non valide",
+	consts.InvalidFilePath:         "Percorso del file non valido",
+	consts.InvalidFormData:         "Dati del form non validi",
+	consts.InvalidHttpUrl:          "URL http(s) non valido",
+	consts.InvalidImage:            "Immagine non valida",
+	consts.InvalidJSON:             "Formato JSON non valido",
+	consts.InvalidJWT:              "JWT non valido",
+	consts.InvalidJWTSecret:        "segreto JWT non valido",
+	consts.InvalidMaxCpu:           "Max CPU non valido, deve essere un intero positivo a 32 bit",
+	consts.InvalidMultipartForm:    "Form multipart non valido",
+	consts.InvalidParam:            "Parametro non valido",
+	consts.InvalidPlacement:        "Vincolo di posizionamento non valido, deve essere nella forma attributo==valore o attributo!=valore",
+	consts.InvalidProofOfWork:      "Proof of work non valida o già usata",
+	consts.InvalidReservedCpu:      "Reserved CPU non valido, deve essere un intero positivo a 32 bit",
+	consts.InvalidRole:             "Ruolo non valido",
+	consts.InvalidSigningAlgorithm: "algoritmo di firma non valido",
+	consts.InvalidSigningMethod:    "metodo di firma non valido",
+	consts.InvalidTeamCredentials:  "Nome o password non validi",
+	consts.InvalidTeamID:           "ID del team non valido, non deve essere negativo",
+	consts.InvalidTemplate:         "Modello non valido",
+	consts.InvalidToken:            "token non valido",
+	consts.InvalidUserID:           "ID dell'utente non valido, non deve essere negativo",
+	consts.InvalidUserName:         "Nome utente non valido",
+	consts.InvalidWriteup:          "Writeup non valido, deve avere un contenuto o un URL",
+
+	consts.MaxError:   "{0} non deve superare {1}",
+	consts.MinError:   "{0} deve essere almeno {1}",
+	consts.OneOfError: "{0} deve essere uno tra: {1}",
+
+	consts.AttachmentAlreadyExists:    "L'allegato esiste già",
+	consts.CategoryAlreadyExists:      "La categoria esiste già",
+	consts.ChallengeAlreadyExists:     "La challenge esiste già",
+	consts.ChallengeNameAlreadyExists: "Il nome della challenge esiste già",
+	consts.FlagAlreadyExists:          "La flag esiste già",
+	consts.NameAlreadyTaken:           "Nome già in uso",
+	consts.RoleAlreadyExists:          "Il ruolo esiste già",
+	consts.TeamAlreadyExists:          "Il team esiste già",
+	consts.UserAlreadyExists:          "L'utente esiste già",
+
+	consts.AnnouncementNotFound:  "Annuncio non trovato",
+	consts.AttachmentNotFound:    "Allegato non trovato",
+	consts.CategoryNotFound:      "Categoria non trovata",
+	consts.ChallengeNotFound:     "Challenge non trovata",
+	consts.ConfigNotFound:        "Configurazione non trovata",
+	consts.EmailTemplateNotFound: "Modello email non trovato",
+	consts.FeedbackNotFound:      "Feedback non trovato",
+	consts.InstanceNotFound:      "Istanza non trovata",
+	consts.OwnerNotFound:         "Proprietario non trovato",
+	consts.RoleNotFound:          "Ruolo non trovato",
+	consts.SubmissionNotFound:    "Sottomissione non trovata",
+	consts.TeamNotFound:          "Team non trovato",
+	consts.TicketNotFound:        "Ticket non trovato",
+	consts.UserNotFound:          "Utente non trovato",
+	consts.WriteupNotFound:       "Writeup non trovato",
+
+	consts.MissingLifetime:           "durata globale mancante",
+	consts.MissingProofOfWork:        "Proof of work richiesta",
+	consts.TooManyPendingPows:        "Troppe proof of work in sospeso, risolvi o attendi le precedenti",
+	consts.MissingRequiredFields:     "Campi obbligatori mancanti",
+	consts.NoDataToUpdate:            "Nessun dato da aggiornare",
+	consts.NoNodeAvailable:           "Nessun nodo disponibile per l'istanza",
+	consts.TeamOnlyRequiresProxy:     "Le istanze riservate al team richiedono il proxy integrato per il loro tipo di connessione",
+	consts.EmailNotConfigured:        "Le email non possono essere inviate finché il server email non è configurato",
+	consts.SharedInstanceFlag:        "Le istanze condivise non possono usare flag per istanza",
+	consts.InstanceFlagNotRendered:   "La variabile FLAG deve contenere {{.Flag}} quando le flag per istanza sono attive",
+	consts.PermissionNotHeld:         "Non puoi concedere permessi che non possiedi",
+	consts.AdminRoleProtected:        "Solo gli admin possono cambiare i ruoli di un admin",
+	consts.NotLoggedIn:               "Accesso non effettuato",
+	consts.NotStartedYet:             "Non ancora iniziato",
+	consts.AlreadyEnded:              "Già terminato",
+	consts.EmailClientNotInitialized: "il client email non è inizializzato",
+	consts.VerificationAlreadySent:   "email di verifica già inviata di recente",
+}
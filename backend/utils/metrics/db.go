@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"time"
+	"trxd/db"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const redisPingTimeout = 2 * time.Second
+
+func dbGauge(name string, help string, value func() float64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      name,
+		Help:      help,
+	}, value)
+}
+
+func dbCounter(name string, help string, value func() float64) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      name,
+		Help:      help,
+	}, value)
+}
+
+func init() {
+	dbGauge("max_open_connections", "Maximum number of open connections to the database",
+		func() float64 { return float64(db.Stats().MaxOpenConnections) })
+	dbGauge("open_connections", "Number of established connections to the database",
+		func() float64 { return float64(db.Stats().OpenConnections) })
+	dbGauge("in_use_connections", "Number of connections currently in use",
+		func() float64 { return float64(db.Stats().InUse) })
+	dbGauge("idle_connections", "Number of idle connections",
+		func() float64 { return float64(db.Stats().Idle) })
+	dbCounter("wait_count_total", "Number of connections waited for",
+		func() float64 { return float64(db.Stats().WaitCount) })
+	dbCounter("wait_duration_seconds_total", "Time spent waiting for a connection",
+		func() float64 { return db.Stats().WaitDuration.Seconds() })
+
+	// Measured at each scrape, NaN if Redis is unreachable
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "redis",
+		Name:      "ping_seconds",
+		Help:      "Round trip time of a ping to Redis",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+		defer cancel()
+
+		rtt, err := db.StoragePing(ctx)
+		if err != nil {
+			return math.NaN()
+		}
+		return rtt.Seconds()
+	})
+}
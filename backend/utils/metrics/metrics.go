@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const Namespace = "trxd"
+
+var InstancesReconciled = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "instancer",
+	Name:      "reconciled_total",
+	Help:      "Number of orphaned Docker objects and dangling instances handled by the reconciler",
+}, []string{"action", "status"})
+
+var RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "Latency of the HTTP requests by route and status",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+var Submissions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "submissions",
+	Name:      "total",
+	Help:      "Number of flag submissions by status",
+}, []string{"status"})
+
+var FirstBloods = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: Namespace,
+	Subsystem: "submissions",
+	Name:      "first_bloods_total",
+	Help:      "Number of first bloods",
+})
+
+var InstanceOperations = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: Namespace,
+	Subsystem: "instancer",
+	Name:      "operation_duration_seconds",
+	Help:      "Duration of the instance creations and deletions, failed ones included",
+	Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+}, []string{"operation", "status"})
+
+var ReclaimLag = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Subsystem: "instancer",
+	Name:      "reclaim_lag_seconds",
+	Help:      "Delay between the expiration and the reclaim of the last batch of expired instances",
+})
+
+// Status is the label value of an operation outcome
+func Status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -20,6 +20,7 @@ type apiTestSession struct {
 	app      *fiber.App
 	global   bool
 	Cookies  []*http.Cookie
+	Header   http.Header // Sent along with every request
 	lastResp *http.Response
 }
 
@@ -28,6 +29,7 @@ func NewApiTestSession(t *testing.T, app *fiber.App, globalRequest ...bool) *api
 		t:       t,
 		app:     app,
 		Cookies: []*http.Cookie{},
+		Header:  http.Header{},
 	}
 
 	s.Get("/info", nil, http.StatusOK)
@@ -55,6 +57,9 @@ func (s *apiTestSession) updateCookies(newCookies []*http.Cookie) {
 }
 
 func (s *apiTestSession) SendRequest(req *http.Request, expectedStatus int) *http.Response {
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
 	for _, cookie := range s.Cookies {
 		if cookie.Name == "csrf_" {
 			req.Header.Set("X-CSRF-Token", cookie.Value)
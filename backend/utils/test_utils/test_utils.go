@@ -159,7 +159,7 @@ func RegisterTeam(t *testing.T, name, password string, userID int32) *sqlc.Team
 
 func CreateChallenge(t *testing.T, name string, category string, description string,
 	challType sqlc.DeployType, maxPoints int32, scoreType sqlc.ScoreType) *sqlc.Challenge {
-	chall, err := challenges_create.CreateChallenge(t.Context(), name, category, description, challType, maxPoints, scoreType)
+	chall, err := challenges_create.CreateChallenge(t.Context(), -1, name, category, description, challType, maxPoints, scoreType)
 	if err != nil {
 		Fatalf(t, "Failed to create challenge %s: %v", name, err)
 	}
@@ -172,7 +172,7 @@ func CreateChallenge(t *testing.T, name string, category string, description str
 
 func TryCreateChallenge(t *testing.T, name string, category string, description string,
 	challType sqlc.DeployType, maxPoints int32, scoreType sqlc.ScoreType) *sqlc.Challenge {
-	chall, err := challenges_create.CreateChallenge(t.Context(), name, category, description, challType, maxPoints, scoreType)
+	chall, err := challenges_create.CreateChallenge(t.Context(), -1, name, category, description, challType, maxPoints, scoreType)
 	if err != nil {
 		Fatalf(t, "Failed to create challenge %s: %v", name, err)
 	}
@@ -190,6 +190,17 @@ func UnveilChallenge(t *testing.T, id int32) {
 	}
 }
 
+func AddChallengeOwner(t *testing.T, challID int32, userID int32, role sqlc.OwnerRole) {
+	err := db.Sql.SetChallengeOwner(t.Context(), sqlc.SetChallengeOwnerParams{
+		ChallID: challID,
+		UserID:  userID,
+		Role:    role,
+	})
+	if err != nil {
+		Fatalf(t, "Failed to add owner %d to challenge %d: %v", userID, challID, err)
+	}
+}
+
 func GetTeamByName(t *testing.T, name string) *sqlc.Team {
 	team, err := db.GetTeamByName(t.Context(), name)
 	if err != nil {
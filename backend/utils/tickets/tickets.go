@@ -0,0 +1,117 @@
+package tickets
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"trxd/db"
+	"trxd/db/sqlc"
+	"trxd/utils/consts"
+	"trxd/utils/discord"
+	"trxd/utils/email"
+	"trxd/utils/log"
+)
+
+// Directory of the attachments of a ticket, not served statically
+func AttachmentsDir(ticketID int32) string {
+	return fmt.Sprintf("attachments/tickets/%d/", ticketID)
+}
+
+// Access returns the ticket if the user can see it, and whether they reply
+// as organizers: the users managing the tickets see every ticket (the authors
+// only the ones about the challenges they own) and the players the ones of
+// their team
+func Access(ctx context.Context, id int32, uid int32, tid int32, role sqlc.UserRole, manage bool) (*sqlc.GetTicketRow, bool, error) {
+	ticket, err := db.Sql.GetTicket(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	if manage {
+		owned := role != sqlc.UserRoleAuthor
+		if !owned && ticket.ChallID.Valid {
+			owned, err = db.CanManageChallenge(ctx, uid, role, ticket.ChallID.Int32)
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		if owned {
+			return &ticket, true, nil
+		}
+	}
+
+	if ticket.TeamID == tid {
+		return &ticket, false, nil
+	}
+
+	return nil, false, nil
+}
+
+// Notify forwards the messages of the teams to the organizers, and emails
+// the replies of the organizers to the members of the team
+func Notify(ctx context.Context, ticket *sqlc.GetTicketRow, user string, body string, staff bool) {
+	if !staff {
+		err := discord.BroadcastTicketMessage(ctx, ticket, user, body)
+		if err != nil {
+			log.Error("Failed to notify ticket message:", "id", ticket.ID, "err", err)
+		}
+		return
+	}
+
+	err := sendEmails(ctx, ticket, body)
+	if err != nil {
+		log.Error("Failed to email ticket reply:", "id", ticket.ID, "err", err)
+	}
+}
+
+// sendEmails sends the reply to every member of the team, in the locale
+// they chose
+func sendEmails(ctx context.Context, ticket *sqlc.GetTicketRow, body string) error {
+	err := email.InitEmailClientFromConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	domain, err := db.GetConfig(ctx, "domain")
+	if err != nil {
+		return err
+	}
+
+	users, err := db.Sql.GetTeamEmails(ctx, sql.NullInt32{Int32: ticket.TeamID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	data := &email.TemplateData{
+		Link:  fmt.Sprintf("http://%s/tickets/%d", domain, ticket.ID),
+		Title: ticket.Title,
+		Body:  body,
+	}
+	messages := make(map[string]*email.Message)
+	for _, user := range users {
+		locale := consts.DefaultLocale
+		if user.Locale.Valid {
+			locale = user.Locale.String
+		}
+
+		msg, ok := messages[locale]
+		if !ok {
+			msg, err = email.RenderTemplate(ctx, sqlc.EmailTemplateNameTicketReply, locale, data)
+			if err != nil {
+				return err
+			}
+			messages[locale] = msg
+		}
+
+		err := email.SendEmail(ctx, user.Email, msg.Subject, msg.Html, msg.Text)
+		if err != nil {
+			log.Error("Failed to send ticket reply email:", "id", ticket.ID, "to", user.Email, "err", err)
+		}
+	}
+
+	return nil
+}
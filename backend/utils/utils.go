@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"trxd/utils/consts"
+	"trxd/utils/i18n"
 
 	"trxd/utils/log"
 
@@ -20,14 +22,64 @@ func In[T comparable](slice T, items []T) bool {
 	return false
 }
 
+// Can reports whether the logged in user holds the permission, through their
+// user role or their custom role
+func Can(c *fiber.Ctx, permission string) bool {
+	permissions, ok := c.Locals("permissions").([]string)
+	return ok && In(permission, permissions)
+}
+
+// CanGrant reports whether the logged in user holds all the permissions, as
+// no one can grant more than their own
+func CanGrant(c *fiber.Ctx, permissions []string) bool {
+	for _, permission := range permissions {
+		if !Can(c, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// Header through which clients opt into the structured errors, the others
+// keep receiving {"error": message} while the frontend migrates
+const HeaderErrorFormat = "X-Error-Format"
+const ErrorFormatStructured = "structured"
+
+type ErrorEntry struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+// Structured reports whether the client opted into the structured errors.
+// Only those are translated, the compatibility format stays in English as
+// clients match its messages.
+func Structured(c *fiber.Ctx) bool {
+	return c != nil && c.Get(HeaderErrorFormat) == ErrorFormatStructured
+}
+
 func Error(c *fiber.Ctx, status int, message string, err ...error) error {
 	if len(err) != 0 {
 		log.Error("API Error:", "desc", message, "err", err[0])
 	}
+
+	entry := ErrorEntry{Code: consts.ErrorCode(message), Message: message}
+	if Structured(c) {
+		entry.Message = i18n.Translate(c, message)
+	}
+	return Errors(c, status, entry)
+}
+
+// Errors responds with one or more errors, only the first one is reported to
+// the clients using the compatibility format
+func Errors(c *fiber.Ctx, status int, entries ...ErrorEntry) error {
 	if c == nil {
-		return errors.New(message)
+		return errors.New(entries[0].Message)
+	}
+	if !Structured(c) {
+		return c.Status(status).JSON(fiber.Map{"error": entries[0].Message})
 	}
-	return c.Status(status).JSON(fiber.Map{"error": message})
+	return c.Status(status).JSON(fiber.Map{"errors": entries})
 }
 
 func BytesToHex(data []byte) (string, error) {
@@ -2,23 +2,37 @@ package validator
 
 import (
 	"trxd/utils/consts"
+	"trxd/utils/i18n"
 
 	"trxd/utils/log"
 
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/it"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
+	it_translations "github.com/go-playground/validator/v10/translations/it"
 )
 
+// Registers the default translations of the validator into a translator
+type defaultTranslations func(*validator.Validate, ut.Translator) error
+
 func initTranslation() {
 	en := en.New()
-	uni = ut.New(en, en)
-	trans, _ = uni.GetTranslator("en")
-	err := en_translations.RegisterDefaultTranslations(validate, trans)
-	if err != nil {
-		log.Error("Failed to register default translations", "err", err)
-		return
+	uni = ut.New(en, en, it.New())
+
+	defaults := map[string]defaultTranslations{
+		"en": en_translations.RegisterDefaultTranslations,
+		"it": it_translations.RegisterDefaultTranslations,
+	}
+	for _, locale := range consts.Locales {
+		trans, _ := uni.GetTranslator(locale)
+		err := defaults[locale](validate, trans)
+		if err != nil {
+			log.Error("Failed to register default translations", "locale", locale, "err", err)
+			return
+		}
+		translators[locale] = trans
 	}
 
 	registerTranslation("required", consts.MissingRequiredFields)
@@ -27,26 +41,47 @@ func initTranslation() {
 	registerTranslation("oneof", consts.OneOfError)
 	registerTranslation("email", consts.InvalidEmail)
 	registerTranslation("jwt", consts.InvalidJWT)
+	registerTranslation("http_url", consts.InvalidHttpUrl)
 
 	registerTranslation("country", consts.InvalidCountry)
 	registerTranslation("challenge_envs", consts.InvalidEnvs)
 	registerTranslation("challenge_max_cpu", consts.InvalidMaxCpu)
+	registerTranslation("challenge_placement", consts.InvalidPlacement)
+	registerTranslation("challenge_reserved_cpu", consts.InvalidReservedCpu)
+}
+
+// Codes of the validation errors, by tag
+var codes = make(map[string]string)
+
+// Translators of the validation errors, by locale
+var translators = make(map[string]ut.Translator)
+
+// translator returns the translator of a locale, English if not supported
+func translator(locale string) ut.Translator {
+	if trans, ok := translators[locale]; ok {
+		return trans
+	}
+	return translators[consts.DefaultLocale]
 }
 
 func registerTranslation(tag string, format string) {
-	err := validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
-		return ut.Add(tag, format, true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		var t string
-		field := fe.Field()
-		if field != "" {
-			t, _ = ut.T(tag, field, fe.Param())
-		} else {
-			t, _ = ut.T(tag, fe.Tag(), fe.Param())
+	codes[tag] = consts.ErrorCode(format)
+
+	for locale, trans := range translators {
+		err := validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+			return ut.Add(tag, i18n.T(locale, format), true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			var t string
+			field := fe.Field()
+			if field != "" {
+				t, _ = ut.T(tag, field, fe.Param())
+			} else {
+				t, _ = ut.T(tag, fe.Tag(), fe.Param())
+			}
+			return t
+		})
+		if err != nil {
+			log.Error("Error Registering Translation", "tag", tag, "locale", locale, "err", err)
 		}
-		return t
-	})
-	if err != nil {
-		log.Error("Error Registering Translation", "tag", tag, "err", err)
 	}
 }
@@ -3,9 +3,11 @@ package validator
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"strings"
 	"trxd/utils"
 	"trxd/utils/consts"
+	"trxd/utils/i18n"
 
 	"trxd/utils/log"
 
@@ -16,7 +18,27 @@ import (
 
 var validate *validator.Validate
 var uni *ut.UniversalTranslator
-var trans ut.Translator
+
+// Tags behind each alias, kept to describe the rules outside of the validator
+var aliases = make(map[string]string)
+
+func registerAlias(alias string, tags string) {
+	aliases[alias] = tags
+	validate.RegisterAlias(alias, tags)
+}
+
+// Rules expands the aliases of a validate tag into the underlying rules
+func Rules(tag string) []string {
+	var rules []string
+	for rule := range strings.SplitSeq(tag, ",") {
+		if tags, ok := aliases[rule]; ok {
+			rules = append(rules, Rules(tags)...)
+		} else if rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
 
 func registerValidation(tag string, fn validator.Func) {
 	err := validate.RegisterValidation(tag, fn)
@@ -30,38 +52,78 @@ func init() {
 
 	initTranslation()
 
-	validate.RegisterAlias("id", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
-	validate.RegisterAlias("password", fmt.Sprintf("min=%d,max=%d", consts.MinPasswordLen, consts.MaxPasswordLen))
+	registerAlias("id", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("password", fmt.Sprintf("min=%d,max=%d", consts.MinPasswordLen, consts.MaxPasswordLen))
 	registerValidation("country", validCountry)
 
-	validate.RegisterAlias("category_name", fmt.Sprintf("max=%d", consts.MaxCategoryLen))
-
-	validate.RegisterAlias("challenge_name", fmt.Sprintf("max=%d", consts.MaxChallNameLen))
-	validate.RegisterAlias("challenge_description", fmt.Sprintf("max=%d", consts.MaxChallDescLen))
-	validate.RegisterAlias("challenge_authors", fmt.Sprintf("dive,max=%d", consts.MaxAuthorNameLen))
-	validate.RegisterAlias("challenge_tags", fmt.Sprintf("dive,max=%d", consts.MaxTagNameLen))
-	validate.RegisterAlias("challenge_type", "oneof="+strings.Join(consts.DeployTypesStr, " "))
-	validate.RegisterAlias("challenge_max_points", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
-	validate.RegisterAlias("challenge_score_type", "oneof="+strings.Join(consts.ScoreTypesStr, " "))
-	validate.RegisterAlias("challenge_port", fmt.Sprintf("min=%d,max=%d", consts.MinPort, consts.MaxPort))
-	validate.RegisterAlias("challenge_conn_type", "oneof="+strings.Join(consts.ConnTypesStr, " "))
-	validate.RegisterAlias("challenge_lifetime", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
-	registerValidation("challenge_envs", validJson)
-	validate.RegisterAlias("challenge_max_memory", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("category_name", fmt.Sprintf("max=%d", consts.MaxCategoryLen))
+
+	registerAlias("challenge_name", fmt.Sprintf("max=%d", consts.MaxChallNameLen))
+	registerAlias("challenge_description", fmt.Sprintf("max=%d", consts.MaxChallDescLen))
+	registerAlias("challenge_authors", fmt.Sprintf("dive,max=%d", consts.MaxAuthorNameLen))
+	registerAlias("challenge_tags", fmt.Sprintf("dive,max=%d", consts.MaxTagNameLen))
+	registerAlias("challenge_type", "oneof="+strings.Join(consts.DeployTypesStr, " "))
+	registerAlias("challenge_max_points", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_score_type", "oneof="+strings.Join(consts.ScoreTypesStr, " "))
+	registerAlias("challenge_port", fmt.Sprintf("min=%d,max=%d", consts.MinPort, consts.MaxPort))
+	registerAlias("challenge_conn_type", "oneof="+strings.Join(consts.ConnTypesStr, " "))
+	registerAlias("challenge_lifetime", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerValidation("challenge_envs", validEnvs)
+	registerAlias("challenge_max_memory", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
 	registerValidation("challenge_max_cpu", validFloat)
+	registerAlias("challenge_pow_difficulty", fmt.Sprintf("min=0,max=%d", consts.MaxPowDifficulty))
+	registerAlias("challenge_max_lifetime", fmt.Sprintf("min=-1,max=%d", math.MaxInt32))
+	registerAlias("challenge_max_extensions", fmt.Sprintf("min=-1,max=%d", math.MaxInt32))
+	registerAlias("challenge_extension_cooldown", fmt.Sprintf("min=-1,max=%d", math.MaxInt32))
+	registerAlias("challenge_extension_window", fmt.Sprintf("min=-1,max=%d", math.MaxInt32))
+	registerAlias("challenge_cpu_threshold", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_memory_threshold", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_pids_threshold", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_network_threshold", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_threshold_duration", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerAlias("challenge_egress", "oneof="+strings.Join(consts.EgressPoliciesStr, " "))
+	registerValidation("challenge_placement", validPlacement)
+	registerAlias("challenge_reserved_memory", fmt.Sprintf("min=0,max=%d", math.MaxInt32))
+	registerValidation("challenge_reserved_cpu", validFloat)
+
+	registerAlias("attachments", fmt.Sprintf("dive,max=%d", consts.MaxAttachmentNameLen))
+
+	registerAlias("pow_solution", fmt.Sprintf("max=%d", consts.MaxPowSolutionLen))
+
+	registerAlias("flag", fmt.Sprintf("max=%d", consts.MaxFlagLen))
+
+	registerAlias("team_name", fmt.Sprintf("max=%d", consts.MaxTeamNameLen))
+
+	registerAlias("user_name", fmt.Sprintf("max=%d", consts.MaxUserNameLen))
+	registerAlias("user_email", fmt.Sprintf("max=%d,email", consts.MaxEmailLen))
+	registerAlias("user_role", "oneof="+strings.Join(consts.RolesStr, " "))
+	registerAlias("locale", "oneof="+strings.Join(consts.Locales, " "))
+
+	registerAlias("announcement_title", fmt.Sprintf("max=%d", consts.MaxAnnouncementTitleLen))
+	registerAlias("announcement_body", fmt.Sprintf("max=%d", consts.MaxAnnouncementBodyLen))
+	registerAlias("announcement_priority", "oneof="+strings.Join(consts.AnnouncementPrioritiesStr, " "))
+
+	registerAlias("feedback_vote", "oneof="+strings.Join(consts.FeedbackVotesStr, " "))
+	registerAlias("feedback_difficulty", fmt.Sprintf("min=%d,max=%d", consts.MinFeedbackDifficulty, consts.MaxFeedbackDifficulty))
+	registerAlias("feedback_comment", fmt.Sprintf("max=%d", consts.MaxFeedbackCommentLen))
+
+	registerAlias("writeup_content", fmt.Sprintf("max=%d", consts.MaxWriteupLen))
+	registerAlias("writeup_url", fmt.Sprintf("max=%d,http_url", consts.MaxWriteupUrlLen))
 
-	validate.RegisterAlias("attachments", fmt.Sprintf("dive,max=%d", consts.MaxAttachmentNameLen))
+	registerAlias("ticket_title", fmt.Sprintf("max=%d", consts.MaxTicketTitleLen))
+	registerAlias("ticket_message", fmt.Sprintf("max=%d", consts.MaxTicketMessageLen))
+	registerAlias("ticket_status", "oneof="+strings.Join(consts.TicketStatusesStr, " "))
 
-	validate.RegisterAlias("flag", fmt.Sprintf("max=%d", consts.MaxFlagLen))
+	registerAlias("owner_role", "oneof="+strings.Join(consts.OwnerRolesStr, " "))
 
-	validate.RegisterAlias("team_name", fmt.Sprintf("max=%d", consts.MaxTeamNameLen))
+	registerAlias("role_name", fmt.Sprintf("max=%d", consts.MaxRoleNameLen))
+	registerAlias("role_description", fmt.Sprintf("max=%d", consts.MaxRoleDescriptionLen))
+	registerAlias("permissions", "dive,oneof="+strings.Join(consts.Permissions, " "))
 
-	validate.RegisterAlias("user_name", fmt.Sprintf("max=%d", consts.MaxUserNameLen))
-	validate.RegisterAlias("user_email", fmt.Sprintf("max=%d,email", consts.MaxEmailLen))
-	validate.RegisterAlias("user_role", "oneof="+strings.Join(consts.RolesStr, " "))
+	registerAlias("email_template", "oneof="+strings.Join(consts.EmailTemplateNamesStr, " "))
 }
 
-func errHandle(c *fiber.Ctx, err error) error {
+func errHandle(c *fiber.Ctx, err error, s any) error {
 	if _, ok := err.(*validator.InvalidValidationError); ok {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.InternalServerError, err)
 	}
@@ -71,13 +133,78 @@ func errHandle(c *fiber.Ctx, err error) error {
 		return utils.Error(c, fiber.StatusInternalServerError, consts.InternalServerError, err)
 	}
 
-	return utils.Error(c, fiber.StatusBadRequest, errs[0].Translate(trans))
+	locale := consts.DefaultLocale
+	if utils.Structured(c) {
+		locale = i18n.Locale(c)
+	}
+	trans := translator(locale)
+	entries := make([]utils.ErrorEntry, len(errs))
+	for i, fe := range errs {
+		entries[i] = utils.ErrorEntry{
+			Code:    errorCode(fe),
+			Message: fe.Translate(trans),
+			Field:   jsonField(s, fe),
+		}
+	}
+
+	return utils.Errors(c, fiber.StatusBadRequest, entries...)
+}
+
+// errorCode returns the code of a validation error, falling back to its tag
+// for the ones without a custom translation
+func errorCode(fe validator.FieldError) string {
+	if code, ok := codes[fe.Tag()]; ok {
+		return code
+	}
+	if code, ok := codes[fe.ActualTag()]; ok {
+		return code
+	}
+	return fe.ActualTag()
+}
+
+// jsonField returns the path of the failing field as sent by the client,
+// following the json tags of the validated struct
+func jsonField(s any, fe validator.FieldError) string {
+	if s == nil {
+		return ""
+	}
+
+	t := reflect.TypeOf(s)
+	parts := strings.Split(fe.StructNamespace(), ".")[1:]
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice ||
+			t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+
+		name, index, _ := strings.Cut(part, "[")
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return ""
+		}
+		t = field.Type
+
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "" {
+			tag = name
+		}
+		if index != "" {
+			tag += "[" + index
+		}
+		path = append(path, tag)
+	}
+
+	return strings.Join(path, ".")
 }
 
 func Struct(c *fiber.Ctx, s any) (bool, error) {
 	err := validate.Struct(s)
 	if err != nil {
-		return false, errHandle(c, err)
+		return false, errHandle(c, err, s)
 	}
 
 	return true, nil
@@ -86,7 +213,7 @@ func Struct(c *fiber.Ctx, s any) (bool, error) {
 func Var(c *fiber.Ctx, v any, tag string) (bool, error) {
 	err := validate.Var(v, tag)
 	if err != nil {
-		return false, errHandle(c, err)
+		return false, errHandle(c, err, nil)
 	}
 
 	return true, nil
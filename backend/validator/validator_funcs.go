@@ -3,7 +3,10 @@ package validator
 import (
 	"encoding/json"
 	"math"
+	"regexp"
 	"strconv"
+	"trxd/instancer/infos"
+	"trxd/utils/consts"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -17,7 +20,7 @@ func validCountry(fl validator.FieldLevel) bool {
 	return err == nil
 }
 
-func validJson(fl validator.FieldLevel) bool {
+func validEnvs(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	if value == "" {
 		return true
@@ -25,7 +28,17 @@ func validJson(fl validator.FieldLevel) bool {
 
 	var tmp map[string]string
 	err := json.Unmarshal([]byte(value), &tmp)
-	return err == nil
+	if err != nil {
+		return false
+	}
+
+	for _, v := range tmp {
+		if infos.CheckTemplate(v) != nil {
+			return false
+		}
+	}
+
+	return true
 }
 
 func validFloat(fl validator.FieldLevel) bool {
@@ -40,3 +53,20 @@ func validFloat(fl validator.FieldLevel) bool {
 
 	return 0.0 < res && res <= math.MaxInt32
 }
+
+var placementRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]+\s*(==|!=)\s*\S+$`)
+
+func validPlacement(fl validator.FieldLevel) bool {
+	constraints, ok := fl.Field().Interface().([]string)
+	if !ok {
+		return false
+	}
+
+	for _, constraint := range constraints {
+		if len(constraint) > consts.MaxPlacementLen || !placementRegex.MatchString(constraint) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -126,6 +126,11 @@ func TestValidators(t *testing.T) {
 	varTest(t, "challenge_envs", `{"key": false}`, consts.InvalidEnvs)
 	varTest(t, "challenge_envs", `{"key": 1}`, consts.InvalidEnvs)
 	varTest(t, "challenge_envs", `{"key": {}}`, consts.InvalidEnvs)
+	varTest(t, "challenge_envs", `{"key": "{{.TeamName}}-{{.RandomHex 16}}"}`)
+	varTest(t, "challenge_envs", `{"FLAG": "{{.Flag}}", "EXP": "{{.ExpiresAt}}"}`)
+	varTest(t, "challenge_envs", `{"key": "{{.TeamName"}`, consts.InvalidEnvs)
+	varTest(t, "challenge_envs", `{"key": "{{.Unknown}}"}`, consts.InvalidEnvs)
+	varTest(t, "challenge_envs", `{"key": "{{.RandomHex 0}}"}`, consts.InvalidEnvs)
 
 	varTest(t, "challenge_max_memory", -1, test_utils.Format(consts.MinError, "challenge_max_memory", 0))
 	varTest(t, "challenge_max_memory", 0)
@@ -133,6 +138,91 @@ func TestValidators(t *testing.T) {
 	varTest(t, "challenge_max_memory", math.MaxInt32)
 	varTest(t, "challenge_max_memory", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_max_memory", math.MaxInt32))
 
+	varTest(t, "challenge_pow_difficulty", -1, test_utils.Format(consts.MinError, "challenge_pow_difficulty", 0))
+	varTest(t, "challenge_pow_difficulty", 0)
+	varTest(t, "challenge_pow_difficulty", 20)
+	varTest(t, "challenge_pow_difficulty", consts.MaxPowDifficulty)
+	varTest(t, "challenge_pow_difficulty", consts.MaxPowDifficulty+1, test_utils.Format(consts.MaxError, "challenge_pow_difficulty", consts.MaxPowDifficulty))
+
+	varTest(t, "challenge_max_lifetime", -2, test_utils.Format(consts.MinError, "challenge_max_lifetime", -1))
+	varTest(t, "challenge_max_lifetime", -1)
+	varTest(t, "challenge_max_lifetime", 0)
+	varTest(t, "challenge_max_lifetime", 1337)
+	varTest(t, "challenge_max_lifetime", math.MaxInt32)
+	varTest(t, "challenge_max_lifetime", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_max_lifetime", math.MaxInt32))
+
+	varTest(t, "challenge_max_extensions", -2, test_utils.Format(consts.MinError, "challenge_max_extensions", -1))
+	varTest(t, "challenge_max_extensions", -1)
+	varTest(t, "challenge_max_extensions", 0)
+	varTest(t, "challenge_max_extensions", 1337)
+	varTest(t, "challenge_max_extensions", math.MaxInt32)
+	varTest(t, "challenge_max_extensions", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_max_extensions", math.MaxInt32))
+
+	varTest(t, "challenge_extension_cooldown", -2, test_utils.Format(consts.MinError, "challenge_extension_cooldown", -1))
+	varTest(t, "challenge_extension_cooldown", -1)
+	varTest(t, "challenge_extension_cooldown", 0)
+	varTest(t, "challenge_extension_cooldown", 1337)
+	varTest(t, "challenge_extension_cooldown", math.MaxInt32)
+	varTest(t, "challenge_extension_cooldown", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_extension_cooldown", math.MaxInt32))
+
+	varTest(t, "challenge_extension_window", -2, test_utils.Format(consts.MinError, "challenge_extension_window", -1))
+	varTest(t, "challenge_extension_window", -1)
+	varTest(t, "challenge_extension_window", 0)
+	varTest(t, "challenge_extension_window", 1337)
+	varTest(t, "challenge_extension_window", math.MaxInt32)
+	varTest(t, "challenge_extension_window", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_extension_window", math.MaxInt32))
+
+	varTest(t, "challenge_cpu_threshold", -1, test_utils.Format(consts.MinError, "challenge_cpu_threshold", 0))
+	varTest(t, "challenge_cpu_threshold", 0)
+	varTest(t, "challenge_cpu_threshold", 1337)
+	varTest(t, "challenge_cpu_threshold", math.MaxInt32)
+	varTest(t, "challenge_cpu_threshold", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_cpu_threshold", math.MaxInt32))
+
+	varTest(t, "challenge_memory_threshold", -1, test_utils.Format(consts.MinError, "challenge_memory_threshold", 0))
+	varTest(t, "challenge_memory_threshold", 0)
+	varTest(t, "challenge_memory_threshold", 1337)
+	varTest(t, "challenge_memory_threshold", math.MaxInt32)
+	varTest(t, "challenge_memory_threshold", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_memory_threshold", math.MaxInt32))
+
+	varTest(t, "challenge_pids_threshold", -1, test_utils.Format(consts.MinError, "challenge_pids_threshold", 0))
+	varTest(t, "challenge_pids_threshold", 0)
+	varTest(t, "challenge_pids_threshold", 1337)
+	varTest(t, "challenge_pids_threshold", math.MaxInt32)
+	varTest(t, "challenge_pids_threshold", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_pids_threshold", math.MaxInt32))
+
+	varTest(t, "challenge_network_threshold", -1, test_utils.Format(consts.MinError, "challenge_network_threshold", 0))
+	varTest(t, "challenge_network_threshold", 0)
+	varTest(t, "challenge_network_threshold", 1337)
+	varTest(t, "challenge_network_threshold", math.MaxInt32)
+	varTest(t, "challenge_network_threshold", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_network_threshold", math.MaxInt32))
+
+	varTest(t, "challenge_threshold_duration", -1, test_utils.Format(consts.MinError, "challenge_threshold_duration", 0))
+	varTest(t, "challenge_threshold_duration", 0)
+	varTest(t, "challenge_threshold_duration", 1337)
+	varTest(t, "challenge_threshold_duration", math.MaxInt32)
+	varTest(t, "challenge_threshold_duration", math.MaxInt32+1, test_utils.Format(consts.MaxError, "challenge_threshold_duration", math.MaxInt32))
+
+	varTest(t, "challenge_egress", "", test_utils.Format(consts.OneOfError, "challenge_egress", strings.Join(consts.EgressPoliciesStr, " ")))
+	varTest(t, "challenge_egress", sqlc.EgressPolicyNone)
+	varTest(t, "challenge_egress", sqlc.EgressPolicyDNS)
+	varTest(t, "challenge_egress", sqlc.EgressPolicyFull)
+	varTest(t, "challenge_egress", "aaa", test_utils.Format(consts.OneOfError, "challenge_egress", strings.Join(consts.EgressPoliciesStr, " ")))
+
+	varTest(t, "challenge_placement", []string{})
+	varTest(t, "challenge_placement", []string{"node.labels.zone==eu", "node.role != manager"})
+	varTest(t, "challenge_placement", []string{"node.labels.zone"}, consts.InvalidPlacement)
+	varTest(t, "challenge_placement", []string{"node.labels.zone=="}, consts.InvalidPlacement)
+	varTest(t, "challenge_placement", []string{"node.id==" + strings.Repeat("a", consts.MaxPlacementLen)}, consts.InvalidPlacement)
+
+	varTest(t, "challenge_reserved_memory", -1, test_utils.Format(consts.MinError, "challenge_reserved_memory", 0))
+	varTest(t, "challenge_reserved_memory", 0)
+	varTest(t, "challenge_reserved_memory", math.MaxInt32)
+
+	varTest(t, "challenge_reserved_cpu", "")
+	varTest(t, "challenge_reserved_cpu", "0.5")
+	varTest(t, "challenge_reserved_cpu", "0", consts.InvalidReservedCpu)
+	varTest(t, "challenge_reserved_cpu", "a", consts.InvalidReservedCpu)
+
 	varTest(t, "challenge_max_cpu", "-1", consts.InvalidMaxCpu)
 	varTest(t, "challenge_max_cpu", "0", consts.InvalidMaxCpu)
 	varTest(t, "challenge_max_cpu", "13.37")
@@ -148,6 +238,11 @@ func TestValidators(t *testing.T) {
 	varTest(t, "attachments", []string{strings.Repeat("a", consts.MaxAttachmentNameLen)})
 	varTest(t, "attachments", []string{strings.Repeat("a", consts.MaxAttachmentNameLen+1)}, test_utils.Format(consts.MaxError, "[0]", consts.MaxAttachmentNameLen))
 
+	varTest(t, "pow_solution", "")
+	varTest(t, "pow_solution", "a")
+	varTest(t, "pow_solution", strings.Repeat("a", consts.MaxPowSolutionLen))
+	varTest(t, "pow_solution", strings.Repeat("a", consts.MaxPowSolutionLen+1), test_utils.Format(consts.MaxError, "pow_solution", consts.MaxPowSolutionLen))
+
 	varTest(t, "flag", "")
 	varTest(t, "flag", "a")
 	varTest(t, "flag", strings.Repeat("a", consts.MaxFlagLen))
@@ -175,4 +270,17 @@ func TestValidators(t *testing.T) {
 	varTest(t, "user_role", sqlc.UserRoleAuthor)
 	varTest(t, "user_role", sqlc.UserRoleAdmin)
 	varTest(t, "user_role", "aaa", test_utils.Format(consts.OneOfError, "user_role", strings.Join(consts.RolesStr, " ")))
+
+	varTest(t, "writeup_url", "", consts.InvalidHttpUrl)
+	varTest(t, "writeup_url", "example.com", consts.InvalidHttpUrl)
+	varTest(t, "writeup_url", "ftp://example.com/writeup", consts.InvalidHttpUrl)
+	varTest(t, "writeup_url", "https://example.com/writeup")
+	varTest(t, "writeup_url", "http://example.com/"+strings.Repeat("a", consts.MaxWriteupUrlLen), test_utils.Format(consts.MaxError, "writeup_url", consts.MaxWriteupUrlLen))
+}
+
+func TestRules(t *testing.T) {
+	test_utils.Compare(t, []string{"required", "min=0", fmt.Sprintf("max=%d", math.MaxInt32)}, validator.Rules("required,id"))
+	test_utils.Compare(t, []string{"dive", fmt.Sprintf("max=%d", consts.MaxTagNameLen)}, validator.Rules("challenge_tags"))
+	test_utils.Compare(t, []string{"omitempty", "country"}, validator.Rules("omitempty,country"))
+	test_utils.Compare(t, []string{"dive", "oneof=" + strings.Join(consts.Permissions, " ")}, validator.Rules("permissions"))
 }
@@ -55,6 +55,11 @@ case "$ACTION" in
 		for rule in "${rules[@]}"; do
 			create_rule $rule
 		done
-		docker network create --subnet=$SUBNET trxd-shared-internal
+		# In Swarm mode the instances on every node attach to an overlay network
+		if [ "$SWARM" = "1" ]; then
+			docker network create --driver overlay --attachable --subnet=$SUBNET trxd-shared-internal
+		else
+			docker network create --subnet=$SUBNET trxd-shared-internal
+		fi
 		;;
 esac